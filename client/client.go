@@ -0,0 +1,238 @@
+/*
+ * @Description: RESP client
+ * @Autor: HTmonster
+ * @Date: 2026-10-18 16:08:12
+ */
+
+package client
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/HTmonster/redissgo/internal/reply"
+)
+
+// returned by Do and Pipeline when the replies of commands buffered by Send
+// have not been received, the connection stays usable
+var ErrPending = errors.New("redis: replies of sent commands are pending")
+
+// a single connection to the server, not safe for concurrent use
+type Conn struct {
+	conn      net.Conn
+	bufreader *bufio.Reader
+	writer    *reply.Writer
+	pending   int   // commands sent but not received
+	err       error // fatal error, the connection can not be used anymore
+}
+
+/**
+ * @description: connect to the server
+ * @param {string} addr e.g. 127.0.0.1:6379
+ * @return {*}
+ */
+func Dial(addr string) (*Conn, error) {
+	return DialTimeout(addr, 0)
+}
+
+/**
+ * @description: connect to the server with a timeout
+ * @param {string} addr
+ * @param {time.Duration} timeout
+ * @return {*}
+ */
+func DialTimeout(addr string, timeout time.Duration) (*Conn, error) {
+	conn, err := net.DialTimeout("tcp", addr, timeout)
+	if err != nil {
+		return nil, err
+	}
+	return NewConn(conn), nil
+}
+
+/**
+ * @description: wrap an established connection
+ * @param {net.Conn} conn
+ * @return {*}
+ */
+func NewConn(conn net.Conn) *Conn {
+	return &Conn{
+		conn:      conn,
+		bufreader: bufio.NewReader(conn),
+		writer:    reply.NewWriter(conn),
+	}
+}
+
+/**
+ * @description: close the connection
+ */
+func (c *Conn) Close() error {
+	return c.conn.Close()
+}
+
+/**
+ * @description: fatal error of the connection, nil if it is still usable
+ */
+func (c *Conn) Err() error {
+	return c.err
+}
+
+/**
+ * @description: send a command and wait for its reply. The replies of
+ *	commands buffered by Send must be received first, Do never drops them.
+ * @param {string} cmd
+ * @param {...interface{}} args string, []byte, integers, float64 or bool
+ * @return {*} reply, Error if the server replied an error, ErrPending if
+ *	replies of sent commands are pending
+ */
+func (c *Conn) Do(cmd string, args ...interface{}) (interface{}, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	if c.pending > 0 {
+		return nil, ErrPending
+	}
+	if err := c.Send(cmd, args...); err != nil {
+		return nil, err
+	}
+	if err := c.Flush(); err != nil {
+		return nil, err
+	}
+	return c.Receive()
+}
+
+/**
+ * @description: buffer a command, call Flush to send all buffered commands
+ * @param {string} cmd
+ * @param {...interface{}} args
+ * @return {*}
+ */
+func (c *Conn) Send(cmd string, args ...interface{}) error {
+	if c.err != nil {
+		return c.err
+	}
+	params := make([][]byte, 0, len(args)+1)
+	params = append(params, []byte(cmd))
+	for _, arg := range args {
+		params = append(params, encodeArg(arg))
+	}
+	if err := c.writer.Write(reply.MakeMultiBulkReply(params)); err != nil {
+		return c.fatal(err)
+	}
+	c.pending++
+	return nil
+}
+
+/**
+ * @description: send all buffered commands
+ */
+func (c *Conn) Flush() error {
+	if c.err != nil {
+		return c.err
+	}
+	if err := c.writer.Flush(); err != nil {
+		return c.fatal(err)
+	}
+	return nil
+}
+
+/**
+ * @description: receive the reply of the oldest pending command
+ * @return {*} reply, Error if the server replied an error
+ */
+func (c *Conn) Receive() (interface{}, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	r, err := readReply(c.bufreader)
+	if err != nil {
+		return nil, c.fatal(err)
+	}
+	// push messages are not replies of a command
+	if _, ok := r.(Push); !ok && c.pending > 0 {
+		c.pending--
+	}
+	if e, ok := r.(Error); ok {
+		return nil, e
+	}
+	return r, nil
+}
+
+/**
+ * @description: send commands in one round trip and receive all replies, like
+ *	Do the replies of commands buffered by Send must be received first
+ * @param {[][]interface{}} cmds every command is name followed by arguments
+ * @return {*} replies, an Error for every failed command
+ */
+func (c *Conn) Pipeline(cmds [][]interface{}) ([]interface{}, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	if c.pending > 0 {
+		return nil, ErrPending
+	}
+	for _, cmd := range cmds {
+		name, _ := cmd[0].(string)
+		if err := c.Send(name, cmd[1:]...); err != nil {
+			return nil, err
+		}
+	}
+	if err := c.Flush(); err != nil {
+		return nil, err
+	}
+	replies := make([]interface{}, len(cmds))
+	for i := range cmds {
+		r, err := c.Receive()
+		if e, ok := err.(Error); ok {
+			replies[i] = e
+		} else if err != nil {
+			return nil, err
+		} else {
+			replies[i] = r
+		}
+	}
+	return replies, nil
+}
+
+/**
+ * @description: mark the connection broken
+ */
+func (c *Conn) fatal(err error) error {
+	if c.err == nil {
+		c.err = err
+		_ = c.conn.Close()
+	}
+	return err
+}
+
+/**
+ * @description: convert a command argument into bytes
+ */
+func encodeArg(arg interface{}) []byte {
+	switch v := arg.(type) {
+	case []byte:
+		return v
+	case string:
+		return []byte(v)
+	case int:
+		return strconv.AppendInt(nil, int64(v), 10)
+	case int64:
+		return strconv.AppendInt(nil, v, 10)
+	case uint64:
+		return strconv.AppendUint(nil, v, 10)
+	case float64:
+		return strconv.AppendFloat(nil, v, 'g', -1, 64)
+	case bool:
+		if v {
+			return []byte("1")
+		}
+		return []byte("0")
+	case nil:
+		return []byte{}
+	default:
+		return []byte(fmt.Sprint(v))
+	}
+}
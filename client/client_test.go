@@ -0,0 +1,184 @@
+/*
+ * @Description:
+ * @Autor: HTmonster
+ * @Date: 2026-10-18 16:58:40
+ */
+package client
+
+import (
+	"bufio"
+	"math/big"
+	"net"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/HTmonster/redissgo/server"
+)
+
+/**
+ * @description: run a server on a random port
+ */
+func startServer(t *testing.T) (string, func()) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	closeChan := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		server.ListenAndServe(listener, closeChan)
+		close(done)
+	}()
+	return listener.Addr().String(), func() {
+		close(closeChan)
+		<-done
+	}
+}
+
+func TestReadReply(t *testing.T) {
+	cases := []struct {
+		data string
+		want interface{}
+	}{
+		{"+OK\r\n", "OK"},
+		{"-ERR oops\r\n", Error("ERR oops")},
+		{":-3\r\n", int64(-3)},
+		{"$3\r\na\r\n\r\n", []byte("a\r\n")},
+		{"$-1\r\n", nil},
+		{"*-1\r\n", nil},
+		{"_\r\n", nil},
+		{"*2\r\n:1\r\n$1\r\nx\r\n", []interface{}{int64(1), []byte("x")}},
+		{"%1\r\n+k\r\n#t\r\n", Map{"k", true}},
+		{"~1\r\n,1.5\r\n", Set{1.5}},
+		{">1\r\n+message\r\n", Push{"message"}},
+		{"=8\r\ntxt:some\r\n", Verbatim{Format: "txt", Text: []byte("some")}},
+		{"|1\r\n+ttl\r\n:3\r\n:7\r\n", int64(7)},
+	}
+	for _, c := range cases {
+		got, err := readReply(bufio.NewReader(strings.NewReader(c.data)))
+		if err != nil {
+			t.Errorf("read %q: %s", c.data, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, c.want) {
+			t.Errorf("read %q: got %#v, want %#v", c.data, got, c.want)
+		}
+	}
+
+	got, err := readReply(bufio.NewReader(strings.NewReader("(3492890328409238509324850943850943825024385\r\n")))
+	want, _ := new(big.Int).SetString("3492890328409238509324850943850943825024385", 10)
+	if err != nil || got.(*big.Int).Cmp(want) != 0 {
+		t.Errorf("read big number: got %v %v", got, err)
+	}
+}
+
+func TestConnDo(t *testing.T) {
+	addr, stop := startServer(t)
+	defer stop()
+
+	conn, err := Dial(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	if s, err := String(conn.Do("PING")); err != nil || s != "PONG" {
+		t.Errorf("PING: got %q %v", s, err)
+	}
+	if s, err := String(conn.Do("ECHO", []byte("a\r\nb"))); err != nil || s != "a\r\nb" {
+		t.Errorf("ECHO: got %q %v", s, err)
+	}
+	if _, err := conn.Do("NOSUCHCMD"); err == nil || !strings.HasPrefix(err.Error(), "ERR unknown command") {
+		t.Errorf("unknown command: got %v", err)
+	}
+	if r, err := conn.Do("HELLO", 3); err != nil {
+		t.Errorf("HELLO 3: %v", err)
+	} else if _, ok := r.(Map); !ok {
+		t.Errorf("HELLO 3: got %T, want Map", r)
+	}
+
+	replies, err := conn.Pipeline([][]interface{}{
+		{"PING"},
+		{"ECHO", "x"},
+		{"ECHO"},
+		{"PING", 7},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []interface{}{"PONG", []byte("x"), Error("ERR wrong number of arguments for 'echo' command"), []byte("7")}
+	if !reflect.DeepEqual(replies, want) {
+		t.Errorf("pipeline: got %#v, want %#v", replies, want)
+	}
+
+	// replies of sent commands are never dropped by Do
+	if err := conn.Send("ECHO", "sent"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := conn.Do("PING"); err != ErrPending {
+		t.Errorf("Do with pending sends: got %v, want ErrPending", err)
+	}
+	if _, err := conn.Pipeline([][]interface{}{{"PING"}}); err != ErrPending {
+		t.Errorf("Pipeline with pending sends: got %v, want ErrPending", err)
+	}
+	if err := conn.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if s, err := String(conn.Receive()); err != nil || s != "sent" {
+		t.Errorf("pending reply: got %q %v", s, err)
+	}
+	if s, err := String(conn.Do("PING")); err != nil || s != "PONG" {
+		t.Errorf("PING after receive: got %q %v", s, err)
+	}
+}
+
+func TestPool(t *testing.T) {
+	addr, stop := startServer(t)
+	defer stop()
+
+	pool := NewPool(addr, 4)
+	defer pool.Close()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 32; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if n, err := Int64(pool.Do("ECHO", i)); err != nil || n != int64(i) {
+				t.Errorf("ECHO %d: got %d %v", i, n, err)
+			}
+		}(i)
+	}
+	wg.Wait()
+}
+
+func TestPoolClose(t *testing.T) {
+	addr, stop := startServer(t)
+	defer stop()
+
+	// clients racing Close get replies or ErrPoolClosed, never a nil connection
+	pool := NewPool(addr, 4)
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				if _, err := pool.Do("PING"); err == ErrPoolClosed {
+					return
+				} else if err != nil {
+					t.Errorf("PING: %v", err)
+					return
+				}
+			}
+		}()
+	}
+	_ = pool.Close()
+	wg.Wait()
+
+	if _, err := pool.Get(); err != ErrPoolClosed {
+		t.Errorf("get from a closed pool: %v", err)
+	}
+}
@@ -0,0 +1,131 @@
+/*
+ * @Description: reply conversion helpers, e.g. client.String(conn.Do("GET", "k"))
+ * @Autor: HTmonster
+ * @Date: 2026-10-18 16:36:25
+ */
+
+package client
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// returned by the helpers when the reply is null
+var ErrNil = errors.New("redis: nil reply")
+
+/**
+ * @description: convert a reply into a string
+ */
+func String(r interface{}, err error) (string, error) {
+	if err != nil {
+		return "", err
+	}
+	switch v := r.(type) {
+	case string:
+		return v, nil
+	case []byte:
+		return string(v), nil
+	case Verbatim:
+		return string(v.Text), nil
+	case nil:
+		return "", ErrNil
+	}
+	return "", fmt.Errorf("redis: unexpected type %T for string", r)
+}
+
+/**
+ * @description: convert a reply into bytes
+ */
+func Bytes(r interface{}, err error) ([]byte, error) {
+	if err != nil {
+		return nil, err
+	}
+	switch v := r.(type) {
+	case []byte:
+		return v, nil
+	case string:
+		return []byte(v), nil
+	case nil:
+		return nil, ErrNil
+	}
+	return nil, fmt.Errorf("redis: unexpected type %T for bytes", r)
+}
+
+/**
+ * @description: convert a reply into an integer
+ */
+func Int64(r interface{}, err error) (int64, error) {
+	if err != nil {
+		return 0, err
+	}
+	switch v := r.(type) {
+	case int64:
+		return v, nil
+	case []byte:
+		return strconv.ParseInt(string(v), 10, 64)
+	case bool:
+		if v {
+			return 1, nil
+		}
+		return 0, nil
+	case nil:
+		return 0, ErrNil
+	}
+	return 0, fmt.Errorf("redis: unexpected type %T for int64", r)
+}
+
+/**
+ * @description: convert a reply into a float
+ */
+func Float64(r interface{}, err error) (float64, error) {
+	if err != nil {
+		return 0, err
+	}
+	switch v := r.(type) {
+	case float64:
+		return v, nil
+	case int64:
+		return float64(v), nil
+	case []byte:
+		return parseDouble(string(v))
+	case nil:
+		return 0, ErrNil
+	}
+	return 0, fmt.Errorf("redis: unexpected type %T for float64", r)
+}
+
+/**
+ * @description: convert an array reply into strings, null elements become ""
+ */
+func Strings(r interface{}, err error) ([]string, error) {
+	if err != nil {
+		return nil, err
+	}
+	var values []interface{}
+	switch v := r.(type) {
+	case []interface{}:
+		values = v
+	case Set:
+		values = v
+	case Map:
+		values = v
+	case nil:
+		return nil, ErrNil
+	default:
+		return nil, fmt.Errorf("redis: unexpected type %T for strings", r)
+	}
+	result := make([]string, len(values))
+	for i, value := range values {
+		if value == nil {
+			continue
+		}
+		s, err := String(value, nil)
+		if err != nil {
+			return nil, err
+		}
+		result[i] = s
+	}
+	return result, nil
+}
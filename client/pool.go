@@ -0,0 +1,114 @@
+/*
+ * @Description: connection pool
+ * @Autor: HTmonster
+ * @Date: 2026-10-18 16:45:03
+ */
+
+package client
+
+import (
+	"errors"
+	"sync"
+)
+
+var ErrPoolClosed = errors.New("redis: pool closed")
+
+// pool of connections to one server, safe for concurrent use
+type Pool struct {
+	dial   func() (*Conn, error)
+	idle   chan *Conn
+	mu     sync.Mutex
+	closed bool
+}
+
+/**
+ * @description: make a pool keeping at most maxIdle idle connections
+ * @param {string} addr
+ * @param {int} maxIdle
+ * @return {*}
+ */
+func NewPool(addr string, maxIdle int) *Pool {
+	return &Pool{
+		dial: func() (*Conn, error) { return Dial(addr) },
+		idle: make(chan *Conn, maxIdle),
+	}
+}
+
+/**
+ * @description: get an idle connection or dial a new one
+ */
+func (p *Pool) Get() (*Conn, error) {
+	p.mu.Lock()
+	closed := p.closed
+	p.mu.Unlock()
+	if closed {
+		return nil, ErrPoolClosed
+	}
+
+	select {
+	case c, ok := <-p.idle:
+		// closed by Close after the check
+		if !ok {
+			return nil, ErrPoolClosed
+		}
+		return c, nil
+	default:
+		return p.dial()
+	}
+}
+
+/**
+ * @description: give the connection back, broken or busy connections are closed
+ * @param {*Conn} c
+ * @return {*}
+ */
+func (p *Pool) Put(c *Conn) {
+	if c.Err() != nil || c.pending > 0 {
+		_ = c.Close()
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closed {
+		_ = c.Close()
+		return
+	}
+	select {
+	case p.idle <- c:
+	default:
+		_ = c.Close()
+	}
+}
+
+/**
+ * @description: run a command on a pooled connection
+ * @param {string} cmd
+ * @param {...interface{}} args
+ * @return {*}
+ */
+func (p *Pool) Do(cmd string, args ...interface{}) (interface{}, error) {
+	c, err := p.Get()
+	if err != nil {
+		return nil, err
+	}
+	defer p.Put(c)
+	return c.Do(cmd, args...)
+}
+
+/**
+ * @description: close all idle connections, the pool can not be used anymore
+ */
+func (p *Pool) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closed {
+		return nil
+	}
+	p.closed = true
+	close(p.idle)
+	for c := range p.idle {
+		_ = c.Close()
+	}
+	return nil
+}
@@ -0,0 +1,169 @@
+/*
+ * @Description: RESP reply parser of the client
+ * @Autor: HTmonster
+ * @Date: 2026-10-18 16:20:44
+ */
+
+package client
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"math"
+	"math/big"
+	"strconv"
+)
+
+// error reply sent by the server, e.g. ERR unknown command
+type Error string
+
+func (e Error) Error() string {
+	return string(e)
+}
+
+// RESP3 aggregate types, RESP2 arrays are []interface{}
+type (
+	Map  []interface{} // key1, value1, key2, value2 ...
+	Set  []interface{}
+	Push []interface{}
+)
+
+// RESP3 verbatim string
+type Verbatim struct {
+	Format string
+	Text   []byte
+}
+
+var errProtocol = errors.New("redis: protocol error")
+
+/**
+ * @description: read one reply, the types are
+ *	simple string: string, error: Error, integer: int64, bulk string: []byte,
+ *	null: nil, array: []interface{}, map: Map, set: Set, push: Push,
+ *	double: float64, boolean: bool, big number: *big.Int, verbatim: Verbatim.
+ *	Attributes are skipped.
+ * @param {*bufio.Reader} bufreader
+ * @return {*}
+ */
+func readReply(bufreader *bufio.Reader) (interface{}, error) {
+	line, err := readLine(bufreader)
+	if err != nil {
+		return nil, err
+	}
+	if len(line) == 0 {
+		return nil, errProtocol
+	}
+
+	body := line[1:]
+	switch line[0] {
+	case '+':
+		return string(body), nil
+	case '-':
+		return Error(body), nil
+	case ':':
+		n, err := strconv.ParseInt(string(body), 10, 64)
+		if err != nil {
+			return nil, errProtocol
+		}
+		return n, nil
+	case '_':
+		return nil, nil
+	case '#':
+		if len(body) != 1 || (body[0] != 't' && body[0] != 'f') {
+			return nil, errProtocol
+		}
+		return body[0] == 't', nil
+	case ',':
+		f, err := parseDouble(string(body))
+		if err != nil {
+			return nil, errProtocol
+		}
+		return f, nil
+	case '(':
+		n, ok := new(big.Int).SetString(string(body), 10)
+		if !ok {
+			return nil, errProtocol
+		}
+		return n, nil
+	case '$', '=':
+		size, err := strconv.ParseInt(string(body), 10, 64)
+		if err != nil {
+			return nil, errProtocol
+		}
+		if size < 0 {
+			return nil, nil
+		}
+		data := make([]byte, size+2)
+		if _, err := io.ReadFull(bufreader, data); err != nil {
+			return nil, err
+		}
+		data = data[:size]
+		if line[0] == '=' {
+			if len(data) < 4 || data[3] != ':' {
+				return nil, errProtocol
+			}
+			return Verbatim{Format: string(data[:3]), Text: data[4:]}, nil
+		}
+		return data, nil
+	case '*', '~', '>', '%', '|':
+		n, err := strconv.ParseInt(string(body), 10, 64)
+		if err != nil {
+			return nil, errProtocol
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		if line[0] == '%' || line[0] == '|' {
+			n *= 2
+		}
+		values := make([]interface{}, n)
+		for i := range values {
+			if values[i], err = readReply(bufreader); err != nil {
+				return nil, err
+			}
+		}
+		switch line[0] {
+		case '~':
+			return Set(values), nil
+		case '>':
+			return Push(values), nil
+		case '%':
+			return Map(values), nil
+		case '|':
+			// attributes come before the real reply
+			return readReply(bufreader)
+		}
+		return values, nil
+	}
+	return nil, errProtocol
+}
+
+/**
+ * @description: read a line, without the trailing \r\n
+ */
+func readLine(bufreader *bufio.Reader) ([]byte, error) {
+	line, err := bufreader.ReadBytes('\n')
+	if err != nil {
+		return nil, err
+	}
+	if len(line) < 2 || line[len(line)-2] != '\r' {
+		return nil, errProtocol
+	}
+	return line[:len(line)-2], nil
+}
+
+/**
+ * @description: parse a RESP3 double, including inf, -inf and nan
+ */
+func parseDouble(s string) (float64, error) {
+	switch s {
+	case "inf":
+		return math.Inf(1), nil
+	case "-inf":
+		return math.Inf(-1), nil
+	case "nan":
+		return math.NaN(), nil
+	}
+	return strconv.ParseFloat(s, 64)
+}
@@ -56,7 +56,7 @@ func initDaemon() {
 	} else {
 		defer func() {
 			if err := context.Release(); err != nil {
-				logger.Log.Error("Unable to release pid-file: %s", err.Error())
+				logger.Log.Error("Unable to release pid-file: ", err.Error())
 			}
 		}()
 
@@ -6,7 +6,10 @@
 package dict
 
 import (
+	"container/heap"
 	"math"
+	"math/bits"
+	"math/rand"
 	"sync"
 	"sync/atomic"
 )
@@ -15,10 +18,11 @@ const prime32 = uint32(16777619)
 
 // Segment locking strategy
 
-//Segment dict
+// Segment dict
 type Segment struct {
-	m      map[string]interface{}
-	mutext sync.RWMutex
+	m       map[string]interface{}
+	expires map[string]int64 // unix time in milliseconds, only keys with TTL
+	mutext  sync.RWMutex
 }
 
 // concurrent dict
@@ -79,7 +83,8 @@ func MakeConcurrentDict(segCount int) *ConcurrentDict {
 	//2. initial every Segment
 	for i := 0; i < capacity; i++ {
 		table[i] = &Segment{
-			m: make(map[string]interface{}),
+			m:       make(map[string]interface{}),
+			expires: make(map[string]int64),
 		}
 	}
 	//3. build the concurrent dictionary
@@ -147,9 +152,20 @@ func (dict *ConcurrentDict) Get(key string) (value interface{}, exists bool) {
 	segment := dict.getSegment(index)
 	//3. lock when read value
 	segment.mutext.RLock()
-	defer segment.mutext.RUnlock()
 	//4. read value
 	value, exists = segment.m[key]
+	expired := exists && segment.isExpired(key, NowMillis())
+	segment.mutext.RUnlock()
+	//5. lazy expire
+	if expired {
+		segment.mutext.Lock()
+		defer segment.mutext.Unlock()
+		if dict.expireIfNeeded(segment, key) {
+			return nil, false
+		}
+		// written by others after the check
+		value, exists = segment.m[key]
+	}
 	return value, exists
 }
 
@@ -172,7 +188,8 @@ func (dict *ConcurrentDict) Put(key string, value interface{}) int {
 	//3. lock when write
 	segment.mutext.Lock()
 	defer segment.mutext.Unlock()
-	//4. write segment
+	//4. write segment, the TTL of a living key is kept
+	dict.expireIfNeeded(segment, key)
 	//4.1 exists or not
 	if _, ok := segment.m[key]; ok {
 		segment.m[key] = value
@@ -184,6 +201,63 @@ func (dict *ConcurrentDict) Put(key string, value interface{}) int {
 	}
 }
 
+/**
+ * @description: Put the key-value only if the key does not exist
+ * @param {string} key
+ * @param {interface{}} value
+ * @return {*} 1 if inserted, 0 if the key exists
+ */
+func (dict *ConcurrentDict) PutIfAbsent(key string, value interface{}) int {
+	if dict == nil {
+		panic("Error, dictionary is nil")
+	}
+	//0. get key hash value
+	hashCode := fnv32(key)
+	//1. get segment index
+	index := dict.spread(hashCode)
+	//2. get segment by index
+	segment := dict.getSegment(index)
+	//3. lock when write
+	segment.mutext.Lock()
+	defer segment.mutext.Unlock()
+	//4. write segment only if absent
+	dict.expireIfNeeded(segment, key)
+	if _, ok := segment.m[key]; ok {
+		return 0
+	}
+	segment.m[key] = value
+	dict.addCount()
+	return 1
+}
+
+/**
+ * @description: Put the key-value only if the key exists
+ * @param {string} key
+ * @param {interface{}} value
+ * @return {*} 1 if updated, 0 if the key does not exist
+ */
+func (dict *ConcurrentDict) PutIfExists(key string, value interface{}) int {
+	if dict == nil {
+		panic("Error, dictionary is nil")
+	}
+	//0. get key hash value
+	hashCode := fnv32(key)
+	//1. get segment index
+	index := dict.spread(hashCode)
+	//2. get segment by index
+	segment := dict.getSegment(index)
+	//3. lock when write
+	segment.mutext.Lock()
+	defer segment.mutext.Unlock()
+	//4. write segment only if exists
+	dict.expireIfNeeded(segment, key)
+	if _, ok := segment.m[key]; ok {
+		segment.m[key] = value
+		return 1
+	}
+	return 0
+}
+
 /**
  * @description: Remove a key from the dictionary
  * @param {string} key
@@ -203,8 +277,12 @@ func (dict *ConcurrentDict) Remove(key string) int {
 	segment.mutext.Lock()
 	defer segment.mutext.Unlock()
 	//4. remove
+	if dict.expireIfNeeded(segment, key) {
+		return 0
+	}
 	if _, ok := segment.m[key]; ok {
 		delete(segment.m, key)
+		delete(segment.expires, key)
 		dict.decreaseCount()
 		return 1
 	}
@@ -237,16 +315,28 @@ func (dict *ConcurrentDict) ForEach(consumer Consumer) {
 		// lock when read
 		segment.mutext.RLock()
 		// operation depended on consumer
-		func() {
-			defer segment.mutext.RUnlock()
-			for key, value := range segment.m {
-				if c := consumer(key, value); !c {
-					return
-				}
-			}
+		next := segment.forEach(NowMillis(), consumer)
+		segment.mutext.RUnlock()
+		if !next {
+			return
+		}
+	}
+}
 
-		}()
+/**
+ * @description: traversal the living keys of a segment, the segment must be locked
+ * @return {*} false if the consumer stopped the traversal
+ */
+func (segment *Segment) forEach(now int64, consumer Consumer) bool {
+	for key, value := range segment.m {
+		if segment.isExpired(key, now) {
+			continue
+		}
+		if !consumer(key, value) {
+			return false
+		}
 	}
+	return true
 }
 
 /**
@@ -270,11 +360,208 @@ func (dict *ConcurrentDict) Keys() []string {
 	return keys
 }
 
+/**
+ * @description: visit the keys from cursor until about count keys are
+ *	visited, only one segment is locked at a time. The low bits of the cursor
+ *	are a segment index, the high bits a position in the segment: the keys of
+ *	a segment are visited in the reverse binary order of their hash, like
+ *	SimpleDict.Scan, so keys present during the whole scan are visited once.
+ *	A cursor is only valid for dictionaries with the same number of segments,
+ *	it does not survive a change of the segment count.
+ * @param {uint64} cursor 0 to start a new scan
+ * @param {int} count
+ * @param {Consumer} consumer called under the segment read lock, it must not
+ *	call methods of the same dictionary, the return value is ignored
+ * @return {*} next cursor, 0 when the scan is completed
+ */
+func (dict *ConcurrentDict) Scan(cursor uint64, count int, consumer Consumer) uint64 {
+	if dict == nil {
+		panic("Error, dictionary is nil")
+	}
+	return dict.scan(cursor, count, consumer, true)
+}
+
+/**
+ * @description: Scan, with or without locking every visited segment
+ * @param {bool} lock false if the caller holds the locks of all segments
+ */
+func (dict *ConcurrentDict) scan(cursor uint64, count int, consumer Consumer, lock bool) uint64 {
+	shift := bits.Len(uint(len(dict.table) - 1))
+	index, position := cursor&uint64(len(dict.table)-1), cursor>>shift
+	visited := 0
+	for int(index) < len(dict.table) {
+		segment := dict.table[index]
+		if lock {
+			segment.mutext.RLock()
+		}
+		next, done := segment.scan(position, count-visited, func(key string, value interface{}) bool {
+			consumer(key, value)
+			visited++
+			return true
+		})
+		if lock {
+			segment.mutext.RUnlock()
+		}
+
+		if !done {
+			return next<<shift | index
+		}
+		// the segment is completed, continue with the next one
+		index, position = index+1, 0
+		if visited >= count {
+			break
+		}
+	}
+	if int(index) >= len(dict.table) {
+		return 0
+	}
+	return index
+}
+
+// max-heap of the smallest positions met by Segment.scan
+type positionHeap []uint64
+
+func (h positionHeap) Len() int            { return len(h) }
+func (h positionHeap) Less(i, j int) bool  { return h[i] > h[j] }
+func (h positionHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *positionHeap) Push(x interface{}) { *h = append(*h, x.(uint64)) }
+func (h *positionHeap) Pop() interface{} {
+	old := *h
+	x := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return x
+}
+
+/**
+ * @description: visit the living keys of a segment from position, in the
+ *	reverse binary order of their hash, the segment must be locked. The
+ *	segment is not sorted: a first pass keeps the count smallest positions,
+ *	a second one visits the keys up to the largest of them.
+ * @param {uint64} position
+ * @param {int} count keys with the same hash are visited together, so the
+ *	consumer may be called a few more times
+ * @param {Consumer} consumer
+ * @return {*} next position, true if the segment is completed
+ */
+func (segment *Segment) scan(position uint64, count int, consumer Consumer) (uint64, bool) {
+	if count < 1 {
+		count = 1
+	}
+	now := NowMillis()
+	smallest := make(positionHeap, 0, count)
+	for key := range segment.m {
+		at := uint64(bits.Reverse32(fnv32(key)))
+		if at < position || segment.isExpired(key, now) {
+			continue
+		}
+		if len(smallest) < count {
+			heap.Push(&smallest, at)
+		} else if at < smallest[0] {
+			smallest[0] = at
+			heap.Fix(&smallest, 0)
+		}
+	}
+
+	// fewer than count keys are left, the segment is completed
+	done := len(smallest) < count
+	limit := uint64(math.MaxUint64)
+	if !done {
+		limit = smallest[0]
+	}
+	for key, value := range segment.m {
+		at := uint64(bits.Reverse32(fnv32(key)))
+		if at >= position && at <= limit && !segment.isExpired(key, now) {
+			consumer(key, value)
+		}
+	}
+	if done {
+		return 0, true
+	}
+	return limit + 1, false
+}
+
+/**
+ * @description: return a random key, segments are visited from a random one
+ * @return {*} key, false if the dictionary is empty
+ */
+func (dict *ConcurrentDict) RandomKey() (string, bool) {
+	if dict == nil {
+		panic("Error, dictionary is nil")
+	}
+	return dict.randomKey(true)
+}
+
+/**
+ * @description: RandomKey, with or without locking every visited segment
+ * @param {bool} lock false if the caller holds the locks of all segments
+ */
+func (dict *ConcurrentDict) randomKey(lock bool) (string, bool) {
+	start := rand.Intn(len(dict.table))
+	for i := 0; i < len(dict.table); i++ {
+		segment := dict.table[(start+i)%len(dict.table)]
+		if lock {
+			segment.mutext.RLock()
+		}
+		key, ok := segment.randomKey(NowMillis())
+		if lock {
+			segment.mutext.RUnlock()
+		}
+		if ok {
+			return key, true
+		}
+	}
+	return "", false
+}
+
+/**
+ * @description: a random living key of the segment, the segment must be locked
+ */
+func (segment *Segment) randomKey(now int64) (string, bool) {
+	if len(segment.m) == 0 {
+		return "", false
+	}
+	// skip a random number of keys, map iteration alone is not uniform
+	skip := rand.Intn(len(segment.m))
+	var fallback string
+	found := false
+	for key := range segment.m {
+		if segment.isExpired(key, now) {
+			continue
+		}
+		if skip <= 0 {
+			return key, true
+		}
+		if !found {
+			fallback, found = key, true
+		}
+		skip--
+	}
+	return fallback, found
+}
+
 /**
  * @description: clear all key and value
  * @param {*}
  * @return {*}
  */
 func (dict *ConcurrentDict) Clear() {
-	*dict = *MakeConcurrentDict(int(dict.segCount))
+	if dict == nil {
+		panic("Error, dictionary is nil")
+	}
+
+	for _, segment := range dict.table {
+		// lock every segment, so it is safe while others are reading
+		segment.mutext.Lock()
+		dict.clearSegment(segment)
+		segment.mutext.Unlock()
+	}
+}
+
+/**
+ * @description: remove all keys of a segment, the segment must be write locked
+ */
+func (dict *ConcurrentDict) clearSegment(segment *Segment) {
+	atomic.AddInt32(&dict.count, -int32(len(segment.m)))
+	segment.m = make(map[string]interface{})
+	segment.expires = make(map[string]int64)
 }
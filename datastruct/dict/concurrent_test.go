@@ -129,3 +129,229 @@ func TestConcurrentDictKeys(t *testing.T) {
 		}
 	}
 }
+
+func TestConcurrentDictPutIf(t *testing.T) {
+	dict := MakeConcurrentDict(16)
+	if ret := dict.PutIfExists("key", 1); ret != 0 {
+		t.Errorf("PutIfExists on a missing key should return 0")
+	}
+	if ret := dict.PutIfAbsent("key", 1); ret != 1 {
+		t.Errorf("PutIfAbsent on a missing key should return 1")
+	}
+	if ret := dict.PutIfAbsent("key", 2); ret != 0 {
+		t.Errorf("PutIfAbsent on an existing key should return 0")
+	}
+	if ret := dict.PutIfExists("key", 3); ret != 1 {
+		t.Errorf("PutIfExists on an existing key should return 1")
+	}
+	if val, _ := dict.Get("key"); val.(int) != 3 || dict.Len() != 1 {
+		t.Errorf("test fail to get value: %v, len %d", val, dict.Len())
+	}
+}
+
+func TestConcurrentDictClear(t *testing.T) {
+	var wg sync.WaitGroup
+
+	dict := MakeConcurrentDict(16)
+	for i := 0; i < 100; i++ {
+		dict.Put("key"+strconv.Itoa(i), i)
+	}
+	// clear while others are reading
+	wg.Add(1)
+	go func() {
+		for i := 0; i < 100; i++ {
+			dict.Get("key" + strconv.Itoa(i))
+		}
+		wg.Done()
+	}()
+	dict.Clear()
+	wg.Wait()
+
+	if dict.Len() != 0 || len(dict.Keys()) != 0 {
+		t.Errorf("test clear fail, len %d", dict.Len())
+	}
+}
+
+func TestConcurrentDictUpdate(t *testing.T) {
+	var wg sync.WaitGroup
+
+	dict := MakeConcurrentDict(16)
+	max := 100
+	wg.Add(max)
+	for i := 0; i < max; i++ {
+		go func() {
+			// concurrent increments must not be lost
+			dict.Update("counter", func(entry *Entry) {
+				if !entry.Exists {
+					entry.Value, entry.Exists = 0, true
+				}
+				entry.Value = entry.Value.(int) + 1
+			})
+			wg.Done()
+		}()
+	}
+	wg.Wait()
+
+	if val, _ := dict.Get("counter"); val.(int) != max {
+		t.Errorf("test update lost increments: %v", val)
+	}
+
+	dict.Update("counter", func(entry *Entry) {
+		entry.Exists = false
+	})
+	if _, ok := dict.Get("counter"); ok || dict.Len() != 0 {
+		t.Errorf("test update fail to remove key")
+	}
+}
+
+func TestConcurrentDictUpdateMulti(t *testing.T) {
+	var wg sync.WaitGroup
+
+	dict := MakeConcurrentDict(16)
+	keys := []string{"a", "b", "c", "d"}
+	for _, key := range keys {
+		dict.Put(key, 100)
+	}
+	max := 100
+	wg.Add(max)
+	for i := 0; i < max; i++ {
+		go func(i int) {
+			// move one unit between two keys, in both orders
+			from, to := keys[i%len(keys)], keys[(i+1)%len(keys)]
+			if i%2 == 0 {
+				from, to = to, from
+			}
+			dict.UpdateMulti([]string{from, to}, func(entries []*Entry) {
+				entries[0].Value = entries[0].Value.(int) - 1
+				entries[1].Value = entries[1].Value.(int) + 1
+			})
+			wg.Done()
+		}(i)
+	}
+	wg.Wait()
+
+	sum := 0
+	for _, key := range keys {
+		val, _ := dict.Get(key)
+		sum += val.(int)
+	}
+	if sum != 100*len(keys) {
+		t.Errorf("test update multi lost updates: %d", sum)
+	}
+
+	// rename a to e
+	dict.UpdateMulti([]string{"a", "e"}, func(entries []*Entry) {
+		*entries[1] = *entries[0]
+		entries[0].Exists = false
+	})
+	if _, ok := dict.Get("a"); ok || dict.Len() != 4 {
+		t.Errorf("test update multi fail to remove key, len %d", dict.Len())
+	}
+	if _, ok := dict.Get("e"); !ok {
+		t.Errorf("test update multi fail to add key")
+	}
+}
+
+func TestConcurrentDictRandomKey(t *testing.T) {
+	dict := MakeConcurrentDict(16)
+	if _, ok := dict.RandomKey(); ok {
+		t.Errorf("test random key of an empty dict")
+	}
+	for i := 0; i < 10; i++ {
+		dict.Put("key"+strconv.Itoa(i), i)
+	}
+	seen := make(map[string]bool)
+	for i := 0; i < 200; i++ {
+		key, ok := dict.RandomKey()
+		if _, exists := dict.Get(key); !ok || !exists {
+			t.Fatalf("test random key fail: %s", key)
+		}
+		seen[key] = true
+	}
+	if len(seen) < 2 {
+		t.Errorf("test random key is not random: %v", seen)
+	}
+}
+
+func TestConcurrentDictScan(t *testing.T) {
+	dict := MakeConcurrentDict(16)
+	for i := 0; i < 1000; i++ {
+		dict.Put(strconv.Itoa(i), i)
+	}
+	dict.PutWithExpire("expired", 0, NowMillis()-1)
+
+	// a call stops in the middle of a segment once count keys are visited
+	seen := make(map[string]int)
+	cursor, calls := uint64(0), 0
+	for {
+		visited := 0
+		cursor = dict.Scan(cursor, 5, func(key string, value interface{}) bool {
+			seen[key]++
+			visited++
+			return true
+		})
+		calls++
+		if visited > 10 {
+			t.Errorf("test scan visited %d keys for count 5", visited)
+		}
+		if cursor == 0 {
+			break
+		}
+	}
+	if len(seen) != 1000 || seen["expired"] != 0 || calls < 100 {
+		t.Errorf("test scan fail: %d keys in %d calls", len(seen), calls)
+	}
+	for key, n := range seen {
+		if n != 1 {
+			t.Errorf("test scan visited %s %d times", key, n)
+		}
+	}
+}
+
+func TestConcurrentDictScanWhileWriting(t *testing.T) {
+	dict := MakeConcurrentDict(16)
+	for i := 0; i < 1000; i++ {
+		dict.Put(strconv.Itoa(i), i)
+	}
+
+	// keys are added and removed during the scan, the others are visited once
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; ; i++ {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			key := "tmp" + strconv.Itoa(i%500)
+			if i%1000 < 500 {
+				dict.Put(key, i)
+			} else {
+				dict.Remove(key)
+			}
+		}
+	}()
+
+	seen := make(map[string]int)
+	cursor := uint64(0)
+	for {
+		cursor = dict.Scan(cursor, 7, func(key string, value interface{}) bool {
+			seen[key]++
+			return true
+		})
+		if cursor == 0 {
+			break
+		}
+	}
+	close(stop)
+	wg.Wait()
+
+	for i := 0; i < 1000; i++ {
+		if n := seen[strconv.Itoa(i)]; n != 1 {
+			t.Errorf("test scan visited %d %d times", i, n)
+		}
+	}
+}
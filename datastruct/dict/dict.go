@@ -9,12 +9,33 @@ package dict
 type Consumer func(key string, value interface{}) bool
 
 // dictionary interface
-type dict interface {
+type Dict interface {
 	Get(key string) (value interface{}, exists bool)
 	Put(key string, value interface{}) int
+	PutIfAbsent(key string, value interface{}) int
+	PutIfExists(key string, value interface{}) int
 	Remove(key string) int
+	PutWithExpire(key string, value interface{}, at int64) int
+	SetExpire(key string, at int64, check ExpireCheck) int
+	GetExpire(key string) (at int64, hasTTL bool, exists bool)
+	Persist(key string) int
+	Update(key string, updater Updater)
+	UpdateMulti(keys []string, updater MultiUpdater)
+	Locks(writeKeys []string, readKeys []string)
+	Unlocks(writeKeys []string, readKeys []string)
+	GetWithLock(key string) (interface{}, bool)
+	UpdateWithLock(key string, updater Updater)
 	Len() int
 	ForEach(consumer Consumer)
+	Scan(cursor uint64, count int, consumer Consumer) uint64
+	RandomKey() (string, bool)
 	Keys() []string
 	Clear()
+	SegmentCount() int
+	ExpireSegment(index int, samples int) (int, int)
 }
+
+var (
+	_ Dict = (*ConcurrentDict)(nil)
+	_ Dict = (*LockedDict)(nil)
+)
@@ -0,0 +1,211 @@
+/*
+ * @Description: key expiration of the concurrent dictionary
+ * @Autor: HTmonster
+ * @Date: 2026-10-18 18:21:40
+ */
+package dict
+
+import (
+	"time"
+)
+
+/**
+ * @description: current unix time in milliseconds
+ */
+func NowMillis() int64 {
+	return time.Now().UnixNano() / int64(time.Millisecond)
+}
+
+/**
+ * @description: check whether the key is expired, the segment must be locked
+ * @param {string} key
+ * @param {int64} now unix time in milliseconds
+ * @return {*}
+ */
+func (segment *Segment) isExpired(key string, now int64) bool {
+	if len(segment.expires) == 0 {
+		return false
+	}
+	at, ok := segment.expires[key]
+	return ok && at <= now
+}
+
+/**
+ * @description: remove the key if it is expired, the segment must be write locked
+ * @param {*Segment} segment
+ * @param {string} key
+ * @return {*} true if removed
+ */
+func (dict *ConcurrentDict) expireIfNeeded(segment *Segment, key string) bool {
+	if !segment.isExpired(key, NowMillis()) {
+		return false
+	}
+	delete(segment.m, key)
+	delete(segment.expires, key)
+	dict.decreaseCount()
+	return true
+}
+
+/**
+ * @description: get the segment of a key
+ */
+func (dict *ConcurrentDict) segmentOf(key string) *Segment {
+	if dict == nil {
+		panic("Error, dictionary is nil")
+	}
+	return dict.getSegment(dict.spread(fnv32(key)))
+}
+
+/**
+ * @description: Put the key-value and replace its TTL
+ * @param {string} key
+ * @param {interface{}} value
+ * @param {int64} at unix time in milliseconds, 0 means no TTL
+ * @return {*} 1 if inserted new one, else 0
+ */
+func (dict *ConcurrentDict) PutWithExpire(key string, value interface{}, at int64) int {
+	segment := dict.segmentOf(key)
+	segment.mutext.Lock()
+	defer segment.mutext.Unlock()
+
+	dict.expireIfNeeded(segment, key)
+	_, exists := segment.m[key]
+	segment.m[key] = value
+	if at > 0 {
+		segment.expires[key] = at
+	} else {
+		delete(segment.expires, key)
+	}
+	if exists {
+		return 0
+	}
+	dict.addCount()
+	return 1
+}
+
+// decide whether to replace a TTL, see SetExpire
+type ExpireCheck func(old int64, hasTTL bool) bool
+
+/**
+ * @description: set the TTL of an existing key
+ * @param {string} key
+ * @param {int64} at unix time in milliseconds
+ * @param {ExpireCheck} check optional condition on the current TTL
+ * @return {*} 1 if the TTL is set, 0 if the key does not exist or check fails
+ */
+func (dict *ConcurrentDict) SetExpire(key string, at int64, check ExpireCheck) int {
+	segment := dict.segmentOf(key)
+	segment.mutext.Lock()
+	defer segment.mutext.Unlock()
+
+	if dict.expireIfNeeded(segment, key) {
+		return 0
+	}
+	if _, ok := segment.m[key]; !ok {
+		return 0
+	}
+	if check != nil {
+		old, hasTTL := segment.expires[key]
+		if !check(old, hasTTL) {
+			return 0
+		}
+	}
+	// a time in the past deletes the key at once
+	if at <= NowMillis() {
+		delete(segment.m, key)
+		delete(segment.expires, key)
+		dict.decreaseCount()
+		return 1
+	}
+	segment.expires[key] = at
+	return 1
+}
+
+/**
+ * @description: get the TTL of a key
+ * @param {string} key
+ * @return {*} unix time in milliseconds, has TTL or not, exists or not
+ */
+func (dict *ConcurrentDict) GetExpire(key string) (at int64, hasTTL bool, exists bool) {
+	segment := dict.segmentOf(key)
+	segment.mutext.RLock()
+	defer segment.mutext.RUnlock()
+
+	if _, ok := segment.m[key]; !ok || segment.isExpired(key, NowMillis()) {
+		return 0, false, false
+	}
+	at, hasTTL = segment.expires[key]
+	return at, hasTTL, true
+}
+
+/**
+ * @description: remove the TTL of a key
+ * @param {string} key
+ * @return {*} 1 if the TTL is removed, 0 if the key does not exist or has no TTL
+ */
+func (dict *ConcurrentDict) Persist(key string) int {
+	segment := dict.segmentOf(key)
+	segment.mutext.Lock()
+	defer segment.mutext.Unlock()
+
+	if dict.expireIfNeeded(segment, key) {
+		return 0
+	}
+	if _, ok := segment.expires[key]; !ok {
+		return 0
+	}
+	delete(segment.expires, key)
+	return 1
+}
+
+/**
+ * @description: number of segments
+ */
+func (dict *ConcurrentDict) SegmentCount() int {
+	return len(dict.table)
+}
+
+/**
+ * @description: remove expired keys of a segment by random sampling,
+ *	like a loop of activeExpireCycle in redis
+ * @param {int} index segment index
+ * @param {int} samples max number of keys with TTL to check
+ * @return {*} checked keys, removed keys
+ */
+func (dict *ConcurrentDict) ExpireSegment(index int, samples int) (int, int) {
+	segment := dict.table[index]
+
+	// most segments have no volatile key, check it without write lock
+	segment.mutext.RLock()
+	empty := len(segment.expires) == 0
+	segment.mutext.RUnlock()
+	if empty {
+		return 0, 0
+	}
+
+	segment.mutext.Lock()
+	defer segment.mutext.Unlock()
+	return dict.expireSegment(segment, samples)
+}
+
+/**
+ * @description: ExpireSegment of a segment write locked by the caller
+ */
+func (dict *ConcurrentDict) expireSegment(segment *Segment, samples int) (int, int) {
+	now := NowMillis()
+	checked, expired := 0, 0
+	// map iteration order is random
+	for key, at := range segment.expires {
+		if checked >= samples {
+			break
+		}
+		checked++
+		if at <= now {
+			delete(segment.m, key)
+			delete(segment.expires, key)
+			dict.decreaseCount()
+			expired++
+		}
+	}
+	return checked, expired
+}
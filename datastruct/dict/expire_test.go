@@ -0,0 +1,92 @@
+/*
+ * @Description:
+ * @Autor: HTmonster
+ * @Date: 2026-10-18 19:12:33
+ */
+package dict
+
+import (
+	"strconv"
+	"testing"
+)
+
+func TestConcurrentDictLazyExpire(t *testing.T) {
+	dict := MakeConcurrentDict(16)
+	now := NowMillis()
+
+	dict.PutWithExpire("past", 1, now-1)
+	dict.PutWithExpire("future", 2, now+100000)
+	dict.Put("forever", 3)
+
+	if _, ok := dict.Get("past"); ok {
+		t.Errorf("expired key should not be found")
+	}
+	if dict.Len() != 2 {
+		t.Errorf("expired key should be removed on access, len %d", dict.Len())
+	}
+	if at, hasTTL, ok := dict.GetExpire("future"); !ok || !hasTTL || at != now+100000 {
+		t.Errorf("test fail to get TTL: %d %v %v", at, hasTTL, ok)
+	}
+	if _, hasTTL, ok := dict.GetExpire("forever"); !ok || hasTTL {
+		t.Errorf("key without TTL: %v %v", hasTTL, ok)
+	}
+
+	// overwriting keeps the TTL, PutWithExpire replaces it
+	dict.Put("future", 4)
+	if _, hasTTL, _ := dict.GetExpire("future"); !hasTTL {
+		t.Errorf("Put should keep the TTL")
+	}
+	dict.PutWithExpire("future", 5, 0)
+	if _, hasTTL, _ := dict.GetExpire("future"); hasTTL {
+		t.Errorf("PutWithExpire should replace the TTL")
+	}
+}
+
+func TestConcurrentDictSetExpire(t *testing.T) {
+	dict := MakeConcurrentDict(16)
+	now := NowMillis()
+	dict.Put("key", 1)
+
+	nx := func(old int64, hasTTL bool) bool { return !hasTTL }
+	if ret := dict.SetExpire("none", now+1000, nil); ret != 0 {
+		t.Errorf("SetExpire on a missing key should return 0")
+	}
+	if ret := dict.SetExpire("key", now+1000, nx); ret != 1 {
+		t.Errorf("SetExpire NX on a key without TTL should return 1")
+	}
+	if ret := dict.SetExpire("key", now+2000, nx); ret != 0 {
+		t.Errorf("SetExpire NX on a key with TTL should return 0")
+	}
+	if ret := dict.Persist("key"); ret != 1 {
+		t.Errorf("Persist should remove the TTL")
+	}
+	if ret := dict.Persist("key"); ret != 0 {
+		t.Errorf("Persist on a key without TTL should return 0")
+	}
+	if ret := dict.SetExpire("key", now-1, nil); ret != 1 {
+		t.Errorf("SetExpire in the past should return 1")
+	}
+	if _, ok := dict.Get("key"); ok || dict.Len() != 0 {
+		t.Errorf("SetExpire in the past should delete the key")
+	}
+}
+
+func TestConcurrentDictExpireSegment(t *testing.T) {
+	dict := MakeConcurrentDict(16)
+	now := NowMillis()
+	for i := 0; i < 1000; i++ {
+		dict.PutWithExpire("key"+strconv.Itoa(i), i, now-1)
+	}
+	dict.Put("alive", 1)
+
+	for i := 0; i < dict.SegmentCount(); i++ {
+		for {
+			if _, expired := dict.ExpireSegment(i, 20); expired == 0 {
+				break
+			}
+		}
+	}
+	if dict.Len() != 1 {
+		t.Errorf("all expired keys should be removed, len %d", dict.Len())
+	}
+}
@@ -0,0 +1,158 @@
+/*
+ * @Description: locking several keys of the concurrent dictionary at once
+ * @Autor: HTmonster
+ * @Date: 2026-10-19 23:51:37
+ */
+package dict
+
+import (
+	"sort"
+)
+
+/**
+ * @description: distinct segment indexes of keys, sorted so that every
+ *	caller takes the locks in the same order and none can deadlock
+ * @param {[]string} keys
+ * @param {bool} reverse descending order, for unlocking
+ * @return {*}
+ */
+func (dict *ConcurrentDict) toLockIndices(keys []string, reverse bool) []uint32 {
+	seen := make(map[uint32]bool, len(keys))
+	indices := make([]uint32, 0, len(keys))
+	for _, key := range keys {
+		index := dict.spread(fnv32(key))
+		if !seen[index] {
+			seen[index] = true
+			indices = append(indices, index)
+		}
+	}
+	sort.Slice(indices, func(i, j int) bool {
+		if reverse {
+			return indices[i] > indices[j]
+		}
+		return indices[i] < indices[j]
+	})
+	return indices
+}
+
+/**
+ * @description: segment indexes to lock, a segment of both a write key and
+ *	a read key is write locked
+ * @return {*} indexes in lock order, whether each one is write locked
+ */
+func (dict *ConcurrentDict) lockPlan(writeKeys []string, readKeys []string, reverse bool) ([]uint32, map[uint32]bool) {
+	writeIndices := make(map[uint32]bool, len(writeKeys))
+	for _, index := range dict.toLockIndices(writeKeys, false) {
+		writeIndices[index] = true
+	}
+	keys := make([]string, 0, len(writeKeys)+len(readKeys))
+	keys = append(keys, writeKeys...)
+	keys = append(keys, readKeys...)
+	return dict.toLockIndices(keys, reverse), writeIndices
+}
+
+/**
+ * @description: lock the segments of several keys, write keys are locked
+ *	exclusively and read keys shared. Keys must be accessed with the
+ *	*WithLock methods until Unlocks is called with the same keys.
+ * @param {[]string} writeKeys
+ * @param {[]string} readKeys
+ * @return {*}
+ */
+func (dict *ConcurrentDict) Locks(writeKeys []string, readKeys []string) {
+	if dict == nil {
+		panic("Error, dictionary is nil")
+	}
+	indices, writeIndices := dict.lockPlan(writeKeys, readKeys, false)
+	for _, index := range indices {
+		segment := dict.getSegment(index)
+		if writeIndices[index] {
+			segment.mutext.Lock()
+		} else {
+			segment.mutext.RLock()
+		}
+	}
+}
+
+/**
+ * @description: unlock the segments locked by Locks with the same keys
+ * @param {[]string} writeKeys
+ * @param {[]string} readKeys
+ * @return {*}
+ */
+func (dict *ConcurrentDict) Unlocks(writeKeys []string, readKeys []string) {
+	if dict == nil {
+		panic("Error, dictionary is nil")
+	}
+	indices, writeIndices := dict.lockPlan(writeKeys, readKeys, true)
+	for _, index := range indices {
+		segment := dict.getSegment(index)
+		if writeIndices[index] {
+			segment.mutext.Unlock()
+		} else {
+			segment.mutext.RUnlock()
+		}
+	}
+}
+
+/**
+ * @description: get the value of a key locked by Locks, for reading or writing
+ * @param {string} key
+ * @return {*} value, exists or not
+ */
+func (dict *ConcurrentDict) GetWithLock(key string) (interface{}, bool) {
+	segment := dict.segmentOf(key)
+	value, exists := segment.m[key]
+	// expired keys are removed by writers only
+	if exists && segment.isExpired(key, NowMillis()) {
+		return nil, false
+	}
+	return value, exists
+}
+
+/**
+ * @description: read and modify a key write locked by Locks, like Update
+ * @param {string} key
+ * @param {Updater} updater
+ * @return {*}
+ */
+func (dict *ConcurrentDict) UpdateWithLock(key string, updater Updater) {
+	segment := dict.segmentOf(key)
+	dict.expireIfNeeded(segment, key)
+	value, exists := segment.m[key]
+	entry := Entry{
+		Value:    value,
+		Exists:   exists,
+		ExpireAt: segment.expires[key],
+	}
+	updater(&entry)
+	dict.writeBack(segment, key, &entry, exists)
+}
+
+/**
+ * @description: write lock every segment in index order, for operations on
+ *	unknown keys such as traversal. Keys are accessed through a LockedDict
+ *	until UnlockAll is called.
+ * @return {*}
+ */
+func (dict *ConcurrentDict) LockAll() {
+	if dict == nil {
+		panic("Error, dictionary is nil")
+	}
+	for _, segment := range dict.table {
+		segment.mutext.Lock()
+	}
+}
+
+/**
+ * @description: unlock the segments locked by LockAll
+ * @return {*}
+ */
+func (dict *ConcurrentDict) UnlockAll() {
+	if dict == nil {
+		panic("Error, dictionary is nil")
+	}
+	for i := len(dict.table) - 1; i >= 0; i-- {
+		dict.table[i].mutext.Unlock()
+	}
+}
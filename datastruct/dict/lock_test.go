@@ -0,0 +1,123 @@
+/*
+ * @Description:
+ * @Autor: HTmonster
+ * @Date: 2026-10-19 23:53:12
+ */
+package dict
+
+import (
+	"math/rand"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+func TestConcurrentDictLocks(t *testing.T) {
+	var wg sync.WaitGroup
+
+	dict := MakeConcurrentDict(16)
+	accounts := 20
+	keys := make([]string, accounts)
+	for i := range keys {
+		keys[i] = "account" + strconv.Itoa(i)
+		dict.Put(keys[i], 100)
+	}
+
+	// writers move money between overlapping groups of accounts,
+	// readers check the total under read locks
+	workers := 50
+	wg.Add(workers * 2)
+	for w := 0; w < workers; w++ {
+		go func(seed int64) {
+			defer wg.Done()
+			r := rand.New(rand.NewSource(seed))
+			for i := 0; i < 100; i++ {
+				picked := r.Perm(accounts)[:2+r.Intn(3)]
+				group := make([]string, len(picked))
+				for j, p := range picked {
+					group[j] = keys[p]
+				}
+				dict.Locks(group, nil)
+				for _, key := range group[1:] {
+					dict.UpdateWithLock(key, func(entry *Entry) {
+						entry.Value = entry.Value.(int) - 1
+					})
+					dict.UpdateWithLock(group[0], func(entry *Entry) {
+						entry.Value = entry.Value.(int) + 1
+					})
+				}
+				dict.Unlocks(group, nil)
+			}
+		}(int64(w))
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 20; i++ {
+				dict.Locks(nil, keys)
+				total := 0
+				for _, key := range keys {
+					value, _ := dict.GetWithLock(key)
+					total += value.(int)
+				}
+				dict.Unlocks(nil, keys)
+				if total != 100*accounts {
+					t.Errorf("test locks saw a partial transfer: %d", total)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestConcurrentDictLocksReadWrite(t *testing.T) {
+	var wg sync.WaitGroup
+
+	dict := MakeConcurrentDict(16)
+	dict.Put("src", 0)
+	max := 100
+	wg.Add(max)
+	for i := 0; i < max; i++ {
+		go func(i int) {
+			defer wg.Done()
+			// a key both read and written is write locked, without deadlock
+			dst := "dst" + strconv.Itoa(i%10)
+			write, read := []string{dst, "src"}, []string{"src", "other"}
+			dict.Locks(write, read)
+			defer dict.Unlocks(write, read)
+			src, _ := dict.GetWithLock("src")
+			dict.UpdateWithLock("src", func(entry *Entry) {
+				entry.Value = src.(int) + 1
+			})
+			dict.UpdateWithLock(dst, func(entry *Entry) {
+				if !entry.Exists {
+					entry.Value, entry.Exists = 0, true
+				}
+				entry.Value = entry.Value.(int) + 1
+			})
+		}(i)
+	}
+	wg.Wait()
+
+	if value, _ := dict.Get("src"); value.(int) != max {
+		t.Errorf("test locks lost increments: %v", value)
+	}
+	if dict.Len() != 11 {
+		t.Errorf("test locks len: %d", dict.Len())
+	}
+
+	// expired keys are invisible, and removed by writers
+	dict.PutWithExpire("expired", 1, NowMillis()-1)
+	dict.Locks([]string{"expired"}, nil)
+	if _, ok := dict.GetWithLock("expired"); ok {
+		t.Errorf("test get with lock an expired key")
+	}
+	dict.UpdateWithLock("expired", func(entry *Entry) {
+		if entry.Exists {
+			t.Errorf("test update with lock an expired key")
+		}
+	})
+	dict.Unlocks([]string{"expired"}, nil)
+	if dict.Len() != 11 {
+		t.Errorf("test expired key should be removed: %d", dict.Len())
+	}
+}
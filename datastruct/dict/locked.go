@@ -0,0 +1,205 @@
+/*
+ * @Description: a view of the concurrent dictionary whose locks are held by the caller
+ * @Autor: HTmonster
+ * @Date: 2026-10-19 23:55:52
+ */
+package dict
+
+// the methods of a LockedDict take no lock, so code written for a Dict can
+// run on keys locked by Locks, or on all keys after LockAll
+type LockedDict struct {
+	dict *ConcurrentDict
+}
+
+/**
+ * @description: make a view of a dictionary, it must only be used while the
+ *	caller holds the locks of the keys it accesses
+ * @param {*ConcurrentDict} dict
+ * @return {*}
+ */
+func MakeLockedDict(dict *ConcurrentDict) *LockedDict {
+	if dict == nil {
+		panic("Error, dictionary is nil")
+	}
+	return &LockedDict{dict: dict}
+}
+
+func (locked *LockedDict) Get(key string) (interface{}, bool) {
+	return locked.dict.GetWithLock(key)
+}
+
+func (locked *LockedDict) Put(key string, value interface{}) int {
+	result := 0
+	locked.dict.UpdateWithLock(key, func(entry *Entry) {
+		if !entry.Exists {
+			result = 1
+		}
+		entry.Value, entry.Exists = value, true
+	})
+	return result
+}
+
+func (locked *LockedDict) PutIfAbsent(key string, value interface{}) int {
+	result := 0
+	locked.dict.UpdateWithLock(key, func(entry *Entry) {
+		if !entry.Exists {
+			entry.Value, entry.Exists = value, true
+			result = 1
+		}
+	})
+	return result
+}
+
+func (locked *LockedDict) PutIfExists(key string, value interface{}) int {
+	result := 0
+	locked.dict.UpdateWithLock(key, func(entry *Entry) {
+		if entry.Exists {
+			entry.Value = value
+			result = 1
+		}
+	})
+	return result
+}
+
+func (locked *LockedDict) Remove(key string) int {
+	result := 0
+	locked.dict.UpdateWithLock(key, func(entry *Entry) {
+		if entry.Exists {
+			entry.Exists = false
+			result = 1
+		}
+	})
+	return result
+}
+
+func (locked *LockedDict) PutWithExpire(key string, value interface{}, at int64) int {
+	result := 0
+	locked.dict.UpdateWithLock(key, func(entry *Entry) {
+		if !entry.Exists {
+			result = 1
+		}
+		entry.Value, entry.Exists, entry.ExpireAt = value, true, at
+	})
+	return result
+}
+
+func (locked *LockedDict) SetExpire(key string, at int64, check ExpireCheck) int {
+	result := 0
+	locked.dict.UpdateWithLock(key, func(entry *Entry) {
+		if !entry.Exists {
+			return
+		}
+		if check != nil && !check(entry.ExpireAt, entry.ExpireAt > 0) {
+			return
+		}
+		// a time in the past deletes the key when written back
+		entry.ExpireAt = at
+		result = 1
+	})
+	return result
+}
+
+func (locked *LockedDict) GetExpire(key string) (int64, bool, bool) {
+	segment := locked.dict.segmentOf(key)
+	if _, ok := segment.m[key]; !ok || segment.isExpired(key, NowMillis()) {
+		return 0, false, false
+	}
+	at, hasTTL := segment.expires[key]
+	return at, hasTTL, true
+}
+
+func (locked *LockedDict) Persist(key string) int {
+	result := 0
+	locked.dict.UpdateWithLock(key, func(entry *Entry) {
+		if entry.Exists && entry.ExpireAt > 0 {
+			entry.ExpireAt = 0
+			result = 1
+		}
+	})
+	return result
+}
+
+func (locked *LockedDict) Update(key string, updater Updater) {
+	locked.dict.UpdateWithLock(key, updater)
+}
+
+func (locked *LockedDict) UpdateMulti(keys []string, updater MultiUpdater) {
+	locked.dict.updateMultiWithLock(keys, updater)
+}
+
+/**
+ * @description: the keys are locked already, Locks and Unlocks do nothing
+ */
+func (locked *LockedDict) Locks(writeKeys []string, readKeys []string) {}
+
+func (locked *LockedDict) Unlocks(writeKeys []string, readKeys []string) {}
+
+func (locked *LockedDict) GetWithLock(key string) (interface{}, bool) {
+	return locked.dict.GetWithLock(key)
+}
+
+func (locked *LockedDict) UpdateWithLock(key string, updater Updater) {
+	locked.dict.UpdateWithLock(key, updater)
+}
+
+func (locked *LockedDict) Len() int {
+	return locked.dict.Len()
+}
+
+/**
+ * @description: traversal the dictionary, all segments must be locked
+ */
+func (locked *LockedDict) ForEach(consumer Consumer) {
+	now := NowMillis()
+	for _, segment := range locked.dict.table {
+		if !segment.forEach(now, consumer) {
+			return
+		}
+	}
+}
+
+/**
+ * @description: see ConcurrentDict.Scan, all segments must be locked
+ */
+func (locked *LockedDict) Scan(cursor uint64, count int, consumer Consumer) uint64 {
+	return locked.dict.scan(cursor, count, consumer, false)
+}
+
+/**
+ * @description: return a random key, all segments must be locked
+ */
+func (locked *LockedDict) RandomKey() (string, bool) {
+	return locked.dict.randomKey(false)
+}
+
+/**
+ * @description: return all keys, all segments must be locked
+ */
+func (locked *LockedDict) Keys() []string {
+	keys := make([]string, 0, locked.Len())
+	locked.ForEach(func(key string, value interface{}) bool {
+		keys = append(keys, key)
+		return true
+	})
+	return keys
+}
+
+/**
+ * @description: remove all keys, all segments must be write locked
+ */
+func (locked *LockedDict) Clear() {
+	for _, segment := range locked.dict.table {
+		locked.dict.clearSegment(segment)
+	}
+}
+
+func (locked *LockedDict) SegmentCount() int {
+	return locked.dict.SegmentCount()
+}
+
+/**
+ * @description: see ConcurrentDict.ExpireSegment, the segment must be write locked
+ */
+func (locked *LockedDict) ExpireSegment(index int, samples int) (int, int) {
+	return locked.dict.expireSegment(locked.dict.table[index], samples)
+}
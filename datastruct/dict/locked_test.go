@@ -0,0 +1,109 @@
+/*
+ * @Description:
+ * @Autor: HTmonster
+ * @Date: 2026-10-19 23:56:48
+ */
+package dict
+
+import (
+	"sort"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+func TestLockedDict(t *testing.T) {
+	dict := MakeConcurrentDict(16)
+	locked := MakeLockedDict(dict)
+	keys := []string{"a", "b", "c"}
+
+	dict.Locks(keys, nil)
+	if locked.Put("a", 1) != 1 || locked.Put("a", 2) != 0 {
+		t.Errorf("test locked put")
+	}
+	if locked.PutIfAbsent("a", 3) != 0 || locked.PutIfExists("b", 3) != 0 || locked.PutIfAbsent("b", 3) != 1 {
+		t.Errorf("test locked put if absent or exists")
+	}
+	if value, ok := locked.Get("a"); !ok || value.(int) != 2 {
+		t.Errorf("test locked get: %v", value)
+	}
+	at := NowMillis() + 100000
+	if locked.PutWithExpire("c", 4, at) != 1 {
+		t.Errorf("test locked put with expire")
+	}
+	if got, hasTTL, exists := locked.GetExpire("c"); got != at || !hasTTL || !exists {
+		t.Errorf("test locked get expire: %d %v %v", got, hasTTL, exists)
+	}
+	if locked.SetExpire("a", at, func(old int64, hasTTL bool) bool { return hasTTL }) != 0 {
+		t.Errorf("test locked set expire with check")
+	}
+	if locked.Persist("c") != 1 || locked.Persist("c") != 0 {
+		t.Errorf("test locked persist")
+	}
+	if locked.SetExpire("b", NowMillis()-1, nil) != 1 {
+		t.Errorf("test locked set expire in the past")
+	}
+	if _, ok := locked.Get("b"); ok || locked.Len() != 2 {
+		t.Errorf("test locked expire in the past should remove, len %d", locked.Len())
+	}
+	if locked.Remove("a") != 1 || locked.Remove("a") != 0 {
+		t.Errorf("test locked remove")
+	}
+	dict.Unlocks(keys, nil)
+
+	// the changes are seen by the dictionary
+	if value, ok := dict.Get("c"); !ok || value.(int) != 4 || dict.Len() != 1 {
+		t.Errorf("test locked changes: %v %d", value, dict.Len())
+	}
+}
+
+func TestLockedDictLockAll(t *testing.T) {
+	var wg sync.WaitGroup
+
+	dict := MakeConcurrentDict(16)
+	for i := 0; i < 100; i++ {
+		dict.Put("key"+strconv.Itoa(i), i)
+	}
+	locked := MakeLockedDict(dict)
+
+	// writers wait until the whole dictionary is unlocked
+	dict.LockAll()
+	wg.Add(10)
+	for i := 0; i < 10; i++ {
+		go func(i int) {
+			defer wg.Done()
+			dict.Put("new"+strconv.Itoa(i), i)
+		}(i)
+	}
+	keys := locked.Keys()
+	sort.Strings(keys)
+	if len(keys) != 100 || keys[0] != "key0" {
+		t.Errorf("test locked keys: %d", len(keys))
+	}
+	scanned := 0
+	for cursor := locked.Scan(0, 10, func(key string, value interface{}) bool {
+		scanned++
+		return true
+	}); cursor != 0; {
+		cursor = locked.Scan(cursor, 10, func(key string, value interface{}) bool {
+			scanned++
+			return true
+		})
+	}
+	if scanned != 100 {
+		t.Errorf("test locked scan: %d", scanned)
+	}
+	if _, ok := locked.RandomKey(); !ok {
+		t.Errorf("test locked random key")
+	}
+	locked.Clear()
+	if locked.Len() != 0 {
+		t.Errorf("test locked clear: %d", locked.Len())
+	}
+	dict.UnlockAll()
+	wg.Wait()
+
+	if dict.Len() != 10 {
+		t.Errorf("test writers after unlock all: %d", dict.Len())
+	}
+}
@@ -0,0 +1,200 @@
+/*
+ * @Description: simple dictionary with a stable scan cursor
+ * @Autor: HTmonster
+ * @Date: 2026-10-18 23:40:12
+ */
+package dict
+
+import (
+	"math/bits"
+	"math/rand"
+)
+
+const (
+	minBuckets   = 4
+	bucketFactor = 8 // average entries per bucket before growing
+)
+
+// SimpleDict is not safe for concurrent use, it is used inside values
+// which are already protected by the keyspace segment lock.
+// Keys are spread over a power of two number of buckets, so a scan can
+// resume from a bucket cursor even if the dictionary is resized.
+type SimpleDict struct {
+	buckets []map[string]interface{}
+	count   int
+}
+
+/**
+ * @description: make an empty simple dictionary
+ * @return {*}
+ */
+func MakeSimpleDict() *SimpleDict {
+	return &SimpleDict{buckets: makeBuckets(minBuckets)}
+}
+
+func makeBuckets(n int) []map[string]interface{} {
+	buckets := make([]map[string]interface{}, n)
+	for i := range buckets {
+		buckets[i] = make(map[string]interface{})
+	}
+	return buckets
+}
+
+/**
+ * @description: bucket of a key
+ */
+func (dict *SimpleDict) bucketOf(key string) map[string]interface{} {
+	return dict.buckets[fnv32(key)&uint32(len(dict.buckets)-1)]
+}
+
+/**
+ * @description: rehash all keys into n buckets
+ */
+func (dict *SimpleDict) resize(n int) {
+	old := dict.buckets
+	dict.buckets = makeBuckets(n)
+	for _, bucket := range old {
+		for key, value := range bucket {
+			dict.bucketOf(key)[key] = value
+		}
+	}
+}
+
+/**
+ * @description: Given a key, get the value if exists.
+ * @param {string} key
+ * @return {*} value, exists or not
+ */
+func (dict *SimpleDict) Get(key string) (value interface{}, exists bool) {
+	value, exists = dict.bucketOf(key)[key]
+	return
+}
+
+/**
+ * @description: Put the key-value into the dictionary
+ * @param {string} key
+ * @param {interface{}} value
+ * @return {*} 1 if inserted new one, else 0
+ */
+func (dict *SimpleDict) Put(key string, value interface{}) int {
+	bucket := dict.bucketOf(key)
+	if _, ok := bucket[key]; ok {
+		bucket[key] = value
+		return 0
+	}
+	bucket[key] = value
+	dict.count++
+	if dict.count > len(dict.buckets)*bucketFactor {
+		dict.resize(len(dict.buckets) * 2)
+	}
+	return 1
+}
+
+/**
+ * @description: Remove a key from the dictionary
+ * @param {string} key
+ * @return {*} 1 if exists, 0 otherwise
+ */
+func (dict *SimpleDict) Remove(key string) int {
+	bucket := dict.bucketOf(key)
+	if _, ok := bucket[key]; !ok {
+		return 0
+	}
+	delete(bucket, key)
+	dict.count--
+	if len(dict.buckets) > minBuckets && dict.count*bucketFactor < len(dict.buckets) {
+		dict.resize(len(dict.buckets) / 2)
+	}
+	return 1
+}
+
+/**
+ * @description: number of keys
+ */
+func (dict *SimpleDict) Len() int {
+	return dict.count
+}
+
+/**
+ * @description: traversal the dictonary
+ * @param {*} consumer (operator)
+ * @return {*}
+ */
+func (dict *SimpleDict) ForEach(consumer Consumer) {
+	for _, bucket := range dict.buckets {
+		for key, value := range bucket {
+			if !consumer(key, value) {
+				return
+			}
+		}
+	}
+}
+
+/**
+ * @description: return all keys
+ */
+func (dict *SimpleDict) Keys() []string {
+	keys := make([]string, 0, dict.count)
+	dict.ForEach(func(key string, value interface{}) bool {
+		keys = append(keys, key)
+		return true
+	})
+	return keys
+}
+
+/**
+ * @description: return a random key-value
+ * @return {*} key, value, false if the dictionary is empty
+ */
+func (dict *SimpleDict) RandomEntry() (string, interface{}, bool) {
+	if dict.count == 0 {
+		return "", nil, false
+	}
+	for {
+		bucket := dict.buckets[rand.Intn(len(dict.buckets))]
+		if len(bucket) == 0 {
+			continue
+		}
+		n := rand.Intn(len(bucket))
+		for key, value := range bucket {
+			if n == 0 {
+				return key, value, true
+			}
+			n--
+		}
+	}
+}
+
+/**
+ * @description: visit the buckets from cursor until about count keys are visited,
+ * keys present during the whole scan are visited at least once
+ * @param {uint64} cursor 0 to start a new scan
+ * @param {int} count
+ * @param {Consumer} consumer the return value is ignored
+ * @return {*} next cursor, 0 when the scan is completed
+ */
+func (dict *SimpleDict) Scan(cursor uint64, count int, consumer Consumer) uint64 {
+	mask := uint64(len(dict.buckets) - 1)
+	visited := 0
+	for {
+		for key, value := range dict.buckets[cursor&mask] {
+			consumer(key, value)
+			visited++
+		}
+		// increase the reversed cursor, so buckets split or merged by a
+		// resize are never skipped
+		cursor |= ^mask
+		cursor = bits.Reverse64(bits.Reverse64(cursor) + 1)
+		if cursor == 0 || visited >= count {
+			return cursor
+		}
+	}
+}
+
+/**
+ * @description: remove all keys
+ */
+func (dict *SimpleDict) Clear() {
+	dict.buckets = makeBuckets(minBuckets)
+	dict.count = 0
+}
@@ -0,0 +1,80 @@
+/*
+ * @Description:
+ * @Autor: HTmonster
+ * @Date: 2026-10-18 23:58:30
+ */
+package dict
+
+import (
+	"strconv"
+	"testing"
+)
+
+func TestSimpleDict(t *testing.T) {
+	dict := MakeSimpleDict()
+	max := 1000
+	for i := 0; i < max; i++ {
+		if ret := dict.Put("key"+strconv.Itoa(i), i); ret != 1 {
+			t.Errorf("test to put key %d fail", i)
+		}
+	}
+	if ret := dict.Put("key0", -1); ret != 0 || dict.Len() != max {
+		t.Errorf("test to update key fail, len %d", dict.Len())
+	}
+	if val, ok := dict.Get("key0"); !ok || val.(int) != -1 {
+		t.Errorf("test to get key fail: %v", val)
+	}
+	if len(dict.Keys()) != max {
+		t.Errorf("test keys fail: %d", len(dict.Keys()))
+	}
+	if _, _, ok := dict.RandomEntry(); !ok {
+		t.Errorf("test random entry fail")
+	}
+
+	for i := 0; i < max; i++ {
+		if ret := dict.Remove("key" + strconv.Itoa(i)); ret != 1 {
+			t.Errorf("test to remove key %d fail", i)
+		}
+	}
+	if dict.Remove("key0") != 0 || dict.Len() != 0 || len(dict.buckets) != minBuckets {
+		t.Errorf("test remove fail, len %d buckets %d", dict.Len(), len(dict.buckets))
+	}
+	if _, _, ok := dict.RandomEntry(); ok {
+		t.Errorf("test random entry of an empty dict fail")
+	}
+}
+
+func TestSimpleDictScan(t *testing.T) {
+	dict := MakeSimpleDict()
+	for i := 0; i < 500; i++ {
+		dict.Put(strconv.Itoa(i), i)
+	}
+
+	// keys kept during the scan must be seen while the dict grows and shrinks
+	seen := make(map[string]bool)
+	cursor, round := uint64(0), 0
+	for {
+		cursor = dict.Scan(cursor, 10, func(key string, value interface{}) bool {
+			seen[key] = true
+			return true
+		})
+		if round%2 == 0 {
+			for i := 0; i < 100; i++ {
+				dict.Put("grow"+strconv.Itoa(round)+"-"+strconv.Itoa(i), i)
+			}
+		} else {
+			for i := 0; i < 100; i++ {
+				dict.Remove("grow" + strconv.Itoa(round-1) + "-" + strconv.Itoa(i))
+			}
+		}
+		round++
+		if cursor == 0 {
+			break
+		}
+	}
+	for i := 0; i < 500; i++ {
+		if !seen[strconv.Itoa(i)] {
+			t.Errorf("test scan missed key %d", i)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+/*
+ * @Description: atomic read-modify-write of the concurrent dictionary
+ * @Autor: HTmonster
+ * @Date: 2026-10-18 19:52:08
+ */
+package dict
+
+// a key seen by an Updater, changes are written back to the dictionary
+type Entry struct {
+	Value    interface{}
+	Exists   bool  // set false to remove the key
+	ExpireAt int64 // unix time in milliseconds, 0 means no TTL
+}
+
+// read and modify an entry under the segment lock,
+// it must not call methods of the same dictionary
+type Updater func(entry *Entry)
+
+// read and modify several entries, in the order of their keys,
+// under the segment locks. It must not call methods of the same dictionary
+type MultiUpdater func(entries []*Entry)
+
+/**
+ * @description: read and modify a key atomically, expired keys are seen as absent
+ * @param {string} key
+ * @param {Updater} updater
+ * @return {*}
+ */
+func (dict *ConcurrentDict) Update(key string, updater Updater) {
+	segment := dict.segmentOf(key)
+	segment.mutext.Lock()
+	defer segment.mutext.Unlock()
+	dict.UpdateWithLock(key, updater)
+}
+
+/**
+ * @description: write a modified entry back, the segment must be write locked
+ * @param {*Segment} segment
+ * @param {string} key
+ * @param {*Entry} entry
+ * @param {bool} existed whether the key existed before the update
+ * @return {*}
+ */
+func (dict *ConcurrentDict) writeBack(segment *Segment, key string, entry *Entry, existed bool) {
+	if !entry.Exists || (entry.ExpireAt > 0 && entry.ExpireAt <= NowMillis()) {
+		// removed, or expired at once
+		if existed {
+			delete(segment.m, key)
+			delete(segment.expires, key)
+			dict.decreaseCount()
+		}
+		return
+	}
+	segment.m[key] = entry.Value
+	if entry.ExpireAt > 0 {
+		segment.expires[key] = entry.ExpireAt
+	} else {
+		delete(segment.expires, key)
+	}
+	if !existed {
+		dict.addCount()
+	}
+}
+
+/**
+ * @description: read and modify several keys atomically, their segments are
+ *	locked by Locks so that concurrent calls cannot deadlock
+ * @param {[]string} keys distinct keys
+ * @param {MultiUpdater} updater
+ * @return {*}
+ */
+func (dict *ConcurrentDict) UpdateMulti(keys []string, updater MultiUpdater) {
+	dict.Locks(keys, nil)
+	defer dict.Unlocks(keys, nil)
+	dict.updateMultiWithLock(keys, updater)
+}
+
+/**
+ * @description: UpdateMulti of keys write locked by the caller
+ */
+func (dict *ConcurrentDict) updateMultiWithLock(keys []string, updater MultiUpdater) {
+	entries := make([]*Entry, len(keys))
+	existed := make([]bool, len(keys))
+	for i, key := range keys {
+		segment := dict.segmentOf(key)
+		dict.expireIfNeeded(segment, key)
+		value, exists := segment.m[key]
+		entries[i] = &Entry{
+			Value:    value,
+			Exists:   exists,
+			ExpireAt: segment.expires[key],
+		}
+		existed[i] = exists
+	}
+	updater(entries)
+	for i, key := range keys {
+		dict.writeBack(dict.segmentOf(key), key, entries[i], existed[i])
+	}
+}
@@ -0,0 +1,235 @@
+/*
+ * @Description: hash, listpack encoded when small, converted to a dictionary when big
+ * @Autor: HTmonster
+ * @Date: 2026-10-19 00:12:47
+ */
+package hash
+
+import (
+	"math/rand"
+
+	"github.com/HTmonster/redissgo/datastruct/dict"
+	"github.com/HTmonster/redissgo/datastruct/listpack"
+)
+
+// encodings
+const (
+	EncodingListpack  = "listpack"
+	EncodingHashtable = "hashtable"
+)
+
+// hash of fields and values, not safe for concurrent use.
+// Values returned by the hash must not be modified.
+type Hash struct {
+	lp   *listpack.Listpack // field and value entries in turn, nil after conversion
+	dict *dict.SimpleDict   // field -> []byte
+
+	maxListpackEntries int // convert when there are more fields
+	maxListpackValue   int // convert when a field or value is longer
+}
+
+/**
+ * @description: make an empty listpack encoded hash
+ * @param {int} maxListpackEntries
+ * @param {int} maxListpackValue
+ * @return {*}
+ */
+func MakeHash(maxListpackEntries, maxListpackValue int) *Hash {
+	return &Hash{
+		lp:                 listpack.New(),
+		maxListpackEntries: maxListpackEntries,
+		maxListpackValue:   maxListpackValue,
+	}
+}
+
+/**
+ * @description: number of fields
+ */
+func (h *Hash) Len() int {
+	if h.lp != nil {
+		return h.lp.Len() / 2
+	}
+	return h.dict.Len()
+}
+
+/**
+ * @description: name of the current encoding
+ */
+func (h *Hash) Encoding() string {
+	if h.lp != nil {
+		return EncodingListpack
+	}
+	return EncodingHashtable
+}
+
+/**
+ * @description: offset of a field entry in the listpack
+ * @return {*} -1 if not found
+ */
+func (h *Hash) find(field string) int {
+	for off := h.lp.First(); off >= 0; off = h.lp.Next(h.lp.Next(off)) {
+		if h.lp.Equal(off, []byte(field)) {
+			return off
+		}
+	}
+	return -1
+}
+
+/**
+ * @description: move all fields from the listpack into a dictionary
+ */
+func (h *Hash) convert() {
+	d := dict.MakeSimpleDict()
+	for off := h.lp.First(); off >= 0; {
+		valueOff := h.lp.Next(off)
+		d.Put(string(h.lp.Get(off)), h.lp.Get(valueOff))
+		off = h.lp.Next(valueOff)
+	}
+	h.lp, h.dict = nil, d
+}
+
+/**
+ * @description: get the value of a field
+ * @param {string} field
+ * @return {*} value, exists or not
+ */
+func (h *Hash) Get(field string) ([]byte, bool) {
+	if h.lp != nil {
+		off := h.find(field)
+		if off < 0 {
+			return nil, false
+		}
+		return h.lp.Get(h.lp.Next(off)), true
+	}
+	value, ok := h.dict.Get(field)
+	if !ok {
+		return nil, false
+	}
+	return value.([]byte), true
+}
+
+/**
+ * @description: set the value of a field, the value is copied
+ * @param {string} field
+ * @param {[]byte} value
+ * @return {*} 1 if the field is new, else 0
+ */
+func (h *Hash) Set(field string, value []byte) int {
+	if h.lp != nil && (len(field) > h.maxListpackValue || len(value) > h.maxListpackValue) {
+		h.convert()
+	}
+	if h.lp == nil {
+		return h.dict.Put(field, append([]byte(nil), value...))
+	}
+
+	if off := h.find(field); off >= 0 {
+		h.lp.Replace(h.lp.Next(off), value)
+		return 0
+	}
+	h.lp.Append([]byte(field))
+	h.lp.Append(value)
+	if h.Len() > h.maxListpackEntries {
+		h.convert()
+	}
+	return 1
+}
+
+/**
+ * @description: remove a field
+ * @param {string} field
+ * @return {*} 1 if removed, else 0
+ */
+func (h *Hash) Remove(field string) int {
+	if h.lp == nil {
+		return h.dict.Remove(field)
+	}
+	off := h.find(field)
+	if off < 0 {
+		return 0
+	}
+	h.lp.Delete(h.lp.Delete(off))
+	return 1
+}
+
+/**
+ * @description: traversal the hash
+ * @param {func(field string, value []byte) bool} consumer returns false to stop
+ * @return {*}
+ */
+func (h *Hash) ForEach(consumer func(field string, value []byte) bool) {
+	if h.lp == nil {
+		h.dict.ForEach(func(key string, value interface{}) bool {
+			return consumer(key, value.([]byte))
+		})
+		return
+	}
+	for off := h.lp.First(); off >= 0; {
+		valueOff := h.lp.Next(off)
+		if !consumer(string(h.lp.Get(off)), h.lp.Get(valueOff)) {
+			return
+		}
+		off = h.lp.Next(valueOff)
+	}
+}
+
+/**
+ * @description: make a copy of the hash with the same encoding,
+ * values are shared as they are never modified
+ */
+func (h *Hash) Clone() *Hash {
+	clone := &Hash{
+		maxListpackEntries: h.maxListpackEntries,
+		maxListpackValue:   h.maxListpackValue,
+	}
+	if h.lp != nil {
+		clone.lp = h.lp.Clone()
+		return clone
+	}
+	clone.dict = dict.MakeSimpleDict()
+	h.dict.ForEach(func(key string, value interface{}) bool {
+		clone.dict.Put(key, value)
+		return true
+	})
+	return clone
+}
+
+/**
+ * @description: return a random field and its value
+ * @return {*} field, value, false if the hash is empty
+ */
+func (h *Hash) RandomEntry() (string, []byte, bool) {
+	if h.lp == nil {
+		field, value, ok := h.dict.RandomEntry()
+		if !ok {
+			return "", nil, false
+		}
+		return field, value.([]byte), true
+	}
+	if h.lp.Len() == 0 {
+		return "", nil, false
+	}
+	off := h.lp.Seek(2 * rand.Intn(h.Len()))
+	return string(h.lp.Get(off)), h.lp.Get(h.lp.Next(off)), true
+}
+
+/**
+ * @description: incrementally iterate the hash, a listpack encoded hash is
+ * returned in a single call
+ * @param {uint64} cursor 0 to start a new scan
+ * @param {int} count
+ * @param {func(field string, value []byte)} consumer
+ * @return {*} next cursor, 0 when the scan is completed
+ */
+func (h *Hash) Scan(cursor uint64, count int, consumer func(field string, value []byte)) uint64 {
+	if h.lp != nil {
+		h.ForEach(func(field string, value []byte) bool {
+			consumer(field, value)
+			return true
+		})
+		return 0
+	}
+	return h.dict.Scan(cursor, count, func(key string, value interface{}) bool {
+		consumer(key, value.([]byte))
+		return true
+	})
+}
@@ -0,0 +1,115 @@
+/*
+ * @Description:
+ * @Autor: HTmonster
+ * @Date: 2026-10-19 00:35:16
+ */
+package hash
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestHashListpack(t *testing.T) {
+	h := MakeHash(128, 64)
+	if h.Set("a", []byte("1")) != 1 || h.Set("b", []byte("2")) != 1 || h.Set("a", []byte("3")) != 0 {
+		t.Errorf("test set fail")
+	}
+	if value, ok := h.Get("a"); !ok || string(value) != "3" {
+		t.Errorf("test get fail: %q", value)
+	}
+	if _, ok := h.Get("c"); ok {
+		t.Errorf("test get a missing field fail")
+	}
+	if h.Len() != 2 || h.Encoding() != EncodingListpack {
+		t.Errorf("test len fail: %d %s", h.Len(), h.Encoding())
+	}
+	if h.Remove("a") != 1 || h.Remove("a") != 0 || h.Len() != 1 {
+		t.Errorf("test remove fail")
+	}
+	if value, ok := h.Get("b"); !ok || string(value) != "2" {
+		t.Errorf("test get after remove fail: %q", value)
+	}
+	if field, _, ok := h.RandomEntry(); !ok || field != "b" {
+		t.Errorf("test random entry fail: %q", field)
+	}
+}
+
+func TestHashConvert(t *testing.T) {
+	// too many entries
+	h := MakeHash(16, 64)
+	for i := 0; i < 16; i++ {
+		h.Set(strconv.Itoa(i), []byte(strconv.Itoa(i)))
+	}
+	if h.Encoding() != EncodingListpack {
+		t.Errorf("test convert too early")
+	}
+	h.Set("16", []byte("16"))
+	if h.Encoding() != EncodingHashtable || h.Len() != 17 {
+		t.Errorf("test convert by entries fail: %s %d", h.Encoding(), h.Len())
+	}
+	for i := 0; i <= 16; i++ {
+		if value, ok := h.Get(strconv.Itoa(i)); !ok || string(value) != strconv.Itoa(i) {
+			t.Errorf("test get after convert fail: %d %q", i, value)
+		}
+	}
+
+	// too long value
+	h = MakeHash(16, 64)
+	h.Set("a", []byte("1"))
+	h.Set("b", []byte(strings.Repeat("x", 65)))
+	if h.Encoding() != EncodingHashtable || h.Len() != 2 {
+		t.Errorf("test convert by value fail: %s %d", h.Encoding(), h.Len())
+	}
+}
+
+func TestHashScan(t *testing.T) {
+	for _, n := range []int{10, 1000} {
+		h := MakeHash(128, 64)
+		for i := 0; i < n; i++ {
+			h.Set(strconv.Itoa(i), []byte("v"))
+		}
+		seen := make(map[string]bool)
+		cursor := uint64(0)
+		for {
+			cursor = h.Scan(cursor, 10, func(field string, value []byte) {
+				seen[field] = true
+			})
+			if cursor == 0 {
+				break
+			}
+		}
+		if len(seen) != n {
+			t.Errorf("test scan %s fail: %d of %d", h.Encoding(), len(seen), n)
+		}
+
+		count := 0
+		h.ForEach(func(field string, value []byte) bool {
+			count++
+			return true
+		})
+		if count != n {
+			t.Errorf("test for each %s fail: %d of %d", h.Encoding(), count, n)
+		}
+	}
+}
+
+func TestHashClone(t *testing.T) {
+	h := MakeHash(16, 64)
+	h.Set("a", []byte("1"))
+	clone := h.Clone()
+	h.Set("a", []byte("2"))
+	if value, _ := clone.Get("a"); string(value) != "1" || clone.Encoding() != EncodingListpack {
+		t.Errorf("test listpack clone fail: %q", value)
+	}
+
+	for i := 0; i < 20; i++ {
+		h.Set(strconv.Itoa(i), []byte(strconv.Itoa(i)))
+	}
+	clone = h.Clone()
+	h.Remove("a")
+	if value, ok := clone.Get("a"); !ok || string(value) != "2" || clone.Len() != 21 || clone.Encoding() != EncodingHashtable {
+		t.Errorf("test hashtable clone fail: %q %d", value, clone.Len())
+	}
+}
@@ -0,0 +1,440 @@
+/*
+ * @Description: HyperLogLog stored in a string, with the same layout as Redis
+ * @Autor: HTmonster
+ * @Date: 2026-10-19 16:20:37
+ */
+package hyperloglog
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+)
+
+// An HLL is a 16 bytes header followed by the registers:
+//
+//	+------+---+-----+----------+
+//	| HYLL | E | N/U | Cardin.  |
+//	+------+---+-----+----------+
+//
+// E is the encoding, 0 dense or 1 sparse. The cardinality is a little endian
+// uint64 cached by PFCOUNT, the most significant bit of its last byte marks
+// the cache as invalid.
+//
+// Dense: 16384 registers of 6 bits, from the least significant bit of each byte.
+//
+// Sparse: opcodes describing runs of registers
+//
+//	ZERO  00xxxxxx           xxxxxx+1 registers (1-64) are 0
+//	XZERO 01xxxxxx yyyyyyyy  xxxxxxyyyyyyyy+1 registers (1-16384) are 0
+//	VAL   1vvvvvxx           xx+1 registers (1-4) are vvvvv+1 (1-32)
+
+const (
+	precision      = 14             // bits of the hash for the register index
+	Registers      = 1 << precision // number of registers
+	registerMask   = Registers - 1
+	registerBits   = 6 // bits of a dense register
+	registerMax    = 1<<registerBits - 1
+	hashBits       = 64 - precision // bits of the hash to count zeros
+	headerSize     = 16
+	denseSize      = headerSize + (Registers*registerBits+7)/8
+	alphaInf       = 0.721347520444481703680 // constant of the estimator, m -> inf
+	murmurSeed     = 0xadc83b19
+	encodingDense  = 0
+	encodingSparse = 1
+
+	// sparse opcodes
+	sparseXZeroBit    = 0x40
+	sparseValBit      = 0x80
+	sparseZeroMaxLen  = 64
+	sparseXZeroMaxLen = 16384
+	sparseValMaxValue = 32
+	sparseValMaxLen   = 4
+)
+
+// the registers cannot be decoded
+var ErrCorrupted = errors.New("corrupted HyperLogLog")
+
+//------------ header --------------
+
+/**
+ * @description: make an empty HLL, sparse encoded
+ * @return {*}
+ */
+func New() []byte {
+	hll := make([]byte, headerSize, headerSize+2)
+	copy(hll, "HYLL")
+	hll[4] = encodingSparse
+	// a single XZERO opcode covers all registers
+	return append(hll, sparseXZeroBit|byte((Registers-1)>>8), byte((Registers-1)&0xff))
+}
+
+/**
+ * @description: check the header of an HLL
+ * @param {[]byte} hll
+ * @return {*}
+ */
+func IsValid(hll []byte) bool {
+	if len(hll) < headerSize || string(hll[:4]) != "HYLL" {
+		return false
+	}
+	switch hll[4] {
+	case encodingDense:
+		return len(hll) == denseSize
+	case encodingSparse:
+		return true
+	}
+	return false
+}
+
+/**
+ * @description: check whether the HLL is dense encoded
+ */
+func IsDense(hll []byte) bool {
+	return hll[4] == encodingDense
+}
+
+/**
+ * @description: get the cached cardinality
+ * @param {[]byte} hll
+ * @return {*} cardinality, false if the cache is invalid
+ */
+func CachedCount(hll []byte) (uint64, bool) {
+	if hll[15]&0x80 != 0 {
+		return 0, false
+	}
+	return binary.LittleEndian.Uint64(hll[8:16]), true
+}
+
+/**
+ * @description: set the cached cardinality, hll is modified
+ */
+func SetCachedCount(hll []byte, count uint64) {
+	binary.LittleEndian.PutUint64(hll[8:16], count)
+}
+
+/**
+ * @description: mark the cached cardinality invalid
+ */
+func invalidateCache(hll []byte) {
+	hll[15] |= 0x80
+}
+
+//------------ hash --------------
+
+/**
+ * @description: MurmurHash64A of Austin Appleby, blocks are read as little endian
+ * @param {[]byte} key
+ * @param {uint64} seed
+ * @return {*}
+ */
+func murmurHash64A(key []byte, seed uint64) uint64 {
+	const m = 0xc6a4a7935bd1e995
+	const r = 47
+	h := seed ^ (uint64(len(key)) * m)
+
+	for len(key) >= 8 {
+		k := binary.LittleEndian.Uint64(key)
+		k *= m
+		k ^= k >> r
+		k *= m
+		h ^= k
+		h *= m
+		key = key[8:]
+	}
+	if len(key) > 0 {
+		for i := len(key) - 1; i >= 0; i-- {
+			h ^= uint64(key[i]) << (8 * uint(i))
+		}
+		h *= m
+	}
+
+	h ^= h >> r
+	h *= m
+	h ^= h >> r
+	return h
+}
+
+/**
+ * @description: register index of an element, and the position of the first
+ *	1 bit of the rest of the hash, which is the value for the register
+ * @param {[]byte} element
+ * @return {*} index, count in [1, 51]
+ */
+func patternLen(element []byte) (int, uint8) {
+	hash := murmurHash64A(element, murmurSeed)
+	index := int(hash & registerMask)
+	hash >>= precision
+	// make sure the loop terminates
+	hash |= 1 << hashBits
+	count := uint8(1)
+	for bit := uint64(1); hash&bit == 0; bit <<= 1 {
+		count++
+	}
+	return index, count
+}
+
+//------------ registers --------------
+
+/**
+ * @description: get a dense register
+ */
+func getRegister(registers []byte, i int) uint8 {
+	pos, shift := i*registerBits/8, uint(i*registerBits&7)
+	value := registers[pos] >> shift
+	if pos+1 < len(registers) {
+		value |= registers[pos+1] << (8 - shift)
+	}
+	return value & registerMax
+}
+
+/**
+ * @description: set a dense register
+ */
+func setRegister(registers []byte, i int, value uint8) {
+	pos, shift := i*registerBits/8, uint(i*registerBits&7)
+	registers[pos] &^= registerMax << shift
+	registers[pos] |= value << shift
+	if pos+1 < len(registers) {
+		registers[pos+1] &^= registerMax >> (8 - shift)
+		registers[pos+1] |= value >> (8 - shift)
+	}
+}
+
+/**
+ * @description: merge the registers of an HLL into raw registers, keeping the max
+ * @param {[]uint8} raw one byte per register
+ * @param {[]byte} hll
+ * @return {*} ErrCorrupted if the sparse opcodes do not cover all registers
+ */
+func Merge(raw []uint8, hll []byte) error {
+	if IsDense(hll) {
+		registers := hll[headerSize:]
+		for i := range raw {
+			if value := getRegister(registers, i); value > raw[i] {
+				raw[i] = value
+			}
+		}
+		return nil
+	}
+
+	i := 0
+	for pos := headerSize; pos < len(hll); {
+		var value uint8
+		var runLen int
+		op := hll[pos]
+		switch {
+		case op&sparseValBit != 0:
+			value, runLen = (op>>2)&0x1f+1, int(op&0x3)+1
+			pos++
+		case op&sparseXZeroBit != 0:
+			if pos+1 >= len(hll) {
+				return ErrCorrupted
+			}
+			runLen = int(op&0x3f)<<8 | int(hll[pos+1]) + 1
+			pos += 2
+		default:
+			runLen = int(op&0x3f) + 1
+			pos++
+		}
+		if i+runLen > Registers {
+			return ErrCorrupted
+		}
+		for end := i + runLen; i < end; i++ {
+			if value > raw[i] {
+				raw[i] = value
+			}
+		}
+	}
+	if i != Registers {
+		return ErrCorrupted
+	}
+	return nil
+}
+
+/**
+ * @description: encode raw registers as a dense HLL
+ */
+func encodeDense(raw []uint8) []byte {
+	hll := make([]byte, denseSize)
+	copy(hll, "HYLL")
+	hll[4] = encodingDense
+	registers := hll[headerSize:]
+	for i, value := range raw {
+		if value != 0 {
+			setRegister(registers, i, value)
+		}
+	}
+	return hll
+}
+
+/**
+ * @description: encode raw registers as a sparse HLL
+ * @return {*} nil if some registers are too large for the sparse encoding
+ */
+func encodeSparse(raw []uint8) []byte {
+	hll := make([]byte, headerSize)
+	copy(hll, "HYLL")
+	hll[4] = encodingSparse
+	for i := 0; i < len(raw); {
+		value := raw[i]
+		if value > sparseValMaxValue {
+			return nil
+		}
+		j := i + 1
+		for j < len(raw) && raw[j] == value {
+			j++
+		}
+		for run := j - i; run > 0; {
+			switch {
+			case value != 0:
+				n := run
+				if n > sparseValMaxLen {
+					n = sparseValMaxLen
+				}
+				hll = append(hll, sparseValBit|(value-1)<<2|byte(n-1))
+				run -= n
+			case run > sparseZeroMaxLen:
+				n := run
+				if n > sparseXZeroMaxLen {
+					n = sparseXZeroMaxLen
+				}
+				hll = append(hll, sparseXZeroBit|byte((n-1)>>8), byte((n-1)&0xff))
+				run -= n
+			default:
+				hll = append(hll, byte(run-1))
+				run = 0
+			}
+		}
+		i = j
+	}
+	return hll
+}
+
+/**
+ * @description: encode raw registers, sparse if possible within sparseMaxBytes
+ * @param {[]uint8} raw
+ * @param {bool} dense always use the dense encoding
+ * @param {int} sparseMaxBytes max size of a sparse HLL, header included
+ * @return {*} HLL with an invalid cached cardinality
+ */
+func Encode(raw []uint8, dense bool, sparseMaxBytes int) []byte {
+	var hll []byte
+	if !dense {
+		hll = encodeSparse(raw)
+	}
+	if hll == nil || len(hll) > sparseMaxBytes {
+		hll = encodeDense(raw)
+	}
+	invalidateCache(hll)
+	return hll
+}
+
+//------------ add and count --------------
+
+/**
+ * @description: add elements, hll is not modified
+ * @param {[]byte} hll a valid HLL
+ * @param {[][]byte} elements
+ * @param {int} sparseMaxBytes max size of a sparse HLL before turning dense
+ * @return {*} new HLL if some register changed else hll, changed or not, ErrCorrupted
+ */
+func Add(hll []byte, elements [][]byte, sparseMaxBytes int) ([]byte, bool, error) {
+	if IsDense(hll) {
+		result, changed := hll, false
+		for _, element := range elements {
+			index, count := patternLen(element)
+			if count <= getRegister(result[headerSize:], index) {
+				continue
+			}
+			if !changed {
+				// stored strings are shared, modify a copy
+				result = make([]byte, len(hll))
+				copy(result, hll)
+				changed = true
+			}
+			setRegister(result[headerSize:], index, count)
+		}
+		if changed {
+			invalidateCache(result)
+		}
+		return result, changed, nil
+	}
+
+	raw := make([]uint8, Registers)
+	if err := Merge(raw, hll); err != nil {
+		return nil, false, err
+	}
+	changed := false
+	for _, element := range elements {
+		index, count := patternLen(element)
+		if count > raw[index] {
+			raw[index] = count
+			changed = true
+		}
+	}
+	if !changed {
+		return hll, false, nil
+	}
+	return Encode(raw, false, sparseMaxBytes), true, nil
+}
+
+/**
+ * @description: estimate the cardinality of raw registers
+ *	with the estimator of Otmar Ertl, as Redis does
+ * @param {[]uint8} raw
+ * @return {*}
+ */
+func Count(raw []uint8) uint64 {
+	var histogram [registerMax + 1]int
+	for _, value := range raw {
+		histogram[value]++
+	}
+
+	m := float64(Registers)
+	z := m * tau((m-float64(histogram[hashBits+1]))/m)
+	for j := hashBits; j >= 1; j-- {
+		z += float64(histogram[j])
+		z *= 0.5
+	}
+	z += m * sigma(float64(histogram[0])/m)
+	return uint64(math.Round(alphaInf * m * m / z))
+}
+
+/**
+ * @description: helper of the estimator for the registers that are 0
+ */
+func sigma(x float64) float64 {
+	if x == 1 {
+		return math.Inf(1)
+	}
+	y, z := 1.0, x
+	for {
+		x *= x
+		zPrime := z
+		z += x * y
+		y += y
+		if zPrime == z {
+			return z
+		}
+	}
+}
+
+/**
+ * @description: helper of the estimator for the registers that overflowed
+ */
+func tau(x float64) float64 {
+	if x == 0 || x == 1 {
+		return 0
+	}
+	y, z := 1.0, 1-x
+	for {
+		x = math.Sqrt(x)
+		zPrime := z
+		y *= 0.5
+		z -= math.Pow(1-x, 2) * y
+		if zPrime == z {
+			return z / 3
+		}
+	}
+}
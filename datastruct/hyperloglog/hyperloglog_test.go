@@ -0,0 +1,148 @@
+/*
+ * @Description:
+ * @Autor: HTmonster
+ * @Date: 2026-10-19 16:58:24
+ */
+package hyperloglog
+
+import (
+	"math"
+	"strconv"
+	"testing"
+)
+
+func TestMurmurHash64A(t *testing.T) {
+	// computed with the C implementation used by Redis
+	cases := []struct {
+		key  string
+		want uint64
+	}{
+		{"", 0xd8dfea6585bc9732},
+		{"a", 0x53d2470a9b43b1a7},
+		{"foo", 0xe64609b8b0141cb4},
+		{"hello world", 0xa919bc3051f624b7},
+		{"0123456789abcdef", 0x9f8565428eaa573d},
+		{"redis-hyperloglog", 0x43aef1272907412a},
+	}
+	for _, c := range cases {
+		if got := murmurHash64A([]byte(c.key), murmurSeed); got != c.want {
+			t.Errorf("murmur %q: got %x, want %x", c.key, got, c.want)
+		}
+	}
+}
+
+func TestRegisters(t *testing.T) {
+	registers := make([]byte, denseSize-headerSize)
+	for i := 0; i < Registers; i++ {
+		setRegister(registers, i, uint8(i%64))
+	}
+	for i := 0; i < Registers; i++ {
+		if got := getRegister(registers, i); got != uint8(i%64) {
+			t.Fatalf("register %d: got %d", i, got)
+		}
+	}
+}
+
+func TestEmpty(t *testing.T) {
+	hll := New()
+	if !IsValid(hll) || IsDense(hll) {
+		t.Fatal("new HLL should be a valid sparse HLL")
+	}
+	if count, ok := CachedCount(hll); !ok || count != 0 {
+		t.Errorf("cached count: %d %v", count, ok)
+	}
+	raw := make([]uint8, Registers)
+	if err := Merge(raw, hll); err != nil || Count(raw) != 0 {
+		t.Errorf("count of empty HLL: %d %v", Count(raw), err)
+	}
+	for _, invalid := range [][]byte{nil, []byte("HYLL"), append([]byte("HYLX"), hll[4:]...), hll[:headerSize+1]} {
+		if IsValid(invalid) && Merge(raw, invalid) == nil {
+			t.Errorf("%q should be rejected", invalid)
+		}
+	}
+}
+
+/**
+ * @description: count of an HLL
+ */
+func countOf(t *testing.T, hll []byte) uint64 {
+	raw := make([]uint8, Registers)
+	if err := Merge(raw, hll); err != nil {
+		t.Fatal(err)
+	}
+	return Count(raw)
+}
+
+func TestAddCount(t *testing.T) {
+	sparse, dense := New(), Encode(make([]uint8, Registers), true, 0)
+	var changed bool
+	var err error
+	for n := 1; n <= 100000; n++ {
+		elements := [][]byte{[]byte(strconv.Itoa(n))}
+		if sparse, _, err = Add(sparse, elements, 3000); err != nil {
+			t.Fatal(err)
+		}
+		if dense, _, err = Add(dense, elements, 3000); err != nil {
+			t.Fatal(err)
+		}
+		if n == 10 || n == 1000 || n == 100000 {
+			got := countOf(t, sparse)
+			if got != countOf(t, dense) {
+				t.Fatalf("%d elements: sparse and dense counts differ", n)
+			}
+			if math.Abs(float64(got)-float64(n)) > float64(n)*0.02 {
+				t.Errorf("%d elements: count %d", n, got)
+			}
+		}
+		if n == 100 && IsDense(sparse) {
+			t.Error("100 elements should stay sparse")
+		}
+	}
+	if !IsDense(sparse) {
+		t.Error("the sparse HLL should be turned dense")
+	}
+	if _, ok := CachedCount(sparse); ok {
+		t.Error("added HLL should have an invalid cache")
+	}
+
+	// adding again changes nothing and shares the HLL
+	before := dense
+	if dense, changed, err = Add(dense, [][]byte{[]byte("1")}, 3000); changed || err != nil || &dense[0] != &before[0] {
+		t.Errorf("add again: changed %v, err %v", changed, err)
+	}
+}
+
+func TestEncodeSparse(t *testing.T) {
+	raw := make([]uint8, Registers)
+	raw[0], raw[1], raw[2], raw[3], raw[4] = 3, 3, 3, 3, 3
+	raw[100] = 32
+	raw[Registers-1] = 1
+	hll := Encode(raw, false, 3000)
+	if IsDense(hll) {
+		t.Fatal("should be sparse")
+	}
+	decoded := make([]uint8, Registers)
+	if err := Merge(decoded, hll); err != nil {
+		t.Fatal(err)
+	}
+	for i := range raw {
+		if raw[i] != decoded[i] {
+			t.Fatalf("register %d: got %d, want %d", i, decoded[i], raw[i])
+		}
+	}
+
+	// too large values for the sparse encoding
+	raw[7] = 33
+	if hll := Encode(raw, false, 3000); !IsDense(hll) {
+		t.Error("registers above 32 need the dense encoding")
+	}
+
+	// opcodes not covering all registers
+	corrupted := append(New(), 0x00)
+	if err := Merge(decoded, corrupted); err != ErrCorrupted {
+		t.Errorf("corrupted: %v", err)
+	}
+	if err := Merge(decoded, New()[:headerSize+1]); err != ErrCorrupted {
+		t.Errorf("truncated: %v", err)
+	}
+}
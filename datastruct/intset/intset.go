@@ -0,0 +1,219 @@
+/*
+ * @Description: intset, sorted integers packed with the smallest fitting width
+ * @Autor: HTmonster
+ * @Date: 2026-10-19 09:10:27
+ */
+package intset
+
+import (
+	"encoding/binary"
+	"math"
+	"math/rand"
+)
+
+// element widths in bytes
+const (
+	encInt16 = 2
+	encInt32 = 4
+	encInt64 = 8
+)
+
+// IntSet keeps distinct integers in ascending order. All elements share
+// the width of the largest one, the width only grows when a value which
+// does not fit is added.
+type IntSet struct {
+	encoding int
+	contents []byte // little endian elements
+}
+
+/**
+ * @description: make an empty intset
+ * @return {*}
+ */
+func New() *IntSet {
+	return &IntSet{encoding: encInt16}
+}
+
+/**
+ * @description: smallest width which can hold v
+ */
+func valueEncoding(v int64) int {
+	if v < math.MinInt32 || v > math.MaxInt32 {
+		return encInt64
+	}
+	if v < math.MinInt16 || v > math.MaxInt16 {
+		return encInt32
+	}
+	return encInt16
+}
+
+/**
+ * @description: number of elements
+ */
+func (is *IntSet) Len() int {
+	return len(is.contents) / is.encoding
+}
+
+/**
+ * @description: bytes used by the elements
+ */
+func (is *IntSet) Size() int {
+	return len(is.contents)
+}
+
+/**
+ * @description: read the element at index with the given width
+ */
+func get(contents []byte, encoding int, index int) int64 {
+	p := contents[index*encoding:]
+	switch encoding {
+	case encInt16:
+		return int64(int16(binary.LittleEndian.Uint16(p)))
+	case encInt32:
+		return int64(int32(binary.LittleEndian.Uint32(p)))
+	default:
+		return int64(binary.LittleEndian.Uint64(p))
+	}
+}
+
+/**
+ * @description: write the element at index with the current width
+ */
+func (is *IntSet) set(index int, v int64) {
+	p := is.contents[index*is.encoding:]
+	switch is.encoding {
+	case encInt16:
+		binary.LittleEndian.PutUint16(p, uint16(v))
+	case encInt32:
+		binary.LittleEndian.PutUint32(p, uint32(v))
+	default:
+		binary.LittleEndian.PutUint64(p, uint64(v))
+	}
+}
+
+/**
+ * @description: get the element at index, index must be in [0, Len())
+ */
+func (is *IntSet) Get(index int) int64 {
+	return get(is.contents, is.encoding, index)
+}
+
+/**
+ * @description: binary search
+ * @return {*} index of v, or where v should be inserted; found or not
+ */
+func (is *IntSet) search(v int64) (int, bool) {
+	lo, hi := 0, is.Len()-1
+	for lo <= hi {
+		mid := int(uint(lo+hi) >> 1)
+		cur := is.Get(mid)
+		if cur == v {
+			return mid, true
+		} else if cur < v {
+			lo = mid + 1
+		} else {
+			hi = mid - 1
+		}
+	}
+	return lo, false
+}
+
+/**
+ * @description: check whether v is in the set
+ */
+func (is *IntSet) Contains(v int64) bool {
+	if valueEncoding(v) > is.encoding {
+		return false
+	}
+	_, ok := is.search(v)
+	return ok
+}
+
+/**
+ * @description: widen all elements and add v, which is out of the old range
+ */
+func (is *IntSet) upgradeAndAdd(v int64) {
+	old, oldEncoding, n := is.contents, is.encoding, is.Len()
+	is.encoding = valueEncoding(v)
+	is.contents = make([]byte, (n+1)*is.encoding)
+	// v is either smaller or bigger than all elements
+	shift := 0
+	if v < 0 {
+		shift = 1
+	}
+	for i := 0; i < n; i++ {
+		is.set(i+shift, get(old, oldEncoding, i))
+	}
+	if v < 0 {
+		is.set(0, v)
+	} else {
+		is.set(n, v)
+	}
+}
+
+/**
+ * @description: add v to the set
+ * @return {*} true if added, false if it already exists
+ */
+func (is *IntSet) Add(v int64) bool {
+	if valueEncoding(v) > is.encoding {
+		is.upgradeAndAdd(v)
+		return true
+	}
+	index, ok := is.search(v)
+	if ok {
+		return false
+	}
+	off := index * is.encoding
+	is.contents = append(is.contents, make([]byte, is.encoding)...)
+	copy(is.contents[off+is.encoding:], is.contents[off:])
+	is.set(index, v)
+	return true
+}
+
+/**
+ * @description: remove v from the set
+ * @return {*} true if removed
+ */
+func (is *IntSet) Remove(v int64) bool {
+	if valueEncoding(v) > is.encoding {
+		return false
+	}
+	index, ok := is.search(v)
+	if !ok {
+		return false
+	}
+	off := index * is.encoding
+	is.contents = append(is.contents[:off], is.contents[off+is.encoding:]...)
+	return true
+}
+
+/**
+ * @description: return a random element, the set must not be empty
+ */
+func (is *IntSet) Random() int64 {
+	return is.Get(rand.Intn(is.Len()))
+}
+
+/**
+ * @description: traversal the elements in ascending order
+ * @param {func(v int64) bool} consumer returns false to stop
+ * @return {*}
+ */
+func (is *IntSet) ForEach(consumer func(v int64) bool) {
+	for i, n := 0, is.Len(); i < n; i++ {
+		if !consumer(is.Get(i)) {
+			return
+		}
+	}
+}
+
+/**
+ * @description: make a copy of the set
+ */
+func (is *IntSet) Clone() *IntSet {
+	return &IntSet{
+		encoding: is.encoding,
+		contents: append([]byte(nil), is.contents...),
+	}
+}
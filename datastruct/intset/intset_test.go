@@ -0,0 +1,88 @@
+/*
+ * @Description:
+ * @Autor: HTmonster
+ * @Date: 2026-10-19 09:31:52
+ */
+package intset
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+func TestIntSetUpgrade(t *testing.T) {
+	is := New()
+	for _, v := range []int64{5, -3, 100} {
+		is.Add(v)
+	}
+	if is.encoding != encInt16 || is.Size() != 6 {
+		t.Errorf("test int16 encoding fail: %d %d", is.encoding, is.Size())
+	}
+	is.Add(math.MaxInt32)
+	if is.encoding != encInt32 {
+		t.Errorf("test upgrade to int32 fail: %d", is.encoding)
+	}
+	is.Add(math.MinInt64)
+	if is.encoding != encInt64 || is.Len() != 5 {
+		t.Errorf("test upgrade to int64 fail: %d %d", is.encoding, is.Len())
+	}
+	want := []int64{math.MinInt64, -3, 5, 100, math.MaxInt32}
+	for i, v := range want {
+		if is.Get(i) != v {
+			t.Errorf("test element %d after upgrade: got %d, want %d", i, is.Get(i), v)
+		}
+	}
+	if is.Contains(math.MaxInt64) || !is.Contains(math.MinInt64) {
+		t.Errorf("test contains after upgrade fail")
+	}
+	if is.Add(5) || !is.Remove(5) || is.Remove(5) || is.Len() != 4 {
+		t.Errorf("test add remove after upgrade fail")
+	}
+}
+
+func TestIntSetModel(t *testing.T) {
+	is := New()
+	model := make(map[int64]bool)
+	values := []int64{0, 1, -1, math.MaxInt16, math.MinInt16, math.MaxInt32 + 1, math.MinInt64, math.MaxInt64}
+	for i := 0; i < 5000; i++ {
+		var v int64
+		if rand.Intn(4) == 0 {
+			v = values[rand.Intn(len(values))]
+		} else {
+			v = int64(rand.Intn(2000) - 1000)
+		}
+		if rand.Intn(3) == 0 {
+			if got := is.Remove(v); got != model[v] {
+				t.Fatalf("remove %d: got %v, want %v", v, got, model[v])
+			}
+			delete(model, v)
+		} else {
+			if got := is.Add(v); got == model[v] {
+				t.Fatalf("add %d: got %v, exists %v", v, got, model[v])
+			}
+			model[v] = true
+		}
+	}
+
+	sorted := make([]int64, 0, len(model))
+	for v := range model {
+		sorted = append(sorted, v)
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	if is.Len() != len(sorted) {
+		t.Fatalf("test len: got %d, want %d", is.Len(), len(sorted))
+	}
+	i := 0
+	is.Clone().ForEach(func(v int64) bool {
+		if v != sorted[i] {
+			t.Fatalf("test order at %d: got %d, want %d", i, v, sorted[i])
+		}
+		i++
+		return true
+	})
+	if is.Len() > 0 && !model[is.Random()] {
+		t.Errorf("test random element not in the set")
+	}
+}
@@ -0,0 +1,457 @@
+/*
+ * @Description: quicklist, a doubly linked list of listpack nodes
+ * @Autor: HTmonster
+ * @Date: 2026-10-18 22:58:36
+ */
+package list
+
+import (
+	"github.com/HTmonster/redissgo/datastruct/listpack"
+)
+
+// a node is full when its listpack is bigger than this,
+// like list-max-listpack-size -2 of redis
+const maxNodeSize = 8 * 1024
+
+// small neighbour nodes are merged after removals
+const mergeNodeSize = maxNodeSize / 2
+
+// node of the quicklist, never empty while linked
+type node struct {
+	lp         *listpack.Listpack
+	prev, next *node
+}
+
+// list of byte slices, O(1) push and pop at both ends.
+// It is not concurrency safe, and values are copied in and out.
+type QuickList struct {
+	head, tail *node
+	count      int
+}
+
+/**
+ * @description: make an empty quicklist
+ * @return {*}
+ */
+func MakeQuickList() *QuickList {
+	return &QuickList{}
+}
+
+/**
+ * @description: number of elements
+ */
+func (ql *QuickList) Len() int {
+	return ql.count
+}
+
+/**
+ * @description: whether data can be added into the node without exceeding its size
+ */
+func (n *node) fits(data []byte) bool {
+	return n.lp.Len() == 0 || n.lp.Size()+listpack.EntrySize(len(data)) <= maxNodeSize
+}
+
+/**
+ * @description: link a new node after at, at nil means before the head
+ */
+func (ql *QuickList) linkAfter(at *node, n *node) {
+	n.prev = at
+	if at == nil {
+		n.next = ql.head
+		ql.head = n
+	} else {
+		n.next = at.next
+		at.next = n
+	}
+	if n.next == nil {
+		ql.tail = n
+	} else {
+		n.next.prev = n
+	}
+}
+
+/**
+ * @description: remove a node from the list
+ */
+func (ql *QuickList) unlink(n *node) {
+	if n.prev == nil {
+		ql.head = n.next
+	} else {
+		n.prev.next = n.next
+	}
+	if n.next == nil {
+		ql.tail = n.prev
+	} else {
+		n.next.prev = n.prev
+	}
+	n.prev, n.next = nil, nil
+}
+
+/**
+ * @description: unlink the node if it is empty, otherwise merge it with small neighbours
+ */
+func (ql *QuickList) compact(n *node) {
+	if n.lp.Len() == 0 {
+		ql.unlink(n)
+		return
+	}
+	if next := n.next; next != nil && n.lp.Size()+next.lp.Size() <= mergeNodeSize {
+		n.lp.Merge(next.lp)
+		ql.unlink(next)
+	}
+	if prev := n.prev; prev != nil && prev.lp.Size()+n.lp.Size() <= mergeNodeSize {
+		prev.lp.Merge(n.lp)
+		ql.unlink(n)
+	}
+}
+
+/**
+ * @description: find the node and the listpack offset of an element
+ * @param {int} index in [0, Len())
+ * @return {*}
+ */
+func (ql *QuickList) locate(index int) (*node, int) {
+	if index < ql.count/2 {
+		n := ql.head
+		for index >= n.lp.Len() {
+			index -= n.lp.Len()
+			n = n.next
+		}
+		return n, n.lp.Seek(index)
+	}
+	index = ql.count - 1 - index
+	n := ql.tail
+	for index >= n.lp.Len() {
+		index -= n.lp.Len()
+		n = n.prev
+	}
+	return n, n.lp.Seek(-1 - index)
+}
+
+/**
+ * @description: add an element at the head
+ */
+func (ql *QuickList) PushFront(data []byte) {
+	if ql.head == nil || !ql.head.fits(data) {
+		ql.linkAfter(nil, &node{lp: listpack.New()})
+	}
+	ql.head.lp.Prepend(data)
+	ql.count++
+}
+
+/**
+ * @description: add an element at the tail
+ */
+func (ql *QuickList) PushBack(data []byte) {
+	if ql.tail == nil || !ql.tail.fits(data) {
+		ql.linkAfter(ql.tail, &node{lp: listpack.New()})
+	}
+	ql.tail.lp.Append(data)
+	ql.count++
+}
+
+/**
+ * @description: remove and return the element at the head
+ * @return {*} element, false if empty
+ */
+func (ql *QuickList) PopFront() ([]byte, bool) {
+	if ql.head == nil {
+		return nil, false
+	}
+	n := ql.head
+	off := n.lp.First()
+	data := n.lp.Get(off)
+	n.lp.Delete(off)
+	ql.count--
+	if n.lp.Len() == 0 {
+		ql.unlink(n)
+	}
+	return data, true
+}
+
+/**
+ * @description: remove and return the element at the tail
+ * @return {*} element, false if empty
+ */
+func (ql *QuickList) PopBack() ([]byte, bool) {
+	if ql.tail == nil {
+		return nil, false
+	}
+	n := ql.tail
+	off := n.lp.Last()
+	data := n.lp.Get(off)
+	n.lp.Delete(off)
+	ql.count--
+	if n.lp.Len() == 0 {
+		ql.unlink(n)
+	}
+	return data, true
+}
+
+/**
+ * @description: get the element at index
+ * @param {int} index in [0, Len())
+ * @return {*}
+ */
+func (ql *QuickList) Get(index int) []byte {
+	n, off := ql.locate(index)
+	return n.lp.Get(off)
+}
+
+/**
+ * @description: replace the element at index
+ * @param {int} index in [0, Len())
+ * @param {[]byte} data
+ */
+func (ql *QuickList) Set(index int, data []byte) {
+	n, off := ql.locate(index)
+	n.lp.Replace(off, data)
+}
+
+/**
+ * @description: insert an element before index
+ * @param {int} index in [0, Len()], Len() appends
+ * @param {[]byte} data
+ */
+func (ql *QuickList) Insert(index int, data []byte) {
+	if index == ql.count {
+		ql.PushBack(data)
+		return
+	}
+	n, off := ql.locate(index)
+	ql.count++
+	if n.fits(data) {
+		n.lp.Insert(off, data)
+		return
+	}
+
+	// the new node is linked after at
+	at := n.prev
+	if off != n.lp.First() {
+		// split the full node, the element at index heads the second part
+		ql.linkAfter(n, &node{lp: n.lp.Split(off)})
+		if n.fits(data) {
+			n.lp.Append(data)
+			return
+		}
+		at = n
+	} else if n.prev != nil && n.prev.fits(data) {
+		n.prev.lp.Append(data)
+		return
+	}
+	m := &node{lp: listpack.New()}
+	m.lp.Append(data)
+	ql.linkAfter(at, m)
+}
+
+/**
+ * @description: remove the element at index
+ * @param {int} index in [0, Len())
+ */
+func (ql *QuickList) Remove(index int) {
+	n, off := ql.locate(index)
+	n.lp.Delete(off)
+	ql.count--
+	ql.compact(n)
+}
+
+/**
+ * @description: remove elements equal to data
+ * @param {[]byte} data
+ * @param {int} count > 0 from head to tail, < 0 from tail to head, 0 all of them
+ * @return {*} number of removed elements
+ */
+func (ql *QuickList) RemoveValue(data []byte, count int) int {
+	limit := count
+	if limit < 0 {
+		limit = -limit
+	}
+	removed := 0
+	done := func() bool { return limit != 0 && removed >= limit }
+
+	if count >= 0 {
+		for n := ql.head; n != nil && !done(); {
+			for off := n.lp.First(); off != -1 && !done(); {
+				if n.lp.Equal(off, data) {
+					off = n.lp.Delete(off)
+					removed++
+				} else {
+					off = n.lp.Next(off)
+				}
+			}
+			next := n.next
+			if n.lp.Len() == 0 {
+				ql.unlink(n)
+			}
+			n = next
+		}
+	} else {
+		for n := ql.tail; n != nil && !done(); {
+			for off := n.lp.Last(); off != -1 && !done(); {
+				// entries before off are not moved by the deletion
+				prev := n.lp.Prev(off)
+				if n.lp.Equal(off, data) {
+					n.lp.Delete(off)
+					removed++
+				}
+				off = prev
+			}
+			prev := n.prev
+			if n.lp.Len() == 0 {
+				ql.unlink(n)
+			}
+			n = prev
+		}
+	}
+	ql.count -= removed
+	return removed
+}
+
+/**
+ * @description: get the elements in [start, stop]
+ * @param {int} start in [0, Len())
+ * @param {int} stop in [start, Len())
+ * @return {*}
+ */
+func (ql *QuickList) Range(start, stop int) [][]byte {
+	result := make([][]byte, 0, stop-start+1)
+	for it := ql.Iterator(start); it.Valid() && it.Index() <= stop; it.Next() {
+		result = append(result, it.Get())
+	}
+	return result
+}
+
+/**
+ * @description: remove n elements from the head
+ */
+func (ql *QuickList) trimFront(n int) {
+	for n > 0 && ql.head != nil {
+		head := ql.head
+		if head.lp.Len() <= n {
+			n -= head.lp.Len()
+			ql.count -= head.lp.Len()
+			ql.unlink(head)
+			continue
+		}
+		head.lp = head.lp.Split(head.lp.Seek(n))
+		ql.count -= n
+		n = 0
+	}
+}
+
+/**
+ * @description: remove n elements from the tail
+ */
+func (ql *QuickList) trimBack(n int) {
+	for n > 0 && ql.tail != nil {
+		tail := ql.tail
+		if tail.lp.Len() <= n {
+			n -= tail.lp.Len()
+			ql.count -= tail.lp.Len()
+			ql.unlink(tail)
+			continue
+		}
+		tail.lp.Split(tail.lp.Seek(-n))
+		ql.count -= n
+		n = 0
+	}
+}
+
+/**
+ * @description: keep only the elements in [start, stop], an empty range clears the list
+ * @param {int} start >= 0
+ * @param {int} stop < Len()
+ */
+func (ql *QuickList) Trim(start, stop int) {
+	if start > stop {
+		ql.head, ql.tail, ql.count = nil, nil, 0
+		return
+	}
+	ql.trimBack(ql.count - 1 - stop)
+	ql.trimFront(start)
+}
+
+/**
+ * @description: make an independent copy of the quicklist
+ */
+func (ql *QuickList) Clone() *QuickList {
+	clone := &QuickList{count: ql.count}
+	for n := ql.head; n != nil; n = n.next {
+		clone.linkAfter(clone.tail, &node{lp: n.lp.Clone()})
+	}
+	return clone
+}
+
+//------------ iterator --------------
+
+// position in a quicklist, invalidated by any modification of the list
+type Iterator struct {
+	n     *node
+	off   int
+	index int
+}
+
+/**
+ * @description: make an iterator at index, it is not valid if index is out of range
+ * @param {int} index
+ * @return {*}
+ */
+func (ql *QuickList) Iterator(index int) *Iterator {
+	if index < 0 || index >= ql.count {
+		return &Iterator{index: index}
+	}
+	n, off := ql.locate(index)
+	return &Iterator{n: n, off: off, index: index}
+}
+
+/**
+ * @description: whether the iterator points to an element
+ */
+func (it *Iterator) Valid() bool {
+	return it.n != nil
+}
+
+/**
+ * @description: index of the current element
+ */
+func (it *Iterator) Index() int {
+	return it.index
+}
+
+/**
+ * @description: get a copy of the current element
+ */
+func (it *Iterator) Get() []byte {
+	return it.n.lp.Get(it.off)
+}
+
+/**
+ * @description: compare the current element without copying
+ */
+func (it *Iterator) Equal(data []byte) bool {
+	return it.n.lp.Equal(it.off, data)
+}
+
+/**
+ * @description: move to the next element
+ */
+func (it *Iterator) Next() {
+	it.index++
+	if it.off = it.n.lp.Next(it.off); it.off == -1 {
+		if it.n = it.n.next; it.n != nil {
+			it.off = it.n.lp.First()
+		}
+	}
+}
+
+/**
+ * @description: move to the previous element
+ */
+func (it *Iterator) Prev() {
+	it.index--
+	if it.off = it.n.lp.Prev(it.off); it.off == -1 {
+		if it.n = it.n.prev; it.n != nil {
+			it.off = it.n.lp.Last()
+		}
+	}
+}
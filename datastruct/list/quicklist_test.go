@@ -0,0 +1,195 @@
+/*
+ * @Description:
+ * @Autor: HTmonster
+ * @Date: 2026-10-18 23:32:17
+ */
+package list
+
+import (
+	"math/rand"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+/**
+ * @description: compare a quicklist with the expected elements
+ */
+func checkList(t *testing.T, ql *QuickList, want []string) {
+	t.Helper()
+	if ql.Len() != len(want) {
+		t.Fatalf("Len() = %d, want %d", ql.Len(), len(want))
+	}
+	// every linked node must be non empty and linked both ways
+	count := 0
+	var prev *node
+	for n := ql.head; n != nil; n = n.next {
+		if n.lp.Len() == 0 || n.prev != prev {
+			t.Fatal("broken node chain")
+		}
+		count += n.lp.Len()
+		prev = n
+	}
+	if prev != ql.tail || count != len(want) {
+		t.Fatalf("nodes hold %d elements, want %d", count, len(want))
+	}
+	if len(want) == 0 {
+		return
+	}
+	var got []string
+	for _, v := range ql.Range(0, ql.Len()-1) {
+		got = append(got, string(v))
+	}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	var backward []string
+	for it := ql.Iterator(ql.Len() - 1); it.Valid(); it.Prev() {
+		backward = append([]string{string(it.Get())}, backward...)
+	}
+	if strings.Join(backward, ",") != strings.Join(want, ",") {
+		t.Fatalf("backward %v, want %v", backward, want)
+	}
+}
+
+func TestQuickListPushPop(t *testing.T) {
+	ql := MakeQuickList()
+	if _, ok := ql.PopFront(); ok {
+		t.Fatal("pop from an empty list")
+	}
+	var want []string
+	for i := 0; i < 3000; i++ {
+		v := strconv.Itoa(i)
+		if i%2 == 0 {
+			ql.PushBack([]byte(v))
+			want = append(want, v)
+		} else {
+			ql.PushFront([]byte(v))
+			want = append([]string{v}, want...)
+		}
+	}
+	checkList(t, ql, want)
+	if ql.head == ql.tail {
+		t.Error("3000 elements should not fit into one node")
+	}
+	for i := 0; i < 1000; i++ {
+		v, _ := ql.PopFront()
+		w, _ := ql.PopBack()
+		if string(v) != want[0] || string(w) != want[len(want)-1] {
+			t.Fatalf("popped %q and %q", v, w)
+		}
+		want = want[1 : len(want)-1]
+	}
+	checkList(t, ql, want)
+}
+
+func TestQuickListBigElements(t *testing.T) {
+	ql := MakeQuickList()
+	big := strings.Repeat("x", maxNodeSize*2)
+	ql.PushBack([]byte("a"))
+	ql.PushBack([]byte(big))
+	ql.PushFront([]byte(big))
+	ql.Insert(2, []byte(big))
+	ql.Insert(1, []byte("b"))
+	checkList(t, ql, []string{big, "b", "a", big, big})
+}
+
+func TestQuickListRandom(t *testing.T) {
+	ql := MakeQuickList()
+	var want []string
+	r := rand.New(rand.NewSource(1))
+	value := func() string {
+		return strconv.Itoa(r.Intn(50)) + strings.Repeat("v", r.Intn(300))
+	}
+	for i := 0; i < 20000; i++ {
+		switch op := r.Intn(10); {
+		case op < 3:
+			v := value()
+			ql.PushBack([]byte(v))
+			want = append(want, v)
+		case op < 5:
+			v := value()
+			index := r.Intn(len(want) + 1)
+			ql.Insert(index, []byte(v))
+			want = append(want[:index], append([]string{v}, want[index:]...)...)
+		case op < 6 && len(want) > 0:
+			index := r.Intn(len(want))
+			ql.Remove(index)
+			want = append(want[:index], want[index+1:]...)
+		case op < 7 && len(want) > 0:
+			index := r.Intn(len(want))
+			v := value()
+			ql.Set(index, []byte(v))
+			want[index] = v
+		case op < 8 && len(want) > 0:
+			index := r.Intn(len(want))
+			if got := string(ql.Get(index)); got != want[index] {
+				t.Fatalf("Get(%d) = %q, want %q", index, got, want[index])
+			}
+		case op < 9 && len(want) > 0:
+			target := want[r.Intn(len(want))]
+			count := r.Intn(5) - 2
+			removed := ql.RemoveValue([]byte(target), count)
+			var rest []string
+			matched := 0
+			if count >= 0 {
+				for _, v := range want {
+					if v == target && (count == 0 || matched < count) {
+						matched++
+						continue
+					}
+					rest = append(rest, v)
+				}
+			} else {
+				for j := len(want) - 1; j >= 0; j-- {
+					if want[j] == target && matched < -count {
+						matched++
+						continue
+					}
+					rest = append([]string{want[j]}, rest...)
+				}
+			}
+			if removed != matched {
+				t.Fatalf("RemoveValue removed %d, want %d", removed, matched)
+			}
+			want = rest
+		case len(want) > 1 && r.Intn(20) == 0:
+			start := r.Intn(len(want))
+			stop := start + r.Intn(len(want)-start)
+			ql.Trim(start, stop)
+			want = append([]string(nil), want[start:stop+1]...)
+		}
+		if i%500 == 0 {
+			checkList(t, ql, want)
+		}
+	}
+	checkList(t, ql, want)
+}
+
+func TestQuickListTrim(t *testing.T) {
+	ql := MakeQuickList()
+	var want []string
+	for i := 0; i < 5000; i++ {
+		ql.PushBack([]byte(strconv.Itoa(i)))
+		want = append(want, strconv.Itoa(i))
+	}
+	ql.Trim(1234, 4321)
+	checkList(t, ql, want[1234:4322])
+	ql.Trim(1, 0)
+	checkList(t, ql, nil)
+}
+
+func TestQuickListClone(t *testing.T) {
+	ql := MakeQuickList()
+	var want []string
+	for i := 0; i < 3000; i++ {
+		ql.PushBack([]byte(strconv.Itoa(i)))
+		want = append(want, strconv.Itoa(i))
+	}
+	clone := ql.Clone()
+	ql.PopFront()
+	ql.Set(0, []byte("x"))
+	checkList(t, clone, want)
+	clone.PushBack([]byte("3000"))
+	checkList(t, clone, append(want, "3000"))
+}
@@ -0,0 +1,288 @@
+/*
+ * @Description: listpack, entries packed into a single byte slice
+ * @Autor: HTmonster
+ * @Date: 2026-10-18 22:31:05
+ */
+package listpack
+
+import (
+	"bytes"
+	"encoding/binary"
+)
+
+// Entry layout:
+//	<length uvarint> <data> <backlen>
+// backlen is the size of length and data, stored so that it can be read
+// from the right, which allows walking the listpack in both directions.
+// Entries are addressed by their byte offset, offsets are invalidated by
+// any modification.
+
+// listpack
+type Listpack struct {
+	buf   []byte
+	count int
+}
+
+/**
+ * @description: make an empty listpack
+ * @return {*}
+ */
+func New() *Listpack {
+	return &Listpack{}
+}
+
+/**
+ * @description: number of entries
+ */
+func (lp *Listpack) Len() int {
+	return lp.count
+}
+
+/**
+ * @description: number of bytes used by the entries
+ */
+func (lp *Listpack) Size() int {
+	return len(lp.buf)
+}
+
+/**
+ * @description: size of an entry holding n bytes of data
+ */
+func EntrySize(n int) int {
+	l := uvarintLen(uint64(n)) + n
+	return l + uvarintLen(uint64(l))
+}
+
+/**
+ * @description: number of bytes of a uvarint
+ */
+func uvarintLen(x uint64) int {
+	n := 1
+	for x >= 0x80 {
+		x >>= 7
+		n++
+	}
+	return n
+}
+
+/**
+ * @description: encode an entry
+ */
+func encode(data []byte) []byte {
+	var header [binary.MaxVarintLen64]byte
+	entry := make([]byte, 0, EntrySize(len(data)))
+	entry = append(entry, header[:binary.PutUvarint(header[:], uint64(len(data)))]...)
+	entry = append(entry, data...)
+	// backlen, the most significant group first, every byte except
+	// the leftmost one has the continuation bit
+	l := uint64(len(entry))
+	var back [binary.MaxVarintLen64]byte
+	i := len(back)
+	for {
+		i--
+		back[i] = byte(l & 0x7f)
+		l >>= 7
+		if l == 0 {
+			break
+		}
+	}
+	for j := i + 1; j < len(back); j++ {
+		back[j] |= 0x80
+	}
+	return append(entry, back[i:]...)
+}
+
+/**
+ * @description: decode the header of the entry at off
+ * @return {*} start and end of its data, offset of the next entry
+ */
+func (lp *Listpack) decode(off int) (int, int, int) {
+	n, hl := binary.Uvarint(lp.buf[off:])
+	start := off + hl
+	end := start + int(n)
+	return start, end, end + uvarintLen(uint64(end-off))
+}
+
+/**
+ * @description: offset of the first entry
+ * @return {*} -1 if empty
+ */
+func (lp *Listpack) First() int {
+	if lp.count == 0 {
+		return -1
+	}
+	return 0
+}
+
+/**
+ * @description: offset of the last entry
+ * @return {*} -1 if empty
+ */
+func (lp *Listpack) Last() int {
+	if lp.count == 0 {
+		return -1
+	}
+	return lp.Prev(len(lp.buf))
+}
+
+/**
+ * @description: offset of the entry after off
+ * @return {*} -1 if off is the last one
+ */
+func (lp *Listpack) Next(off int) int {
+	_, _, next := lp.decode(off)
+	if next >= len(lp.buf) {
+		return -1
+	}
+	return next
+}
+
+/**
+ * @description: offset of the entry before off, off can be Size() to get the last entry
+ * @return {*} -1 if off is the first one
+ */
+func (lp *Listpack) Prev(off int) int {
+	if off <= 0 {
+		return -1
+	}
+	p, l, shift := off, 0, 0
+	for {
+		p--
+		b := lp.buf[p]
+		l |= int(b&0x7f) << shift
+		shift += 7
+		if b&0x80 == 0 {
+			break
+		}
+	}
+	return p - l
+}
+
+/**
+ * @description: offset of the entry at index, negative index counts from the end
+ * @return {*} -1 if out of range
+ */
+func (lp *Listpack) Seek(index int) int {
+	if index < 0 {
+		index += lp.count
+	}
+	if index < 0 || index >= lp.count {
+		return -1
+	}
+	if index < lp.count/2 {
+		off := 0
+		for ; index > 0; index-- {
+			off = lp.Next(off)
+		}
+		return off
+	}
+	off := lp.Last()
+	for i := lp.count - 1; i > index; i-- {
+		off = lp.Prev(off)
+	}
+	return off
+}
+
+/**
+ * @description: get a copy of the data of the entry at off
+ */
+func (lp *Listpack) Get(off int) []byte {
+	start, end, _ := lp.decode(off)
+	data := make([]byte, end-start)
+	copy(data, lp.buf[start:end])
+	return data
+}
+
+/**
+ * @description: compare the data of the entry at off without copying
+ */
+func (lp *Listpack) Equal(off int, data []byte) bool {
+	start, end, _ := lp.decode(off)
+	return bytes.Equal(lp.buf[start:end], data)
+}
+
+/**
+ * @description: insert an entry before off, off can be Size() to append
+ * @return {*} offset of the new entry
+ */
+func (lp *Listpack) Insert(off int, data []byte) int {
+	entry := encode(data)
+	n := len(lp.buf)
+	if cap(lp.buf)-n >= len(entry) {
+		lp.buf = lp.buf[:n+len(entry)]
+	} else {
+		grown := make([]byte, n+len(entry), 2*n+len(entry))
+		copy(grown, lp.buf)
+		lp.buf = grown
+	}
+	copy(lp.buf[off+len(entry):], lp.buf[off:n])
+	copy(lp.buf[off:], entry)
+	lp.count++
+	return off
+}
+
+/**
+ * @description: add an entry at the end
+ */
+func (lp *Listpack) Append(data []byte) {
+	lp.Insert(len(lp.buf), data)
+}
+
+/**
+ * @description: add an entry at the beginning
+ */
+func (lp *Listpack) Prepend(data []byte) {
+	lp.Insert(0, data)
+}
+
+/**
+ * @description: delete the entry at off
+ * @return {*} offset of the entry which followed it, -1 if it was the last one
+ */
+func (lp *Listpack) Delete(off int) int {
+	_, _, next := lp.decode(off)
+	lp.buf = append(lp.buf[:off], lp.buf[next:]...)
+	lp.count--
+	if off >= len(lp.buf) {
+		return -1
+	}
+	return off
+}
+
+/**
+ * @description: replace the data of the entry at off
+ */
+func (lp *Listpack) Replace(off int, data []byte) {
+	lp.Delete(off)
+	lp.Insert(off, data)
+}
+
+/**
+ * @description: split the listpack, entries from off move to a new listpack
+ * @return {*} the new listpack
+ */
+func (lp *Listpack) Split(off int) *Listpack {
+	tail := &Listpack{buf: append([]byte(nil), lp.buf[off:]...)}
+	for p := 0; p >= 0 && p < len(tail.buf); p = tail.Next(p) {
+		tail.count++
+	}
+	lp.buf = lp.buf[:off:off]
+	lp.count -= tail.count
+	return tail
+}
+
+/**
+ * @description: move all entries of other to the end of lp
+ */
+func (lp *Listpack) Merge(other *Listpack) {
+	lp.buf = append(lp.buf, other.buf...)
+	lp.count += other.count
+	other.buf, other.count = nil, 0
+}
+
+/**
+ * @description: make an independent copy of the listpack
+ */
+func (lp *Listpack) Clone() *Listpack {
+	return &Listpack{buf: append([]byte(nil), lp.buf...), count: lp.count}
+}
@@ -0,0 +1,115 @@
+/*
+ * @Description:
+ * @Autor: HTmonster
+ * @Date: 2026-10-18 23:20:44
+ */
+package listpack
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+/**
+ * @description: collect the entries walking forward and backward
+ */
+func entries(t *testing.T, lp *Listpack) []string {
+	t.Helper()
+	var forward []string
+	for off := lp.First(); off != -1; off = lp.Next(off) {
+		forward = append(forward, string(lp.Get(off)))
+	}
+	var backward []string
+	for off := lp.Last(); off != -1; off = lp.Prev(off) {
+		backward = append([]string{string(lp.Get(off))}, backward...)
+	}
+	if strings.Join(forward, ",") != strings.Join(backward, ",") {
+		t.Fatalf("forward %q and backward %q walks differ", forward, backward)
+	}
+	if len(forward) != lp.Len() {
+		t.Fatalf("Len() = %d, walked %d entries", lp.Len(), len(forward))
+	}
+	return forward
+}
+
+func TestListpack(t *testing.T) {
+	lp := New()
+	if lp.First() != -1 || lp.Last() != -1 || lp.Seek(0) != -1 {
+		t.Fatal("empty listpack should have no entries")
+	}
+
+	lp.Append([]byte("b"))
+	lp.Prepend([]byte("a"))
+	lp.Append([]byte(""))
+	// a long entry needs multi-byte length and backlen
+	long := strings.Repeat("x", 300)
+	lp.Append([]byte(long))
+	lp.Insert(lp.Seek(2), []byte("c"))
+
+	want := []string{"a", "b", "c", "", long}
+	got := entries(t, lp)
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+	for i, w := range want {
+		if !lp.Equal(lp.Seek(i), []byte(w)) || !lp.Equal(lp.Seek(i-len(want)), []byte(w)) {
+			t.Errorf("entry %d should equal %q", i, w)
+		}
+	}
+
+	lp.Replace(lp.Seek(1), []byte("B"))
+	if next := lp.Delete(lp.Seek(0)); !lp.Equal(next, []byte("B")) {
+		t.Errorf("Delete should return the next entry")
+	}
+	if next := lp.Delete(lp.Seek(-1)); next != -1 {
+		t.Errorf("deleting the last entry should return -1, got %d", next)
+	}
+	if got := strings.Join(entries(t, lp), ","); got != "B,c," {
+		t.Errorf("got %q", got)
+	}
+	if lp.Size() != EntrySize(1)*2+EntrySize(0) {
+		t.Errorf("Size() = %d", lp.Size())
+	}
+}
+
+func TestListpackGetCopies(t *testing.T) {
+	lp := New()
+	lp.Append([]byte("abc"))
+	data := lp.Get(0)
+	lp.Replace(0, []byte("xyz"))
+	if !bytes.Equal(data, []byte("abc")) {
+		t.Errorf("Get should return a copy, got %q", data)
+	}
+}
+
+func TestListpackSplitMerge(t *testing.T) {
+	lp := New()
+	for i := 0; i < 100; i++ {
+		lp.Append([]byte(strconv.Itoa(i)))
+	}
+	tail := lp.Split(lp.Seek(40))
+	if lp.Len() != 40 || tail.Len() != 60 {
+		t.Fatalf("split into %d and %d entries", lp.Len(), tail.Len())
+	}
+	if !tail.Equal(tail.First(), []byte("40")) || !lp.Equal(lp.Last(), []byte("39")) {
+		t.Error("split at the wrong entry")
+	}
+	lp.Append([]byte("x"))
+	if !tail.Equal(tail.First(), []byte("40")) {
+		t.Error("appending after a split should not change the other part")
+	}
+	lp.Delete(lp.Last())
+
+	lp.Merge(tail)
+	got := entries(t, lp)
+	for i, s := range got {
+		if s != strconv.Itoa(i) {
+			t.Fatalf("entry %d is %q after merge", i, s)
+		}
+	}
+	if tail.Len() != 0 {
+		t.Error("merged listpack should be empty")
+	}
+}
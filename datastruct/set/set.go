@@ -0,0 +1,216 @@
+/*
+ * @Description: set, intset encoded while it only holds a few integers
+ * @Autor: HTmonster
+ * @Date: 2026-10-19 09:48:15
+ */
+package set
+
+import (
+	"strconv"
+
+	"github.com/HTmonster/redissgo/datastruct/dict"
+	"github.com/HTmonster/redissgo/datastruct/intset"
+)
+
+// encodings
+const (
+	EncodingIntset    = "intset"
+	EncodingHashtable = "hashtable"
+)
+
+// set of strings, not safe for concurrent use
+type Set struct {
+	is   *intset.IntSet   // nil after conversion
+	dict *dict.SimpleDict // member -> nil
+
+	maxIntsetEntries int // convert when there are more members
+}
+
+/**
+ * @description: make an empty intset encoded set
+ * @param {int} maxIntsetEntries
+ * @return {*}
+ */
+func MakeSet(maxIntsetEntries int) *Set {
+	return &Set{
+		is:               intset.New(),
+		maxIntsetEntries: maxIntsetEntries,
+	}
+}
+
+/**
+ * @description: parse a member which can be kept in an intset,
+ * only canonical integers like redis string2ll
+ * @param {string} member
+ * @return {*}
+ */
+func toInt(member string) (int64, bool) {
+	v, err := strconv.ParseInt(member, 10, 64)
+	if err != nil || strconv.FormatInt(v, 10) != member {
+		return 0, false
+	}
+	return v, true
+}
+
+/**
+ * @description: number of members
+ */
+func (s *Set) Len() int {
+	if s.is != nil {
+		return s.is.Len()
+	}
+	return s.dict.Len()
+}
+
+/**
+ * @description: name of the current encoding
+ */
+func (s *Set) Encoding() string {
+	if s.is != nil {
+		return EncodingIntset
+	}
+	return EncodingHashtable
+}
+
+/**
+ * @description: move all members from the intset into a dictionary
+ */
+func (s *Set) convert() {
+	d := dict.MakeSimpleDict()
+	s.is.ForEach(func(v int64) bool {
+		d.Put(strconv.FormatInt(v, 10), nil)
+		return true
+	})
+	s.is, s.dict = nil, d
+}
+
+/**
+ * @description: add a member
+ * @param {string} member
+ * @return {*} 1 if added, 0 if it exists
+ */
+func (s *Set) Add(member string) int {
+	if s.is != nil {
+		if v, ok := toInt(member); ok {
+			if !s.is.Add(v) {
+				return 0
+			}
+			if s.is.Len() > s.maxIntsetEntries {
+				s.convert()
+			}
+			return 1
+		}
+		s.convert()
+	}
+	return s.dict.Put(member, nil)
+}
+
+/**
+ * @description: remove a member
+ * @param {string} member
+ * @return {*} 1 if removed, else 0
+ */
+func (s *Set) Remove(member string) int {
+	if s.is != nil {
+		if v, ok := toInt(member); ok && s.is.Remove(v) {
+			return 1
+		}
+		return 0
+	}
+	return s.dict.Remove(member)
+}
+
+/**
+ * @description: check whether the member is in the set
+ */
+func (s *Set) Contains(member string) bool {
+	if s.is != nil {
+		v, ok := toInt(member)
+		return ok && s.is.Contains(v)
+	}
+	_, ok := s.dict.Get(member)
+	return ok
+}
+
+/**
+ * @description: traversal the set, an intset is visited in ascending order
+ * @param {func(member string) bool} consumer returns false to stop
+ * @return {*}
+ */
+func (s *Set) ForEach(consumer func(member string) bool) {
+	if s.is != nil {
+		s.is.ForEach(func(v int64) bool {
+			return consumer(strconv.FormatInt(v, 10))
+		})
+		return
+	}
+	s.dict.ForEach(func(key string, value interface{}) bool {
+		return consumer(key)
+	})
+}
+
+/**
+ * @description: return all members
+ */
+func (s *Set) Members() []string {
+	members := make([]string, 0, s.Len())
+	s.ForEach(func(member string) bool {
+		members = append(members, member)
+		return true
+	})
+	return members
+}
+
+/**
+ * @description: return a random member
+ * @return {*} member, false if the set is empty
+ */
+func (s *Set) RandomMember() (string, bool) {
+	if s.is != nil {
+		if s.is.Len() == 0 {
+			return "", false
+		}
+		return strconv.FormatInt(s.is.Random(), 10), true
+	}
+	member, _, ok := s.dict.RandomEntry()
+	return member, ok
+}
+
+/**
+ * @description: make a copy of the set with the same encoding
+ */
+func (s *Set) Clone() *Set {
+	clone := &Set{maxIntsetEntries: s.maxIntsetEntries}
+	if s.is != nil {
+		clone.is = s.is.Clone()
+		return clone
+	}
+	clone.dict = dict.MakeSimpleDict()
+	s.dict.ForEach(func(key string, value interface{}) bool {
+		clone.dict.Put(key, nil)
+		return true
+	})
+	return clone
+}
+
+/**
+ * @description: incrementally iterate the set, an intset encoded set is
+ * returned in a single call
+ * @param {uint64} cursor 0 to start a new scan
+ * @param {int} count
+ * @param {func(member string)} consumer
+ * @return {*} next cursor, 0 when the scan is completed
+ */
+func (s *Set) Scan(cursor uint64, count int, consumer func(member string)) uint64 {
+	if s.is != nil {
+		s.ForEach(func(member string) bool {
+			consumer(member)
+			return true
+		})
+		return 0
+	}
+	return s.dict.Scan(cursor, count, func(key string, value interface{}) bool {
+		consumer(key)
+		return true
+	})
+}
@@ -0,0 +1,66 @@
+/*
+ * @Description:
+ * @Autor: HTmonster
+ * @Date: 2026-10-19 10:06:33
+ */
+package set
+
+import (
+	"strconv"
+	"testing"
+)
+
+func TestSetIntset(t *testing.T) {
+	s := MakeSet(512)
+	if s.Add("3") != 1 || s.Add("-1") != 1 || s.Add("3") != 0 {
+		t.Errorf("test add fail")
+	}
+	if s.Encoding() != EncodingIntset || s.Len() != 2 {
+		t.Errorf("test intset encoding fail: %s %d", s.Encoding(), s.Len())
+	}
+	if !s.Contains("3") || s.Contains("03") || s.Contains("x") {
+		t.Errorf("test contains fail")
+	}
+	// not canonical integers are kept as strings
+	if s.Remove("03") != 0 || s.Remove("3") != 1 || s.Len() != 1 {
+		t.Errorf("test remove fail")
+	}
+	if member, ok := s.RandomMember(); !ok || member != "-1" {
+		t.Errorf("test random member fail: %q", member)
+	}
+
+	s.Add("03")
+	if s.Encoding() != EncodingHashtable || !s.Contains("03") || !s.Contains("-1") || s.Len() != 2 {
+		t.Errorf("test convert by string fail: %s %v", s.Encoding(), s.Members())
+	}
+}
+
+func TestSetConvertByEntries(t *testing.T) {
+	s := MakeSet(16)
+	for i := 0; i < 16; i++ {
+		s.Add(strconv.Itoa(i))
+	}
+	clone := s.Clone()
+	if s.Encoding() != EncodingIntset {
+		t.Errorf("test convert too early")
+	}
+	s.Add("16")
+	if s.Encoding() != EncodingHashtable || s.Len() != 17 {
+		t.Errorf("test convert by entries fail: %s %d", s.Encoding(), s.Len())
+	}
+	for i := 0; i <= 16; i++ {
+		if !s.Contains(strconv.Itoa(i)) {
+			t.Errorf("test contains after convert: %d", i)
+		}
+	}
+
+	// clones are independent
+	if clone.Len() != 16 || clone.Encoding() != EncodingIntset {
+		t.Errorf("test clone fail: %d %s", clone.Len(), clone.Encoding())
+	}
+	clone = s.Clone()
+	s.Remove("0")
+	if !clone.Contains("0") || clone.Len() != 17 {
+		t.Errorf("test hashtable clone fail")
+	}
+}
@@ -0,0 +1,99 @@
+/*
+ * @Description: score and lexicographical ranges of a sorted set
+ * @Autor: HTmonster
+ * @Date: 2026-10-19 13:48:20
+ */
+package sortedset
+
+// range of elements in skiplist order
+type Range interface {
+	aboveMin(e *Element) bool // e is not before the min border
+	belowMax(e *Element) bool // e is not after the max border
+	isEmpty() bool            // no element can be in the range
+}
+
+//------------ score --------------
+
+// score border, e.g. 1.5 or (1.5
+type ScoreBorder struct {
+	Value   float64
+	Exclude bool
+}
+
+// range of scores
+type ScoreRange struct {
+	Min ScoreBorder
+	Max ScoreBorder
+}
+
+func (r *ScoreRange) aboveMin(e *Element) bool {
+	if r.Min.Exclude {
+		return e.Score > r.Min.Value
+	}
+	return e.Score >= r.Min.Value
+}
+
+func (r *ScoreRange) belowMax(e *Element) bool {
+	if r.Max.Exclude {
+		return e.Score < r.Max.Value
+	}
+	return e.Score <= r.Max.Value
+}
+
+func (r *ScoreRange) isEmpty() bool {
+	return r.Min.Value > r.Max.Value ||
+		(r.Min.Value == r.Max.Value && (r.Min.Exclude || r.Max.Exclude))
+}
+
+//------------ lex --------------
+
+// member border, e.g. [a (a - +
+type LexBorder struct {
+	Value   string
+	Exclude bool
+	Inf     int // -1 for "-", 1 for "+", the value is ignored
+}
+
+// range of members, only meaningful when all scores are the same
+type LexRange struct {
+	Min LexBorder
+	Max LexBorder
+}
+
+/**
+ * @description: compare a member with a border
+ * @return {*} -1, 0 or 1
+ */
+func (b *LexBorder) compare(member string) int {
+	if b.Inf != 0 {
+		return -b.Inf
+	}
+	switch {
+	case member < b.Value:
+		return -1
+	case member > b.Value:
+		return 1
+	}
+	return 0
+}
+
+func (r *LexRange) aboveMin(e *Element) bool {
+	cmp := r.Min.compare(e.Member)
+	return cmp > 0 || (cmp == 0 && !r.Min.Exclude)
+}
+
+func (r *LexRange) belowMax(e *Element) bool {
+	cmp := r.Max.compare(e.Member)
+	return cmp < 0 || (cmp == 0 && !r.Max.Exclude)
+}
+
+func (r *LexRange) isEmpty() bool {
+	if r.Min.Inf == 1 || r.Max.Inf == -1 {
+		return true
+	}
+	if r.Min.Inf == -1 || r.Max.Inf == 1 {
+		return false
+	}
+	return r.Min.Value > r.Max.Value ||
+		(r.Min.Value == r.Max.Value && (r.Min.Exclude || r.Max.Exclude))
+}
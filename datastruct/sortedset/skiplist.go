@@ -0,0 +1,277 @@
+/*
+ * @Description: skiplist ordered by score then member, with spans to compute ranks
+ * @Autor: HTmonster
+ * @Date: 2026-10-19 13:15:42
+ */
+package sortedset
+
+import (
+	"math/rand"
+)
+
+const (
+	maxLevel    = 32
+	levelFactor = 0.25 // probability to grow one more level
+)
+
+// member and its score
+type Element struct {
+	Member string
+	Score  float64
+}
+
+// level of a node
+type level struct {
+	forward *node
+	span    int64 // number of nodes skipped by forward
+}
+
+// skiplist node
+type node struct {
+	Element
+	backward *node
+	level    []level
+}
+
+// skiplist
+type skiplist struct {
+	header *node
+	tail   *node
+	length int64
+	level  int
+}
+
+/**
+ * @description: make a node with n levels
+ */
+func makeNode(n int, score float64, member string) *node {
+	return &node{
+		Element: Element{Member: member, Score: score},
+		level:   make([]level, n),
+	}
+}
+
+/**
+ * @description: make an empty skiplist
+ */
+func makeSkiplist() *skiplist {
+	return &skiplist{
+		header: makeNode(maxLevel, 0, ""),
+		level:  1,
+	}
+}
+
+/**
+ * @description: random level of a new node, higher levels are less likely
+ */
+func randomLevel() int {
+	n := 1
+	for n < maxLevel && rand.Float64() < levelFactor {
+		n++
+	}
+	return n
+}
+
+/**
+ * @description: order of elements, by score then by member
+ * @return {*} whether a is before (score, member)
+ */
+func (e *Element) less(score float64, member string) bool {
+	return e.Score < score || (e.Score == score && e.Member < member)
+}
+
+/**
+ * @description: insert a new element, the member must not exist
+ * @param {string} member
+ * @param {float64} score
+ * @return {*} the new node
+ */
+func (sl *skiplist) insert(member string, score float64) *node {
+	var update [maxLevel]*node
+	var rank [maxLevel]int64
+
+	// find the position on every level
+	x := sl.header
+	for i := sl.level - 1; i >= 0; i-- {
+		if i < sl.level-1 {
+			rank[i] = rank[i+1]
+		}
+		for x.level[i].forward != nil && x.level[i].forward.less(score, member) {
+			rank[i] += x.level[i].span
+			x = x.level[i].forward
+		}
+		update[i] = x
+	}
+
+	n := randomLevel()
+	if n > sl.level {
+		for i := sl.level; i < n; i++ {
+			rank[i] = 0
+			update[i] = sl.header
+			update[i].level[i].span = sl.length
+		}
+		sl.level = n
+	}
+
+	x = makeNode(n, score, member)
+	for i := 0; i < n; i++ {
+		x.level[i].forward = update[i].level[i].forward
+		update[i].level[i].forward = x
+		// rank[0] - rank[i] nodes are between update[i] and x
+		x.level[i].span = update[i].level[i].span - (rank[0] - rank[i])
+		update[i].level[i].span = rank[0] - rank[i] + 1
+	}
+	// higher levels skip one more node
+	for i := n; i < sl.level; i++ {
+		update[i].level[i].span++
+	}
+
+	if update[0] != sl.header {
+		x.backward = update[0]
+	}
+	if x.level[0].forward != nil {
+		x.level[0].forward.backward = x
+	} else {
+		sl.tail = x
+	}
+	sl.length++
+	return x
+}
+
+/**
+ * @description: unlink x, update holds its predecessors on every level
+ */
+func (sl *skiplist) removeNode(x *node, update []*node) {
+	for i := 0; i < sl.level; i++ {
+		if update[i].level[i].forward == x {
+			update[i].level[i].span += x.level[i].span - 1
+			update[i].level[i].forward = x.level[i].forward
+		} else {
+			update[i].level[i].span--
+		}
+	}
+	if x.level[0].forward != nil {
+		x.level[0].forward.backward = x.backward
+	} else {
+		sl.tail = x.backward
+	}
+	for sl.level > 1 && sl.header.level[sl.level-1].forward == nil {
+		sl.level--
+	}
+	sl.length--
+}
+
+/**
+ * @description: remove an element
+ * @return {*} true if found and removed
+ */
+func (sl *skiplist) remove(member string, score float64) bool {
+	update := make([]*node, maxLevel)
+	x := sl.header
+	for i := sl.level - 1; i >= 0; i-- {
+		for x.level[i].forward != nil && x.level[i].forward.less(score, member) {
+			x = x.level[i].forward
+		}
+		update[i] = x
+	}
+	x = x.level[0].forward
+	if x != nil && x.Score == score && x.Member == member {
+		sl.removeNode(x, update)
+		return true
+	}
+	return false
+}
+
+/**
+ * @description: 1-based rank of an element
+ * @return {*} 0 if not found
+ */
+func (sl *skiplist) getRank(member string, score float64) int64 {
+	var rank int64
+	x := sl.header
+	for i := sl.level - 1; i >= 0; i-- {
+		for x.level[i].forward != nil &&
+			(x.level[i].forward.less(score, member) || x.level[i].forward.Member == member && x.level[i].forward.Score == score) {
+			rank += x.level[i].span
+			x = x.level[i].forward
+		}
+		if x != sl.header && x.Member == member {
+			return rank
+		}
+	}
+	return 0
+}
+
+/**
+ * @description: node at a 1-based rank
+ * @return {*} nil if out of range
+ */
+func (sl *skiplist) getByRank(rank int64) *node {
+	if rank < 1 || rank > sl.length {
+		return nil
+	}
+	var traversed int64
+	x := sl.header
+	for i := sl.level - 1; i >= 0; i-- {
+		for x.level[i].forward != nil && traversed+x.level[i].span <= rank {
+			traversed += x.level[i].span
+			x = x.level[i].forward
+		}
+		if traversed == rank {
+			return x
+		}
+	}
+	return nil
+}
+
+/**
+ * @description: check whether some elements may be in the range
+ */
+func (sl *skiplist) hasInRange(r Range) bool {
+	if r.isEmpty() || sl.tail == nil {
+		return false
+	}
+	first := sl.header.level[0].forward
+	return r.aboveMin(&sl.tail.Element) && r.belowMax(&first.Element)
+}
+
+/**
+ * @description: first node in the range
+ * @return {*} nil if none
+ */
+func (sl *skiplist) firstInRange(r Range) *node {
+	if !sl.hasInRange(r) {
+		return nil
+	}
+	x := sl.header
+	for i := sl.level - 1; i >= 0; i-- {
+		for x.level[i].forward != nil && !r.aboveMin(&x.level[i].forward.Element) {
+			x = x.level[i].forward
+		}
+	}
+	x = x.level[0].forward
+	if !r.belowMax(&x.Element) {
+		return nil
+	}
+	return x
+}
+
+/**
+ * @description: last node in the range
+ * @return {*} nil if none
+ */
+func (sl *skiplist) lastInRange(r Range) *node {
+	if !sl.hasInRange(r) {
+		return nil
+	}
+	x := sl.header
+	for i := sl.level - 1; i >= 0; i-- {
+		for x.level[i].forward != nil && r.belowMax(&x.level[i].forward.Element) {
+			x = x.level[i].forward
+		}
+	}
+	if x == sl.header || !r.aboveMin(&x.Element) {
+		return nil
+	}
+	return x
+}
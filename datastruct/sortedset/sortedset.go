@@ -0,0 +1,250 @@
+/*
+ * @Description: sorted set, a dictionary for scores and a skiplist for order
+ * @Autor: HTmonster
+ * @Date: 2026-10-19 14:02:55
+ */
+package sortedset
+
+import (
+	"github.com/HTmonster/redissgo/datastruct/dict"
+)
+
+// sorted set, not safe for concurrent use
+type SortedSet struct {
+	dict     *dict.SimpleDict // member -> float64
+	skiplist *skiplist
+}
+
+/**
+ * @description: make an empty sorted set
+ * @return {*}
+ */
+func Make() *SortedSet {
+	return &SortedSet{
+		dict:     dict.MakeSimpleDict(),
+		skiplist: makeSkiplist(),
+	}
+}
+
+/**
+ * @description: number of members
+ */
+func (ss *SortedSet) Len() int64 {
+	return ss.skiplist.length
+}
+
+/**
+ * @description: add a member or update its score
+ * @param {string} member
+ * @param {float64} score
+ * @return {*} true if the member is new
+ */
+func (ss *SortedSet) Add(member string, score float64) bool {
+	if old, ok := ss.dict.Get(member); ok {
+		if old.(float64) != score {
+			ss.skiplist.remove(member, old.(float64))
+			ss.skiplist.insert(member, score)
+			ss.dict.Put(member, score)
+		}
+		return false
+	}
+	ss.skiplist.insert(member, score)
+	ss.dict.Put(member, score)
+	return true
+}
+
+/**
+ * @description: get the score of a member
+ * @return {*} score, exists or not
+ */
+func (ss *SortedSet) Score(member string) (float64, bool) {
+	score, ok := ss.dict.Get(member)
+	if !ok {
+		return 0, false
+	}
+	return score.(float64), true
+}
+
+/**
+ * @description: remove a member
+ * @return {*} true if removed
+ */
+func (ss *SortedSet) Remove(member string) bool {
+	score, ok := ss.dict.Get(member)
+	if !ok {
+		return false
+	}
+	ss.skiplist.remove(member, score.(float64))
+	ss.dict.Remove(member)
+	return true
+}
+
+/**
+ * @description: 0-based rank of a member
+ * @param {string} member
+ * @param {bool} desc rank from the highest score
+ * @return {*} rank, exists or not
+ */
+func (ss *SortedSet) Rank(member string, desc bool) (int64, bool) {
+	score, ok := ss.dict.Get(member)
+	if !ok {
+		return 0, false
+	}
+	rank := ss.skiplist.getRank(member, score.(float64))
+	if desc {
+		return ss.skiplist.length - rank, true
+	}
+	return rank - 1, true
+}
+
+/**
+ * @description: elements between two 0-based ranks
+ * @param {int64} start must be in [0, Len())
+ * @param {int64} stop must be in [start, Len())
+ * @param {bool} desc ranks from the highest score
+ * @return {*}
+ */
+func (ss *SortedSet) RangeByRank(start, stop int64, desc bool) []Element {
+	elements := make([]Element, 0, stop-start+1)
+	var x *node
+	if desc {
+		x = ss.skiplist.getByRank(ss.skiplist.length - start)
+	} else {
+		x = ss.skiplist.getByRank(start + 1)
+	}
+	for i := start; i <= stop && x != nil; i++ {
+		elements = append(elements, x.Element)
+		if desc {
+			x = x.backward
+		} else {
+			x = x.level[0].forward
+		}
+	}
+	return elements
+}
+
+/**
+ * @description: number of elements in the range
+ */
+func (ss *SortedSet) Count(r Range) int64 {
+	first := ss.skiplist.firstInRange(r)
+	if first == nil {
+		return 0
+	}
+	last := ss.skiplist.lastInRange(r)
+	return ss.skiplist.getRank(last.Member, last.Score) - ss.skiplist.getRank(first.Member, first.Score) + 1
+}
+
+/**
+ * @description: elements in the range
+ * @param {Range} r
+ * @param {int64} offset elements to skip, negative skips everything
+ * @param {int64} limit negative means no limit
+ * @param {bool} desc from the max border
+ * @return {*}
+ */
+func (ss *SortedSet) Range(r Range, offset, limit int64, desc bool) []Element {
+	if offset < 0 {
+		return nil
+	}
+	var x *node
+	if desc {
+		x = ss.skiplist.lastInRange(r)
+	} else {
+		x = ss.skiplist.firstInRange(r)
+	}
+	next := func(x *node) *node {
+		if desc {
+			return x.backward
+		}
+		return x.level[0].forward
+	}
+	for ; x != nil && offset > 0; offset-- {
+		x = next(x)
+	}
+
+	var elements []Element
+	for ; x != nil && limit != 0; limit-- {
+		if desc && !r.aboveMin(&x.Element) || !desc && !r.belowMax(&x.Element) {
+			break
+		}
+		elements = append(elements, x.Element)
+		x = next(x)
+	}
+	return elements
+}
+
+/**
+ * @description: remove and return the elements with the lowest or highest scores
+ * @param {int64} count
+ * @param {bool} max pop the highest scores
+ * @return {*}
+ */
+func (ss *SortedSet) Pop(count int64, max bool) []Element {
+	if count > ss.skiplist.length {
+		count = ss.skiplist.length
+	}
+	elements := make([]Element, 0, count)
+	for i := int64(0); i < count; i++ {
+		var x *node
+		if max {
+			x = ss.skiplist.tail
+		} else {
+			x = ss.skiplist.header.level[0].forward
+		}
+		elements = append(elements, x.Element)
+		ss.Remove(x.Member)
+	}
+	return elements
+}
+
+/**
+ * @description: traversal the elements from the lowest score
+ * @param {func(e Element) bool} consumer returns false to stop
+ * @return {*}
+ */
+func (ss *SortedSet) ForEach(consumer func(e Element) bool) {
+	for x := ss.skiplist.header.level[0].forward; x != nil; x = x.level[0].forward {
+		if !consumer(x.Element) {
+			return
+		}
+	}
+}
+
+/**
+ * @description: return a random element
+ * @return {*} element, false if the set is empty
+ */
+func (ss *SortedSet) RandomElement() (Element, bool) {
+	member, score, ok := ss.dict.RandomEntry()
+	if !ok {
+		return Element{}, false
+	}
+	return Element{Member: member, Score: score.(float64)}, true
+}
+
+/**
+ * @description: make an independent copy of the sorted set
+ */
+func (ss *SortedSet) Clone() *SortedSet {
+	clone := Make()
+	ss.ForEach(func(e Element) bool {
+		clone.Add(e.Member, e.Score)
+		return true
+	})
+	return clone
+}
+
+/**
+ * @description: incrementally iterate the members and their scores
+ * @param {uint64} cursor 0 to start a new scan
+ * @param {int} count
+ * @param {func(e Element)} consumer
+ * @return {*} next cursor, 0 when the scan is completed
+ */
+func (ss *SortedSet) Scan(cursor uint64, count int, consumer func(e Element)) uint64 {
+	return ss.dict.Scan(cursor, count, func(key string, value interface{}) bool {
+		consumer(Element{Member: key, Score: value.(float64)})
+		return true
+	})
+}
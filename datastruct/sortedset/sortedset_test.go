@@ -0,0 +1,181 @@
+/*
+ * @Description:
+ * @Autor: HTmonster
+ * @Date: 2026-10-19 14:40:18
+ */
+package sortedset
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"strconv"
+	"testing"
+)
+
+/**
+ * @description: elements of the model in sorted set order
+ */
+func sortedModel(model map[string]float64) []Element {
+	elements := make([]Element, 0, len(model))
+	for member, score := range model {
+		elements = append(elements, Element{Member: member, Score: score})
+	}
+	sort.Slice(elements, func(i, j int) bool {
+		return elements[i].less(elements[j].Score, elements[j].Member)
+	})
+	return elements
+}
+
+func TestSortedSetModel(t *testing.T) {
+	ss := Make()
+	model := make(map[string]float64)
+	for i := 0; i < 3000; i++ {
+		member := "m" + strconv.Itoa(rand.Intn(300))
+		if rand.Intn(4) == 0 {
+			_, exists := model[member]
+			if ss.Remove(member) != exists {
+				t.Fatalf("remove %s: exists %v", member, exists)
+			}
+			delete(model, member)
+			continue
+		}
+		score := float64(rand.Intn(50))
+		_, exists := model[member]
+		if ss.Add(member, score) == exists {
+			t.Fatalf("add %s: exists %v", member, exists)
+		}
+		model[member] = score
+	}
+
+	want := sortedModel(model)
+	if ss.Len() != int64(len(want)) {
+		t.Fatalf("test len: got %d, want %d", ss.Len(), len(want))
+	}
+	for i, e := range want {
+		if rank, ok := ss.Rank(e.Member, false); !ok || rank != int64(i) {
+			t.Fatalf("rank %s: got %d %v, want %d", e.Member, rank, ok, i)
+		}
+		if rank, _ := ss.Rank(e.Member, true); rank != int64(len(want)-1-i) {
+			t.Fatalf("rev rank %s: got %d", e.Member, rank)
+		}
+		if score, ok := ss.Score(e.Member); !ok || score != e.Score {
+			t.Fatalf("score %s: got %v", e.Member, score)
+		}
+	}
+
+	// ranges by rank in both directions
+	n := int64(len(want))
+	got := ss.RangeByRank(0, n-1, false)
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("range by rank at %d: got %v, want %v", i, got[i], want[i])
+		}
+	}
+	got = ss.RangeByRank(1, 3, true)
+	for i, e := range got {
+		if e != want[len(want)-2-i] {
+			t.Fatalf("rev range by rank at %d: got %v", i, e)
+		}
+	}
+
+	// ranges by score
+	r := &ScoreRange{Min: ScoreBorder{Value: 10}, Max: ScoreBorder{Value: 20, Exclude: true}}
+	var inRange []Element
+	for _, e := range want {
+		if e.Score >= 10 && e.Score < 20 {
+			inRange = append(inRange, e)
+		}
+	}
+	if ss.Count(r) != int64(len(inRange)) {
+		t.Fatalf("count: got %d, want %d", ss.Count(r), len(inRange))
+	}
+	got = ss.Range(r, 0, -1, false)
+	if len(got) != len(inRange) || (len(got) > 0 && got[0] != inRange[0]) {
+		t.Fatalf("range by score: got %d elements", len(got))
+	}
+	got = ss.Range(r, 1, 2, true)
+	if len(inRange) >= 3 && (len(got) != 2 || got[0] != inRange[len(inRange)-2]) {
+		t.Fatalf("rev range by score with limit: got %v", got)
+	}
+	if len(ss.Range(r, -1, -1, false)) != 0 {
+		t.Fatalf("negative offset should return nothing")
+	}
+
+	// pop
+	popped := ss.Pop(2, false)
+	if len(popped) != 2 || popped[0] != want[0] || popped[1] != want[1] {
+		t.Fatalf("pop min: got %v", popped)
+	}
+	popped = ss.Pop(1, true)
+	if len(popped) != 1 || popped[0] != want[len(want)-1] {
+		t.Fatalf("pop max: got %v", popped)
+	}
+	if ss.Len() != n-3 {
+		t.Fatalf("len after pop: %d", ss.Len())
+	}
+}
+
+func TestSortedSetLex(t *testing.T) {
+	ss := Make()
+	for _, member := range []string{"a", "b", "c", "d", "e"} {
+		ss.Add(member, 0)
+	}
+	cases := []struct {
+		r    LexRange
+		want string
+	}{
+		{LexRange{Min: LexBorder{Inf: -1}, Max: LexBorder{Inf: 1}}, "abcde"},
+		{LexRange{Min: LexBorder{Value: "b"}, Max: LexBorder{Value: "d"}}, "bcd"},
+		{LexRange{Min: LexBorder{Value: "b", Exclude: true}, Max: LexBorder{Value: "d", Exclude: true}}, "c"},
+		{LexRange{Min: LexBorder{Value: "bb"}, Max: LexBorder{Inf: 1}}, "cde"},
+		{LexRange{Min: LexBorder{Inf: 1}, Max: LexBorder{Inf: -1}}, ""},
+		{LexRange{Min: LexBorder{Value: "d"}, Max: LexBorder{Value: "b"}}, ""},
+	}
+	for _, c := range cases {
+		var got string
+		for _, e := range ss.Range(&c.r, 0, -1, false) {
+			got += e.Member
+		}
+		if got != c.want || ss.Count(&c.r) != int64(len(c.want)) {
+			t.Errorf("lex range %+v: got %q, count %d, want %q", c.r, got, ss.Count(&c.r), c.want)
+		}
+	}
+}
+
+func TestSortedSetInf(t *testing.T) {
+	ss := Make()
+	ss.Add("low", math.Inf(-1))
+	ss.Add("mid", 0)
+	ss.Add("high", math.Inf(1))
+	r := &ScoreRange{Min: ScoreBorder{Value: math.Inf(-1)}, Max: ScoreBorder{Value: math.Inf(1)}}
+	if ss.Count(r) != 3 {
+		t.Errorf("test inf count: %d", ss.Count(r))
+	}
+	r.Min.Exclude, r.Max.Exclude = true, true
+	if got := ss.Range(r, 0, -1, false); len(got) != 1 || got[0].Member != "mid" {
+		t.Errorf("test exclusive inf range: %v", got)
+	}
+	if e, ok := ss.RandomElement(); !ok || e.Member == "" {
+		t.Errorf("test random element: %v", e)
+	}
+}
+
+func TestSortedSetClone(t *testing.T) {
+	ss := Make()
+	for i := 0; i < 100; i++ {
+		ss.Add(strconv.Itoa(i), float64(i%10))
+	}
+	clone := ss.Clone()
+	ss.Remove("0")
+	ss.Add("1", 100)
+	if clone.Len() != 100 {
+		t.Errorf("test clone len fail: %d", clone.Len())
+	}
+	if score, ok := clone.Score("1"); !ok || score != 1 {
+		t.Errorf("test clone score fail: %v", score)
+	}
+	if rank, _ := clone.Rank("0", false); rank != 0 {
+		t.Errorf("test clone rank fail: %d", rank)
+	}
+}
@@ -0,0 +1,406 @@
+/*
+ * @Description: consumer groups of a stream
+ * @Autor: HTmonster
+ * @Date: 2026-10-19 21:36:50
+ */
+package stream
+
+import (
+	"sort"
+
+	"github.com/HTmonster/redissgo/datastruct/sortedset"
+)
+
+// entries read by a group is unknown
+const InvalidEntriesRead = -1
+
+// entry delivered to a consumer but not acknowledged yet
+type PendingEntry struct {
+	ID            ID
+	Consumer      *Consumer
+	DeliveryTime  int64 // unix time in milliseconds
+	DeliveryCount int64
+}
+
+// consumer of a group
+type Consumer struct {
+	Name       string
+	SeenTime   int64 // last attempted interaction
+	ActiveTime int64 // last successful interaction, -1 if never
+	pending    *pendingList
+}
+
+// consumer group
+type Group struct {
+	Name        string
+	LastID      ID    // ID of the last entry delivered
+	EntriesRead int64 // logical position of LastID, InvalidEntriesRead if unknown
+	pending     *pendingList
+	consumers   map[string]*Consumer
+}
+
+//------------ pending entries list --------------
+
+// pending entries ordered by ID, in a sorted set where all scores are 0
+// and members are the big endian IDs
+type pendingList struct {
+	order   *sortedset.SortedSet
+	entries map[ID]*PendingEntry
+}
+
+func makePendingList() *pendingList {
+	return &pendingList{
+		order:   sortedset.Make(),
+		entries: make(map[ID]*PendingEntry),
+	}
+}
+
+func (l *pendingList) add(p *PendingEntry) {
+	l.order.Add(p.ID.key(), 0)
+	l.entries[p.ID] = p
+}
+
+func (l *pendingList) remove(id ID) {
+	l.order.Remove(id.key())
+	delete(l.entries, id)
+}
+
+/**
+ * @description: pending entries with IDs between start and end, both included
+ * @param {int} count max number of entries, 0 for no limit
+ */
+func (l *pendingList) rangeOf(start, end ID, count int) []*PendingEntry {
+	r := &sortedset.LexRange{
+		Min: sortedset.LexBorder{Value: start.key()},
+		Max: sortedset.LexBorder{Value: end.key()},
+	}
+	limit := int64(count)
+	if count == 0 {
+		limit = -1
+	}
+	elements := l.order.Range(r, 0, limit, false)
+	pending := make([]*PendingEntry, len(elements))
+	for i, e := range elements {
+		pending[i] = l.entries[idOfKey(e.Member)]
+	}
+	return pending
+}
+
+//------------ groups --------------
+
+/**
+ * @description: create a consumer group
+ * @param {string} name
+ * @param {ID} lastID entries after it are delivered
+ * @param {int64} entriesRead logical position of lastID
+ * @return {*} group, false if it exists already
+ */
+func (s *Stream) CreateGroup(name string, lastID ID, entriesRead int64) (*Group, bool) {
+	if g, ok := s.groups[name]; ok {
+		return g, false
+	}
+	g := &Group{
+		Name:        name,
+		LastID:      lastID,
+		EntriesRead: entriesRead,
+		pending:     makePendingList(),
+		consumers:   make(map[string]*Consumer),
+	}
+	s.groups[name] = g
+	return g, true
+}
+
+/**
+ * @description: get a consumer group
+ * @return {*} nil if it does not exist
+ */
+func (s *Stream) Group(name string) *Group {
+	return s.groups[name]
+}
+
+/**
+ * @description: remove a consumer group
+ * @return {*} false if it does not exist
+ */
+func (s *Stream) DestroyGroup(name string) bool {
+	if _, ok := s.groups[name]; !ok {
+		return false
+	}
+	delete(s.groups, name)
+	return true
+}
+
+/**
+ * @description: consumer groups ordered by name
+ */
+func (s *Stream) Groups() []*Group {
+	groups := make([]*Group, 0, len(s.groups))
+	for _, g := range s.groups {
+		groups = append(groups, g)
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		return groups[i].Name < groups[j].Name
+	})
+	return groups
+}
+
+/**
+ * @description: whether entries from start to the end have been deleted by Delete
+ */
+func (s *Stream) hasTombstones(start ID) bool {
+	if s.length == 0 || s.maxDeletedID.IsZero() {
+		return false
+	}
+	return !s.maxDeletedID.Less(start)
+}
+
+/**
+ * @description: logical position of an ID, the number of entries added
+ *	up to it, like streamEstimateDistanceFromFirstEverEntry of redis
+ * @return {*} InvalidEntriesRead if it cannot be known
+ */
+func (s *Stream) EstimateEntriesRead(id ID) int64 {
+	if s.entriesAdded == 0 {
+		return 0
+	}
+	cmpLast := id.Compare(s.lastID)
+	if s.length == 0 && cmpLast <= 0 {
+		return s.entriesAdded
+	}
+	if cmpLast == 0 {
+		return s.entriesAdded
+	}
+	if cmpLast > 0 {
+		return InvalidEntriesRead
+	}
+	// no fragmentation ahead
+	if s.maxDeletedID.IsZero() || s.maxDeletedID.Less(s.firstID) {
+		switch id.Compare(s.firstID) {
+		case -1:
+			return s.entriesAdded - s.length
+		case 0:
+			return s.entriesAdded - s.length + 1
+		}
+	}
+	return InvalidEntriesRead
+}
+
+/**
+ * @description: number of entries not delivered to the group yet
+ * @return {*} lag, false if it cannot be known
+ */
+func (s *Stream) Lag(g *Group) (int64, bool) {
+	if s.entriesAdded == 0 {
+		return 0, true
+	}
+	if g.EntriesRead != InvalidEntriesRead && !s.hasTombstones(g.LastID) {
+		return s.entriesAdded - g.EntriesRead, true
+	}
+	if read := s.EstimateEntriesRead(g.LastID); read != InvalidEntriesRead {
+		return s.entriesAdded - read, true
+	}
+	return 0, false
+}
+
+/**
+ * @description: move the last delivered ID of the group forward
+ * @param {*Group} g
+ * @param {ID} id delivered entry
+ * @return {*}
+ */
+func (s *Stream) Advance(g *Group, id ID) {
+	if !g.LastID.Less(id) {
+		return
+	}
+	if g.EntriesRead != InvalidEntriesRead && !s.hasTombstones(id) {
+		g.EntriesRead++
+	} else if s.entriesAdded > 0 {
+		g.EntriesRead = s.EstimateEntriesRead(id)
+	}
+	g.LastID = id
+}
+
+/**
+ * @description: make an independent copy of the group, its consumers
+ *	and pending entries
+ */
+func (g *Group) clone() *Group {
+	clone := &Group{
+		Name:        g.Name,
+		LastID:      g.LastID,
+		EntriesRead: g.EntriesRead,
+		pending:     makePendingList(),
+		consumers:   make(map[string]*Consumer, len(g.consumers)),
+	}
+	for name, c := range g.consumers {
+		clone.consumers[name] = &Consumer{
+			Name:       c.Name,
+			SeenTime:   c.SeenTime,
+			ActiveTime: c.ActiveTime,
+			pending:    makePendingList(),
+		}
+	}
+	for _, p := range g.pending.rangeOf(ID{}, MaxID, 0) {
+		copied := *p
+		copied.Consumer = clone.consumers[p.Consumer.Name]
+		clone.pending.add(&copied)
+		copied.Consumer.pending.add(&copied)
+	}
+	return clone
+}
+
+//------------ consumers --------------
+
+/**
+ * @description: get a consumer
+ * @return {*} nil if it does not exist
+ */
+func (g *Group) Consumer(name string) *Consumer {
+	return g.consumers[name]
+}
+
+/**
+ * @description: create a consumer
+ * @param {string} name
+ * @param {int64} now unix time in milliseconds
+ * @return {*} consumer, false if it exists already
+ */
+func (g *Group) CreateConsumer(name string, now int64) (*Consumer, bool) {
+	if c, ok := g.consumers[name]; ok {
+		return c, false
+	}
+	c := &Consumer{
+		Name:       name,
+		SeenTime:   now,
+		ActiveTime: -1,
+		pending:    makePendingList(),
+	}
+	g.consumers[name] = c
+	return c, true
+}
+
+/**
+ * @description: remove a consumer and its pending entries
+ * @return {*} number of pending entries of the consumer, false if it does not exist
+ */
+func (g *Group) DeleteConsumer(name string) (int64, bool) {
+	c, ok := g.consumers[name]
+	if !ok {
+		return 0, false
+	}
+	for id := range c.pending.entries {
+		g.pending.remove(id)
+	}
+	delete(g.consumers, name)
+	return int64(len(c.pending.entries)), true
+}
+
+/**
+ * @description: consumers ordered by name
+ */
+func (g *Group) Consumers() []*Consumer {
+	consumers := make([]*Consumer, 0, len(g.consumers))
+	for _, c := range g.consumers {
+		consumers = append(consumers, c)
+	}
+	sort.Slice(consumers, func(i, j int) bool {
+		return consumers[i].Name < consumers[j].Name
+	})
+	return consumers
+}
+
+/**
+ * @description: number of consumers
+ */
+func (g *Group) ConsumerCount() int {
+	return len(g.consumers)
+}
+
+/**
+ * @description: number of entries pending for the consumer
+ */
+func (c *Consumer) PendingLen() int64 {
+	return int64(len(c.pending.entries))
+}
+
+/**
+ * @description: pending entries of the consumer with IDs between start and end
+ * @param {int} count max number of entries, 0 for no limit
+ */
+func (c *Consumer) PendingRange(start, end ID, count int) []*PendingEntry {
+	return c.pending.rangeOf(start, end, count)
+}
+
+//------------ pending entries --------------
+
+/**
+ * @description: number of pending entries
+ */
+func (g *Group) PendingLen() int64 {
+	return int64(len(g.pending.entries))
+}
+
+/**
+ * @description: get a pending entry
+ * @return {*} nil if the entry is not pending
+ */
+func (g *Group) Pending(id ID) *PendingEntry {
+	return g.pending.entries[id]
+}
+
+/**
+ * @description: pending entries with IDs between start and end, both included
+ * @param {int} count max number of entries, 0 for no limit
+ */
+func (g *Group) PendingRange(start, end ID, count int) []*PendingEntry {
+	return g.pending.rangeOf(start, end, count)
+}
+
+/**
+ * @description: smallest and greatest IDs of the pending entries,
+ *	the group must have pending entries
+ */
+func (g *Group) PendingBounds() (ID, ID) {
+	first := g.pending.order.RangeByRank(0, 0, false)
+	last := g.pending.order.RangeByRank(0, 0, true)
+	return idOfKey(first[0].Member), idOfKey(last[0].Member)
+}
+
+/**
+ * @description: make an entry pending for a consumer, it is added to
+ *	the list or taken from its previous consumer
+ * @param {ID} id
+ * @param {*Consumer} c
+ * @param {int64} deliveryTime unix time in milliseconds
+ * @return {*} the pending entry, a new one is delivered once
+ */
+func (g *Group) Assign(id ID, c *Consumer, deliveryTime int64) *PendingEntry {
+	p, ok := g.pending.entries[id]
+	if !ok {
+		p = &PendingEntry{ID: id, DeliveryCount: 1}
+		g.pending.add(p)
+	}
+	if p.Consumer != c {
+		if p.Consumer != nil {
+			p.Consumer.pending.remove(id)
+		}
+		p.Consumer = c
+		c.pending.add(p)
+	}
+	p.DeliveryTime = deliveryTime
+	return p
+}
+
+/**
+ * @description: acknowledge an entry, it is not pending anymore
+ * @return {*} false if it was not pending
+ */
+func (g *Group) Ack(id ID) bool {
+	p, ok := g.pending.entries[id]
+	if !ok {
+		return false
+	}
+	g.pending.remove(id)
+	p.Consumer.pending.remove(id)
+	return true
+}
@@ -0,0 +1,124 @@
+/*
+ * @Description:
+ * @Autor: HTmonster
+ * @Date: 2026-10-19 21:58:24
+ */
+package stream
+
+import (
+	"testing"
+)
+
+func TestPending(t *testing.T) {
+	s := Make(100, 0)
+	for i := 1; i <= 5; i++ {
+		s.Add(ID{Ms: uint64(i)}, fields(i))
+	}
+	g, _ := s.CreateGroup("g", ID{}, 0)
+	if _, created := s.CreateGroup("g", ID{}, 0); created {
+		t.Error("group exists already")
+	}
+	alice, _ := g.CreateConsumer("alice", 100)
+	bob, _ := g.CreateConsumer("bob", 100)
+	for i := 1; i <= 4; i++ {
+		c := alice
+		if i%2 == 0 {
+			c = bob
+		}
+		g.Assign(ID{Ms: uint64(i)}, c, 100)
+		s.Advance(g, ID{Ms: uint64(i)})
+	}
+	if g.PendingLen() != 4 || alice.PendingLen() != 2 || g.LastID != (ID{Ms: 4}) || g.EntriesRead != 4 {
+		t.Fatalf("pending %d, alice %d, last %v, read %d", g.PendingLen(), alice.PendingLen(), g.LastID, g.EntriesRead)
+	}
+	if lag, ok := s.Lag(g); !ok || lag != 1 {
+		t.Errorf("lag %d %v", lag, ok)
+	}
+
+	// claim an entry of alice
+	p := g.Assign(ID{Ms: 3}, bob, 200)
+	if p.Consumer != bob || p.DeliveryTime != 200 || alice.PendingLen() != 1 || bob.PendingLen() != 3 {
+		t.Errorf("claim: %+v", p)
+	}
+	pending := g.PendingRange(ID{Ms: 2}, MaxID, 2)
+	if len(pending) != 2 || pending[0].ID != (ID{Ms: 2}) || pending[1].ID != (ID{Ms: 3}) {
+		t.Errorf("pending range: %v", pending)
+	}
+	if got := bob.PendingRange(ID{}, MaxID, 0); len(got) != 3 {
+		t.Errorf("pending of bob: %v", got)
+	}
+
+	if !g.Ack(ID{Ms: 2}) || g.Ack(ID{Ms: 2}) || bob.PendingLen() != 2 {
+		t.Error("ack")
+	}
+	if n, ok := g.DeleteConsumer("bob"); !ok || n != 2 || g.PendingLen() != 1 {
+		t.Errorf("delete consumer: %d %v, pending %d", n, ok, g.PendingLen())
+	}
+	if consumers := g.Consumers(); len(consumers) != 1 || consumers[0] != alice {
+		t.Errorf("consumers: %v", consumers)
+	}
+	if !s.DestroyGroup("g") || s.Group("g") != nil || len(s.Groups()) != 0 {
+		t.Error("destroy group")
+	}
+}
+
+func TestLag(t *testing.T) {
+	s := Make(100, 0)
+	g, _ := s.CreateGroup("g", ID{}, InvalidEntriesRead)
+	if lag, ok := s.Lag(g); !ok || lag != 0 {
+		t.Errorf("lag of an empty stream: %d", lag)
+	}
+	for i := 1; i <= 5; i++ {
+		s.Add(ID{Ms: uint64(i)}, fields(i))
+	}
+	if lag, ok := s.Lag(g); !ok || lag != 5 {
+		t.Errorf("lag before the first entry: %d %v", lag, ok)
+	}
+	s.Advance(g, ID{Ms: 1})
+	if g.EntriesRead != 1 {
+		t.Errorf("entries read: %d", g.EntriesRead)
+	}
+
+	// a deletion ahead of the group makes the lag unknown
+	s.Delete(ID{Ms: 3})
+	g.EntriesRead = InvalidEntriesRead
+	if _, ok := s.Lag(g); ok {
+		t.Error("lag with tombstones ahead should be unknown")
+	}
+	s.Advance(g, ID{Ms: 5})
+	if lag, ok := s.Lag(g); !ok || lag != 0 || g.EntriesRead != 5 {
+		t.Errorf("lag at the last entry: %d %v, read %d", lag, ok, g.EntriesRead)
+	}
+	if read := s.EstimateEntriesRead(ID{Ms: 6}); read != InvalidEntriesRead {
+		t.Errorf("future ID: %d", read)
+	}
+}
+
+func TestClone(t *testing.T) {
+	s := Make(2, 0)
+	for i := 1; i <= 5; i++ {
+		s.Add(ID{Ms: uint64(i)}, fields(i))
+	}
+	g, _ := s.CreateGroup("g", ID{}, 0)
+	alice, _ := g.CreateConsumer("alice", 100)
+	g.Assign(ID{Ms: 1}, alice, 100)
+	g.Assign(ID{Ms: 2}, alice, 100)
+
+	clone := s.Clone()
+	s.Delete(ID{Ms: 1})
+	s.Add(ID{Ms: 6}, fields(6))
+	g.Ack(ID{Ms: 1})
+	alice.SeenTime = 200
+
+	if clone.Len() != 5 || clone.LastID() != (ID{Ms: 5}) || !clone.Exists(ID{Ms: 1}) {
+		t.Errorf("clone: len %d, last %v", clone.Len(), clone.LastID())
+	}
+	cg := clone.Group("g")
+	if cg == nil || cg.PendingLen() != 2 {
+		t.Fatal("groups should be cloned")
+	}
+	ca := cg.Consumer("alice")
+	if ca == alice || ca.SeenTime != 100 || ca.PendingLen() != 2 || cg.Pending(ID{Ms: 1}).Consumer != ca {
+		t.Errorf("consumers should be cloned: %+v", ca)
+	}
+}
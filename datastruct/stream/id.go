@@ -0,0 +1,107 @@
+/*
+ * @Description: stream entry IDs
+ * @Autor: HTmonster
+ * @Date: 2026-10-19 20:41:37
+ */
+package stream
+
+import (
+	"encoding/binary"
+	"math"
+	"strconv"
+)
+
+// ID of a stream entry, <milliseconds>-<sequence number>
+type ID struct {
+	Ms  uint64
+	Seq uint64
+}
+
+// the greatest possible ID
+var MaxID = ID{Ms: math.MaxUint64, Seq: math.MaxUint64}
+
+/**
+ * @description: format as <ms>-<seq>
+ */
+func (id ID) String() string {
+	buf := strconv.AppendUint(nil, id.Ms, 10)
+	buf = append(buf, '-')
+	return string(strconv.AppendUint(buf, id.Seq, 10))
+}
+
+/**
+ * @description: compare two IDs
+ * @return {*} -1, 0 or 1
+ */
+func (id ID) Compare(other ID) int {
+	switch {
+	case id.Ms < other.Ms:
+		return -1
+	case id.Ms > other.Ms:
+		return 1
+	case id.Seq < other.Seq:
+		return -1
+	case id.Seq > other.Seq:
+		return 1
+	}
+	return 0
+}
+
+/**
+ * @description: whether id is smaller than other
+ */
+func (id ID) Less(other ID) bool {
+	return id.Compare(other) < 0
+}
+
+/**
+ * @description: whether id is 0-0
+ */
+func (id ID) IsZero() bool {
+	return id.Ms == 0 && id.Seq == 0
+}
+
+/**
+ * @description: the next ID
+ * @return {*} next ID, false if id is MaxID
+ */
+func (id ID) Incr() (ID, bool) {
+	switch {
+	case id.Seq < math.MaxUint64:
+		return ID{Ms: id.Ms, Seq: id.Seq + 1}, true
+	case id.Ms < math.MaxUint64:
+		return ID{Ms: id.Ms + 1}, true
+	}
+	return id, false
+}
+
+/**
+ * @description: the previous ID
+ * @return {*} previous ID, false if id is 0-0
+ */
+func (id ID) Decr() (ID, bool) {
+	switch {
+	case id.Seq > 0:
+		return ID{Ms: id.Ms, Seq: id.Seq - 1}, true
+	case id.Ms > 0:
+		return ID{Ms: id.Ms - 1, Seq: math.MaxUint64}, true
+	}
+	return id, false
+}
+
+/**
+ * @description: big endian encoding, ordered like the IDs
+ */
+func (id ID) key() string {
+	var buf [16]byte
+	binary.BigEndian.PutUint64(buf[:8], id.Ms)
+	binary.BigEndian.PutUint64(buf[8:], id.Seq)
+	return string(buf[:])
+}
+
+/**
+ * @description: reverse of key
+ */
+func idOfKey(key string) ID {
+	return ID{Ms: binary.BigEndian.Uint64([]byte(key[:8])), Seq: binary.BigEndian.Uint64([]byte(key[8:]))}
+}
@@ -0,0 +1,525 @@
+/*
+ * @Description: stream, entries packed in listpack nodes ordered by ID
+ * @Autor: HTmonster
+ * @Date: 2026-10-19 20:58:12
+ */
+package stream
+
+import (
+	"encoding/binary"
+	"sort"
+
+	"github.com/HTmonster/redissgo/datastruct/listpack"
+)
+
+// Node layout, one listpack entry for each item:
+//	<flags> <ms delta> <seq> <number of fields> <field> <value> ...
+// ms delta is relative to the master ID of the node, the ID of the first
+// entry added to it. Like redis, deleted entries are only flagged and
+// the node is removed once all its entries are deleted.
+
+const flagDeleted = 1
+
+// node of a stream
+type node struct {
+	master  ID
+	lp      *listpack.Listpack
+	count   int // entries, including the deleted ones
+	deleted int
+}
+
+// entry of a stream
+type Entry struct {
+	ID     ID
+	Fields [][]byte // field, value, field, value ...
+}
+
+// stream, not safe for concurrent use
+type Stream struct {
+	// ordered by master ID, like the radix tree of redis
+	nodes []*node
+
+	length       int64
+	lastID       ID    // ID of the last added entry
+	firstID      ID    // ID of the first entry, 0-0 if empty
+	maxDeletedID ID    // greatest ID deleted by XDEL
+	entriesAdded int64 // entries ever added
+
+	groups map[string]*Group
+
+	nodeMaxEntries int
+	nodeMaxBytes   int
+}
+
+/**
+ * @description: make an empty stream
+ * @param {int} nodeMaxEntries entries of a node, 0 for no limit
+ * @param {int} nodeMaxBytes bytes of a node, 0 for no limit
+ * @return {*}
+ */
+func Make(nodeMaxEntries, nodeMaxBytes int) *Stream {
+	return &Stream{
+		groups:         make(map[string]*Group),
+		nodeMaxEntries: nodeMaxEntries,
+		nodeMaxBytes:   nodeMaxBytes,
+	}
+}
+
+/**
+ * @description: number of entries
+ */
+func (s *Stream) Len() int64 {
+	return s.length
+}
+
+/**
+ * @description: ID of the last added entry, even if it has been deleted
+ */
+func (s *Stream) LastID() ID {
+	return s.lastID
+}
+
+/**
+ * @description: ID of the first entry, 0-0 if empty
+ */
+func (s *Stream) FirstID() ID {
+	return s.firstID
+}
+
+/**
+ * @description: greatest ID deleted by Delete
+ */
+func (s *Stream) MaxDeletedID() ID {
+	return s.maxDeletedID
+}
+
+/**
+ * @description: number of entries ever added
+ */
+func (s *Stream) EntriesAdded() int64 {
+	return s.entriesAdded
+}
+
+/**
+ * @description: number of nodes
+ */
+func (s *Stream) Nodes() int {
+	return len(s.nodes)
+}
+
+/**
+ * @description: make an independent copy of the stream and its groups
+ */
+func (s *Stream) Clone() *Stream {
+	clone := *s
+	clone.nodes = make([]*node, len(s.nodes))
+	for i, n := range s.nodes {
+		copied := *n
+		copied.lp = n.lp.Clone()
+		clone.nodes[i] = &copied
+	}
+	clone.groups = make(map[string]*Group, len(s.groups))
+	for name, g := range s.groups {
+		clone.groups[name] = g.clone()
+	}
+	return &clone
+}
+
+//------------ node --------------
+
+// position of an entry in a node
+type entryHeader struct {
+	id        ID
+	deleted   bool
+	off       int // offset of the flags
+	fieldsOff int // offset of the first field
+	numFields int
+	next      int // offset of the next entry, -1 if it is the last one
+}
+
+/**
+ * @description: read a number written by putUvarint
+ */
+func (n *node) uvarint(off int) uint64 {
+	x, _ := binary.Uvarint(n.lp.Get(off))
+	return x
+}
+
+/**
+ * @description: append a number
+ */
+func (n *node) putUvarint(x uint64) {
+	var buf [binary.MaxVarintLen64]byte
+	n.lp.Append(buf[:binary.PutUvarint(buf[:], x)])
+}
+
+/**
+ * @description: read the header of the entry at off
+ */
+func (n *node) header(off int) entryHeader {
+	lp := n.lp
+	h := entryHeader{off: off}
+	h.deleted = lp.Get(off)[0]&flagDeleted != 0
+	off = lp.Next(off)
+	h.id.Ms = n.master.Ms + n.uvarint(off)
+	off = lp.Next(off)
+	h.id.Seq = n.uvarint(off)
+	off = lp.Next(off)
+	h.numFields = int(n.uvarint(off))
+	off = lp.Next(off)
+	h.fieldsOff = off
+	for i := 0; i < 2*h.numFields; i++ {
+		off = lp.Next(off)
+	}
+	h.next = off
+	return h
+}
+
+/**
+ * @description: read the entry of a header
+ */
+func (n *node) entry(h entryHeader) Entry {
+	fields := make([][]byte, 2*h.numFields)
+	off := h.fieldsOff
+	for i := range fields {
+		fields[i] = n.lp.Get(off)
+		off = n.lp.Next(off)
+	}
+	return Entry{ID: h.id, Fields: fields}
+}
+
+/**
+ * @description: headers of the entries, including the deleted ones
+ */
+func (n *node) headers() []entryHeader {
+	headers := make([]entryHeader, 0, n.count)
+	for off := n.lp.First(); off != -1; {
+		h := n.header(off)
+		headers = append(headers, h)
+		off = h.next
+	}
+	return headers
+}
+
+/**
+ * @description: append an entry
+ */
+func (n *node) append(id ID, fields [][]byte) {
+	n.lp.Append([]byte{0})
+	n.putUvarint(id.Ms - n.master.Ms)
+	n.putUvarint(id.Seq)
+	n.putUvarint(uint64(len(fields) / 2))
+	for _, field := range fields {
+		n.lp.Append(field)
+	}
+	n.count++
+}
+
+/**
+ * @description: flag an entry as deleted
+ */
+func (n *node) markDeleted(h entryHeader) {
+	n.lp.Replace(h.off, []byte{flagDeleted})
+	n.deleted++
+}
+
+/**
+ * @description: whether a new entry should go to a new node
+ */
+func (s *Stream) full(n *node, fields [][]byte) bool {
+	if s.nodeMaxEntries > 0 && n.count >= s.nodeMaxEntries {
+		return true
+	}
+	if s.nodeMaxBytes > 0 {
+		size := n.lp.Size()
+		for _, field := range fields {
+			size += listpack.EntrySize(len(field))
+		}
+		return size > s.nodeMaxBytes
+	}
+	return false
+}
+
+/**
+ * @description: index of the node which may hold id
+ * @return {*} -1 if id is before the first node
+ */
+func (s *Stream) nodeIndex(id ID) int {
+	return sort.Search(len(s.nodes), func(i int) bool {
+		return id.Less(s.nodes[i].master)
+	}) - 1
+}
+
+/**
+ * @description: remove the node at index i
+ */
+func (s *Stream) removeNode(i int) {
+	copy(s.nodes[i:], s.nodes[i+1:])
+	s.nodes[len(s.nodes)-1] = nil
+	s.nodes = s.nodes[:len(s.nodes)-1]
+}
+
+/**
+ * @description: update the first ID after removals
+ */
+func (s *Stream) updateFirstID() {
+	s.firstID = ID{}
+	if entries := s.Range(ID{}, MaxID, 1, false); len(entries) > 0 {
+		s.firstID = entries[0].ID
+	}
+}
+
+//------------ entries --------------
+
+/**
+ * @description: ID of an entry added at time ms, for the ID *
+ * @param {uint64} ms unix time in milliseconds
+ * @return {*} ID, false if the last possible ID has been used
+ */
+func (s *Stream) AutoID(ms uint64) (ID, bool) {
+	if ms > s.lastID.Ms {
+		return ID{Ms: ms}, true
+	}
+	return s.lastID.Incr()
+}
+
+/**
+ * @description: ID with an automatic sequence number, for the ID <ms>-*
+ * @param {uint64} ms
+ * @return {*} ID, false if the ID would not be greater than the last ID
+ */
+func (s *Stream) AutoSeqID(ms uint64) (ID, bool) {
+	switch {
+	case ms > s.lastID.Ms:
+		return ID{Ms: ms}, true
+	case ms == s.lastID.Ms && s.lastID.Seq < MaxID.Seq:
+		return ID{Ms: ms, Seq: s.lastID.Seq + 1}, true
+	}
+	return ID{}, false
+}
+
+/**
+ * @description: add an entry at the end
+ * @param {ID} id must be greater than the last ID
+ * @param {[][]byte} fields field, value pairs, at least one pair
+ * @return {*} false if the ID is too small
+ */
+func (s *Stream) Add(id ID, fields [][]byte) bool {
+	if !s.lastID.Less(id) {
+		return false
+	}
+	var n *node
+	if len(s.nodes) > 0 {
+		n = s.nodes[len(s.nodes)-1]
+	}
+	if n == nil || s.full(n, fields) {
+		n = &node{master: id, lp: listpack.New()}
+		s.nodes = append(s.nodes, n)
+	}
+	n.append(id, fields)
+	if s.length == 0 {
+		s.firstID = id
+	}
+	s.length++
+	s.entriesAdded++
+	s.lastID = id
+	return true
+}
+
+/**
+ * @description: entries with IDs between start and end, both included
+ * @param {ID} start
+ * @param {ID} end
+ * @param {int} count max number of entries, 0 for no limit
+ * @param {bool} rev from end to start
+ * @return {*}
+ */
+func (s *Stream) Range(start, end ID, count int, rev bool) []Entry {
+	var entries []Entry
+	if end.Less(start) {
+		return nil
+	}
+	add := func(n *node, h entryHeader) bool {
+		if h.deleted || h.id.Less(start) || end.Less(h.id) {
+			return true
+		}
+		entries = append(entries, n.entry(h))
+		return count == 0 || len(entries) < count
+	}
+
+	if !rev {
+		i := s.nodeIndex(start)
+		if i < 0 {
+			i = 0
+		}
+		for ; i < len(s.nodes) && !end.Less(s.nodes[i].master); i++ {
+			n := s.nodes[i]
+			for off := n.lp.First(); off != -1; {
+				h := n.header(off)
+				if end.Less(h.id) {
+					return entries
+				}
+				if !add(n, h) {
+					return entries
+				}
+				off = h.next
+			}
+		}
+		return entries
+	}
+
+	for i := s.nodeIndex(end); i >= 0; i-- {
+		n := s.nodes[i]
+		headers := n.headers()
+		for j := len(headers) - 1; j >= 0; j-- {
+			if headers[j].id.Less(start) {
+				return entries
+			}
+			if !add(n, headers[j]) {
+				return entries
+			}
+		}
+	}
+	return entries
+}
+
+/**
+ * @description: get an entry
+ * @return {*} entry, false if it does not exist
+ */
+func (s *Stream) Get(id ID) (Entry, bool) {
+	entries := s.Range(id, id, 1, false)
+	if len(entries) == 0 {
+		return Entry{}, false
+	}
+	return entries[0], true
+}
+
+/**
+ * @description: whether an entry exists
+ */
+func (s *Stream) Exists(id ID) bool {
+	i := s.nodeIndex(id)
+	if i < 0 {
+		return false
+	}
+	n := s.nodes[i]
+	for off := n.lp.First(); off != -1; {
+		h := n.header(off)
+		if h.id == id {
+			return !h.deleted
+		}
+		off = h.next
+	}
+	return false
+}
+
+/**
+ * @description: delete an entry
+ * @return {*} false if it does not exist
+ */
+func (s *Stream) Delete(id ID) bool {
+	i := s.nodeIndex(id)
+	if i < 0 {
+		return false
+	}
+	n := s.nodes[i]
+	for off := n.lp.First(); off != -1; {
+		h := n.header(off)
+		if h.id == id {
+			if h.deleted {
+				return false
+			}
+			n.markDeleted(h)
+			if n.deleted == n.count {
+				s.removeNode(i)
+			}
+			s.length--
+			if s.maxDeletedID.Less(id) {
+				s.maxDeletedID = id
+			}
+			if id == s.firstID {
+				s.updateFirstID()
+			}
+			return true
+		}
+		off = h.next
+	}
+	return false
+}
+
+//------------ trim --------------
+
+/**
+ * @description: remove the oldest entries so that at most maxLen entries are left
+ * @param {int64} maxLen
+ * @param {bool} approx only remove whole nodes, which is cheaper
+ * @param {int64} limit max number of removed entries, 0 for no limit
+ * @return {*} number of removed entries
+ */
+func (s *Stream) TrimByLen(maxLen int64, approx bool, limit int64) int64 {
+	return s.trim(func(n *node) bool {
+		return s.length-int64(n.count-n.deleted) >= maxLen
+	}, func(h entryHeader) bool {
+		return s.length > maxLen
+	}, approx, limit)
+}
+
+/**
+ * @description: remove the entries with IDs smaller than minID
+ * @param {ID} minID
+ * @param {bool} approx only remove whole nodes, which is cheaper
+ * @param {int64} limit max number of removed entries, 0 for no limit
+ * @return {*} number of removed entries
+ */
+func (s *Stream) TrimByMinID(minID ID, approx bool, limit int64) int64 {
+	return s.trim(func(n *node) bool {
+		headers := n.headers()
+		return headers[len(headers)-1].id.Less(minID)
+	}, func(h entryHeader) bool {
+		return h.id.Less(minID)
+	}, approx, limit)
+}
+
+/**
+ * @description: remove entries from the beginning, like streamTrim of redis
+ * @param {func} removeNode whether a whole node can be removed
+ * @param {func} remove whether an entry of the first node left must be removed
+ * @return {*} number of removed entries
+ */
+func (s *Stream) trim(removeNode func(n *node) bool, remove func(h entryHeader) bool, approx bool, limit int64) int64 {
+	var removed int64
+	for len(s.nodes) > 0 {
+		n := s.nodes[0]
+		entries := int64(n.count - n.deleted)
+		if limit > 0 && removed+entries > limit {
+			break
+		}
+		if removeNode(n) {
+			s.removeNode(0)
+			s.length -= entries
+			removed += entries
+			continue
+		}
+		if approx {
+			break
+		}
+		for off := n.lp.First(); off != -1; {
+			h := n.header(off)
+			if !h.deleted {
+				if !remove(h) {
+					break
+				}
+				n.markDeleted(h)
+				s.length--
+				removed++
+			}
+			off = h.next
+		}
+		break
+	}
+	if removed > 0 {
+		s.updateFirstID()
+	}
+	return removed
+}
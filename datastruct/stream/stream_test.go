@@ -0,0 +1,171 @@
+/*
+ * @Description:
+ * @Autor: HTmonster
+ * @Date: 2026-10-19 21:12:40
+ */
+package stream
+
+import (
+	"strconv"
+	"testing"
+)
+
+func fields(i int) [][]byte {
+	return [][]byte{[]byte("f"), []byte(strconv.Itoa(i))}
+}
+
+func ids(entries []Entry) []ID {
+	result := make([]ID, len(entries))
+	for i, e := range entries {
+		result[i] = e.ID
+	}
+	return result
+}
+
+func TestID(t *testing.T) {
+	if s := (ID{Ms: 12, Seq: 3}).String(); s != "12-3" {
+		t.Errorf("string: %s", s)
+	}
+	if next, _ := (ID{Ms: 1, Seq: MaxID.Seq}).Incr(); next != (ID{Ms: 2}) {
+		t.Errorf("incr: %v", next)
+	}
+	if prev, _ := (ID{Ms: 2}).Decr(); prev != (ID{Ms: 1, Seq: MaxID.Seq}) {
+		t.Errorf("decr: %v", prev)
+	}
+	if _, ok := MaxID.Incr(); ok {
+		t.Error("MaxID has no next ID")
+	}
+	if _, ok := (ID{}).Decr(); ok {
+		t.Error("0-0 has no previous ID")
+	}
+	a, b := ID{Ms: 1, Seq: 300}, ID{Ms: 2, Seq: 1}
+	if !a.Less(b) || a.key() >= b.key() || idOfKey(a.key()) != a {
+		t.Error("keys should be ordered like the IDs")
+	}
+}
+
+func TestAddRange(t *testing.T) {
+	s := Make(3, 0)
+	for i := 1; i <= 10; i++ {
+		if !s.Add(ID{Ms: uint64(i)}, fields(i)) {
+			t.Fatalf("add %d", i)
+		}
+	}
+	if s.Add(ID{Ms: 10}, fields(0)) {
+		t.Error("IDs must grow")
+	}
+	if s.Len() != 10 || s.Nodes() != 4 || s.FirstID() != (ID{Ms: 1}) || s.LastID() != (ID{Ms: 10}) {
+		t.Fatalf("len %d, nodes %d, first %v, last %v", s.Len(), s.Nodes(), s.FirstID(), s.LastID())
+	}
+
+	entries := s.Range(ID{Ms: 3}, ID{Ms: 7}, 0, false)
+	if got := ids(entries); len(got) != 5 || got[0] != (ID{Ms: 3}) || got[4] != (ID{Ms: 7}) {
+		t.Errorf("range: %v", got)
+	}
+	if string(entries[1].Fields[1]) != "4" {
+		t.Errorf("fields: %q", entries[1].Fields)
+	}
+	if got := ids(s.Range(ID{Ms: 3}, ID{Ms: 7}, 2, true)); len(got) != 2 || got[0] != (ID{Ms: 7}) || got[1] != (ID{Ms: 6}) {
+		t.Errorf("reverse range: %v", got)
+	}
+	if got := s.Range(ID{Ms: 7}, ID{Ms: 3}, 0, false); len(got) != 0 {
+		t.Errorf("empty range: %v", got)
+	}
+	if e, ok := s.Get(ID{Ms: 9}); !ok || string(e.Fields[1]) != "9" {
+		t.Errorf("get: %v %v", e, ok)
+	}
+}
+
+func TestAutoID(t *testing.T) {
+	s := Make(100, 0)
+	if id, _ := s.AutoID(5); id != (ID{Ms: 5}) {
+		t.Errorf("auto: %v", id)
+	}
+	s.Add(ID{Ms: 5, Seq: 1}, fields(0))
+	if id, _ := s.AutoID(3); id != (ID{Ms: 5, Seq: 2}) {
+		t.Errorf("clock going back: %v", id)
+	}
+	if id, ok := s.AutoSeqID(5); !ok || id != (ID{Ms: 5, Seq: 2}) {
+		t.Errorf("auto seq: %v", id)
+	}
+	if _, ok := s.AutoSeqID(4); ok {
+		t.Error("auto seq of a smaller time")
+	}
+	s.Add(MaxID, fields(0))
+	if _, ok := s.AutoID(6); ok {
+		t.Error("IDs are exhausted")
+	}
+}
+
+func TestDelete(t *testing.T) {
+	s := Make(2, 0)
+	for i := 1; i <= 5; i++ {
+		s.Add(ID{Ms: uint64(i)}, fields(i))
+	}
+	if !s.Delete(ID{Ms: 1}) || s.Delete(ID{Ms: 1}) || s.Delete(ID{Ms: 9}) {
+		t.Fatal("delete should report existing entries only")
+	}
+	if s.FirstID() != (ID{Ms: 2}) || s.MaxDeletedID() != (ID{Ms: 1}) {
+		t.Errorf("first %v, max deleted %v", s.FirstID(), s.MaxDeletedID())
+	}
+	s.Delete(ID{Ms: 2})
+	if s.Nodes() != 2 || s.Len() != 3 || s.Exists(ID{Ms: 2}) {
+		t.Errorf("empty nodes should be removed, nodes %d len %d", s.Nodes(), s.Len())
+	}
+	s.Delete(ID{Ms: 4})
+	if got := ids(s.Range(ID{}, MaxID, 0, true)); len(got) != 2 || got[0] != (ID{Ms: 5}) || got[1] != (ID{Ms: 3}) {
+		t.Errorf("range over deleted entries: %v", got)
+	}
+	if s.EntriesAdded() != 5 || s.LastID() != (ID{Ms: 5}) {
+		t.Error("deletions do not change the last ID")
+	}
+}
+
+func TestTrim(t *testing.T) {
+	make10 := func() *Stream {
+		s := Make(3, 0)
+		for i := 1; i <= 10; i++ {
+			s.Add(ID{Ms: uint64(i)}, fields(i))
+		}
+		return s
+	}
+
+	s := make10()
+	if n := s.TrimByLen(5, false, 0); n != 5 || s.Len() != 5 || s.FirstID() != (ID{Ms: 6}) {
+		t.Errorf("exact maxlen: removed %d, len %d, first %v", n, s.Len(), s.FirstID())
+	}
+	s = make10()
+	// only whole nodes of 3 entries
+	if n := s.TrimByLen(5, true, 0); n != 3 || s.FirstID() != (ID{Ms: 4}) {
+		t.Errorf("approximated maxlen: removed %d, first %v", n, s.FirstID())
+	}
+	s = make10()
+	if n := s.TrimByLen(0, true, 4); n != 3 {
+		t.Errorf("limited: removed %d", n)
+	}
+	s = make10()
+	if n := s.TrimByMinID(ID{Ms: 5}, false, 0); n != 4 || s.FirstID() != (ID{Ms: 5}) {
+		t.Errorf("exact minid: removed %d, first %v", n, s.FirstID())
+	}
+	s = make10()
+	if n := s.TrimByMinID(ID{Ms: 5}, true, 0); n != 3 {
+		t.Errorf("approximated minid: removed %d", n)
+	}
+	s = make10()
+	if n := s.TrimByLen(0, false, 0); n != 10 || s.Len() != 0 || s.Nodes() != 0 || !s.FirstID().IsZero() {
+		t.Errorf("trim everything: removed %d, nodes %d", n, s.Nodes())
+	}
+}
+
+func TestNodeMaxBytes(t *testing.T) {
+	s := Make(0, 64)
+	for i := 1; i <= 10; i++ {
+		s.Add(ID{Ms: uint64(i)}, [][]byte{[]byte("field"), []byte("value-value")})
+	}
+	if s.Nodes() < 3 {
+		t.Errorf("nodes should be split by size: %d", s.Nodes())
+	}
+	if got := s.Range(ID{}, MaxID, 0, false); len(got) != 10 {
+		t.Errorf("range: %d", len(got))
+	}
+}
@@ -26,7 +26,23 @@ type ConfigProperties struct {
 	Timeout   int    `json:"timeout"`   //e.g. timeout 0
 	Daemonize bool   `json:"daemonize"` //e.g. daemonize yes
 	Logfile   string `json:"logfile"`   //e.g. logfile /var/log/redis/redis-server.log
-	Database  int    `json:"database"`  //e.g. databases 16
+	Database  int    `json:"databases"` //e.g. databases 16
+
+	Requirepass string `json:"requirepass"` //e.g. requirepass foobared
+
+	ProtoMaxBulkLen      int `json:"proto-max-bulk-len"`      //e.g. proto-max-bulk-len 512mb
+	ProtoMaxMultibulkLen int `json:"proto-max-multibulk-len"` //e.g. proto-max-multibulk-len 1048576
+
+	Hz int `json:"hz"` //e.g. hz 10
+
+	HashMaxListpackEntries int `json:"hash-max-listpack-entries"` //e.g. hash-max-listpack-entries 128
+	HashMaxListpackValue   int `json:"hash-max-listpack-value"`   //e.g. hash-max-listpack-value 64
+	SetMaxIntsetEntries    int `json:"set-max-intset-entries"`    //e.g. set-max-intset-entries 512
+
+	HllSparseMaxBytes int `json:"hll-sparse-max-bytes"` //e.g. hll-sparse-max-bytes 3000
+
+	StreamNodeMaxBytes   int `json:"stream-node-max-bytes"`   //e.g. stream-node-max-bytes 4096
+	StreamNodeMaxEntries int `json:"stream-node-max-entries"` //e.g. stream-node-max-entries 100
 }
 
 // global vars
@@ -52,7 +68,67 @@ func init() {
 		Daemonize: false,
 		Logfile:   "",
 		Database:  16,
+
+		Requirepass: "",
+
+		ProtoMaxBulkLen:      512 * 1024 * 1024,
+		ProtoMaxMultibulkLen: 1024 * 1024,
+
+		Hz: 10,
+
+		HashMaxListpackEntries: 128,
+		HashMaxListpackValue:   64,
+		SetMaxIntsetEntries:    512,
+
+		HllSparseMaxBytes: 3000,
+
+		StreamNodeMaxBytes:   4096,
+		StreamNodeMaxEntries: 100,
+	}
+}
+
+// old names of renamed config items
+var aliases = map[string]string{
+	"hash-max-ziplist-entries": "hash-max-listpack-entries",
+	"hash-max-ziplist-value":   "hash-max-listpack-value",
+}
+
+/**
+ * @description: translate an old config name to the current one
+ * @param {string} key
+ * @return {*}
+ */
+func canonicalKey(key string) string {
+	if name, ok := aliases[key]; ok {
+		return name
+	}
+	return key
+}
+
+/**
+ * @description: parse an integer with an optional memory unit, e.g. 1k 5gb 4M
+ * @param {string} value
+ * @return {*}
+ */
+func parseMemory(value string) (int64, error) {
+	units := []struct {
+		suffix string
+		mul    int64
+	}{
+		{"kb", 1024}, {"mb", 1024 * 1024}, {"gb", 1024 * 1024 * 1024},
+		{"k", 1000}, {"m", 1000 * 1000}, {"g", 1000 * 1000 * 1000},
+	}
+	lower := strings.ToLower(value)
+	for _, unit := range units {
+		if strings.HasSuffix(lower, unit.suffix) {
+			n, err := strconv.ParseInt(lower[:len(lower)-len(unit.suffix)], 10, 64)
+			if err != nil {
+				return 0, err
+			}
+			return n * unit.mul, nil
+		}
 	}
+	return strconv.ParseInt(value, 10, 64)
 }
 
 /**
@@ -92,7 +168,7 @@ func parseConfigFile(file string) (*ConfigProperties, error) {
 		fileds := strings.Fields(string(line))
 		key, value := fileds[0], fileds[1]
 
-		configMap[key] = value
+		configMap[canonicalKey(key)] = value
 	}
 
 	// parse config
@@ -114,7 +190,7 @@ func parseConfigFile(file string) (*ConfigProperties, error) {
 				filedValue.SetString(value)
 
 			case reflect.Int:
-				if intValue, err := strconv.ParseInt(value, 10, 64); err == nil {
+				if intValue, err := parseMemory(value); err == nil {
 					filedValue.SetInt(intValue)
 				}
 
@@ -149,13 +225,13 @@ func parseConfigArg(key, value string) (*ConfigProperties, error) {
 			refKey = filed.Name
 		}
 
-		if refKey == key {
+		if refKey == canonicalKey(key) {
 			switch filed.Type.Kind() {
 			case reflect.String:
 				filedValue.SetString(value)
 
 			case reflect.Int:
-				if intValue, err := strconv.ParseInt(value, 10, 64); err == nil {
+				if intValue, err := parseMemory(value); err == nil {
 					filedValue.SetInt(intValue)
 				}
 
@@ -31,3 +31,35 @@ func TestParseConfigArg(t *testing.T) {
 		t.Errorf("error parsing config arg: %s", err)
 	}
 }
+
+func TestParseMemory(t *testing.T) {
+	cases := map[string]int64{
+		"100":   100,
+		"1k":    1000,
+		"1kb":   1024,
+		"512mb": 512 * 1024 * 1024,
+		"2G":    2 * 1000 * 1000 * 1000,
+	}
+	for value, want := range cases {
+		if got, err := parseMemory(value); err != nil || got != want {
+			t.Errorf("parse %s: got %d %v, want %d", value, got, err, want)
+		}
+	}
+	if _, err := parseMemory("12xb"); err == nil {
+		t.Errorf("parse 12xb should fail")
+	}
+}
+
+func TestParseConfigAlias(t *testing.T) {
+	entries, value := Properties.HashMaxListpackEntries, Properties.HashMaxListpackValue
+	defer func() {
+		Properties.HashMaxListpackEntries, Properties.HashMaxListpackValue = entries, value
+	}()
+
+	parseConfigArg("hash-max-listpack-entries", "256")
+	parseConfigArg("hash-max-ziplist-value", "32")
+	if Properties.HashMaxListpackEntries != 256 || Properties.HashMaxListpackValue != 32 {
+		t.Errorf("test parse hash config fail: %d %d",
+			Properties.HashMaxListpackEntries, Properties.HashMaxListpackValue)
+	}
+}
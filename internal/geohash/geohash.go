@@ -0,0 +1,373 @@
+/*
+ * @Description: geohash encoding and search areas, compatible with redis
+ * @Autor: HTmonster
+ * @Date: 2026-10-19 18:05:19
+ */
+package geohash
+
+import (
+	"math"
+)
+
+// limits of EPSG:900913 / EPSG:3785 / OSGEO:41001, used for the scores
+const (
+	LonMin = -180.0
+	LonMax = 180.0
+	LatMin = -85.05112878
+	LatMax = 85.05112878
+)
+
+const (
+	MaxStep     = 26 // steps of the stored 52 bits hashes
+	earthRadius = 6372797.560856
+	mercatorMax = 20037726.37
+	degToRad    = math.Pi / 180.0
+	alphabet    = "0123456789bcdefghjkmnpqrstuvwxyz"
+)
+
+// cell of the grid after step divisions of both ranges,
+// latitude bits are the even bits and longitude bits the odd bits
+type Hash struct {
+	Bits uint64
+	Step uint
+}
+
+// bounds of a cell
+type Area struct {
+	LonMin, LonMax float64
+	LatMin, LatMax float64
+}
+
+//------------ bits --------------
+
+/**
+ * @description: interleave the 32 bits of x and y, x takes the even bits
+ */
+func interleave64(x, y uint32) uint64 {
+	masks := [...]uint64{0x5555555555555555, 0x3333333333333333, 0x0F0F0F0F0F0F0F0F, 0x00FF00FF00FF00FF, 0x0000FFFF0000FFFF}
+	shifts := [...]uint{1, 2, 4, 8, 16}
+	xx, yy := uint64(x), uint64(y)
+	for i := len(masks) - 1; i >= 0; i-- {
+		xx = (xx | xx<<shifts[i]) & masks[i]
+		yy = (yy | yy<<shifts[i]) & masks[i]
+	}
+	return xx | yy<<1
+}
+
+/**
+ * @description: reverse of interleave64
+ * @return {*} x in the low 32 bits, y in the high 32 bits
+ */
+func deinterleave64(interleaved uint64) uint64 {
+	masks := [...]uint64{0x5555555555555555, 0x3333333333333333, 0x0F0F0F0F0F0F0F0F, 0x00FF00FF00FF00FF, 0x0000FFFF0000FFFF, 0x00000000FFFFFFFF}
+	shifts := [...]uint{0, 1, 2, 4, 8, 16}
+	x, y := interleaved, interleaved>>1
+	for i := range masks {
+		x = (x | x>>shifts[i]) & masks[i]
+		y = (y | y>>shifts[i]) & masks[i]
+	}
+	return x | y<<32
+}
+
+//------------ encode and decode --------------
+
+/**
+ * @description: check a coordinate against the limits of the scores
+ */
+func Valid(lon, lat float64) bool {
+	return lon >= LonMin && lon <= LonMax && lat >= LatMin && lat <= LatMax
+}
+
+/**
+ * @description: encode a coordinate in the given ranges
+ */
+func encode(lon, lat float64, lonMin, lonMax, latMin, latMax float64, step uint) Hash {
+	latOffset := (lat - latMin) / (latMax - latMin)
+	lonOffset := (lon - lonMin) / (lonMax - lonMin)
+	latOffset *= float64(uint64(1) << step)
+	lonOffset *= float64(uint64(1) << step)
+	return Hash{Bits: interleave64(uint32(latOffset), uint32(lonOffset)), Step: step}
+}
+
+/**
+ * @description: cell of a coordinate, the coordinate must be valid
+ * @param {float64} lon
+ * @param {float64} lat
+ * @param {uint} step number of divisions of the ranges, 1-26
+ * @return {*}
+ */
+func Encode(lon, lat float64, step uint) Hash {
+	return encode(lon, lat, LonMin, LonMax, LatMin, LatMax, step)
+}
+
+/**
+ * @description: 52 bits hash of a coordinate, stored as the score of the member
+ */
+func Encode52(lon, lat float64) uint64 {
+	return Encode(lon, lat, MaxStep).Bits
+}
+
+/**
+ * @description: bounds of a cell
+ */
+func Decode(h Hash) Area {
+	sep := deinterleave64(h.Bits)
+	latScale, lonScale := LatMax-LatMin, LonMax-LonMin
+	ilat, ilon := uint32(sep), uint32(sep>>32)
+	cells := float64(uint64(1) << h.Step)
+	return Area{
+		LatMin: LatMin + (float64(ilat)/cells)*latScale,
+		LatMax: LatMin + (float64(ilat+1)/cells)*latScale,
+		LonMin: LonMin + (float64(ilon)/cells)*lonScale,
+		LonMax: LonMin + (float64(ilon+1)/cells)*lonScale,
+	}
+}
+
+/**
+ * @description: center of the cell of a 52 bits hash
+ * @return {*} longitude, latitude
+ */
+func Decode52(bits uint64) (float64, float64) {
+	area := Decode(Hash{Bits: bits, Step: MaxStep})
+	lon := math.Max(LonMin, math.Min(LonMax, (area.LonMin+area.LonMax)/2))
+	lat := math.Max(LatMin, math.Min(LatMax, (area.LatMin+area.LatMax)/2))
+	return lon, lat
+}
+
+/**
+ * @description: standard 11 characters geohash string, with the latitude range -90 90
+ */
+func String(lon, lat float64) string {
+	h := encode(lon, lat, -180, 180, -90, 90, MaxStep)
+	buf := make([]byte, 11)
+	for i := range buf {
+		idx := 0
+		// 52 bits give 10 characters and 2 bits, the last one is padded with 0
+		if i < 10 {
+			idx = int(h.Bits>>(52-uint(i+1)*5)) & 0x1f
+		}
+		buf[i] = alphabet[idx]
+	}
+	return string(buf)
+}
+
+/**
+ * @description: range of 52 bits scores in a cell, max is excluded
+ */
+func (h Hash) ScoreRange() (uint64, uint64) {
+	shift := 52 - h.Step*2
+	return h.Bits << shift, (h.Bits + 1) << shift
+}
+
+//------------ neighbors --------------
+
+/**
+ * @description: move along the longitude, d is -1 (west) or 1 (east)
+ */
+func (h Hash) moveX(d int) Hash {
+	x := h.Bits & 0xaaaaaaaaaaaaaaaa
+	y := h.Bits & 0x5555555555555555
+	zz := uint64(0x5555555555555555) >> (64 - h.Step*2)
+	if d > 0 {
+		x += zz + 1
+	} else {
+		x |= zz
+		x -= zz + 1
+	}
+	x &= uint64(0xaaaaaaaaaaaaaaaa) >> (64 - h.Step*2)
+	return Hash{Bits: x | y, Step: h.Step}
+}
+
+/**
+ * @description: move along the latitude, d is -1 (south) or 1 (north)
+ */
+func (h Hash) moveY(d int) Hash {
+	x := h.Bits & 0xaaaaaaaaaaaaaaaa
+	y := h.Bits & 0x5555555555555555
+	zz := uint64(0xaaaaaaaaaaaaaaaa) >> (64 - h.Step*2)
+	if d > 0 {
+		y += zz + 1
+	} else {
+		y |= zz
+		y -= zz + 1
+	}
+	y &= uint64(0x5555555555555555) >> (64 - h.Step*2)
+	return Hash{Bits: x | y, Step: h.Step}
+}
+
+//------------ distance --------------
+
+/**
+ * @description: distance along a meridian in meters
+ */
+func latDistance(lat1, lat2 float64) float64 {
+	return earthRadius * math.Abs(lat2*degToRad-lat1*degToRad)
+}
+
+/**
+ * @description: haversine distance in meters
+ */
+func Distance(lon1, lat1, lon2, lat2 float64) float64 {
+	lon1r, lon2r := lon1*degToRad, lon2*degToRad
+	v := math.Sin((lon2r - lon1r) / 2)
+	// same longitudes
+	if v == 0 {
+		return latDistance(lat1, lat2)
+	}
+	lat1r, lat2r := lat1*degToRad, lat2*degToRad
+	u := math.Sin((lat2r - lat1r) / 2)
+	a := u*u + math.Cos(lat1r)*math.Cos(lat2r)*v*v
+	return 2 * earthRadius * math.Asin(math.Sqrt(a))
+}
+
+//------------ shape --------------
+
+// search area, a circle or a box around a center, sizes are in meters
+type Shape struct {
+	Lon, Lat      float64
+	Radius        float64 // circle
+	Width, Height float64 // box
+	IsBox         bool
+}
+
+/**
+ * @description: check whether a point is in the shape
+ * @return {*} distance to the center in meters, in the shape or not
+ */
+func (s *Shape) Contains(lon, lat float64) (float64, bool) {
+	if s.IsBox {
+		if latDistance(lat, s.Lat) > s.Height/2 {
+			return 0, false
+		}
+		if Distance(lon, lat, s.Lon, lat) > s.Width/2 {
+			return 0, false
+		}
+		return Distance(s.Lon, s.Lat, lon, lat), true
+	}
+	distance := Distance(s.Lon, s.Lat, lon, lat)
+	return distance, distance <= s.Radius
+}
+
+/**
+ * @description: bounding box of the shape
+ * @return {*} min longitude, min latitude, max longitude, max latitude
+ */
+func (s *Shape) boundingBox() (float64, float64, float64, float64) {
+	height, width := s.Radius, s.Radius
+	if s.IsBox {
+		height, width = s.Height/2, s.Width/2
+	}
+	latDelta := height / earthRadius / degToRad
+	lonDeltaTop := width / earthRadius / math.Cos((s.Lat+latDelta)*degToRad) / degToRad
+	lonDeltaBottom := width / earthRadius / math.Cos((s.Lat-latDelta)*degToRad) / degToRad
+	// the wider side is toward the equator
+	lonDelta := lonDeltaTop
+	if s.Lat < 0 {
+		lonDelta = lonDeltaBottom
+	}
+	return s.Lon - lonDelta, s.Lat - latDelta, s.Lon + lonDelta, s.Lat + latDelta
+}
+
+/**
+ * @description: number of steps so that a cell is about as large as the range
+ */
+func estimateSteps(rangeMeters, lat float64) uint {
+	if rangeMeters == 0 {
+		return MaxStep
+	}
+	step := 1
+	for rangeMeters < mercatorMax {
+		rangeMeters *= 2
+		step++
+	}
+	// make sure the range is included in most of the base cases
+	step -= 2
+
+	// cells are narrower towards the poles
+	if lat > 66 || lat < -66 {
+		step--
+		if lat > 80 || lat < -80 {
+			step--
+		}
+	}
+	if step < 1 {
+		step = 1
+	}
+	if step > MaxStep {
+		step = MaxStep
+	}
+	return uint(step)
+}
+
+/**
+ * @description: cells covering the shape, the cell of the center and its
+ *	neighbors which may intersect the shape
+ * @return {*}
+ */
+func (s *Shape) SearchAreas() []Hash {
+	minLon, minLat, maxLon, maxLat := s.boundingBox()
+	radius := s.Radius
+	if s.IsBox {
+		radius = math.Sqrt(s.Width/2*s.Width/2 + s.Height/2*s.Height/2)
+	}
+	step := estimateSteps(radius, s.Lat)
+
+	// center, north, south, east, west, north east, north west, south east, south west
+	var cells [9]Hash
+	neighbors := func(step uint) {
+		center := Encode(s.Lon, s.Lat, step)
+		north, south := center.moveY(1), center.moveY(-1)
+		cells = [9]Hash{center, north, south, center.moveX(1), center.moveX(-1),
+			north.moveX(1), north.moveX(-1), south.moveX(1), south.moveX(-1)}
+	}
+	neighbors(step)
+
+	// near the border of a cell the neighbors may be too small to cover the shape
+	if step > 1 && (Decode(cells[1]).LatMax < maxLat || Decode(cells[2]).LatMin > minLat ||
+		Decode(cells[3]).LonMax < maxLon || Decode(cells[4]).LonMin > minLon) {
+		step--
+		neighbors(step)
+	}
+
+	// exclude the neighbors outside of the bounding box
+	skip := make([]bool, len(cells))
+	if step >= 2 {
+		area := Decode(cells[0])
+		exclude := func(indexes ...int) {
+			for _, i := range indexes {
+				skip[i] = true
+			}
+		}
+		if area.LatMin < minLat {
+			exclude(2, 7, 8)
+		}
+		if area.LatMax > maxLat {
+			exclude(1, 5, 6)
+		}
+		if area.LonMin < minLon {
+			exclude(4, 6, 8)
+		}
+		if area.LonMax > maxLon {
+			exclude(3, 5, 7)
+		}
+	}
+
+	// near the poles or the antimeridian some neighbors are the same cell
+	areas := make([]Hash, 0, len(cells))
+	for i, cell := range cells {
+		if skip[i] {
+			continue
+		}
+		duplicate := false
+		for _, area := range areas {
+			if area == cell {
+				duplicate = true
+				break
+			}
+		}
+		if !duplicate {
+			areas = append(areas, cell)
+		}
+	}
+	return areas
+}
@@ -0,0 +1,110 @@
+/*
+ * @Description:
+ * @Autor: HTmonster
+ * @Date: 2026-10-19 19:32:51
+ */
+package geohash
+
+import (
+	"math"
+	"testing"
+)
+
+func TestInterleave(t *testing.T) {
+	for _, c := range []struct{ x, y uint32 }{{0, 0}, {1, 0}, {0, 1}, {0xffffffff, 0}, {0x12345678, 0x9abcdef0}} {
+		bits := interleave64(c.x, c.y)
+		if sep := deinterleave64(bits); uint32(sep) != c.x || uint32(sep>>32) != c.y {
+			t.Errorf("interleave %x %x: got %x", c.x, c.y, sep)
+		}
+	}
+	if interleave64(1, 0) != 1 || interleave64(0, 1) != 2 {
+		t.Error("x should take the even bits")
+	}
+}
+
+func TestEncodeDecode(t *testing.T) {
+	// scores of Palermo and Catania in redis
+	cases := []struct {
+		lon, lat float64
+		bits     uint64
+		hash     string
+	}{
+		{13.361389, 38.115556, 3479099956230698, "sqc8b49rny0"},
+		{15.087269, 37.502669, 3479447370796909, "sqdtr74hyu0"},
+	}
+	for _, c := range cases {
+		if bits := Encode52(c.lon, c.lat); bits != c.bits {
+			t.Errorf("encode %v %v: got %d, want %d", c.lon, c.lat, bits, c.bits)
+		}
+		lon, lat := Decode52(c.bits)
+		if math.Abs(lon-c.lon) > 1e-5 || math.Abs(lat-c.lat) > 1e-5 {
+			t.Errorf("decode %d: got %v %v", c.bits, lon, lat)
+		}
+		if hash := String(lon, lat); hash != c.hash {
+			t.Errorf("geohash %v %v: got %s, want %s", lon, lat, hash, c.hash)
+		}
+	}
+}
+
+func TestDistance(t *testing.T) {
+	lon1, lat1 := Decode52(3479099956230698)
+	lon2, lat2 := Decode52(3479447370796909)
+	if d := Distance(lon1, lat1, lon2, lat2); math.Abs(d-166274.1516) > 1e-4 {
+		t.Errorf("distance: %v", d)
+	}
+	if d := Distance(10, 0, 10, 1); math.Abs(d-earthRadius*degToRad) > 1e-6 {
+		t.Errorf("distance along a meridian: %v", d)
+	}
+}
+
+func TestNeighbors(t *testing.T) {
+	h := Encode(13.361389, 38.115556, 10)
+	area := Decode(h)
+	east, north := Decode(h.moveX(1)), Decode(h.moveY(1))
+	if east.LonMin != area.LonMax || east.LatMin != area.LatMin {
+		t.Errorf("east of %+v: %+v", area, east)
+	}
+	if north.LatMin != area.LatMax || north.LonMin != area.LonMin {
+		t.Errorf("north of %+v: %+v", area, north)
+	}
+	if h.moveX(1).moveX(-1) != h || h.moveY(-1).moveY(1) != h {
+		t.Error("moving back should return to the cell")
+	}
+}
+
+func TestSearchAreas(t *testing.T) {
+	// every point in the shape must be in one of the search areas
+	shapes := []Shape{
+		{Lon: 15, Lat: 37, Radius: 200000},
+		{Lon: 15, Lat: 37, Width: 400000, Height: 100000, IsBox: true},
+		{Lon: 179.9, Lat: -60, Radius: 50000},
+		{Lon: 0, Lat: 84, Radius: 1000},
+		{Lon: 0, Lat: 0, Radius: 0},
+	}
+	for _, s := range shapes {
+		areas := s.SearchAreas()
+		for lon := -180.0; lon <= 180; lon += 0.05 {
+			for lat := s.Lat - 3; lat <= s.Lat+3; lat += 0.05 {
+				if !Valid(lon, lat) {
+					continue
+				}
+				bits := Encode52(lon, lat)
+				plon, plat := Decode52(bits)
+				if _, ok := s.Contains(plon, plat); !ok {
+					continue
+				}
+				found := false
+				for _, area := range areas {
+					min, max := area.ScoreRange()
+					if bits >= min && bits < max {
+						found = true
+						break
+					}
+				}
+				if !found {
+					t.Fatalf("shape %+v: %v %v is not covered", s, lon, lat)
+				}
+			}
+		}
+	}
+}
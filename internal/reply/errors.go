@@ -0,0 +1,55 @@
+/*
+ * @Description: frequently used error replies
+ * @Autor: HTmonster
+ * @Date: 2026-10-18 10:36:44
+ */
+
+package reply
+
+/**
+ * @description: ERR unknown command
+ * @param {string} cmd
+ * @return {*}
+ */
+func MakeUnknownCmdErrReply(cmd string) *StandardErrReply {
+	return MakeErrReply("ERR unknown command '" + cmd + "'")
+}
+
+/**
+ * @description: ERR wrong number of arguments
+ * @param {string} cmd
+ * @return {*}
+ */
+func MakeArgNumErrReply(cmd string) *StandardErrReply {
+	return MakeErrReply("ERR wrong number of arguments for '" + cmd + "' command")
+}
+
+/**
+ * @description: ERR syntax error
+ */
+func MakeSyntaxErrReply() *StandardErrReply {
+	return MakeErrReply("ERR syntax error")
+}
+
+/**
+ * @description: ERR value is not an integer or out of range
+ */
+func MakeNotIntegerErrReply() *StandardErrReply {
+	return MakeErrReply("ERR value is not an integer or out of range")
+}
+
+/**
+ * @description: WRONGTYPE, operation against a key holding the wrong kind of value
+ */
+func MakeWrongTypeErrReply() *StandardErrReply {
+	return MakeErrReply("WRONGTYPE Operation against a key holding the wrong kind of value")
+}
+
+/**
+ * @description: ERR Protocol error
+ * @param {string} msg
+ * @return {*}
+ */
+func MakeProtocolErrReply(msg string) *StandardErrReply {
+	return MakeErrReply("ERR Protocol error: " + msg)
+}
@@ -0,0 +1,276 @@
+/*
+ * @Description: RESP reply
+ * @Autor: HTmonster
+ * @Date: 2026-10-18 10:21:05
+ */
+
+package reply
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+)
+
+var CRLF = "\r\n"
+
+// reply interface, every reply can be serialized to RESP
+type Reply interface {
+	ToBytes() []byte
+}
+
+// error reply interface
+type ErrorReply interface {
+	Reply
+	Error() string
+}
+
+//------------ simple string --------------
+type StatusReply struct {
+	Status string
+}
+
+/**
+ * @description: make a simple string reply
+ * @param {string} status
+ * @return {*}
+ */
+func MakeStatusReply(status string) *StatusReply {
+	return &StatusReply{Status: status}
+}
+
+func (r *StatusReply) ToBytes() []byte {
+	return []byte("+" + r.Status + CRLF)
+}
+
+// frequently used status replies
+var (
+	okBytes     = []byte("+OK" + CRLF)
+	pongBytes   = []byte("+PONG" + CRLF)
+	queuedBytes = []byte("+QUEUED" + CRLF)
+)
+
+type OkReply struct{}
+
+func (r *OkReply) ToBytes() []byte {
+	return okBytes
+}
+
+var theOkReply = new(OkReply)
+
+/**
+ * @description: make a +OK reply
+ */
+func MakeOkReply() *OkReply {
+	return theOkReply
+}
+
+type PongReply struct{}
+
+func (r *PongReply) ToBytes() []byte {
+	return pongBytes
+}
+
+/**
+ * @description: make a +PONG reply
+ */
+func MakePongReply() *PongReply {
+	return &PongReply{}
+}
+
+type QueuedReply struct{}
+
+func (r *QueuedReply) ToBytes() []byte {
+	return queuedBytes
+}
+
+/**
+ * @description: make a +QUEUED reply
+ */
+func MakeQueuedReply() *QueuedReply {
+	return &QueuedReply{}
+}
+
+//------------ error --------------
+type StandardErrReply struct {
+	Status string
+}
+
+// error messages may echo arguments, a line break would end the reply early
+var errLineReplacer = strings.NewReplacer("\r", " ", "\n", " ")
+
+/**
+ * @description: make an error reply, msg should start with the error code, e.g. ERR.
+ *	Like redis, CR and LF in msg are replaced by spaces.
+ * @param {string} msg
+ * @return {*}
+ */
+func MakeErrReply(msg string) *StandardErrReply {
+	return &StandardErrReply{Status: errLineReplacer.Replace(msg)}
+}
+
+func (r *StandardErrReply) ToBytes() []byte {
+	return []byte("-" + r.Status + CRLF)
+}
+
+func (r *StandardErrReply) Error() string {
+	return r.Status
+}
+
+/**
+ * @description: check whether the reply is an error reply
+ * @param {Reply} r
+ * @return {*}
+ */
+func IsErrorReply(r Reply) bool {
+	_, ok := r.(ErrorReply)
+	return ok
+}
+
+//------------ integer --------------
+type IntReply struct {
+	Code int64
+}
+
+/**
+ * @description: make an integer reply
+ * @param {int64} code
+ * @return {*}
+ */
+func MakeIntReply(code int64) *IntReply {
+	return &IntReply{Code: code}
+}
+
+func (r *IntReply) ToBytes() []byte {
+	buf := make([]byte, 0, 24)
+	buf = append(buf, ':')
+	buf = strconv.AppendInt(buf, r.Code, 10)
+	return append(buf, CRLF...)
+}
+
+//------------ bulk string --------------
+type BulkReply struct {
+	Arg []byte
+}
+
+/**
+ * @description: make a bulk string reply (binary safe)
+ * @param {[]byte} arg
+ * @return {*}
+ */
+func MakeBulkReply(arg []byte) *BulkReply {
+	return &BulkReply{Arg: arg}
+}
+
+func (r *BulkReply) ToBytes() []byte {
+	if r.Arg == nil {
+		return nullBulkBytes
+	}
+	return appendBulk(make([]byte, 0, len(r.Arg)+16), r.Arg)
+}
+
+var nullBulkBytes = []byte("$-1" + CRLF)
+
+type NullBulkReply struct{}
+
+func (r *NullBulkReply) ToBytes() []byte {
+	return nullBulkBytes
+}
+
+/**
+ * @description: make a null bulk string reply, $-1
+ */
+func MakeNullBulkReply() *NullBulkReply {
+	return &NullBulkReply{}
+}
+
+//------------ array --------------
+type MultiBulkReply struct {
+	Args [][]byte
+}
+
+/**
+ * @description: make an array of bulk strings, nil element is a null bulk
+ * @param {[][]byte} args
+ * @return {*}
+ */
+func MakeMultiBulkReply(args [][]byte) *MultiBulkReply {
+	return &MultiBulkReply{Args: args}
+}
+
+func (r *MultiBulkReply) ToBytes() []byte {
+	var buf bytes.Buffer
+	buf.WriteString("*" + strconv.Itoa(len(r.Args)) + CRLF)
+	for _, arg := range r.Args {
+		if arg == nil {
+			buf.Write(nullBulkBytes)
+		} else {
+			buf.Write(appendBulk(nil, arg))
+		}
+	}
+	return buf.Bytes()
+}
+
+// array of any replies, can be nested
+type ArrayReply struct {
+	Replies []Reply
+}
+
+/**
+ * @description: make an array reply of any replies
+ * @param {[]Reply} replies
+ * @return {*}
+ */
+func MakeArrayReply(replies []Reply) *ArrayReply {
+	return &ArrayReply{Replies: replies}
+}
+
+func (r *ArrayReply) ToBytes() []byte {
+	var buf bytes.Buffer
+	buf.WriteString("*" + strconv.Itoa(len(r.Replies)) + CRLF)
+	for _, sub := range r.Replies {
+		buf.Write(sub.ToBytes())
+	}
+	return buf.Bytes()
+}
+
+var emptyArrayBytes = []byte("*0" + CRLF)
+
+type EmptyArrayReply struct{}
+
+func (r *EmptyArrayReply) ToBytes() []byte {
+	return emptyArrayBytes
+}
+
+/**
+ * @description: make an empty array reply, *0
+ */
+func MakeEmptyArrayReply() *EmptyArrayReply {
+	return &EmptyArrayReply{}
+}
+
+var nullArrayBytes = []byte("*-1" + CRLF)
+
+type NullArrayReply struct{}
+
+func (r *NullArrayReply) ToBytes() []byte {
+	return nullArrayBytes
+}
+
+/**
+ * @description: make a null array reply, *-1
+ */
+func MakeNullArrayReply() *NullArrayReply {
+	return &NullArrayReply{}
+}
+
+/**
+ * @description: append $<len>\r\n<data>\r\n to buf
+ */
+func appendBulk(buf []byte, arg []byte) []byte {
+	buf = append(buf, '$')
+	buf = strconv.AppendInt(buf, int64(len(arg)), 10)
+	buf = append(buf, CRLF...)
+	buf = append(buf, arg...)
+	return append(buf, CRLF...)
+}
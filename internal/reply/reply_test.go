@@ -0,0 +1,84 @@
+/*
+ * @Description:
+ * @Autor: HTmonster
+ * @Date: 2026-10-18 10:52:30
+ */
+package reply
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/HTmonster/redissgo/internal/request"
+)
+
+func TestReplyToBytes(t *testing.T) {
+	cases := []struct {
+		reply Reply
+		want  string
+	}{
+		{MakeOkReply(), "+OK\r\n"},
+		{MakeStatusReply("PONG"), "+PONG\r\n"},
+		{MakeErrReply("ERR oops"), "-ERR oops\r\n"},
+		{MakeErrReply("ERR 'a\r\n+OK'"), "-ERR 'a  +OK'\r\n"},
+		{MakeIntReply(-42), ":-42\r\n"},
+		{MakeBulkReply([]byte("a\r\nb")), "$4\r\na\r\nb\r\n"},
+		{MakeBulkReply([]byte{}), "$0\r\n\r\n"},
+		{MakeNullBulkReply(), "$-1\r\n"},
+		{MakeMultiBulkReply([][]byte{[]byte("a"), nil}), "*2\r\n$1\r\na\r\n$-1\r\n"},
+		{MakeEmptyArrayReply(), "*0\r\n"},
+		{MakeNullArrayReply(), "*-1\r\n"},
+		{MakeArrayReply([]Reply{
+			MakeIntReply(1),
+			MakeArrayReply([]Reply{MakeBulkReply([]byte("x")), MakeNullBulkReply()}),
+		}), "*2\r\n:1\r\n*2\r\n$1\r\nx\r\n$-1\r\n"},
+	}
+	for _, c := range cases {
+		if got := string(c.reply.ToBytes()); got != c.want {
+			t.Errorf("got %q, want %q", got, c.want)
+		}
+	}
+}
+
+func TestReplyRoundTrip(t *testing.T) {
+	args := [][]byte{
+		[]byte("SET"),
+		[]byte("key"),
+		[]byte("binary\r\n\x00value"),
+		[]byte(""),
+	}
+
+	var buf bytes.Buffer
+	w := NewWriter(&buf)
+	if err := w.Write(MakeMultiBulkReply(args)); err != nil {
+		t.Fatal(err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("reply should stay buffered before flush")
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	req := <-request.ParseRequest(&buf)
+	if req.Err != nil {
+		t.Fatal(req.Err)
+	}
+	if len(req.Params) != len(args) {
+		t.Fatalf("got %d params, want %d", len(req.Params), len(args))
+	}
+	for i := range args {
+		if !bytes.Equal(req.Params[i], args[i]) {
+			t.Errorf("param %d: got %q, want %q", i, req.Params[i], args[i])
+		}
+	}
+}
+
+func TestIsErrorReply(t *testing.T) {
+	if !IsErrorReply(MakeWrongTypeErrReply()) {
+		t.Errorf("WRONGTYPE should be an error reply")
+	}
+	if IsErrorReply(MakeOkReply()) {
+		t.Errorf("OK should not be an error reply")
+	}
+}
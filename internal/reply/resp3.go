@@ -0,0 +1,330 @@
+/*
+ * @Description: RESP3 reply
+ * @Autor: HTmonster
+ * @Date: 2026-10-18 13:05:47
+ */
+
+package reply
+
+import (
+	"bytes"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// protocol versions
+const (
+	RESP2 = 2
+	RESP3 = 3
+)
+
+// reply which has a different RESP3 representation,
+// ToBytes always returns the RESP2 fallback
+type Resp3Reply interface {
+	Reply
+	ToBytes3() []byte
+}
+
+/**
+ * @description: serialize the reply with the given protocol version
+ * @param {Reply} r
+ * @param {int} protocol
+ * @return {*}
+ */
+func Encode(r Reply, protocol int) []byte {
+	if protocol >= RESP3 {
+		if r3, ok := r.(Resp3Reply); ok {
+			return r3.ToBytes3()
+		}
+	}
+	return r.ToBytes()
+}
+
+/**
+ * @description: write "<prefix><n>\r\n" and all sub replies
+ */
+func encodeAggregate(prefix byte, replies []Reply, protocol int) []byte {
+	var buf bytes.Buffer
+	buf.WriteByte(prefix)
+	buf.WriteString(strconv.Itoa(len(replies)))
+	buf.WriteString(CRLF)
+	for _, sub := range replies {
+		buf.Write(Encode(sub, protocol))
+	}
+	return buf.Bytes()
+}
+
+var nullBytes = []byte("_" + CRLF)
+
+func (r *NullBulkReply) ToBytes3() []byte {
+	return nullBytes
+}
+
+func (r *NullArrayReply) ToBytes3() []byte {
+	return nullBytes
+}
+
+func (r *BulkReply) ToBytes3() []byte {
+	if r.Arg == nil {
+		return nullBytes
+	}
+	return r.ToBytes()
+}
+
+func (r *ArrayReply) ToBytes3() []byte {
+	return encodeAggregate('*', r.Replies, RESP3)
+}
+
+//------------ map --------------
+type MapReply struct {
+	Pairs []Reply // key1, value1, key2, value2 ...
+}
+
+/**
+ * @description: make a map reply, RESP2 falls back to a flat array
+ * @param {[]Reply} pairs
+ * @return {*}
+ */
+func MakeMapReply(pairs []Reply) *MapReply {
+	return &MapReply{Pairs: pairs}
+}
+
+func (r *MapReply) ToBytes() []byte {
+	return encodeAggregate('*', r.Pairs, RESP2)
+}
+
+func (r *MapReply) ToBytes3() []byte {
+	var buf bytes.Buffer
+	buf.WriteString("%" + strconv.Itoa(len(r.Pairs)/2) + CRLF)
+	for _, sub := range r.Pairs {
+		buf.Write(Encode(sub, RESP3))
+	}
+	return buf.Bytes()
+}
+
+//------------ set --------------
+type SetReply struct {
+	Members []Reply
+}
+
+/**
+ * @description: make a set reply, RESP2 falls back to an array
+ * @param {[]Reply} members
+ * @return {*}
+ */
+func MakeSetReply(members []Reply) *SetReply {
+	return &SetReply{Members: members}
+}
+
+func (r *SetReply) ToBytes() []byte {
+	return encodeAggregate('*', r.Members, RESP2)
+}
+
+func (r *SetReply) ToBytes3() []byte {
+	return encodeAggregate('~', r.Members, RESP3)
+}
+
+//------------ push --------------
+type PushReply struct {
+	Replies []Reply
+}
+
+/**
+ * @description: make an out of band push reply, RESP2 falls back to an array
+ * @param {[]Reply} replies
+ * @return {*}
+ */
+func MakePushReply(replies []Reply) *PushReply {
+	return &PushReply{Replies: replies}
+}
+
+func (r *PushReply) ToBytes() []byte {
+	return encodeAggregate('*', r.Replies, RESP2)
+}
+
+func (r *PushReply) ToBytes3() []byte {
+	return encodeAggregate('>', r.Replies, RESP3)
+}
+
+//------------ attribute --------------
+type AttributeReply struct {
+	Pairs []Reply // attribute key1, value1 ...
+	Reply Reply   // the real reply
+}
+
+/**
+ * @description: attach attributes to a reply, RESP2 drops the attributes
+ * @param {[]Reply} pairs
+ * @param {Reply} r
+ * @return {*}
+ */
+func MakeAttributeReply(pairs []Reply, r Reply) *AttributeReply {
+	return &AttributeReply{Pairs: pairs, Reply: r}
+}
+
+func (r *AttributeReply) ToBytes() []byte {
+	return r.Reply.ToBytes()
+}
+
+func (r *AttributeReply) ToBytes3() []byte {
+	var buf bytes.Buffer
+	buf.WriteString("|" + strconv.Itoa(len(r.Pairs)/2) + CRLF)
+	for _, sub := range r.Pairs {
+		buf.Write(Encode(sub, RESP3))
+	}
+	buf.Write(Encode(r.Reply, RESP3))
+	return buf.Bytes()
+}
+
+//------------ double --------------
+type DoubleReply struct {
+	Value float64
+	Human bool // fixed 17 decimals without trailing zeros, e.g. coordinates
+}
+
+/**
+ * @description: make a double reply, RESP2 falls back to a bulk string
+ * @param {float64} value
+ * @return {*}
+ */
+func MakeDoubleReply(value float64) *DoubleReply {
+	return &DoubleReply{Value: value}
+}
+
+/**
+ * @description: format a float like redis, e.g. 1.5, inf, -inf
+ * @param {float64} f
+ * @return {*}
+ */
+func FormatFloat(f float64) string {
+	switch {
+	case math.IsInf(f, 1):
+		return "inf"
+	case math.IsInf(f, -1):
+		return "-inf"
+	case math.IsNaN(f):
+		return "nan"
+	}
+	return strconv.FormatFloat(f, 'g', 17, 64)
+}
+
+/**
+ * @description: make a double reply formatted like the long doubles of redis
+ *	in human mode, e.g. 13.36138933897018433
+ * @param {float64} value
+ * @return {*}
+ */
+func MakeHumanDoubleReply(value float64) *DoubleReply {
+	return &DoubleReply{Value: value, Human: true}
+}
+
+/**
+ * @description: format a float with 17 decimals, trailing zeros removed
+ * @param {float64} f
+ * @return {*}
+ */
+func FormatHumanFloat(f float64) string {
+	if math.IsInf(f, 0) || math.IsNaN(f) {
+		return FormatFloat(f)
+	}
+	s := strconv.FormatFloat(f, 'f', 17, 64)
+	s = strings.TrimRight(s, "0")
+	return strings.TrimSuffix(s, ".")
+}
+
+func (r *DoubleReply) format() string {
+	if r.Human {
+		return FormatHumanFloat(r.Value)
+	}
+	return FormatFloat(r.Value)
+}
+
+func (r *DoubleReply) ToBytes() []byte {
+	return MakeBulkReply([]byte(r.format())).ToBytes()
+}
+
+func (r *DoubleReply) ToBytes3() []byte {
+	return []byte("," + r.format() + CRLF)
+}
+
+//------------ boolean --------------
+type BooleanReply struct {
+	Value bool
+}
+
+/**
+ * @description: make a boolean reply, RESP2 falls back to integer 1 or 0
+ * @param {bool} value
+ * @return {*}
+ */
+func MakeBooleanReply(value bool) *BooleanReply {
+	return &BooleanReply{Value: value}
+}
+
+func (r *BooleanReply) ToBytes() []byte {
+	if r.Value {
+		return []byte(":1" + CRLF)
+	}
+	return []byte(":0" + CRLF)
+}
+
+func (r *BooleanReply) ToBytes3() []byte {
+	if r.Value {
+		return []byte("#t" + CRLF)
+	}
+	return []byte("#f" + CRLF)
+}
+
+//------------ big number --------------
+type BigNumberReply struct {
+	Number string
+}
+
+/**
+ * @description: make a big number reply, RESP2 falls back to a bulk string
+ * @param {string} number
+ * @return {*}
+ */
+func MakeBigNumberReply(number string) *BigNumberReply {
+	return &BigNumberReply{Number: number}
+}
+
+func (r *BigNumberReply) ToBytes() []byte {
+	return MakeBulkReply([]byte(r.Number)).ToBytes()
+}
+
+func (r *BigNumberReply) ToBytes3() []byte {
+	return []byte("(" + r.Number + CRLF)
+}
+
+//------------ verbatim string --------------
+type VerbatimReply struct {
+	Format string // three bytes, e.g. txt or mkd
+	Text   []byte
+}
+
+/**
+ * @description: make a verbatim string reply, RESP2 falls back to a bulk string
+ * @param {string} format
+ * @param {[]byte} text
+ * @return {*}
+ */
+func MakeVerbatimReply(format string, text []byte) *VerbatimReply {
+	return &VerbatimReply{Format: format, Text: text}
+}
+
+func (r *VerbatimReply) ToBytes() []byte {
+	return MakeBulkReply(r.Text).ToBytes()
+}
+
+func (r *VerbatimReply) ToBytes3() []byte {
+	buf := make([]byte, 0, len(r.Text)+16)
+	buf = append(buf, '=')
+	buf = strconv.AppendInt(buf, int64(len(r.Text)+len(r.Format)+1), 10)
+	buf = append(buf, CRLF...)
+	buf = append(buf, r.Format...)
+	buf = append(buf, ':')
+	buf = append(buf, r.Text...)
+	return append(buf, CRLF...)
+}
@@ -0,0 +1,61 @@
+/*
+ * @Description:
+ * @Autor: HTmonster
+ * @Date: 2026-10-18 13:41:20
+ */
+package reply
+
+import (
+	"bytes"
+	"math"
+	"testing"
+)
+
+func TestResp3Encode(t *testing.T) {
+	m := MakeMapReply([]Reply{
+		MakeBulkReply([]byte("a")), MakeIntReply(1),
+		MakeBulkReply([]byte("b")), MakeSetReply([]Reply{MakeBulkReply([]byte("x"))}),
+	})
+	cases := []struct {
+		reply Reply
+		resp2 string
+		resp3 string
+	}{
+		{m, "*4\r\n$1\r\na\r\n:1\r\n$1\r\nb\r\n*1\r\n$1\r\nx\r\n",
+			"%2\r\n$1\r\na\r\n:1\r\n$1\r\nb\r\n~1\r\n$1\r\nx\r\n"},
+		{MakeDoubleReply(1.5), "$3\r\n1.5\r\n", ",1.5\r\n"},
+		{MakeDoubleReply(math.Inf(-1)), "$4\r\n-inf\r\n", ",-inf\r\n"},
+		{MakeHumanDoubleReply(13.361389338970184), "$20\r\n13.36138933897018433\r\n", ",13.36138933897018433\r\n"},
+		{MakeHumanDoubleReply(-2), "$2\r\n-2\r\n", ",-2\r\n"},
+		{MakeBooleanReply(true), ":1\r\n", "#t\r\n"},
+		{MakeBooleanReply(false), ":0\r\n", "#f\r\n"},
+		{MakeBigNumberReply("12345678901234567890"), "$20\r\n12345678901234567890\r\n", "(12345678901234567890\r\n"},
+		{MakeVerbatimReply("txt", []byte("hi")), "$2\r\nhi\r\n", "=6\r\ntxt:hi\r\n"},
+		{MakePushReply([]Reply{MakeBulkReply([]byte("message"))}), "*1\r\n$7\r\nmessage\r\n", ">1\r\n$7\r\nmessage\r\n"},
+		{MakeAttributeReply([]Reply{MakeBulkReply([]byte("ttl")), MakeIntReply(3)}, MakeIntReply(7)),
+			":7\r\n", "|1\r\n$3\r\nttl\r\n:3\r\n:7\r\n"},
+		{MakeNullBulkReply(), "$-1\r\n", "_\r\n"},
+		{MakeNullArrayReply(), "*-1\r\n", "_\r\n"},
+		{MakeArrayReply([]Reply{MakeNullBulkReply(), MakeBooleanReply(true)}), "*2\r\n$-1\r\n:1\r\n", "*2\r\n_\r\n#t\r\n"},
+	}
+	for _, c := range cases {
+		if got := string(Encode(c.reply, RESP2)); got != c.resp2 {
+			t.Errorf("RESP2: got %q, want %q", got, c.resp2)
+		}
+		if got := string(Encode(c.reply, RESP3)); got != c.resp3 {
+			t.Errorf("RESP3: got %q, want %q", got, c.resp3)
+		}
+	}
+}
+
+func TestWriterProtocol(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewWriter(&buf)
+	_ = w.Write(MakeBooleanReply(true))
+	w.SetProtocol(RESP3)
+	_ = w.Write(MakeBooleanReply(true))
+	_ = w.Flush()
+	if got := buf.String(); got != ":1\r\n#t\r\n" {
+		t.Errorf("got %q", got)
+	}
+}
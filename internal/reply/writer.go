@@ -0,0 +1,66 @@
+/*
+ * @Description: buffered reply writer
+ * @Autor: HTmonster
+ * @Date: 2026-10-18 10:44:02
+ */
+
+package reply
+
+import (
+	"bufio"
+	"io"
+)
+
+// default size of the output buffer
+const defaultWriterSize = 16 * 1024
+
+// serialize replies into a buffered writer, e.g. net.Conn
+type Writer struct {
+	bufwriter *bufio.Writer
+	protocol  int // RESP2 or RESP3
+}
+
+/**
+ * @description: creat a new reply writer
+ * @param {io.Writer} w
+ * @return {*}
+ */
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{
+		bufwriter: bufio.NewWriterSize(w, defaultWriterSize),
+		protocol:  RESP2,
+	}
+}
+
+/**
+ * @description: set the protocol version used to serialize replies
+ * @param {int} protocol
+ * @return {*}
+ */
+func (w *Writer) SetProtocol(protocol int) {
+	w.protocol = protocol
+}
+
+/**
+ * @description: serialize the reply into the buffer, call Flush to send it
+ * @param {Reply} r
+ * @return {*}
+ */
+func (w *Writer) Write(r Reply) error {
+	_, err := w.bufwriter.Write(Encode(r, w.protocol))
+	return err
+}
+
+/**
+ * @description: send all buffered replies
+ */
+func (w *Writer) Flush() error {
+	return w.bufwriter.Flush()
+}
+
+/**
+ * @description: number of bytes waiting to be flushed
+ */
+func (w *Writer) Buffered() int {
+	return w.bufwriter.Buffered()
+}
@@ -0,0 +1,155 @@
+/*
+ * @Description: inline command parser, e.g. PING\r\n
+ * @Autor: HTmonster
+ * @Date: 2026-10-18 11:34:18
+ */
+
+package request
+
+import (
+	"bufio"
+
+	. "github.com/HTmonster/redissgo/internal/logger"
+)
+
+var errUnbalancedQuotes = protocolError("unbalanced quotes in request")
+
+/**
+ * @description: read one inline command line and split it into parameters
+ * @param {*bufio.Reader} bufreader
+ * @return {*}
+ */
+func parseInline(bufreader *bufio.Reader) ([][]byte, error) {
+	line, err := readLine(bufreader, "too big inline request")
+	if err != nil {
+		return nil, err
+	}
+	// '\n' and an optional '\r'
+	line = line[:len(line)-1]
+	if len(line) > 0 && line[len(line)-1] == '\r' {
+		line = line[:len(line)-1]
+	}
+
+	params, err := splitArgs(line)
+	if err != nil {
+		Log.Error(err)
+		return nil, err
+	}
+	return params, nil
+}
+
+/**
+ * @description: split a line into arguments like redis sdssplitargs
+ * 	"double quotes" support \n \r \t \b \a \\ \" and \xHH escapes,
+ * 	'single quotes' only support \' escape,
+ * 	a closing quote must be followed by a space or the end of line.
+ * @param {[]byte} line
+ * @return {*}
+ */
+func splitArgs(line []byte) ([][]byte, error) {
+	var args [][]byte
+	i, n := 0, len(line)
+	for {
+		// skip blanks
+		for i < n && isSpace(line[i]) {
+			i++
+		}
+		if i >= n {
+			return args, nil
+		}
+
+		var current []byte
+		inDQ, inSQ, done := false, false, false
+		for !done {
+			if inDQ {
+				if i >= n {
+					return nil, errUnbalancedQuotes
+				}
+				if line[i] == '\\' && i+3 < n && line[i+1] == 'x' &&
+					isHexDigit(line[i+2]) && isHexDigit(line[i+3]) {
+					current = append(current, hexDigitToInt(line[i+2])*16+hexDigitToInt(line[i+3]))
+					i += 3
+				} else if line[i] == '\\' && i+1 < n {
+					i++
+					switch line[i] {
+					case 'n':
+						current = append(current, '\n')
+					case 'r':
+						current = append(current, '\r')
+					case 't':
+						current = append(current, '\t')
+					case 'b':
+						current = append(current, '\b')
+					case 'a':
+						current = append(current, '\a')
+					default:
+						current = append(current, line[i])
+					}
+				} else if line[i] == '"' {
+					// closing quote must be followed by a space or nothing at all
+					if i+1 < n && !isSpace(line[i+1]) {
+						return nil, errUnbalancedQuotes
+					}
+					done = true
+				} else {
+					current = append(current, line[i])
+				}
+			} else if inSQ {
+				if i >= n {
+					return nil, errUnbalancedQuotes
+				}
+				if line[i] == '\\' && i+1 < n && line[i+1] == '\'' {
+					i++
+					current = append(current, '\'')
+				} else if line[i] == '\'' {
+					if i+1 < n && !isSpace(line[i+1]) {
+						return nil, errUnbalancedQuotes
+					}
+					done = true
+				} else {
+					current = append(current, line[i])
+				}
+			} else {
+				if i >= n {
+					break
+				}
+				switch line[i] {
+				case ' ', '\n', '\r', '\t', 0:
+					done = true
+				case '"':
+					inDQ = true
+				case '\'':
+					inSQ = true
+				default:
+					current = append(current, line[i])
+				}
+			}
+			if i < n {
+				i++
+			}
+		}
+		if current == nil {
+			current = []byte{}
+		}
+		args = append(args, current)
+	}
+}
+
+func isSpace(b byte) bool {
+	return b == ' ' || b == '\n' || b == '\r' || b == '\t' || b == '\v' || b == '\f' || b == 0
+}
+
+func isHexDigit(b byte) bool {
+	return (b >= '0' && b <= '9') || (b >= 'a' && b <= 'f') || (b >= 'A' && b <= 'F')
+}
+
+func hexDigitToInt(b byte) byte {
+	switch {
+	case b >= '0' && b <= '9':
+		return b - '0'
+	case b >= 'a' && b <= 'f':
+		return b - 'a' + 10
+	default:
+		return b - 'A' + 10
+	}
+}
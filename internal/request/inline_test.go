@@ -0,0 +1,69 @@
+/*
+ * @Description:
+ * @Autor: HTmonster
+ * @Date: 2026-10-18 11:58:03
+ */
+package request
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestSplitArgs(t *testing.T) {
+	cases := []struct {
+		line string
+		want []string
+	}{
+		{"PING", []string{"PING"}},
+		{"  SET  a   b ", []string{"SET", "a", "b"}},
+		{`SET a "b c"`, []string{"SET", "a", "b c"}},
+		{`SET a "\x41\n\"\\"`, []string{"SET", "a", "A\n\"\\"}},
+		{`SET a 'it\'s "raw" \n'`, []string{"SET", "a", `it's "raw" \n`}},
+		{`SET a ""`, []string{"SET", "a", ""}},
+		{"", nil},
+	}
+	for _, c := range cases {
+		got, err := splitArgs([]byte(c.line))
+		if err != nil {
+			t.Errorf("split %q: %s", c.line, err)
+			continue
+		}
+		if len(got) != len(c.want) {
+			t.Errorf("split %q: got %q, want %q", c.line, got, c.want)
+			continue
+		}
+		for i := range got {
+			if string(got[i]) != c.want[i] {
+				t.Errorf("split %q: got %q, want %q", c.line, got, c.want)
+			}
+		}
+	}
+
+	for _, line := range []string{`SET a "b`, `SET a "b"c`, `SET a 'b`, `SET a 'b'c`} {
+		if _, err := splitArgs([]byte(line)); err != errUnbalancedQuotes {
+			t.Errorf("split %q: expected unbalanced quotes, got %v", line, err)
+		}
+	}
+}
+
+func TestParseInlineRequest(t *testing.T) {
+	stream := "PING\r\nSET a \"b c\"\n*2\r\n$3\r\nGET\r\n$1\r\na\r\n\r\n"
+	want := [][]string{{"PING"}, {"SET", "a", "b c"}, {"GET", "a"}, {}}
+
+	ch := ParseRequest(bytes.NewReader([]byte(stream)))
+	for i, params := range want {
+		req := <-ch
+		if req.Err != nil {
+			t.Fatalf("request %d: %s", i, req.Err)
+		}
+		if len(req.Params) != len(params) {
+			t.Fatalf("request %d: got %q, want %q", i, req.Params, params)
+		}
+		for j := range params {
+			if string(req.Params[j]) != params[j] {
+				t.Errorf("request %d: got %q, want %q", i, req.Params, params)
+			}
+		}
+	}
+}
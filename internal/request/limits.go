@@ -0,0 +1,48 @@
+/*
+ * @Description: protocol limits
+ * @Autor: HTmonster
+ * @Date: 2026-10-18 14:32:09
+ */
+
+package request
+
+import (
+	"github.com/HTmonster/redissgo/internal/config"
+)
+
+// max size of an inline request or a header line, PROTO_INLINE_MAX_SIZE in redis
+const maxInlineSize = 64 * 1024
+
+// protocol limits of a connection
+type Limits struct {
+	MaxBulkLen      int64 // proto-max-bulk-len
+	MaxMultibulkLen int64 // proto-max-multibulk-len
+}
+
+/**
+ * @description: read the limits from the server configuration
+ */
+func currentLimits() Limits {
+	return Limits{
+		MaxBulkLen:      int64(config.Properties.ProtoMaxBulkLen),
+		MaxMultibulkLen: int64(config.Properties.ProtoMaxMultibulkLen),
+	}
+}
+
+// error caused by a malformed request, the connection should be closed
+type ProtocolError struct {
+	Msg string
+}
+
+func (e *ProtocolError) Error() string {
+	return e.Msg
+}
+
+/**
+ * @description: make a protocol error
+ * @param {string} msg
+ * @return {*}
+ */
+func protocolError(msg string) error {
+	return &ProtocolError{Msg: msg}
+}
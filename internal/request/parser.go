@@ -8,39 +8,62 @@ package request
 
 import (
 	"bufio"
-	"errors"
 	"io"
 	"strconv"
 
 	. "github.com/HTmonster/redissgo/internal/logger"
 )
 
+// number of parsed requests waiting to be handled
+const pendingRequests = 64
+
 /**
- * @description: given a request, parse it
+ * @description: given a request stream, parse it in a new goroutine.
+ *	Every Request owns its parameters, NewReader is cheaper when
+ *	the caller handles requests synchronously.
  * @param {io.Reader} reader
- * @return {*}
+ * @return {*} one Request per command, closed after EOF or error
  */
 func ParseRequest(reader io.Reader) <-chan *Request {
-	ch := make(chan *Request)
+	ch := make(chan *Request, pendingRequests)
 	go parse(reader, ch)
 	return ch
 }
 
 /**
- * @description: parse request, using channel to send result
+ * @description: parse requests one by one, using channel to send result
  * @param {io.Reader} reader
  * @param {<-chan*Request} ch
  * @return {*}
  */
 func parse(reader io.Reader, ch chan<- *Request) {
+	limits := currentLimits()
+
 	defer func() {
 		if err := recover(); err != nil {
 			Log.Error(err)
 		}
+		close(ch)
 	}()
 
 	bufreader := bufio.NewReader(reader)
+	for {
+		request := parseOne(bufreader, limits)
+		ch <- request
+		if request.Err != nil {
+			// stop at EOF or protocol error
+			return
+		}
+	}
+}
 
+/**
+ * @description: parse a single multibulk or inline request from the stream
+ * @param {*bufio.Reader} bufreader
+ * @param {Limits} limits
+ * @return {*}
+ */
+func parseOne(bufreader *bufio.Reader, limits Limits) *Request {
 	var params [][]byte
 	var err error
 	var paramLen, paramBytes int64
@@ -50,19 +73,31 @@ func parse(reader io.Reader, ch chan<- *Request) {
 	for {
 		switch status {
 		case Begin:
-			if err = parseBegin(bufreader); err != nil {
+			var inline bool
+			if inline, err = parseBegin(bufreader); err != nil {
+				goto End
+			}
+			if inline {
+				params, err = parseInline(bufreader)
+				paramLen = int64(len(params))
 				goto End
 			}
 			status = ParamLen
 		case ParamLen:
-			paramLen, err = parseParamLen(bufreader)
+			paramLen, err = parseParamLen(bufreader, limits)
 			if err != nil {
 				goto End
 			}
 			paramCnt = 0
+			if paramLen <= 0 {
+				// redis ignores empty and negative multibulk requests
+				paramLen = 0
+				goto End
+			}
+			params = make([][]byte, 0, minInt64(paramLen, 1024))
 			status = ParamBytes
 		case ParamBytes:
-			paramBytes, err = parseParamBytes(bufreader)
+			paramBytes, err = parseParamBytes(bufreader, limits)
 			if err != nil {
 				goto End
 			}
@@ -87,58 +122,98 @@ func parse(reader io.Reader, ch chan<- *Request) {
 	}
 
 End:
-	ch <- &Request{
+	return &Request{
 		Params: params,
 		Len:    paramLen,
 		Err:    err,
 	}
-	close(ch)
 }
 
 /**
- * @description: parse request begin
+ * @description: parse request begin, a request not starting with '*' is an inline command
  * @param {*bufio.Reader} bufreader
- * @return {*}
+ * @return {*} inline or not
  */
-func parseBegin(bufreader *bufio.Reader) error {
+func parseBegin(bufreader *bufio.Reader) (bool, error) {
 	b, err := bufreader.ReadByte()
 
 	if err != nil {
-		Log.Error(err)
-		return err
+		if err != io.EOF {
+			Log.Error(err)
+		}
+		return false, err
 	}
 
 	if b != '*' {
-		err := errors.New("Unkonw request begin symbol :" + string(b))
-		Log.Error(err)
-		return err
+		// leave the byte for the inline parser
+		if err := bufreader.UnreadByte(); err != nil {
+			Log.Error(err)
+			return false, err
+		}
+		return true, nil
 	}
 
-	return nil
+	return false, nil
+}
+
+/**
+ * @description: read a line ending with '\n', at most maxInlineSize bytes
+ * @param {*bufio.Reader} bufreader
+ * @param {string} tooBig error message if the line is too long
+ * @return {*} a copy of the line
+ */
+func readLine(bufreader *bufio.Reader, tooBig string) ([]byte, error) {
+	var line []byte
+	for {
+		frag, err := bufreader.ReadSlice('\n')
+		line = append(line, frag...)
+		if len(line) > maxInlineSize {
+			return nil, protocolError(tooBig)
+		}
+		if err == nil {
+			return line, nil
+		}
+		if err != bufio.ErrBufferFull {
+			return nil, err
+		}
+	}
+}
+
+/**
+ * @description: parse a "<number>\r\n" header line
+ * @param {*bufio.Reader} bufreader
+ * @param {string} tooBig error message if the line is too long
+ * @return {*} number, ok or not
+ */
+func parseHeaderNumber(bufreader *bufio.Reader, tooBig string) (int64, bool, error) {
+	msg, err := readLine(bufreader, tooBig)
+	if err != nil {
+		return 0, false, err
+	}
+	if len(msg) < 2 || msg[len(msg)-2] != '\r' {
+		return 0, false, nil
+	}
+	n, err := strconv.ParseInt(string(msg[:len(msg)-2]), 10, 64)
+	if err != nil {
+		return 0, false, nil
+	}
+	return n, true, nil
 }
 
 /**
  * @description: parse request length
  * @param {*bufio.Reader} bufreader
+ * @param {Limits} limits
  * @return {*}
  */
-func parseParamLen(bufreader *bufio.Reader) (int64, error) {
-	msg, err := bufreader.ReadBytes('\n')
+func parseParamLen(bufreader *bufio.Reader, limits Limits) (int64, error) {
+	paramLen, ok, err := parseHeaderNumber(bufreader, "too big mbulk count string")
 	if err != nil {
 		Log.Error(err)
 		return 0, err
 	}
-	if len(msg) == 0 || msg[len(msg)-2] != '\r' {
-		err = errors.New("Error protocol, parameter length error:" + string(msg))
-		Log.Error(err)
-		return 0, err
-	}
-
-	msg = msg[:len(msg)-2]
-
-	paramLen, err := strconv.ParseInt(string(msg), 10, 64)
-	if err != nil {
-		err = errors.New("Error parameter length error:" + string(msg))
+	if !ok || paramLen > limits.MaxMultibulkLen {
+		err = protocolError("invalid multibulk length")
 		Log.Error(err)
 		return 0, err
 	}
@@ -146,7 +221,13 @@ func parseParamLen(bufreader *bufio.Reader) (int64, error) {
 	return paramLen, nil
 }
 
-func parseParamBytes(bufreader *bufio.Reader) (int64, error) {
+/**
+ * @description: parse request parameter size, e.g. $3\r\n
+ * @param {*bufio.Reader} bufreader
+ * @param {Limits} limits
+ * @return {*}
+ */
+func parseParamBytes(bufreader *bufio.Reader, limits Limits) (int64, error) {
 	b, err := bufreader.ReadByte()
 
 	if err != nil {
@@ -155,27 +236,18 @@ func parseParamBytes(bufreader *bufio.Reader) (int64, error) {
 	}
 
 	if b != '$' {
-		err := errors.New("Unkonw request paramater size symbol :" + string(b))
+		err := protocolError("expected '$', got '" + string(b) + "'")
 		Log.Error(err)
 		return 0, err
 	}
 
-	msg, err := bufreader.ReadBytes('\n')
+	paramSize, ok, err := parseHeaderNumber(bufreader, "too big bulk count string")
 	if err != nil {
 		Log.Error(err)
 		return 0, err
 	}
-	if len(msg) == 0 || msg[len(msg)-2] != '\r' {
-		err = errors.New("Error protocol, parameter size error:" + string(msg))
-		Log.Error(err)
-		return 0, err
-	}
-
-	msg = msg[:len(msg)-2]
-
-	paramSize, err := strconv.ParseInt(string(msg), 10, 64)
-	if err != nil {
-		err = errors.New("Error parameter size:" + string(msg))
+	if !ok || paramSize < 0 || paramSize > limits.MaxBulkLen {
+		err = protocolError("invalid bulk length")
 		Log.Error(err)
 		return 0, err
 	}
@@ -183,24 +255,40 @@ func parseParamBytes(bufreader *bufio.Reader) (int64, error) {
 	return paramSize, nil
 }
 
+// large parameters are read in chunks, so a client can not
+// make the server allocate memory it never sends
+const bulkChunkSize = 1024 * 1024
+
 /**
  * @description: read request parameter content (binary safe)
  * @param {bufio.Reader} bufreader
+ * @param {int64} bytes
  * @return {*}
  */
 func parseParamData(bufreader *bufio.Reader, bytes int64) ([]byte, error) {
-
-	msg := make([]byte, bytes+2)
-	_, err := io.ReadFull(bufreader, msg)
-	if err != nil {
-		Log.Error(err)
-		return nil, err
+	total := bytes + 2
+	msg := make([]byte, 0, minInt64(total, bulkChunkSize))
+	for int64(len(msg)) < total {
+		n := minInt64(total-int64(len(msg)), bulkChunkSize)
+		start := len(msg)
+		msg = append(msg, make([]byte, n)...)
+		if _, err := io.ReadFull(bufreader, msg[start:]); err != nil {
+			Log.Error(err)
+			return nil, err
+		}
 	}
-	if len(msg) == 0 || msg[len(msg)-1] != '\n' || msg[len(msg)-2] != '\r' {
-		err = errors.New("Error parameter content:" + string(msg))
+	if msg[len(msg)-1] != '\n' || msg[len(msg)-2] != '\r' {
+		err := protocolError("invalid bulk data")
 		Log.Error(err)
 		return nil, err
 	}
 	msg = msg[0 : len(msg)-2]
 	return msg, nil
 }
+
+func minInt64(a, b int64) int64 {
+	if a < b {
+		return a
+	}
+	return b
+}
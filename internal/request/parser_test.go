@@ -6,9 +6,15 @@
 package request
 
 import (
+	"bufio"
 	"bytes"
 	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
 	"testing"
+
+	. "github.com/HTmonster/redissgo/internal/logger"
 )
 
 func TestParseRequest(t *testing.T) {
@@ -44,3 +50,104 @@ func TestParseRequest(t *testing.T) {
 	}
 	fmt.Print(<-ch)
 }
+
+func TestParsePipelinedRequest(t *testing.T) {
+	request := []byte("*3\r\n$3\r\nSET\r\n$3\r\nkey\r\n$5\r\nvalue\r\n" +
+		"*2\r\n$3\r\nGET\r\n$3\r\nkey\r\n" +
+		"*0\r\n" +
+		"*2\r\n$4\r\nINCR\r\n$3\r\ncnt\r\n")
+	want := [][]string{
+		{"SET", "key", "value"},
+		{"GET", "key"},
+		{},
+		{"INCR", "cnt"},
+	}
+
+	var got []*Request
+	for req := range ParseRequest(bytes.NewReader(request)) {
+		got = append(got, req)
+	}
+	if len(got) != len(want)+1 {
+		t.Fatalf("got %d requests, want %d and EOF", len(got), len(want))
+	}
+	for i, params := range want {
+		if got[i].Err != nil {
+			t.Fatalf("request %d: %s", i, got[i].Err)
+		}
+		if len(got[i].Params) != len(params) {
+			t.Fatalf("request %d: got %d params, want %d", i, len(got[i].Params), len(params))
+		}
+		for j := range params {
+			if string(got[i].Params[j]) != params[j] {
+				t.Errorf("request %d param %d: got %q, want %q", i, j, got[i].Params[j], params[j])
+			}
+		}
+	}
+	if got[len(want)].Err != io.EOF {
+		t.Errorf("last request should carry EOF, got %v", got[len(want)].Err)
+	}
+}
+
+func TestParseRequestLimits(t *testing.T) {
+	limits := Limits{MaxBulkLen: 8, MaxMultibulkLen: 4}
+	cases := []struct {
+		request string
+		errMsg  string
+	}{
+		{"*5\r\n", "invalid multibulk length"},
+		{"*99999999999\r\n", "invalid multibulk length"},
+		{"*abc\r\n", "invalid multibulk length"},
+		{"*1\r\n$9\r\n", "invalid bulk length"},
+		{"*1\r\n$4000000000\r\n", "invalid bulk length"},
+		{"*1\r\n$-1\r\n", "invalid bulk length"},
+		{"*1\r\n:1\r\n", "expected '$', got ':'"},
+		{"*1\r\n$3\r\nabcde", "invalid bulk data"},
+		{"*1" + strings.Repeat("1", maxInlineSize) + "\r\n", "too big mbulk count string"},
+		{"PING " + strings.Repeat("a", maxInlineSize) + "\r\n", "too big inline request"},
+	}
+	for _, c := range cases {
+		req := parseOne(bufio.NewReader(strings.NewReader(c.request)), limits)
+		perr, ok := req.Err.(*ProtocolError)
+		if !ok || perr.Msg != c.errMsg {
+			t.Errorf("request %.32q: got error %v, want %q", c.request, req.Err, c.errMsg)
+		}
+	}
+
+	// within limits
+	req := parseOne(bufio.NewReader(strings.NewReader("*4\r\n$8\r\n12345678\r\n$0\r\n\r\n$1\r\na\r\n$1\r\nb\r\n")), limits)
+	if req.Err != nil || len(req.Params) != 4 {
+		t.Errorf("request within limits: got %v %q", req.Err, req.Params)
+	}
+	// negative multibulk length is ignored
+	req = parseOne(bufio.NewReader(strings.NewReader("*-1\r\n")), limits)
+	if req.Err != nil || len(req.Params) != 0 {
+		t.Errorf("negative multibulk length: got %v %q", req.Err, req.Params)
+	}
+}
+
+func FuzzParseRequest(f *testing.F) {
+	f.Add([]byte("*3\r\n$3\r\nSET\r\n$3\r\nkey\r\n$5\r\nvalue\r\n"))
+	f.Add([]byte("*1\r\n$4\r\nPING\r\n*2\r\n$4\r\nECHO\r\n$0\r\n\r\n"))
+	f.Add([]byte("SET a \"b \\x41\" 'c'\r\nPING\n"))
+	f.Add([]byte("*-1\r\n*0\r\n"))
+	f.Add([]byte("*1\r\n$-5\r\n"))
+
+	Log.SetOutput(ioutil.Discard)
+	limits := Limits{MaxBulkLen: 1024, MaxMultibulkLen: 64}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		bufreader := bufio.NewReader(bytes.NewReader(data))
+		for i := 0; i <= len(data); i++ {
+			req := parseOne(bufreader, limits)
+			if req.Err != nil {
+				return
+			}
+			if int64(len(req.Params)) != req.Len {
+				t.Fatalf("got %d params, header says %d", len(req.Params), req.Len)
+			}
+			if req.Len > limits.MaxMultibulkLen {
+				t.Fatalf("multibulk length %d exceeds the limit", req.Len)
+			}
+		}
+		t.Fatalf("parser made no progress on %q", data)
+	})
+}
@@ -0,0 +1,281 @@
+/*
+ * @Description: streaming RESP reader, reuses buffers between requests
+ * @Autor: HTmonster
+ * @Date: 2026-10-18 15:10:36
+ */
+
+package request
+
+import (
+	"bufio"
+	"io"
+	"sync"
+)
+
+// size of the input buffer of a connection
+const readerBufferSize = 16 * 1024
+
+// arenas bigger than this are not kept after a request
+const maxIdleArenaSize = 1024 * 1024
+
+// pool of readers, including their input buffers and argument arenas
+var readerPool = sync.Pool{
+	New: func() interface{} {
+		return &Reader{
+			bufreader: bufio.NewReaderSize(nil, readerBufferSize),
+			arena:     make([]byte, 0, 4096),
+		}
+	},
+}
+
+// synchronous request reader.
+// The Request returned by Next, including its parameters, is only valid
+// until the next call to Next, callers must copy anything they keep.
+type Reader struct {
+	bufreader *bufio.Reader
+	limits    Limits
+
+	arena   []byte   // parameter data of the current request
+	offsets []int    // end offset of every parameter in arena
+	params  [][]byte // reused parameter slice
+	request Request  // reused request
+}
+
+/**
+ * @description: get a reader from the pool, call Release when the connection is closed
+ * @param {io.Reader} reader
+ * @return {*}
+ */
+func NewReader(reader io.Reader) *Reader {
+	r := readerPool.Get().(*Reader)
+	r.bufreader.Reset(reader)
+	r.limits = currentLimits()
+	return r
+}
+
+/**
+ * @description: put the reader back to the pool
+ */
+func (r *Reader) Release() {
+	r.bufreader.Reset(nil)
+	if cap(r.arena) > maxIdleArenaSize {
+		r.arena = make([]byte, 0, 4096)
+	}
+	r.arena = r.arena[:0]
+	r.offsets = r.offsets[:0]
+	for i := range r.params {
+		r.params[i] = nil
+	}
+	r.params = r.params[:0]
+	readerPool.Put(r)
+}
+
+/**
+ * @description: number of bytes which can be read without blocking,
+ *	0 means no pipelined request is waiting
+ */
+func (r *Reader) Buffered() int {
+	return r.bufreader.Buffered()
+}
+
+/**
+ * @description: read more input into the buffer without consuming it, so a
+ *	closed connection is noticed while a request is still running
+ * @return {*} nil if some data was read, bufio.ErrBufferFull if the buffer is full
+ */
+func (r *Reader) Fill() error {
+	_, err := r.bufreader.Peek(r.bufreader.Buffered() + 1)
+	return err
+}
+
+/**
+ * @description: read the next multibulk or inline request
+ * @return {*} request valid until the next call, io.EOF or *ProtocolError
+ */
+func (r *Reader) Next() (*Request, error) {
+	if cap(r.arena) > maxIdleArenaSize {
+		r.arena = make([]byte, 0, 4096)
+	}
+	r.arena = r.arena[:0]
+	r.offsets = r.offsets[:0]
+
+	b, err := r.bufreader.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+
+	if b != '*' {
+		_ = r.bufreader.UnreadByte()
+		params, err := parseInline(r.bufreader)
+		if err != nil {
+			return nil, err
+		}
+		for _, param := range params {
+			r.arena = append(r.arena, param...)
+			r.offsets = append(r.offsets, len(r.arena))
+		}
+		return r.build(), nil
+	}
+
+	paramLen, ok, err := r.readNumber("too big mbulk count string")
+	if err != nil {
+		return nil, err
+	}
+	if !ok || paramLen > r.limits.MaxMultibulkLen {
+		return nil, protocolError("invalid multibulk length")
+	}
+
+	for i := int64(0); i < paramLen; i++ {
+		if err := r.readParam(); err != nil {
+			return nil, err
+		}
+	}
+	return r.build(), nil
+}
+
+/**
+ * @description: build the request from the arena
+ */
+func (r *Reader) build() *Request {
+	r.params = r.params[:0]
+	start := 0
+	for _, end := range r.offsets {
+		r.params = append(r.params, r.arena[start:end:end])
+		start = end
+	}
+	r.request = Request{
+		Params: r.params,
+		Len:    int64(len(r.params)),
+	}
+	return &r.request
+}
+
+/**
+ * @description: read one "$<len>\r\n<data>\r\n" parameter into the arena
+ */
+func (r *Reader) readParam() error {
+	b, err := r.bufreader.ReadByte()
+	if err != nil {
+		return err
+	}
+	if b != '$' {
+		return protocolError("expected '$', got '" + string(b) + "'")
+	}
+
+	size, ok, err := r.readNumber("too big bulk count string")
+	if err != nil {
+		return err
+	}
+	if !ok || size < 0 || size > r.limits.MaxBulkLen {
+		return protocolError("invalid bulk length")
+	}
+
+	// grow the arena chunk by chunk, do not trust the announced size
+	start := len(r.arena)
+	total := int(size) + 2
+	for len(r.arena)-start < total {
+		n := total - (len(r.arena) - start)
+		if n > bulkChunkSize {
+			n = bulkChunkSize
+		}
+		from := len(r.arena)
+		r.arena = growBytes(r.arena, n)
+		if _, err := io.ReadFull(r.bufreader, r.arena[from:]); err != nil {
+			return err
+		}
+	}
+
+	end := len(r.arena)
+	if r.arena[end-2] != '\r' || r.arena[end-1] != '\n' {
+		return protocolError("invalid bulk data")
+	}
+	r.arena = r.arena[:end-2]
+	r.offsets = append(r.offsets, len(r.arena))
+	return nil
+}
+
+/**
+ * @description: read a "<number>\r\n" header line without allocation
+ * @param {string} tooBig error message if the line is too long
+ * @return {*} number, ok or not
+ */
+func (r *Reader) readNumber(tooBig string) (int64, bool, error) {
+	line, err := r.bufreader.ReadSlice('\n')
+	if err == bufio.ErrBufferFull {
+		// pathological header, fall back to the copying reader,
+		// line points into the input buffer and must be copied first
+		line = append([]byte(nil), line...)
+		rest, err := readLine(r.bufreader, tooBig)
+		if err != nil {
+			return 0, false, err
+		}
+		if len(line)+len(rest) > maxInlineSize {
+			return 0, false, protocolError(tooBig)
+		}
+		line = append(line, rest...)
+	} else if err != nil {
+		return 0, false, err
+	}
+
+	if len(line) < 2 || line[len(line)-2] != '\r' {
+		return 0, false, nil
+	}
+	n, ok := parseInt64(line[:len(line)-2])
+	return n, ok, nil
+}
+
+/**
+ * @description: extend b by n bytes, reusing its capacity if possible
+ */
+func growBytes(b []byte, n int) []byte {
+	if cap(b)-len(b) >= n {
+		return b[:len(b)+n]
+	}
+	grown := make([]byte, len(b)+n, 2*cap(b)+n)
+	copy(grown, b)
+	return grown
+}
+
+/**
+ * @description: parse a base 10 integer like strconv.ParseInt without allocation
+ * @param {[]byte} b
+ * @return {*} number, ok or not
+ */
+func parseInt64(b []byte) (int64, bool) {
+	if len(b) == 0 {
+		return 0, false
+	}
+	neg := false
+	if b[0] == '+' || b[0] == '-' {
+		neg = b[0] == '-'
+		b = b[1:]
+		if len(b) == 0 {
+			return 0, false
+		}
+	}
+
+	// accumulate as a negative number, so MinInt64 does not overflow
+	const minInt64 = -1 << 63
+	var n int64
+	for _, c := range b {
+		if c < '0' || c > '9' {
+			return 0, false
+		}
+		if n < minInt64/10 {
+			return 0, false
+		}
+		n *= 10
+		d := int64(c - '0')
+		if n < minInt64+d {
+			return 0, false
+		}
+		n -= d
+	}
+	if !neg {
+		if n == minInt64 {
+			return 0, false
+		}
+		n = -n
+	}
+	return n, true
+}
@@ -0,0 +1,174 @@
+/*
+ * @Description:
+ * @Autor: HTmonster
+ * @Date: 2026-10-18 15:47:51
+ */
+package request
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"io/ioutil"
+	"math"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+
+	. "github.com/HTmonster/redissgo/internal/logger"
+)
+
+func TestReaderNext(t *testing.T) {
+	stream := "*3\r\n$3\r\nSET\r\n$3\r\nkey\r\n$5\r\nvalue\r\n" +
+		"PING \"a b\"\r\n" +
+		"*0\r\n" +
+		"*2\r\n$3\r\nGET\r\n$0\r\n\r\n"
+	want := [][]string{{"SET", "key", "value"}, {"PING", "a b"}, {}, {"GET", ""}}
+
+	reader := NewReader(strings.NewReader(stream))
+	defer reader.Release()
+	for i, params := range want {
+		req, err := reader.Next()
+		if err != nil {
+			t.Fatalf("request %d: %s", i, err)
+		}
+		if len(req.Params) != len(params) || req.Len != int64(len(params)) {
+			t.Fatalf("request %d: got %q, want %q", i, req.Params, params)
+		}
+		for j := range params {
+			if string(req.Params[j]) != params[j] {
+				t.Errorf("request %d: got %q, want %q", i, req.Params, params)
+			}
+		}
+	}
+	if _, err := reader.Next(); err != io.EOF {
+		t.Errorf("expected EOF, got %v", err)
+	}
+}
+
+func TestReaderProtocolError(t *testing.T) {
+	reader := NewReader(strings.NewReader("*1\r\n$-1\r\n"))
+	defer reader.Release()
+	_, err := reader.Next()
+	if perr, ok := err.(*ProtocolError); !ok || perr.Msg != "invalid bulk length" {
+		t.Errorf("got %v, want invalid bulk length", err)
+	}
+}
+
+func TestParseInt64(t *testing.T) {
+	inputs := []string{"0", "-0", "+7", "007", "-12", "", "-", "+", "1a", " 1",
+		strconv.FormatInt(math.MaxInt64, 10), strconv.FormatInt(math.MinInt64, 10),
+		"9223372036854775808", "-9223372036854775809", "99999999999999999999"}
+	for _, in := range inputs {
+		want, err := strconv.ParseInt(in, 10, 64)
+		got, ok := parseInt64([]byte(in))
+		if ok != (err == nil) || (ok && got != want) {
+			t.Errorf("parse %q: got %d %v, want %d %v", in, got, ok, want, err)
+		}
+	}
+}
+
+// the reader must accept exactly what the channel based parser accepts
+func FuzzReader(f *testing.F) {
+	f.Add([]byte("*3\r\n$3\r\nSET\r\n$3\r\nkey\r\n$5\r\nvalue\r\n"))
+	f.Add([]byte("PING\r\nSET a \"b c\"\n*0\r\n"))
+	f.Add([]byte("*1\r\n$-1\r\n"))
+
+	Log.SetOutput(ioutil.Discard)
+	limits := Limits{MaxBulkLen: 1024, MaxMultibulkLen: 64}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		bufreader := bufio.NewReader(bytes.NewReader(data))
+		reader := NewReader(bytes.NewReader(data))
+		reader.limits = limits
+		defer reader.Release()
+
+		for i := 0; i <= len(data); i++ {
+			want := parseOne(bufreader, limits)
+			got, err := reader.Next()
+			if (want.Err == nil) != (err == nil) {
+				t.Fatalf("parser error %v, reader error %v", want.Err, err)
+			}
+			if err != nil {
+				return
+			}
+			if len(got.Params) != len(want.Params) {
+				t.Fatalf("parser got %q, reader got %q", want.Params, got.Params)
+			}
+			for j := range got.Params {
+				if !bytes.Equal(got.Params[j], want.Params[j]) {
+					t.Fatalf("parser got %q, reader got %q", want.Params, got.Params)
+				}
+			}
+		}
+	})
+}
+
+/**
+ * @description: a pipeline of n SET commands
+ */
+func pipeline(n int) []byte {
+	var buf bytes.Buffer
+	for i := 0; i < n; i++ {
+		key := "key:" + strconv.Itoa(i)
+		buf.WriteString("*3\r\n$3\r\nSET\r\n$" + strconv.Itoa(len(key)) + "\r\n" + key + "\r\n")
+		buf.WriteString("$16\r\n0123456789abcdef\r\n")
+	}
+	return buf.Bytes()
+}
+
+func BenchmarkParseRequest(b *testing.B) {
+	data := pipeline(1000)
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for req := range ParseRequest(bytes.NewReader(data)) {
+			_ = req
+		}
+	}
+}
+
+func BenchmarkReader(b *testing.B) {
+	data := pipeline(1000)
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		reader := NewReader(bytes.NewReader(data))
+		for {
+			if _, err := reader.Next(); err != nil {
+				break
+			}
+		}
+		reader.Release()
+	}
+}
+
+func TestReaderFill(t *testing.T) {
+	server, client := net.Pipe()
+	defer server.Close()
+	r := NewReader(server)
+	defer r.Release()
+
+	go func() {
+		_, _ = client.Write([]byte("PING\r\n"))
+		_ = client.Close()
+	}()
+	if err := r.Fill(); err != nil {
+		t.Fatalf("Fill: %v", err)
+	}
+	// buffered input is kept until the connection is closed
+	for {
+		if err := r.Fill(); err != nil {
+			if err != io.EOF {
+				t.Fatalf("Fill should end with io.EOF, got %v", err)
+			}
+			break
+		}
+	}
+	req, err := r.Next()
+	if err != nil || string(req.Params[0]) != "PING" {
+		t.Fatalf("the filled request should still be readable, got %v %v", req, err)
+	}
+}
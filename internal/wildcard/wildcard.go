@@ -0,0 +1,118 @@
+/*
+ * @Description: glob-style pattern matching of KEYS, SCAN and MATCH options
+ * @Autor: HTmonster
+ * @Date: 2026-10-19 00:52:26
+ */
+package wildcard
+
+// Supported patterns, same as redis stringmatchlen:
+//	?       matches a single byte
+//	*       matches any number of bytes
+//	[abc]   matches one of the bytes, [^abc] negates, [a-z] is a range
+//	\x      escapes the special meaning of x
+
+/**
+ * @description: check whether s matches the glob pattern
+ * @param {[]byte} pattern
+ * @param {[]byte} s
+ * @return {*}
+ */
+func Match(pattern, s []byte) bool {
+	// position to retry when a later part fails after the last star
+	starP, starS := -1, 0
+	p, i := 0, 0
+	for i < len(s) {
+		if p < len(pattern) {
+			switch pattern[p] {
+			case '*':
+				// collapse successive stars
+				for p < len(pattern) && pattern[p] == '*' {
+					p++
+				}
+				if p == len(pattern) {
+					return true
+				}
+				starP, starS = p, i
+				continue
+			case '?':
+				p++
+				i++
+				continue
+			case '[':
+				if next, ok := matchClass(pattern, p, s[i]); ok {
+					p = next
+					i++
+					continue
+				}
+			case '\\':
+				if p+1 < len(pattern) {
+					if pattern[p+1] == s[i] {
+						p += 2
+						i++
+						continue
+					}
+					break
+				}
+				// a trailing backslash matches itself
+				fallthrough
+			default:
+				if pattern[p] == s[i] {
+					p++
+					i++
+					continue
+				}
+			}
+		}
+		// mismatch, let the last star eat one more byte
+		if starP < 0 {
+			return false
+		}
+		starS++
+		p, i = starP, starS
+	}
+	for p < len(pattern) && pattern[p] == '*' {
+		p++
+	}
+	return p == len(pattern)
+}
+
+/**
+ * @description: match c against the class starting at pattern[p] == '['
+ * @return {*} position after the class, matched or not
+ */
+func matchClass(pattern []byte, p int, c byte) (int, bool) {
+	p++
+	not := p < len(pattern) && pattern[p] == '^'
+	if not {
+		p++
+	}
+	match := false
+	for p < len(pattern) && pattern[p] != ']' {
+		switch {
+		case pattern[p] == '\\' && p+1 < len(pattern):
+			p++
+			if pattern[p] == c {
+				match = true
+			}
+		case p+2 < len(pattern) && pattern[p+1] == '-' && pattern[p+2] != ']':
+			start, end := pattern[p], pattern[p+2]
+			if start > end {
+				start, end = end, start
+			}
+			if c >= start && c <= end {
+				match = true
+			}
+			p += 2
+		default:
+			if pattern[p] == c {
+				match = true
+			}
+		}
+		p++
+	}
+	// an unclosed class ends at the end of the pattern
+	if p < len(pattern) {
+		p++
+	}
+	return p, match != not
+}
@@ -0,0 +1,46 @@
+/*
+ * @Description:
+ * @Autor: HTmonster
+ * @Date: 2026-10-19 01:05:43
+ */
+package wildcard
+
+import "testing"
+
+func TestMatch(t *testing.T) {
+	cases := []struct {
+		pattern, s string
+		want       bool
+	}{
+		{"*", "", true},
+		{"*", "anything", true},
+		{"h?llo", "hello", true},
+		{"h?llo", "hllo", false},
+		{"h*llo", "hllo", true},
+		{"h*llo", "heeeello", true},
+		{"h*llo", "hello world", false},
+		{"h[ae]llo", "hallo", true},
+		{"h[ae]llo", "hillo", false},
+		{"h[^e]llo", "hallo", true},
+		{"h[^e]llo", "hello", false},
+		{"h[a-b]llo", "hbllo", true},
+		{"h[b-a]llo", "hallo", true},
+		{"h[a-b]llo", "hcllo", false},
+		{"h\\*llo", "h*llo", true},
+		{"h\\*llo", "hello", false},
+		{"user:*:name", "user:1000:name", true},
+		{"user:*:name", "user:1000:age", false},
+		{"*a*b", "xxaxxbxb", true},
+		{"a**", "a", true},
+		{"[abc", "a", true},
+		{"[abc", "ab", false},
+		{"abc\\", "abc\\", true},
+		{"", "", true},
+		{"", "a", false},
+	}
+	for _, c := range cases {
+		if got := Match([]byte(c.pattern), []byte(c.s)); got != c.want {
+			t.Errorf("match %q %q: got %v, want %v", c.pattern, c.s, got, c.want)
+		}
+	}
+}
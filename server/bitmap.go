@@ -0,0 +1,714 @@
+/*
+ * @Description: bitmap commands over string values
+ * @Autor: HTmonster
+ * @Date: 2026-10-18 21:40:13
+ */
+
+package server
+
+import (
+	"encoding/binary"
+	"math"
+	"math/bits"
+	"strings"
+
+	"github.com/HTmonster/redissgo/datastruct/dict"
+	"github.com/HTmonster/redissgo/internal/config"
+	"github.com/HTmonster/redissgo/internal/reply"
+)
+
+func init() {
+	registerCommand("setbit", execSetBit, 4, flagWrite).keys(1, 1, 1)
+	registerCommand("getbit", execGetBit, 3, flagReadonly|flagFast).keys(1, 1, 1)
+	registerCommand("bitcount", execBitCount, -2, flagReadonly).keys(1, 1, 1)
+	registerCommand("bitpos", execBitPos, -3, flagReadonly).keys(1, 1, 1)
+	registerCommand("bitop", execBitOp, -4, flagWrite).keys(2, -1, 1)
+	registerCommand("bitfield", execBitField, -2, flagWrite).keys(1, 1, 1)
+	registerCommand("bitfield_ro", execBitFieldRO, -2, flagReadonly|flagFast).keys(1, 1, 1)
+}
+
+// strings are shared with readers once stored, bit commands never write
+// into a stored string, they modify a copy and store the copy
+
+//------------ bit helpers --------------
+
+/**
+ * @description: get a bit, bit 0 is the most significant bit of the first byte,
+ *	bits after the end of the string are 0
+ */
+func getBit(bytes []byte, offset int64) byte {
+	i := offset >> 3
+	if i >= int64(len(bytes)) {
+		return 0
+	}
+	return (bytes[i] >> (7 - uint(offset&7))) & 1
+}
+
+/**
+ * @description: set a bit, bytes must be long enough
+ */
+func setBit(bytes []byte, offset int64, value byte) {
+	i, mask := offset>>3, byte(1)<<(7-uint(offset&7))
+	if value == 1 {
+		bytes[i] |= mask
+	} else {
+		bytes[i] &^= mask
+	}
+}
+
+/**
+ * @description: count the set bits of bytes
+ */
+func popCount(bytes []byte) int64 {
+	var count int
+	for len(bytes) >= 8 {
+		count += bits.OnesCount64(binary.LittleEndian.Uint64(bytes))
+		bytes = bytes[8:]
+	}
+	for _, b := range bytes {
+		count += bits.OnesCount8(b)
+	}
+	return int64(count)
+}
+
+/**
+ * @description: count the set bits between two bit offsets (inclusive)
+ */
+func countBits(bytes []byte, start, end int64) int64 {
+	first, last := start>>3, end>>3
+	headMask := byte(0xff) >> uint(start&7)
+	tailMask := byte(0xff) << uint(7-end&7)
+	if first == last {
+		return int64(bits.OnesCount8(bytes[first] & headMask & tailMask))
+	}
+	return int64(bits.OnesCount8(bytes[first]&headMask)) +
+		popCount(bytes[first+1:last]) +
+		int64(bits.OnesCount8(bytes[last]&tailMask))
+}
+
+/**
+ * @description: find the first bit equal to bit between two bit offsets (inclusive)
+ * @return {*} offset, -1 if not found
+ */
+func findBit(bytes []byte, bit byte, start, end int64) int64 {
+	// a byte without the wanted bit
+	skip := byte(0)
+	if bit == 0 {
+		skip = 0xff
+	}
+	for pos := start; pos <= end; {
+		if pos&7 == 0 && pos+7 <= end && bytes[pos>>3] == skip {
+			pos += 8
+			continue
+		}
+		if getBit(bytes, pos) == bit {
+			return pos
+		}
+		pos++
+	}
+	return -1
+}
+
+/**
+ * @description: parse a bit offset, at most proto-max-bulk-len bytes can be addressed
+ * @param {[]byte} arg offset, "#N" means N*width if hashAllowed
+ * @param {bool} hashAllowed
+ * @param {int64} width
+ * @return {*}
+ */
+func parseBitOffset(arg []byte, hashAllowed bool, width int64) (int64, reply.ErrorReply) {
+	errReply := reply.MakeErrReply("ERR bit offset is not an integer or out of range")
+	hash := hashAllowed && len(arg) > 0 && arg[0] == '#'
+	if hash {
+		arg = arg[1:]
+	}
+	offset, ok := parseCanonicalInt(arg)
+	if !ok || offset < 0 {
+		return 0, errReply
+	}
+	if hash {
+		if offset > math.MaxInt64/width {
+			return 0, errReply
+		}
+		offset *= width
+	}
+	if offset>>3 >= int64(config.Properties.ProtoMaxBulkLen) {
+		return 0, errReply
+	}
+	return offset, nil
+}
+
+/**
+ * @description: clamp a [start, end] range like GETRANGE, negative indexes count from the end
+ * @param {int64} total length of the string, in bytes or bits
+ * @return {*} range, empty or not
+ */
+func clampRange(start, end, total int64) (int64, int64, bool) {
+	if start < 0 {
+		start += total
+	}
+	if end < 0 {
+		end += total
+	}
+	if start < 0 {
+		start = 0
+	}
+	if end < 0 {
+		end = 0
+	}
+	if end >= total {
+		end = total - 1
+	}
+	return start, end, start > end
+}
+
+// a start/end range of BITCOUNT and BITPOS
+type bitRange struct {
+	start, end int64
+	hasEnd     bool
+	isBit      bool // BIT unit instead of BYTE
+}
+
+/**
+ * @description: parse [start [end [BYTE|BIT]]]
+ * @param {[][]byte} args
+ * @param {bool} endRequired the end can not be omitted if start is given
+ * @return {*} range, nil if not given
+ */
+func parseBitRange(args [][]byte, endRequired bool) (*bitRange, reply.ErrorReply) {
+	if len(args) == 0 {
+		return nil, nil
+	}
+	if len(args) > 3 || (endRequired && len(args) == 1) {
+		return nil, reply.MakeSyntaxErrReply()
+	}
+	r := &bitRange{end: -1}
+	var ok bool
+	if r.start, ok = parseCanonicalInt(args[0]); !ok {
+		return nil, reply.MakeNotIntegerErrReply()
+	}
+	if len(args) >= 2 {
+		if r.end, ok = parseCanonicalInt(args[1]); !ok {
+			return nil, reply.MakeNotIntegerErrReply()
+		}
+		r.hasEnd = true
+	}
+	if len(args) == 3 {
+		switch strings.ToLower(string(args[2])) {
+		case "bit":
+			r.isBit = true
+		case "byte":
+		default:
+			return nil, reply.MakeSyntaxErrReply()
+		}
+	}
+	return r, nil
+}
+
+/**
+ * @description: convert a range to bit offsets inside the string
+ * @return {*} first bit, last bit, empty or not
+ */
+func (r *bitRange) bitOffsets(size int64) (int64, int64, bool) {
+	if r == nil {
+		return 0, size*8 - 1, size == 0
+	}
+	if r.isBit {
+		return clampRange(r.start, r.end, size*8)
+	}
+	start, end, empty := clampRange(r.start, r.end, size)
+	return start * 8, end*8 + 7, empty
+}
+
+//------------ SETBIT GETBIT --------------
+
+/**
+ * @description: SETBIT key offset value
+ */
+func execSetBit(c *Client, args [][]byte) reply.Reply {
+	offset, errReply := parseBitOffset(args[1], false, 1)
+	if errReply != nil {
+		return errReply
+	}
+	if len(args[2]) != 1 || (args[2][0] != '0' && args[2][0] != '1') {
+		return reply.MakeErrReply("ERR bit is not an integer or out of range")
+	}
+	value := args[2][0] - '0'
+
+	var result reply.Reply
+	c.db.Update(string(args[0]), func(entry *dict.Entry) {
+		var old []byte
+		if entry.Exists {
+			bytes, ok := asString(entry.Value)
+			if !ok {
+				result = reply.MakeWrongTypeErrReply()
+				return
+			}
+			old = bytes
+		}
+		oldBit := getBit(old, offset)
+		result = reply.MakeIntReply(int64(oldBit))
+		size := offset>>3 + 1
+		if entry.Exists && oldBit == value && size <= int64(len(old)) {
+			return
+		}
+		bytes := growString(old, size)
+		setBit(bytes, offset, value)
+		entry.Value, entry.Exists = bytes, true
+	})
+	return result
+}
+
+/**
+ * @description: GETBIT key offset
+ */
+func execGetBit(c *Client, args [][]byte) reply.Reply {
+	offset, errReply := parseBitOffset(args[1], false, 1)
+	if errReply != nil {
+		return errReply
+	}
+	var bit byte
+	if errReply := c.db.readString(string(args[0]), func(bytes []byte) {
+		bit = getBit(bytes, offset)
+	}); errReply != nil {
+		return errReply
+	}
+	return reply.MakeIntReply(int64(bit))
+}
+
+//------------ BITCOUNT BITPOS --------------
+
+/**
+ * @description: BITCOUNT key [start end [BYTE|BIT]]
+ */
+func execBitCount(c *Client, args [][]byte) reply.Reply {
+	r, errReply := parseBitRange(args[1:], true)
+	if errReply != nil {
+		return errReply
+	}
+	var count int64
+	if errReply := c.db.readString(string(args[0]), func(bytes []byte) {
+		if start, end, empty := r.bitOffsets(int64(len(bytes))); !empty {
+			count = countBits(bytes, start, end)
+		}
+	}); errReply != nil {
+		return errReply
+	}
+	return reply.MakeIntReply(count)
+}
+
+/**
+ * @description: BITPOS key bit [start [end [BYTE|BIT]]]
+ */
+func execBitPos(c *Client, args [][]byte) reply.Reply {
+	if len(args[1]) != 1 || (args[1][0] != '0' && args[1][0] != '1') {
+		return reply.MakeErrReply("ERR The bit argument must be 1 or 0.")
+	}
+	bit := args[1][0] - '0'
+	r, errReply := parseBitRange(args[2:], false)
+	if errReply != nil {
+		return errReply
+	}
+
+	var pos int64
+	if errReply := c.db.readString(string(args[0]), func(bytes []byte) {
+		if bytes == nil {
+			// a missing key is an empty string, all zeros
+			if bit == 1 {
+				pos = -1
+			}
+			return
+		}
+		start, end, empty := r.bitOffsets(int64(len(bytes)))
+		if empty {
+			pos = -1
+			return
+		}
+		pos = findBit(bytes, bit, start, end)
+		// without an explicit end the string is seen as padded with zeros
+		if pos == -1 && bit == 0 && (r == nil || !r.hasEnd) {
+			pos = end + 1
+		}
+	}); errReply != nil {
+		return errReply
+	}
+	return reply.MakeIntReply(pos)
+}
+
+//------------ BITOP --------------
+
+/**
+ * @description: BITOP AND|OR|XOR|NOT destkey key [key ...]
+ */
+func execBitOp(c *Client, args [][]byte) reply.Reply {
+	op := strings.ToLower(string(args[0]))
+	if op != "and" && op != "or" && op != "xor" && op != "not" {
+		return reply.MakeSyntaxErrReply()
+	}
+	keys := args[2:]
+	if op == "not" && len(keys) != 1 {
+		return reply.MakeErrReply("ERR BITOP NOT must be called with a single source key.")
+	}
+
+	// missing keys are empty strings
+	sources := make([][]byte, len(keys))
+	size := 0
+	for i, key := range keys {
+		bytes, errReply := c.db.getAsString(string(key))
+		if errReply != nil {
+			return errReply
+		}
+		sources[i] = bytes
+		if len(bytes) > size {
+			size = len(bytes)
+		}
+	}
+
+	dest := string(args[1])
+	if size == 0 {
+		// an empty result only removes dest
+		if c.db.Remove(dest) == 0 {
+			c.keepVersions()
+		}
+		return reply.MakeIntReply(0)
+	}
+
+	result := make([]byte, size)
+	copy(result, sources[0])
+	switch op {
+	case "not":
+		for i := range result {
+			result[i] = ^result[i]
+		}
+	case "and":
+		for _, src := range sources[1:] {
+			for i := range result {
+				if i < len(src) {
+					result[i] &= src[i]
+				} else {
+					result[i] = 0
+				}
+			}
+		}
+	case "or":
+		for _, src := range sources[1:] {
+			for i := range src {
+				result[i] |= src[i]
+			}
+		}
+	case "xor":
+		for _, src := range sources[1:] {
+			for i := range src {
+				result[i] ^= src[i]
+			}
+		}
+	}
+	c.db.PutEntityWithExpire(dest, result, 0)
+	return reply.MakeIntReply(int64(size))
+}
+
+//------------ BITFIELD --------------
+
+// overflow behaviours of BITFIELD
+const (
+	overflowWrap = iota
+	overflowSat
+	overflowFail
+)
+
+// BITFIELD sub commands
+const (
+	bitfieldGet = iota
+	bitfieldSet
+	bitfieldIncrBy
+)
+
+// a parsed BITFIELD sub command
+type bitfieldOp struct {
+	opcode   int
+	signed   bool
+	width    uint
+	offset   int64
+	value    int64 // value of SET, increment of INCRBY
+	overflow int
+}
+
+/**
+ * @description: parse a type like i16 or u8
+ * @return {*} signed or not, width
+ */
+func parseBitfieldType(arg []byte) (bool, uint, reply.ErrorReply) {
+	errReply := reply.MakeErrReply("ERR Invalid bitfield type. Use something like i16 u8. Note that u64 is not supported but i64 is.")
+	if len(arg) < 2 {
+		return false, 0, errReply
+	}
+	signed := arg[0] == 'i' || arg[0] == 'I'
+	if !signed && arg[0] != 'u' && arg[0] != 'U' {
+		return false, 0, errReply
+	}
+	width, ok := parseCanonicalInt(arg[1:])
+	if !ok || width < 1 || (signed && width > 64) || (!signed && width > 63) {
+		return false, 0, errReply
+	}
+	return signed, uint(width), nil
+}
+
+/**
+ * @description: parse the sub commands of BITFIELD
+ * @param {[][]byte} args
+ * @return {*} operations, size in bytes needed by the writes
+ */
+func parseBitfieldOps(args [][]byte) ([]*bitfieldOp, int64, reply.ErrorReply) {
+	var ops []*bitfieldOp
+	var writeSize int64
+	overflow := overflowWrap
+	for i := 0; i < len(args); i++ {
+		remain := len(args) - i - 1
+		op := &bitfieldOp{}
+		switch sub := strings.ToLower(string(args[i])); {
+		case sub == "get" && remain >= 2:
+			op.opcode = bitfieldGet
+		case sub == "set" && remain >= 3:
+			op.opcode = bitfieldSet
+		case sub == "incrby" && remain >= 3:
+			op.opcode = bitfieldIncrBy
+		case sub == "overflow" && remain >= 1:
+			switch strings.ToLower(string(args[i+1])) {
+			case "wrap":
+				overflow = overflowWrap
+			case "sat":
+				overflow = overflowSat
+			case "fail":
+				overflow = overflowFail
+			default:
+				return nil, 0, reply.MakeErrReply("ERR Invalid OVERFLOW type specified")
+			}
+			i++
+			continue
+		default:
+			return nil, 0, reply.MakeSyntaxErrReply()
+		}
+
+		var errReply reply.ErrorReply
+		if op.signed, op.width, errReply = parseBitfieldType(args[i+1]); errReply != nil {
+			return nil, 0, errReply
+		}
+		if op.offset, errReply = parseBitOffset(args[i+2], true, int64(op.width)); errReply != nil {
+			return nil, 0, errReply
+		}
+		op.overflow = overflow
+		i += 2
+		if op.opcode != bitfieldGet {
+			value, ok := parseCanonicalInt(args[i+1])
+			if !ok {
+				return nil, 0, reply.MakeNotIntegerErrReply()
+			}
+			op.value = value
+			if size := (op.offset+int64(op.width)-1)>>3 + 1; size > writeSize {
+				writeSize = size
+			}
+			i++
+		}
+		ops = append(ops, op)
+	}
+	return ops, writeSize, nil
+}
+
+/**
+ * @description: read an unsigned field of width bits
+ */
+func getUnsignedField(bytes []byte, offset int64, width uint) uint64 {
+	var value uint64
+	for i := uint(0); i < width; i++ {
+		value = value<<1 | uint64(getBit(bytes, offset+int64(i)))
+	}
+	return value
+}
+
+/**
+ * @description: read a two's complement field of width bits
+ */
+func getSignedField(bytes []byte, offset int64, width uint) int64 {
+	value := getUnsignedField(bytes, offset, width)
+	// sign extension
+	if width < 64 && value&(1<<(width-1)) != 0 {
+		value |= math.MaxUint64 << width
+	}
+	return int64(value)
+}
+
+/**
+ * @description: write the low width bits of value
+ */
+func setField(bytes []byte, offset int64, width uint, value uint64) {
+	for i := uint(0); i < width; i++ {
+		setBit(bytes, offset+int64(i), byte(value>>(width-1-i))&1)
+	}
+}
+
+/**
+ * @description: add incr to an unsigned field value and handle overflow
+ * @return {*} result, overflowed or not
+ */
+func unsignedFieldAdd(value uint64, incr int64, width uint, overflow int) (uint64, bool) {
+	max := uint64(1)<<width - 1
+	switch {
+	case value > max || (incr > 0 && uint64(incr) > max-value):
+		if overflow == overflowSat {
+			return max, true
+		}
+	case incr < 0 && uint64(-incr) > value:
+		if overflow == overflowSat {
+			return 0, true
+		}
+	default:
+		return value + uint64(incr), false
+	}
+	// wrap around, the result of FAIL is not used
+	return (value + uint64(incr)) & max, true
+}
+
+/**
+ * @description: add incr to a signed field value and handle overflow
+ * @return {*} result, overflowed or not
+ */
+func signedFieldAdd(value int64, incr int64, width uint, overflow int) (int64, bool) {
+	max := int64(math.MaxInt64)
+	if width < 64 {
+		max = int64(1)<<(width-1) - 1
+	}
+	min := -max - 1
+	switch {
+	case value > max || (incr > 0 && value > max-incr):
+		if overflow == overflowSat {
+			return max, true
+		}
+	case value < min || (incr < 0 && value < min-incr):
+		if overflow == overflowSat {
+			return min, true
+		}
+	default:
+		return value + incr, false
+	}
+	// wrap around and sign extend, the result of FAIL is not used
+	result := uint64(value) + uint64(incr)
+	if width < 64 {
+		mask := uint64(math.MaxUint64) << width
+		if result&(1<<(width-1)) != 0 {
+			result |= mask
+		} else {
+			result &^= mask
+		}
+	}
+	return int64(result), true
+}
+
+/**
+ * @description: run a BITFIELD operation on bytes
+ * @return {*} reply of the operation
+ */
+func (op *bitfieldOp) apply(bytes []byte) reply.Reply {
+	if op.signed {
+		old := getSignedField(bytes, op.offset, op.width)
+		var value int64
+		var overflowed bool
+		switch op.opcode {
+		case bitfieldGet:
+			return reply.MakeIntReply(old)
+		case bitfieldSet:
+			value, overflowed = signedFieldAdd(op.value, 0, op.width, op.overflow)
+		case bitfieldIncrBy:
+			value, overflowed = signedFieldAdd(old, op.value, op.width, op.overflow)
+		}
+		if overflowed && op.overflow == overflowFail {
+			return reply.MakeNullBulkReply()
+		}
+		setField(bytes, op.offset, op.width, uint64(value))
+		if op.opcode == bitfieldSet {
+			return reply.MakeIntReply(old)
+		}
+		return reply.MakeIntReply(value)
+	}
+
+	old := getUnsignedField(bytes, op.offset, op.width)
+	var value uint64
+	var overflowed bool
+	switch op.opcode {
+	case bitfieldGet:
+		return reply.MakeIntReply(int64(old))
+	case bitfieldSet:
+		value, overflowed = unsignedFieldAdd(uint64(op.value), 0, op.width, op.overflow)
+	case bitfieldIncrBy:
+		value, overflowed = unsignedFieldAdd(old, op.value, op.width, op.overflow)
+	}
+	if overflowed && op.overflow == overflowFail {
+		return reply.MakeNullBulkReply()
+	}
+	setField(bytes, op.offset, op.width, value)
+	if op.opcode == bitfieldSet {
+		return reply.MakeIntReply(int64(old))
+	}
+	return reply.MakeIntReply(int64(value))
+}
+
+/**
+ * @description: BITFIELD and BITFIELD_RO
+ */
+func bitfieldGeneric(c *Client, args [][]byte, readonly bool) reply.Reply {
+	ops, writeSize, errReply := parseBitfieldOps(args[1:])
+	if errReply != nil {
+		return errReply
+	}
+	key := string(args[0])
+	replies := make([]reply.Reply, len(ops))
+
+	if writeSize == 0 {
+		// only GET, missing keys are not created
+		if errReply := c.db.readString(key, func(bytes []byte) {
+			for i, op := range ops {
+				replies[i] = op.apply(bytes)
+			}
+		}); errReply != nil {
+			return errReply
+		}
+		c.keepVersions()
+		return reply.MakeArrayReply(replies)
+	}
+	if readonly {
+		return reply.MakeErrReply("ERR BITFIELD_RO only supports the GET subcommand")
+	}
+
+	var result reply.Reply
+	c.db.Update(key, func(entry *dict.Entry) {
+		var old []byte
+		if entry.Exists {
+			bytes, ok := asString(entry.Value)
+			if !ok {
+				result = reply.MakeWrongTypeErrReply()
+				return
+			}
+			old = bytes
+		}
+		bytes := growString(old, writeSize)
+		for i, op := range ops {
+			replies[i] = op.apply(bytes)
+		}
+		entry.Value, entry.Exists = bytes, true
+		result = reply.MakeArrayReply(replies)
+	})
+	return result
+}
+
+/**
+ * @description: BITFIELD key [GET encoding offset] [SET encoding offset value] [INCRBY encoding offset increment] [OVERFLOW WRAP|SAT|FAIL]
+ */
+func execBitField(c *Client, args [][]byte) reply.Reply {
+	return bitfieldGeneric(c, args, false)
+}
+
+/**
+ * @description: BITFIELD_RO key [GET encoding offset ...]
+ */
+func execBitFieldRO(c *Client, args [][]byte) reply.Reply {
+	return bitfieldGeneric(c, args, true)
+}
@@ -0,0 +1,231 @@
+/*
+ * @Description:
+ * @Autor: HTmonster
+ * @Date: 2026-10-18 22:10:41
+ */
+package server
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/HTmonster/redissgo/internal/reply"
+)
+
+func TestSetBitGetBit(t *testing.T) {
+	h := NewHandler()
+	defer h.Close()
+	c := newTestClient(h)
+	c.db = h.selectDB(0)
+	c.db.PutEntity("list", struct{}{})
+
+	runCases(t, h, c, []cmdCase{
+		{[]string{"SETBIT", "k", "7", "1"}, ":0\r\n"},
+		{[]string{"SETBIT", "k", "7", "1"}, ":1\r\n"},
+		{[]string{"GETBIT", "k", "0"}, ":0\r\n"},
+		{[]string{"GETBIT", "k", "7"}, ":1\r\n"},
+		{[]string{"GETBIT", "k", "100"}, ":0\r\n"},
+		{[]string{"GET", "k"}, "$1\r\n\x01\r\n"},
+		{[]string{"SETBIT", "k", "17", "0"}, ":0\r\n"},
+		{[]string{"STRLEN", "k"}, ":3\r\n"},
+		{[]string{"SETBIT", "k", "7", "0"}, ":1\r\n"},
+		{[]string{"GET", "k"}, "$3\r\n\x00\x00\x00\r\n"},
+		{[]string{"SET", "n", "1"}, "+OK\r\n"},
+		{[]string{"SETBIT", "n", "6", "1"}, ":0\r\n"},
+		{[]string{"GET", "n"}, "$1\r\n3\r\n"},
+		{[]string{"SETBIT", "k", "-1", "1"}, "-ERR bit offset is not an integer or out of range\r\n"},
+		{[]string{"SETBIT", "k", "4294967296", "1"}, "-ERR bit offset is not an integer or out of range\r\n"},
+		{[]string{"SETBIT", "k", "1", "2"}, "-ERR bit is not an integer or out of range\r\n"},
+		{[]string{"GETBIT", "k", "x"}, "-ERR bit offset is not an integer or out of range\r\n"},
+		{[]string{"SETBIT", "list", "1", "1"}, "-WRONGTYPE Operation against a key holding the wrong kind of value\r\n"},
+		{[]string{"GETBIT", "list", "1"}, "-WRONGTYPE Operation against a key holding the wrong kind of value\r\n"},
+	})
+}
+
+func TestSetBitInPlace(t *testing.T) {
+	h := NewHandler()
+	defer h.Close()
+	c := newTestClient(h)
+	c.db = h.selectDB(0)
+
+	// a bit inside the string is written without copying it
+	execString(h, c, "SETBIT", "k", strconv.Itoa(8<<20-1), "1")
+	stored := func() *byte {
+		value, _ := c.db.GetEntity("k")
+		return &value.([]byte)[0]
+	}
+	first := stored()
+	execString(h, c, "SETBIT", "k", "0", "1")
+	execString(h, c, "BITFIELD", "k", "SET", "u8", "8", "255")
+	if stored() != first {
+		t.Errorf("SETBIT and BITFIELD inside the string should not copy it")
+	}
+
+	// replies are copies
+	got := h.exec(c, [][]byte{[]byte("GETRANGE"), []byte("k"), []byte("0"), []byte("0")})
+	execString(h, c, "SETBIT", "k", "1", "1")
+	if string(reply.Encode(got, c.protocol)) != "$1\r\n\x80\r\n" {
+		t.Errorf("GETRANGE reply changed by SETBIT: %q", reply.Encode(got, c.protocol))
+	}
+
+	// readers never see a write in progress
+	var wg sync.WaitGroup
+	for w := 0; w < 4; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			c := newTestClient(h)
+			for i := 0; i < 200; i++ {
+				if w%2 == 0 {
+					execString(h, c, "SETBIT", "k", strconv.Itoa(i*8+w), strconv.Itoa(i%2))
+				} else {
+					execString(h, c, "GETRANGE", "k", "0", "64")
+					execString(h, c, "BITCOUNT", "k", "0", "64")
+					execString(h, c, "BITFIELD_RO", "k", "GET", "u8", "0")
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+}
+
+func TestBitCount(t *testing.T) {
+	h := NewHandler()
+	defer h.Close()
+	c := newTestClient(h)
+
+	runCases(t, h, c, []cmdCase{
+		{[]string{"BITCOUNT", "none"}, ":0\r\n"},
+		{[]string{"SET", "k", "foobar"}, "+OK\r\n"},
+		{[]string{"BITCOUNT", "k"}, ":26\r\n"},
+		{[]string{"BITCOUNT", "k", "0", "0"}, ":4\r\n"},
+		{[]string{"BITCOUNT", "k", "1", "1"}, ":6\r\n"},
+		{[]string{"BITCOUNT", "k", "1", "1", "BYTE"}, ":6\r\n"},
+		{[]string{"BITCOUNT", "k", "5", "30", "BIT"}, ":17\r\n"},
+		{[]string{"BITCOUNT", "k", "-2", "-1"}, ":7\r\n"},
+		{[]string{"BITCOUNT", "k", "-1", "-2"}, ":0\r\n"},
+		{[]string{"BITCOUNT", "k", "0", "100"}, ":26\r\n"},
+		{[]string{"BITCOUNT", "k", "0"}, "-ERR syntax error\r\n"},
+		{[]string{"BITCOUNT", "k", "0", "1", "WORD"}, "-ERR syntax error\r\n"},
+		{[]string{"BITCOUNT", "k", "a", "1"}, "-ERR value is not an integer or out of range\r\n"},
+	})
+}
+
+func TestBitPos(t *testing.T) {
+	h := NewHandler()
+	defer h.Close()
+	c := newTestClient(h)
+
+	runCases(t, h, c, []cmdCase{
+		{[]string{"BITPOS", "none", "1"}, ":-1\r\n"},
+		{[]string{"BITPOS", "none", "0"}, ":0\r\n"},
+		{[]string{"SET", "k", "\xff\xf0\x00"}, "+OK\r\n"},
+		{[]string{"BITPOS", "k", "0"}, ":12\r\n"},
+		{[]string{"SET", "k", "\x00\xff\xf0"}, "+OK\r\n"},
+		{[]string{"BITPOS", "k", "1", "0"}, ":8\r\n"},
+		{[]string{"BITPOS", "k", "1", "2"}, ":16\r\n"},
+		{[]string{"BITPOS", "k", "1", "2", "-1", "BYTE"}, ":16\r\n"},
+		{[]string{"BITPOS", "k", "1", "7", "15", "BIT"}, ":8\r\n"},
+		{[]string{"BITPOS", "k", "1", "7", "-3", "BIT"}, ":8\r\n"},
+		{[]string{"BITPOS", "k", "0", "9", "-1", "BIT"}, ":20\r\n"},
+		{[]string{"SET", "k", "\x00\x00\x00"}, "+OK\r\n"},
+		{[]string{"BITPOS", "k", "1"}, ":-1\r\n"},
+		{[]string{"SET", "k", "\xff\xff\xff"}, "+OK\r\n"},
+		{[]string{"BITPOS", "k", "0"}, ":24\r\n"},
+		{[]string{"BITPOS", "k", "0", "1"}, ":24\r\n"},
+		{[]string{"BITPOS", "k", "0", "0", "-1"}, ":-1\r\n"},
+		{[]string{"BITPOS", "k", "1", "5", "3"}, ":-1\r\n"},
+		{[]string{"BITPOS", "k", "2"}, "-ERR The bit argument must be 1 or 0.\r\n"},
+		{[]string{"BITPOS", "k", "1", "0", "1", "BIT", "x"}, "-ERR syntax error\r\n"},
+	})
+}
+
+func TestBitOp(t *testing.T) {
+	h := NewHandler()
+	defer h.Close()
+	c := newTestClient(h)
+	c.db = h.selectDB(0)
+	c.db.PutEntity("list", struct{}{})
+
+	runCases(t, h, c, []cmdCase{
+		{[]string{"SET", "a", "foobar"}, "+OK\r\n"},
+		{[]string{"SET", "b", "abcdef"}, "+OK\r\n"},
+		{[]string{"BITOP", "AND", "dest", "a", "b"}, ":6\r\n"},
+		{[]string{"GET", "dest"}, "$6\r\n`bc`ab\r\n"},
+		{[]string{"BITOP", "OR", "dest", "a", "b"}, ":6\r\n"},
+		{[]string{"GET", "dest"}, "$6\r\ngoofev\r\n"},
+		{[]string{"BITOP", "XOR", "dest", "a", "b"}, ":6\r\n"},
+		{[]string{"GET", "dest"}, "$6\r\n\x07\r\x0c\x06\x04\x14\r\n"},
+		{[]string{"SET", "c", "\x0f\xff"}, "+OK\r\n"},
+		{[]string{"BITOP", "NOT", "dest", "c"}, ":2\r\n"},
+		{[]string{"GET", "dest"}, "$2\r\n\xf0\x00\r\n"},
+		{[]string{"SET", "d", "\xff"}, "+OK\r\n"},
+		{[]string{"BITOP", "AND", "dest", "c", "d"}, ":2\r\n"},
+		{[]string{"GET", "dest"}, "$2\r\n\x0f\x00\r\n"},
+		{[]string{"BITOP", "AND", "dest", "c", "none"}, ":2\r\n"},
+		{[]string{"GET", "dest"}, "$2\r\n\x00\x00\r\n"},
+		{[]string{"BITOP", "OR", "dest", "none"}, ":0\r\n"},
+		{[]string{"GET", "dest"}, "$-1\r\n"},
+		{[]string{"BITOP", "NOT", "dest", "a", "b"}, "-ERR BITOP NOT must be called with a single source key.\r\n"},
+		{[]string{"BITOP", "NAND", "dest", "a"}, "-ERR syntax error\r\n"},
+		{[]string{"BITOP", "OR", "dest", "a", "list"}, "-WRONGTYPE Operation against a key holding the wrong kind of value\r\n"},
+	})
+}
+
+func TestBitField(t *testing.T) {
+	h := NewHandler()
+	defer h.Close()
+	c := newTestClient(h)
+
+	runCases(t, h, c, []cmdCase{
+		{[]string{"BITFIELD", "k", "INCRBY", "i5", "100", "1", "GET", "u4", "0"}, "*2\r\n:1\r\n:0\r\n"},
+		{[]string{"BITFIELD", "ro", "GET", "u8", "0"}, "*1\r\n:0\r\n"},
+		{[]string{"GET", "ro"}, "$-1\r\n"},
+
+		{[]string{"BITFIELD", "s", "SET", "i8", "0", "127", "INCRBY", "i8", "0", "1"}, "*2\r\n:0\r\n:-128\r\n"},
+		{[]string{"BITFIELD", "s", "OVERFLOW", "SAT", "INCRBY", "i8", "0", "-1"}, "*1\r\n:-128\r\n"},
+		{[]string{"BITFIELD", "s", "OVERFLOW", "FAIL", "INCRBY", "i8", "0", "-1", "GET", "i8", "0"}, "*2\r\n$-1\r\n:-128\r\n"},
+		{[]string{"BITFIELD", "s", "SET", "u8", "#1", "255", "GET", "u8", "8", "GET", "i8", "8"}, "*3\r\n:0\r\n:255\r\n:-1\r\n"},
+		{[]string{"BITFIELD", "s", "SET", "u8", "#1", "-1"}, "*1\r\n:255\r\n"},
+		{[]string{"BITFIELD", "s", "OVERFLOW", "SAT", "SET", "u8", "#1", "300", "GET", "u8", "#1"}, "*2\r\n:255\r\n:255\r\n"},
+		{[]string{"BITFIELD", "s", "SET", "u8", "#1", "256"}, "*1\r\n:255\r\n"},
+		{[]string{"BITFIELD", "s", "GET", "u8", "#1"}, "*1\r\n:0\r\n"},
+
+		{[]string{"BITFIELD", "big", "SET", "i64", "0", "9223372036854775807", "INCRBY", "i64", "0", "1"},
+			"*2\r\n:0\r\n:-9223372036854775808\r\n"},
+		{[]string{"BITFIELD", "big", "OVERFLOW", "SAT", "INCRBY", "i64", "0", "-1"}, "*1\r\n:-9223372036854775808\r\n"},
+		{[]string{"BITFIELD", "big", "SET", "u63", "0", "-1", "GET", "u63", "0"}, "*2\r\n:4611686018427387904\r\n:9223372036854775807\r\n"},
+		{[]string{"STRLEN", "big"}, ":8\r\n"},
+
+		{[]string{"BITFIELD", "k", "GET", "u64", "0"}, "-ERR Invalid bitfield type. Use something like i16 u8. Note that u64 is not supported but i64 is.\r\n"},
+		{[]string{"BITFIELD", "k", "GET", "i0", "0"}, "-ERR Invalid bitfield type. Use something like i16 u8. Note that u64 is not supported but i64 is.\r\n"},
+		{[]string{"BITFIELD", "k", "GET", "u8", "-1"}, "-ERR bit offset is not an integer or out of range\r\n"},
+		{[]string{"BITFIELD", "k", "OVERFLOW", "WRAPS"}, "-ERR Invalid OVERFLOW type specified\r\n"},
+		{[]string{"BITFIELD", "k", "GET", "u8"}, "-ERR syntax error\r\n"},
+		{[]string{"BITFIELD", "k", "INCRBY", "u8", "0", "x"}, "-ERR value is not an integer or out of range\r\n"},
+		{[]string{"BITFIELD", "k"}, "*0\r\n"},
+		{[]string{"BITFIELD_RO", "k", "GET", "u4", "0"}, "*1\r\n:0\r\n"},
+		{[]string{"BITFIELD_RO", "k", "SET", "u4", "0", "1"}, "-ERR BITFIELD_RO only supports the GET subcommand\r\n"},
+	})
+}
+
+func TestBitFieldUnsignedSat(t *testing.T) {
+	h := NewHandler()
+	defer h.Close()
+	c := newTestClient(h)
+
+	// from the BITFIELD documentation
+	want := []string{
+		"*2\r\n:1\r\n:1\r\n",
+		"*2\r\n:2\r\n:2\r\n",
+		"*2\r\n:3\r\n:3\r\n",
+		"*2\r\n:0\r\n:3\r\n",
+	}
+	for i, w := range want {
+		got := execString(h, c, "BITFIELD", "k", "INCRBY", "u2", "100", "1", "OVERFLOW", "SAT", "INCRBY", "u2", "102", "1")
+		if got != w {
+			t.Errorf("call %d: got %q, want %q", i, got, w)
+		}
+	}
+}
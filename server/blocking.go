@@ -0,0 +1,293 @@
+/*
+ * @Description: clients blocked on keys, used by blocking list and sorted set commands
+ * @Autor: HTmonster
+ * @Date: 2026-10-19 00:52:31
+ */
+
+package server
+
+import (
+	"bufio"
+	"container/list"
+	"math"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/HTmonster/redissgo/internal/reply"
+)
+
+// a key in a database
+type blockingKey struct {
+	db  int
+	key string
+}
+
+// a client blocked on some keys
+type waiter struct {
+	db       int
+	keys     []string
+	dest     string // BLMOVE destination, becomes ready when the client is served
+	readonly bool   // serving does not modify the keys, like XREAD
+
+	// take data from the value at key for the client,
+	// false if there is nothing to take
+	serve func(db *DB, key string) (reply.Reply, bool)
+
+	result   chan reply.Reply // receives the reply once served
+	elements []*list.Element  // positions in the queues, nil once unregistered
+}
+
+// blocked clients of every key, served in FIFO order.
+// Lock order: the registry lock, then segment locks.
+type blockingRegistry struct {
+	mu     sync.Mutex
+	queues map[blockingKey]*list.List
+}
+
+/**
+ * @description: make an empty registry
+ */
+func makeBlockingRegistry() *blockingRegistry {
+	return &blockingRegistry{
+		queues: make(map[blockingKey]*list.List),
+	}
+}
+
+/**
+ * @description: add a waiter at the end of the queue of each of its keys
+ * @param {*waiter} w
+ * @return {*}
+ */
+func (r *blockingRegistry) register(w *waiter) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, key := range w.keys {
+		k := blockingKey{db: w.db, key: key}
+		queue, ok := r.queues[k]
+		if !ok {
+			queue = list.New()
+			r.queues[k] = queue
+		}
+		w.elements = append(w.elements, queue.PushBack(w))
+	}
+}
+
+/**
+ * @description: remove a waiter from all its queues, the lock must be held
+ */
+func (r *blockingRegistry) unregisterLocked(w *waiter) {
+	for i, key := range w.keys {
+		k := blockingKey{db: w.db, key: key}
+		queue := r.queues[k]
+		queue.Remove(w.elements[i])
+		if queue.Len() == 0 {
+			delete(r.queues, k)
+		}
+	}
+	w.elements = nil
+}
+
+/**
+ * @description: remove a waiter which gives up waiting
+ * @param {*waiter} w
+ * @return {*} false if it has been served already
+ */
+func (r *blockingRegistry) unregister(w *waiter) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if w.elements == nil {
+		return false
+	}
+	r.unregisterLocked(w)
+	return true
+}
+
+/**
+ * @description: whether some clients are blocked on the key
+ */
+func (r *blockingRegistry) hasWaiters(k blockingKey) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	_, ok := r.queues[k]
+	return ok
+}
+
+/**
+ * @description: keys of a database with blocked clients
+ */
+func (r *blockingRegistry) keysOf(db int) []blockingKey {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var keys []blockingKey
+	for k := range r.queues {
+		if k.db == db {
+			keys = append(keys, k)
+		}
+	}
+	return keys
+}
+
+/**
+ * @description: serve the clients blocked on keys which may hold data now,
+ *	the first client of a queue is served first
+ * @param {*Handler} h
+ * @param {[]blockingKey} ready
+ * @return {*}
+ */
+func (r *blockingRegistry) serve(h *Handler, ready []blockingKey) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for len(ready) > 0 {
+		k := ready[0]
+		ready = ready[1:]
+		for {
+			queue, ok := r.queues[k]
+			if !ok {
+				break
+			}
+			w := queue.Front().Value.(*waiter)
+			db := h.selectDB(k.db)
+			written := []string{k.key}
+			if w.dest != "" {
+				written = append(written, w.dest)
+			}
+			if !w.readonly {
+				db.watched.beginWrite(written)
+			}
+			result, served := w.serve(db, k.key)
+			if !w.readonly {
+				db.watched.endWrite(written, served)
+			}
+			if !served {
+				break
+			}
+			r.unregisterLocked(w)
+			w.result <- result
+			if w.dest != "" {
+				ready = append(ready, blockingKey{db: k.db, key: w.dest})
+			}
+		}
+	}
+}
+
+//------------ client side --------------
+
+/**
+ * @description: mark a key as ready if clients are blocked on it,
+ *	they are served after the running command
+ * @param {*DB} db
+ * @param {string} key
+ * @return {*}
+ */
+func (c *Client) signalKeyReady(db *DB, key string) {
+	k := blockingKey{db: db.index, key: key}
+	// EXEC holds segment locks, the registry lock must not be taken after them
+	if c.views != nil || c.handler.blocking.hasWaiters(k) {
+		c.readyKeys = append(c.readyKeys, k)
+	}
+}
+
+/**
+ * @description: block until the waiter is served, the timeout expires,
+ *	the connection is closed or the server shuts down
+ * @param {*waiter} w
+ * @param {time.Duration} timeout 0 blocks forever
+ * @param {reply.Reply} timeoutReply
+ * @return {*}
+ */
+func (c *Client) block(w *waiter, timeout time.Duration, timeoutReply reply.Reply) reply.Reply {
+	// commands of a transaction never block
+	if c.views != nil {
+		return timeoutReply
+	}
+	// nothing is written while waiting, the waiter is served by others
+	c.endWrite(false)
+
+	h := c.handler
+	w.result = make(chan reply.Reply, 1)
+	h.blocking.register(w)
+
+	// data may have been added since the caller looked at the keys
+	ready := make([]blockingKey, len(w.keys))
+	for i, key := range w.keys {
+		ready[i] = blockingKey{db: w.db, key: key}
+	}
+	h.blocking.serve(h, ready)
+
+	var expired <-chan time.Time
+	if timeout > 0 {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		expired = timer.C
+	}
+	closed, stopWatching := c.watchConnection()
+	defer stopWatching()
+
+	select {
+	case result := <-w.result:
+		return result
+	case <-expired:
+	case <-closed:
+	case <-h.closeChan:
+	}
+	if h.blocking.unregister(w) {
+		return timeoutReply
+	}
+	// served while giving up
+	return <-w.result
+}
+
+/**
+ * @description: watch the connection while the client is blocked,
+ *	pipelined requests are buffered and handled later
+ * @return {*} channel closed when the connection is closed, function to stop watching
+ */
+func (c *Client) watchConnection() (<-chan struct{}, func()) {
+	closed := make(chan struct{})
+	if c.reader == nil {
+		return closed, func() {}
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for {
+			err := c.reader.Fill()
+			if err == bufio.ErrBufferFull {
+				// nothing more can be read, stop watching
+				return
+			}
+			if err != nil {
+				// also the read deadline set to stop watching
+				close(closed)
+				return
+			}
+		}
+	}()
+
+	stop := func() {
+		// interrupt the pending read, the reader is used again after that
+		_ = c.conn.SetReadDeadline(time.Now())
+		<-done
+		_ = c.conn.SetReadDeadline(time.Time{})
+	}
+	return closed, stop
+}
+
+/**
+ * @description: parse the timeout of a blocking command, in seconds
+ */
+func parseTimeout(arg []byte) (time.Duration, reply.ErrorReply) {
+	seconds, err := strconv.ParseFloat(string(arg), 64)
+	if err != nil || math.IsNaN(seconds) || math.IsInf(seconds, 0) {
+		return 0, reply.MakeErrReply("ERR timeout is not a float or out of range")
+	}
+	if seconds < 0 {
+		return 0, reply.MakeErrReply("ERR timeout is negative")
+	}
+	if seconds > float64(math.MaxInt64)/float64(time.Second) {
+		return 0, reply.MakeErrReply("ERR timeout is out of range")
+	}
+	return time.Duration(seconds * float64(time.Second)), nil
+}
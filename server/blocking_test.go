@@ -0,0 +1,332 @@
+/*
+ * @Description:
+ * @Autor: HTmonster
+ * @Date: 2026-10-19 01:30:12
+ */
+package server
+
+import (
+	"context"
+	"net"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/HTmonster/redissgo/client"
+)
+
+/**
+ * @description: serve a handler on a random port
+ * @return {*} handler, address, function to shut down the server
+ */
+func startTestServer(t *testing.T) (*Handler, string, func()) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := NewHandler()
+	var conns sync.WaitGroup
+	accepting := make(chan struct{})
+	go func() {
+		defer close(accepting)
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			conns.Add(1)
+			go func() {
+				defer conns.Done()
+				_ = h.Handle(context.Background(), conn)
+			}()
+		}
+	}()
+	return h, listener.Addr().String(), func() {
+		_ = listener.Close()
+		<-accepting
+		_ = h.Close()
+		conns.Wait()
+	}
+}
+
+/**
+ * @description: dial the test server
+ */
+func dial(t *testing.T, addr string) *client.Conn {
+	conn, err := client.Dial(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return conn
+}
+
+/**
+ * @description: number of clients blocked on a key
+ */
+func blockedOn(h *Handler, db int, key string) int {
+	h.blocking.mu.Lock()
+	defer h.blocking.mu.Unlock()
+	if queue, ok := h.blocking.queues[blockingKey{db: db, key: key}]; ok {
+		return queue.Len()
+	}
+	return 0
+}
+
+/**
+ * @description: wait until n clients are blocked on a key
+ */
+func waitBlocked(t *testing.T, h *Handler, db int, key string, n int) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for blockedOn(h, db, key) != n {
+		if time.Now().After(deadline) {
+			t.Fatalf("%d clients blocked on %q, want %d", blockedOn(h, db, key), key, n)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// reply of a command sent in another goroutine
+type asyncReply struct {
+	reply interface{}
+	err   error
+}
+
+/**
+ * @description: send a command without waiting for the reply
+ */
+func doAsync(conn *client.Conn, cmd string, args ...interface{}) <-chan asyncReply {
+	ch := make(chan asyncReply, 1)
+	go func() {
+		r, err := conn.Do(cmd, args...)
+		ch <- asyncReply{r, err}
+	}()
+	return ch
+}
+
+/**
+ * @description: wait for an async reply and compare it
+ */
+func expectReply(t *testing.T, ch <-chan asyncReply, want interface{}) {
+	t.Helper()
+	select {
+	case got := <-ch:
+		if got.err != nil {
+			t.Fatalf("unexpected error: %v", got.err)
+		}
+		if !reflect.DeepEqual(got.reply, want) {
+			t.Errorf("got %#v, want %#v", got.reply, want)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatalf("no reply, want %#v", want)
+	}
+}
+
+/**
+ * @description: make the reply of BLPOP
+ */
+func keyValue(key, value string) []interface{} {
+	return []interface{}{[]byte(key), []byte(value)}
+}
+
+func TestBLPopFIFO(t *testing.T) {
+	h, addr, stop := startTestServer(t)
+	defer stop()
+	first, second, pusher := dial(t, addr), dial(t, addr), dial(t, addr)
+	defer first.Close()
+	defer second.Close()
+	defer pusher.Close()
+
+	r1 := doAsync(first, "BLPOP", "q", "0")
+	waitBlocked(t, h, 0, "q", 1)
+	r2 := doAsync(second, "BRPOP", "other", "q", "0")
+	waitBlocked(t, h, 0, "q", 2)
+
+	if n, err := client.Int64(pusher.Do("RPUSH", "q", "a", "b")); err != nil || n != 2 {
+		t.Fatalf("RPUSH: %d %v", n, err)
+	}
+	expectReply(t, r1, keyValue("q", "a"))
+	expectReply(t, r2, keyValue("q", "b"))
+	if blockedOn(h, 0, "q") != 0 || blockedOn(h, 0, "other") != 0 {
+		t.Error("served clients should be unregistered from all keys")
+	}
+	if n, err := client.Int64(pusher.Do("LLEN", "q")); err != nil || n != 0 {
+		t.Error("the list should be empty")
+	}
+}
+
+func TestBLPopNoBlock(t *testing.T) {
+	h := NewHandler()
+	defer h.Close()
+	c := newTestClient(h)
+
+	runCases(t, h, c, []cmdCase{
+		{[]string{"RPUSH", "b", "x", "y"}, ":2\r\n"},
+		{[]string{"BLPOP", "a", "b", "0"}, "*2\r\n$1\r\nb\r\n$1\r\nx\r\n"},
+		{[]string{"BRPOP", "a", "b", "0"}, "*2\r\n$1\r\nb\r\n$1\r\ny\r\n"},
+		{[]string{"BLPOP", "a", "0.01"}, "*-1\r\n"},
+		{[]string{"BLMOVE", "a", "b", "LEFT", "LEFT", "0.01"}, "$-1\r\n"},
+		{[]string{"BLMPOP", "0.01", "1", "a", "LEFT"}, "*-1\r\n"},
+		{[]string{"BLPOP", "a", "-1"}, "-ERR timeout is negative\r\n"},
+		{[]string{"BLPOP", "a", "x"}, "-ERR timeout is not a float or out of range\r\n"},
+		{[]string{"BLPOP", "a", "inf"}, "-ERR timeout is not a float or out of range\r\n"},
+		{[]string{"SET", "s", "v"}, "+OK\r\n"},
+		{[]string{"BLPOP", "s", "0"}, wrongType},
+		{[]string{"BLMOVE", "s", "b", "LEFT", "LEFT", "0"}, wrongType},
+		{[]string{"BLMPOP", "0", "1", "a", "UP"}, "-ERR syntax error\r\n"},
+	})
+	if blockedOn(h, 0, "a") != 0 {
+		t.Error("timed out clients should be unregistered")
+	}
+}
+
+func TestBLMoveChain(t *testing.T) {
+	h, addr, stop := startTestServer(t)
+	defer stop()
+	mover, popper, pusher := dial(t, addr), dial(t, addr), dial(t, addr)
+	defer mover.Close()
+	defer popper.Close()
+	defer pusher.Close()
+
+	moved := doAsync(mover, "BLMOVE", "src", "dst", "LEFT", "RIGHT", "0")
+	waitBlocked(t, h, 0, "src", 1)
+	popped := doAsync(popper, "BLMPOP", "0", "1", "dst", "LEFT", "COUNT", "5")
+	waitBlocked(t, h, 0, "dst", 1)
+
+	// the element goes to src, then to dst, then to the BLMPOP client
+	if _, err := pusher.Do("LPUSH", "src", "x"); err != nil {
+		t.Fatal(err)
+	}
+	expectReply(t, moved, []byte("x"))
+	expectReply(t, popped, []interface{}{[]byte("dst"), []interface{}{[]byte("x")}})
+}
+
+func TestBlockedServedAfterCommand(t *testing.T) {
+	h, addr, stop := startTestServer(t)
+	defer stop()
+	waiting, writer := dial(t, addr), dial(t, addr)
+	defer waiting.Close()
+	defer writer.Close()
+
+	// LMOVE fills dst
+	r := doAsync(waiting, "BLPOP", "dst", "0")
+	waitBlocked(t, h, 0, "dst", 1)
+	if _, err := writer.Do("RPUSH", "src", "a"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := writer.Do("LMOVE", "src", "dst", "LEFT", "LEFT"); err != nil {
+		t.Fatal(err)
+	}
+	expectReply(t, r, keyValue("dst", "a"))
+
+	// MOVE and SWAPDB bring keys of other databases
+	r = doAsync(waiting, "BLPOP", "k", "0")
+	waitBlocked(t, h, 0, "k", 1)
+	if _, err := writer.Do("SELECT", "1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := writer.Do("RPUSH", "k", "moved"); err != nil {
+		t.Fatal(err)
+	}
+	if n, err := client.Int64(writer.Do("MOVE", "k", "0")); err != nil || n != 1 {
+		t.Fatalf("MOVE: %d %v", n, err)
+	}
+	expectReply(t, r, keyValue("k", "moved"))
+
+	r = doAsync(waiting, "BLPOP", "k", "0")
+	waitBlocked(t, h, 0, "k", 1)
+	if _, err := writer.Do("RPUSH", "k", "swapped"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := writer.Do("SWAPDB", "0", "1"); err != nil {
+		t.Fatal(err)
+	}
+	expectReply(t, r, keyValue("k", "swapped"))
+}
+
+func TestBlockedPipeline(t *testing.T) {
+	h, addr, stop := startTestServer(t)
+	defer stop()
+	waiting, pusher := dial(t, addr), dial(t, addr)
+	defer waiting.Close()
+	defer pusher.Close()
+
+	// the PING is buffered while the client is blocked
+	if err := waiting.Send("BLPOP", "q", "0"); err != nil {
+		t.Fatal(err)
+	}
+	if err := waiting.Send("PING"); err != nil {
+		t.Fatal(err)
+	}
+	if err := waiting.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	waitBlocked(t, h, 0, "q", 1)
+	time.Sleep(10 * time.Millisecond)
+
+	if _, err := pusher.Do("RPUSH", "q", "x"); err != nil {
+		t.Fatal(err)
+	}
+	if r, err := waiting.Receive(); err != nil || !reflect.DeepEqual(r, keyValue("q", "x")) {
+		t.Fatalf("BLPOP: got %#v %v", r, err)
+	}
+	if r, err := client.String(waiting.Receive()); err != nil || r != "PONG" {
+		t.Fatalf("PING: got %q %v", r, err)
+	}
+}
+
+func TestBlockedDisconnect(t *testing.T) {
+	h, addr, stop := startTestServer(t)
+	defer stop()
+	waiting, pusher := dial(t, addr), dial(t, addr)
+	defer pusher.Close()
+
+	if err := waiting.Send("BLPOP", "q", "0"); err != nil {
+		t.Fatal(err)
+	}
+	if err := waiting.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	waitBlocked(t, h, 0, "q", 1)
+	_ = waiting.Close()
+	waitBlocked(t, h, 0, "q", 0)
+
+	// nothing is handed to the closed connection
+	if _, err := pusher.Do("RPUSH", "q", "x"); err != nil {
+		t.Fatal(err)
+	}
+	if n, err := client.Int64(pusher.Do("LLEN", "q")); err != nil || n != 1 {
+		t.Errorf("LLEN: %d %v", n, err)
+	}
+}
+
+func TestBlockedShutdown(t *testing.T) {
+	h, addr, stop := startTestServer(t)
+	waiting := dial(t, addr)
+	defer waiting.Close()
+	idle := dial(t, addr)
+	defer idle.Close()
+	if _, err := idle.Do("PING"); err != nil {
+		t.Fatal(err)
+	}
+
+	r := doAsync(waiting, "BLPOP", "q", "0")
+	waitBlocked(t, h, 0, "q", 1)
+
+	stopped := make(chan struct{})
+	go func() {
+		stop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(2 * time.Second):
+		t.Fatal("shutdown waits for blocked and idle clients")
+	}
+	select {
+	case <-r:
+	case <-time.After(2 * time.Second):
+		t.Fatal("blocked client is not released")
+	}
+}
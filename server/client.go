@@ -0,0 +1,143 @@
+/*
+ * @Description: client connection state
+ * @Autor: HTmonster
+ * @Date: 2026-10-18 09:31:52
+ */
+
+package server
+
+import (
+	"net"
+	"sync/atomic"
+
+	"github.com/HTmonster/redissgo/internal/reply"
+	"github.com/HTmonster/redissgo/internal/request"
+)
+
+// global client id generator
+var nextClientID uint64
+
+// state of a connected client
+type Client struct {
+	id      uint64
+	handler *Handler
+	conn    net.Conn
+	reader  *request.Reader
+	writer  *reply.Writer
+
+	dbIndex int // selected by SELECT
+	db      *DB // database of the running command
+
+	name          string
+	protocol      int  // RESP2 or RESP3, negotiated by HELLO
+	authenticated bool // passed AUTH, or no requirepass
+
+	readyKeys []blockingKey // written keys with blocked clients, served after the command
+	readyDBs  []int         // swapped databases, their blocked clients are served after the command
+
+	// keys being written by the running command, see Handler.call
+	writingDB   *DB
+	writingKeys []string
+	unchanged   bool // the running command wrote nothing, see keepVersions
+
+	// transaction state, see multi.go
+	multi   bool             // between MULTI and EXEC
+	dirty   bool             // a command was rejected while queueing
+	queued  []*queuedCommand // commands to run by EXEC
+	watched []*watchedKey    // keys watched by WATCH
+	views   map[int]*DB      // locked databases while EXEC runs
+}
+
+/**
+ * @description: creat a new client for the connection
+ * @param {*Handler} h
+ * @param {net.Conn} conn
+ * @return {*}
+ */
+func newClient(h *Handler, conn net.Conn) *Client {
+	return &Client{
+		id:       atomic.AddUint64(&nextClientID, 1),
+		handler:  h,
+		conn:     conn,
+		writer:   reply.NewWriter(conn),
+		protocol: reply.RESP2,
+	}
+}
+
+/**
+ * @description: remote address of the client
+ */
+func (c *Client) RemoteAddr() string {
+	return c.conn.RemoteAddr().String()
+}
+
+/**
+ * @description: switch the protocol of all following replies
+ * @param {int} protocol
+ * @return {*}
+ */
+func (c *Client) SetProtocol(protocol int) {
+	c.protocol = protocol
+	c.writer.SetProtocol(protocol)
+}
+
+/**
+ * @description: get a database by index, the locked view of the database
+ *	while EXEC runs
+ * @param {int} index
+ * @return {*}
+ */
+func (c *Client) selectDB(index int) *DB {
+	if db, ok := c.views[index]; ok {
+		return db
+	}
+	return c.handler.selectDB(index)
+}
+
+/**
+ * @description: mark the keys of db as being written by the running command,
+ *	a transaction watching them fails until endWrite
+ * @param {*DB} db
+ * @param {[]string} keys
+ * @return {*}
+ */
+func (c *Client) beginWrite(db *DB, keys []string) {
+	db.watched.beginWrite(keys)
+	c.writingDB, c.writingKeys = db, keys
+}
+
+/**
+ * @description: end the write started by beginWrite, if not ended yet
+ * @param {bool} modified increase the versions of the keys
+ * @return {*}
+ */
+func (c *Client) endWrite(modified bool) {
+	if c.writingDB == nil {
+		return
+	}
+	c.writingDB.watched.endWrite(c.writingKeys, modified)
+	c.writingDB, c.writingKeys = nil, nil
+}
+
+/**
+ * @description: report that the running write command changed nothing,
+ *	like SETNX on an existing key, the versions of its keys are kept and
+ *	transactions watching them are not aborted
+ * @return {*}
+ */
+func (c *Client) keepVersions() {
+	c.unchanged = true
+}
+
+/**
+ * @description: reply the number of changes of a write command,
+ *	see keepVersions
+ * @param {int64} changes 0 if nothing was written
+ * @return {*}
+ */
+func (c *Client) changesReply(changes int64) reply.Reply {
+	if changes == 0 {
+		c.keepVersions()
+	}
+	return reply.MakeIntReply(changes)
+}
@@ -0,0 +1,229 @@
+/*
+ * @Description: command table
+ * @Autor: HTmonster
+ * @Date: 2026-10-18 09:12:40
+ */
+
+package server
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/HTmonster/redissgo/internal/reply"
+)
+
+// command flags
+const (
+	flagWrite    = 1 << iota // may modify the keyspace
+	flagReadonly             // never modifies the keyspace
+	flagFast                 // O(1) or O(log(N)) command
+	flagNoAuth               // can run before authentication
+	flagMultiDB              // may access other databases than the selected one
+	flagNoQueue              // runs at once between MULTI and EXEC
+)
+
+// command executor, args do not contain the command name.
+// args are reused after the call, executors must copy what they store.
+type ExecFunc func(c *Client, args [][]byte) reply.Reply
+
+// find the keys of a command, params contain the command name
+type keyFinder func(params [][]byte) []string
+
+// command description
+type command struct {
+	name     string
+	executor ExecFunc
+	arity    int // arity < 0 means len(params) >= -arity
+	flags    int
+
+	// positions of the keys in params like the redis command table,
+	// lastKey < 0 counts from the end, 0 means no key
+	firstKey int
+	lastKey  int
+	keyStep  int
+	finder   keyFinder // keys at variable positions, replaces the positions
+}
+
+// all registered commands, key is the lower case command name
+var cmdTable = make(map[string]*command)
+
+/**
+ * @description: register a command into the command table
+ * @param {string} name
+ * @param {ExecFunc} executor
+ * @param {int} arity (including the command name)
+ * @param {int} flags
+ * @return {*}
+ */
+func registerCommand(name string, executor ExecFunc, arity int, flags int) *command {
+	name = strings.ToLower(name)
+	cmd := &command{
+		name:     name,
+		executor: executor,
+		arity:    arity,
+		flags:    flags,
+	}
+	cmdTable[name] = cmd
+	return cmd
+}
+
+/**
+ * @description: set the positions of the keys, see command
+ * @param {int} first
+ * @param {int} last
+ * @param {int} step
+ * @return {*}
+ */
+func (cmd *command) keys(first, last, step int) *command {
+	cmd.firstKey, cmd.lastKey, cmd.keyStep = first, last, step
+	return cmd
+}
+
+/**
+ * @description: find the keys by a function, for keys following numkeys
+ *	or an option
+ * @param {keyFinder} finder
+ * @return {*}
+ */
+func (cmd *command) keysBy(finder keyFinder) *command {
+	cmd.finder = finder
+	return cmd
+}
+
+/**
+ * @description: whether the command accesses some given keys
+ */
+func (cmd *command) hasKeys() bool {
+	return cmd.firstKey > 0 || cmd.finder != nil
+}
+
+/**
+ * @description: keys accessed by the command, positions out of the
+ *	params are ignored, the command replies an error for them
+ * @param {[][]byte} params (including the command name)
+ * @return {*}
+ */
+func (cmd *command) extractKeys(params [][]byte) []string {
+	if cmd.finder != nil {
+		return cmd.finder(params)
+	}
+	if cmd.firstKey <= 0 {
+		return nil
+	}
+	last := cmd.lastKey
+	if last < 0 {
+		last += len(params)
+	}
+	var keys []string
+	for i := cmd.firstKey; i <= last && i < len(params); i += cmd.keyStep {
+		keys = append(keys, string(params[i]))
+	}
+	return keys
+}
+
+/**
+ * @description: finder of the keys following a numkeys argument
+ * @param {int} numkeys position of numkeys
+ * @param {bool} dest a destination key at position 1
+ * @return {*}
+ */
+func numKeysFinder(numkeys int, dest bool) keyFinder {
+	return func(params [][]byte) []string {
+		var keys []string
+		if dest {
+			keys = append(keys, string(params[1]))
+		}
+		if numkeys >= len(params) {
+			return keys
+		}
+		n, err := strconv.Atoi(string(params[numkeys]))
+		if err != nil || n <= 0 || numkeys+n >= len(params) {
+			return keys
+		}
+		for _, key := range params[numkeys+1 : numkeys+1+n] {
+			keys = append(keys, string(key))
+		}
+		return keys
+	}
+}
+
+/**
+ * @description: finder of the stream keys of XREAD and XREADGROUP,
+ *	the first half of the arguments after STREAMS. The options before it are
+ *	skipped with their values, so a group or consumer named streams is not
+ *	taken for the keyword, see parseXReadSpec
+ */
+func streamsKeyFinder(params [][]byte) []string {
+	for i := 1; i < len(params); i++ {
+		switch strings.ToLower(string(params[i])) {
+		case "count", "block":
+			i++
+		case "group":
+			i += 2
+		case "noack":
+		case "streams":
+			rest := params[i+1:]
+			keys := make([]string, len(rest)/2)
+			for j := range keys {
+				keys[j] = string(rest[j])
+			}
+			return keys
+		default:
+			return nil
+		}
+	}
+	return nil
+}
+
+/**
+ * @description: look up a command case-insensitively
+ * @param {[]byte} name
+ * @return {*} command, exists or not
+ */
+func lookupCommand(name []byte) (*command, bool) {
+	cmd, ok := cmdTable[strings.ToLower(string(name))]
+	return cmd, ok
+}
+
+/**
+ * @description: check the number of parameters against the command arity
+ * @param {int} arity
+ * @param {[][]byte} params (including the command name)
+ * @return {*}
+ */
+func validateArity(arity int, params [][]byte) bool {
+	n := len(params)
+	if arity >= 0 {
+		return n == arity
+	}
+	return n >= -arity
+}
+
+/**
+ * @description: build the reply of an unknown command
+ * @param {[][]byte} params
+ * @return {*}
+ */
+func unknownCommandReply(params [][]byte) reply.Reply {
+	var sb strings.Builder
+	sb.WriteString("ERR unknown command '")
+	sb.WriteString(truncate(string(params[0]), 128))
+	sb.WriteString("', with args beginning with: ")
+	for _, arg := range params[1:] {
+		sb.WriteString("'")
+		sb.WriteString(truncate(string(arg), 128))
+		sb.WriteString("' ")
+	}
+	return reply.MakeErrReply(sb.String())
+}
+
+/**
+ * @description: cut a string to at most n bytes
+ */
+func truncate(s string, n int) string {
+	if len(s) > n {
+		return s[:n]
+	}
+	return s
+}
@@ -0,0 +1,162 @@
+/*
+ * @Description: connection commands
+ * @Autor: HTmonster
+ * @Date: 2026-10-18 09:40:27
+ */
+
+package server
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/HTmonster/redissgo/internal/config"
+	"github.com/HTmonster/redissgo/internal/reply"
+)
+
+// redis version reported to clients
+const redisVersion = "7.0.0"
+
+func init() {
+	registerCommand("ping", execPing, -1, flagFast)
+	registerCommand("echo", execEcho, 2, flagFast)
+	registerCommand("auth", execAuth, -2, flagNoAuth|flagFast)
+	registerCommand("hello", execHello, -1, flagNoAuth|flagFast)
+}
+
+/**
+ * @description: PING [message]
+ */
+func execPing(c *Client, args [][]byte) reply.Reply {
+	switch len(args) {
+	case 0:
+		return reply.MakePongReply()
+	case 1:
+		return reply.MakeBulkReply(args[0])
+	default:
+		return reply.MakeArgNumErrReply("ping")
+	}
+}
+
+/**
+ * @description: ECHO message
+ */
+func execEcho(c *Client, args [][]byte) reply.Reply {
+	return reply.MakeBulkReply(args[0])
+}
+
+/**
+ * @description: check username and password of the default user
+ * @param {string} username
+ * @param {string} password
+ * @return {*}
+ */
+func checkPassword(username, password string) bool {
+	if username != "default" {
+		return false
+	}
+	requirepass := config.Properties.Requirepass
+	return requirepass == "" || password == requirepass
+}
+
+var wrongPassReply = reply.MakeErrReply("WRONGPASS invalid username-password pair or user is disabled.")
+
+/**
+ * @description: AUTH [username] password
+ */
+func execAuth(c *Client, args [][]byte) reply.Reply {
+	if len(args) > 2 {
+		return reply.MakeSyntaxErrReply()
+	}
+	username, password := "default", string(args[0])
+	if len(args) == 2 {
+		username, password = string(args[0]), string(args[1])
+	} else if config.Properties.Requirepass == "" {
+		return reply.MakeErrReply("ERR AUTH <password> called without any password configured " +
+			"for the default user. Are you sure your configuration is correct?")
+	}
+
+	if !checkPassword(username, password) {
+		return wrongPassReply
+	}
+	c.authenticated = true
+	return reply.MakeOkReply()
+}
+
+/**
+ * @description: check a client name like redis, no spaces or special characters
+ * @param {[]byte} name
+ * @return {*}
+ */
+func validateClientName(name []byte) bool {
+	for _, b := range name {
+		if b < '!' || b > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+/**
+ * @description: HELLO [protover [AUTH username password] [SETNAME clientname]]
+ */
+func execHello(c *Client, args [][]byte) reply.Reply {
+	protocol := c.protocol
+	if len(args) > 0 {
+		ver, err := strconv.ParseInt(string(args[0]), 10, 64)
+		if err != nil {
+			return reply.MakeErrReply("ERR Protocol version is not an integer or out of range")
+		}
+		if ver < reply.RESP2 || ver > reply.RESP3 {
+			return reply.MakeErrReply("NOPROTO sorry, this protocol version is not supported.")
+		}
+		protocol = int(ver)
+	}
+
+	var username, password, name []byte
+	var withAuth, withName bool
+	for i := 1; i < len(args); i++ {
+		more := len(args) - i - 1
+		opt := strings.ToLower(string(args[i]))
+		if opt == "auth" && more >= 2 {
+			username, password = args[i+1], args[i+2]
+			withAuth = true
+			i += 2
+		} else if opt == "setname" && more >= 1 {
+			name = args[i+1]
+			withName = true
+			i++
+		} else {
+			return reply.MakeErrReply("ERR Syntax error in HELLO option '" + string(args[i]) + "'")
+		}
+	}
+
+	if withAuth {
+		if !checkPassword(string(username), string(password)) {
+			return wrongPassReply
+		}
+		c.authenticated = true
+	}
+	if !c.authenticated {
+		return reply.MakeErrReply("NOAUTH HELLO must be called with the client already authenticated, " +
+			"otherwise the HELLO <proto> AUTH <user> <pass> option can be used to authenticate " +
+			"the client and select the RESP protocol version at the same time")
+	}
+	if withName {
+		if !validateClientName(name) {
+			return reply.MakeErrReply("ERR Client names cannot contain spaces, newlines or special characters.")
+		}
+		c.name = string(name)
+	}
+
+	c.SetProtocol(protocol)
+	return reply.MakeMapReply([]reply.Reply{
+		reply.MakeBulkReply([]byte("server")), reply.MakeBulkReply([]byte("redis")),
+		reply.MakeBulkReply([]byte("version")), reply.MakeBulkReply([]byte(redisVersion)),
+		reply.MakeBulkReply([]byte("proto")), reply.MakeIntReply(int64(protocol)),
+		reply.MakeBulkReply([]byte("id")), reply.MakeIntReply(int64(c.id)),
+		reply.MakeBulkReply([]byte("mode")), reply.MakeBulkReply([]byte("standalone")),
+		reply.MakeBulkReply([]byte("role")), reply.MakeBulkReply([]byte("master")),
+		reply.MakeBulkReply([]byte("modules")), reply.MakeEmptyArrayReply(),
+	})
+}
@@ -0,0 +1,61 @@
+/*
+ * @Description:
+ * @Autor: HTmonster
+ * @Date: 2026-10-18 13:58:12
+ */
+package server
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/HTmonster/redissgo/internal/config"
+)
+
+func TestHello(t *testing.T) {
+	h := NewHandler()
+	defer h.Close()
+	c := newTestClient(h)
+
+	if got := execString(h, c, "HELLO", "4"); !strings.HasPrefix(got, "-NOPROTO") {
+		t.Errorf("HELLO 4: got %q", got)
+	}
+	if got := execString(h, c, "HELLO", "3", "SETNAME"); !strings.HasPrefix(got, "-ERR Syntax error") {
+		t.Errorf("HELLO 3 SETNAME: got %q", got)
+	}
+	if got := execString(h, c, "HELLO", "3", "SETNAME", "worker-1"); !strings.HasPrefix(got, "%7\r\n") {
+		t.Errorf("HELLO 3: got %q", got)
+	}
+	if c.protocol != 3 || c.name != "worker-1" {
+		t.Errorf("HELLO 3 should switch protocol and set name, got %d %q", c.protocol, c.name)
+	}
+	if got := execString(h, c, "HELLO", "2"); !strings.HasPrefix(got, "*14\r\n") {
+		t.Errorf("HELLO 2: got %q", got)
+	}
+}
+
+func TestAuth(t *testing.T) {
+	config.Properties.Requirepass = "secret"
+	defer func() { config.Properties.Requirepass = "" }()
+
+	h := NewHandler()
+	defer h.Close()
+	c := newTestClient(h)
+	c.authenticated = false
+
+	if got := execString(h, c, "PING"); !strings.HasPrefix(got, "-NOAUTH") {
+		t.Errorf("PING before AUTH: got %q", got)
+	}
+	if got := execString(h, c, "HELLO", "3"); !strings.HasPrefix(got, "-NOAUTH") {
+		t.Errorf("HELLO before AUTH: got %q", got)
+	}
+	if got := execString(h, c, "AUTH", "wrong"); !strings.HasPrefix(got, "-WRONGPASS") {
+		t.Errorf("AUTH wrong: got %q", got)
+	}
+	if got := execString(h, c, "HELLO", "3", "AUTH", "default", "secret"); !strings.HasPrefix(got, "%7\r\n") {
+		t.Errorf("HELLO 3 AUTH: got %q", got)
+	}
+	if got := execString(h, c, "PING"); got != "+PONG\r\n" {
+		t.Errorf("PING after AUTH: got %q", got)
+	}
+}
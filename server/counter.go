@@ -0,0 +1,162 @@
+/*
+ * @Description: counter commands, atomic under the segment lock
+ * @Autor: HTmonster
+ * @Date: 2026-10-18 21:05:52
+ */
+
+package server
+
+import (
+	"math"
+	"strconv"
+
+	"github.com/HTmonster/redissgo/datastruct/dict"
+	"github.com/HTmonster/redissgo/internal/reply"
+)
+
+func init() {
+	registerCommand("incr", execIncr, 2, flagWrite|flagFast).keys(1, 1, 1)
+	registerCommand("decr", execDecr, 2, flagWrite|flagFast).keys(1, 1, 1)
+	registerCommand("incrby", execIncrBy, 3, flagWrite|flagFast).keys(1, 1, 1)
+	registerCommand("decrby", execDecrBy, 3, flagWrite|flagFast).keys(1, 1, 1)
+	registerCommand("incrbyfloat", execIncrByFloat, 3, flagWrite|flagFast).keys(1, 1, 1)
+}
+
+/**
+ * @description: get the integer of a string value
+ * @param {interface{}} entity
+ * @return {*} number, error if not a string or not an integer
+ */
+func asInt(entity interface{}) (int64, reply.ErrorReply) {
+	switch value := entity.(type) {
+	case int64:
+		return value, nil
+	case []byte:
+		if n, ok := parseCanonicalInt(value); ok {
+			return n, nil
+		}
+		return 0, reply.MakeNotIntegerErrReply()
+	}
+	return 0, reply.MakeWrongTypeErrReply()
+}
+
+/**
+ * @description: parse a float like redis strtold, nan and inf are not allowed
+ * @param {[]byte} b
+ * @return {*} number, ok or not
+ */
+func parseFloat(b []byte) (float64, bool) {
+	f, err := strconv.ParseFloat(string(b), 64)
+	if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+		return 0, false
+	}
+	return f, true
+}
+
+/**
+ * @description: add incr to the integer at key, the key is created with 0 if not exists
+ * @param {*Client} c
+ * @param {string} key
+ * @param {int64} incr
+ * @return {*}
+ */
+func incrGeneric(c *Client, key string, incr int64) reply.Reply {
+	var result reply.Reply
+	c.db.Update(key, func(entry *dict.Entry) {
+		var value int64
+		if entry.Exists {
+			n, errReply := asInt(entry.Value)
+			if errReply != nil {
+				result = errReply
+				return
+			}
+			value = n
+		}
+		if (incr < 0 && value < 0 && incr < math.MinInt64-value) ||
+			(incr > 0 && value > 0 && incr > math.MaxInt64-value) {
+			result = reply.MakeErrReply("ERR increment or decrement would overflow")
+			return
+		}
+		value += incr
+		// TTL is kept
+		entry.Value, entry.Exists = value, true
+		result = reply.MakeIntReply(value)
+	})
+	return result
+}
+
+/**
+ * @description: INCR key
+ */
+func execIncr(c *Client, args [][]byte) reply.Reply {
+	return incrGeneric(c, string(args[0]), 1)
+}
+
+/**
+ * @description: DECR key
+ */
+func execDecr(c *Client, args [][]byte) reply.Reply {
+	return incrGeneric(c, string(args[0]), -1)
+}
+
+/**
+ * @description: INCRBY key increment
+ */
+func execIncrBy(c *Client, args [][]byte) reply.Reply {
+	incr, ok := parseCanonicalInt(args[1])
+	if !ok {
+		return reply.MakeNotIntegerErrReply()
+	}
+	return incrGeneric(c, string(args[0]), incr)
+}
+
+/**
+ * @description: DECRBY key decrement
+ */
+func execDecrBy(c *Client, args [][]byte) reply.Reply {
+	decr, ok := parseCanonicalInt(args[1])
+	if !ok {
+		return reply.MakeNotIntegerErrReply()
+	}
+	// -MinInt64 does not fit into int64
+	if decr == math.MinInt64 {
+		return reply.MakeErrReply("ERR decrement would overflow")
+	}
+	return incrGeneric(c, string(args[0]), -decr)
+}
+
+/**
+ * @description: INCRBYFLOAT key increment
+ */
+func execIncrByFloat(c *Client, args [][]byte) reply.Reply {
+	incr, ok := parseFloat(args[1])
+	if !ok {
+		return reply.MakeErrReply("ERR value is not a valid float")
+	}
+
+	var result reply.Reply
+	c.db.Update(string(args[0]), func(entry *dict.Entry) {
+		var value float64
+		if entry.Exists {
+			bytes, ok := asString(entry.Value)
+			if !ok {
+				result = reply.MakeWrongTypeErrReply()
+				return
+			}
+			if value, ok = parseFloat(bytes); !ok {
+				result = reply.MakeErrReply("ERR value is not a valid float")
+				return
+			}
+		}
+		value += incr
+		if math.IsNaN(value) || math.IsInf(value, 0) {
+			result = reply.MakeErrReply("ERR increment would produce NaN or Infinity")
+			return
+		}
+		// human friendly format, no exponent and no trailing zeros
+		bytes := strconv.AppendFloat(nil, value, 'f', -1, 64)
+		entry.Value, entry.Exists = makeString(bytes), true
+		result = reply.MakeBulkReply(bytes)
+	})
+	return result
+}
@@ -0,0 +1,122 @@
+/*
+ * @Description:
+ * @Autor: HTmonster
+ * @Date: 2026-10-18 21:20:06
+ */
+package server
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+)
+
+func TestIncrDecr(t *testing.T) {
+	h := NewHandler()
+	defer h.Close()
+	c := newTestClient(h)
+	c.db = h.selectDB(0)
+	c.db.PutEntity("list", struct{}{})
+
+	runCases(t, h, c, []cmdCase{
+		{[]string{"INCR", "n"}, ":1\r\n"},
+		{[]string{"INCRBY", "n", "41"}, ":42\r\n"},
+		{[]string{"DECR", "n"}, ":41\r\n"},
+		{[]string{"DECRBY", "n", "-9"}, ":50\r\n"},
+		{[]string{"GET", "n"}, "$2\r\n50\r\n"},
+		{[]string{"APPEND", "n", "0"}, ":3\r\n"},
+		{[]string{"INCR", "n"}, ":501\r\n"},
+		{[]string{"SET", "n", "10", "EX", "100"}, "+OK\r\n"},
+		{[]string{"INCR", "n"}, ":11\r\n"},
+		{[]string{"TTL", "n"}, ":100\r\n"},
+
+		{[]string{"SET", "s", "abc"}, "+OK\r\n"},
+		{[]string{"INCR", "s"}, "-ERR value is not an integer or out of range\r\n"},
+		{[]string{"SET", "s", " 1"}, "+OK\r\n"},
+		{[]string{"INCR", "s"}, "-ERR value is not an integer or out of range\r\n"},
+		{[]string{"SET", "s", "01"}, "+OK\r\n"},
+		{[]string{"INCR", "s"}, "-ERR value is not an integer or out of range\r\n"},
+		{[]string{"INCRBY", "n", "1.5"}, "-ERR value is not an integer or out of range\r\n"},
+		{[]string{"INCRBY", "n", "+1"}, "-ERR value is not an integer or out of range\r\n"},
+		{[]string{"INCR", "list"}, "-WRONGTYPE Operation against a key holding the wrong kind of value\r\n"},
+
+		{[]string{"SET", "max", "9223372036854775807"}, "+OK\r\n"},
+		{[]string{"INCR", "max"}, "-ERR increment or decrement would overflow\r\n"},
+		{[]string{"SET", "min", "-9223372036854775808"}, "+OK\r\n"},
+		{[]string{"DECR", "min"}, "-ERR increment or decrement would overflow\r\n"},
+		{[]string{"INCRBY", "min", "-1"}, "-ERR increment or decrement would overflow\r\n"},
+		{[]string{"DECRBY", "n", "-9223372036854775808"}, "-ERR decrement would overflow\r\n"},
+		{[]string{"SET", "big", "9223372036854775808"}, "+OK\r\n"},
+		{[]string{"INCR", "big"}, "-ERR value is not an integer or out of range\r\n"},
+	})
+}
+
+func TestIncrByFloat(t *testing.T) {
+	h := NewHandler()
+	defer h.Close()
+	c := newTestClient(h)
+
+	runCases(t, h, c, []cmdCase{
+		{[]string{"INCRBYFLOAT", "f", "10.5"}, "$4\r\n10.5\r\n"},
+		{[]string{"INCRBYFLOAT", "f", "0.1"}, "$4\r\n10.6\r\n"},
+		{[]string{"INCRBYFLOAT", "f", "-5.6"}, "$1\r\n5\r\n"},
+		{[]string{"INCR", "f"}, ":6\r\n"},
+		{[]string{"INCRBYFLOAT", "f", "5.0e3"}, "$4\r\n5006\r\n"},
+		{[]string{"INCRBYFLOAT", "f", "abc"}, "-ERR value is not a valid float\r\n"},
+		{[]string{"INCRBYFLOAT", "f", "inf"}, "-ERR value is not a valid float\r\n"},
+		{[]string{"SET", "s", "abc"}, "+OK\r\n"},
+		{[]string{"INCRBYFLOAT", "s", "1"}, "-ERR value is not a valid float\r\n"},
+		{[]string{"SET", "m", "1.7e308"}, "+OK\r\n"},
+		{[]string{"INCRBYFLOAT", "m", "1.7e308"}, "-ERR increment would produce NaN or Infinity\r\n"},
+	})
+}
+
+func TestIntEncoding(t *testing.T) {
+	h := NewHandler()
+	defer h.Close()
+	c := newTestClient(h)
+	c.db = h.selectDB(0)
+
+	execString(h, c, "SET", "int", "-123")
+	execString(h, c, "SET", "raw", "0123")
+	if entity, _ := c.db.GetEntity("int"); entity != int64(-123) {
+		t.Errorf("canonical integer should be stored as int64, got %#v", entity)
+	}
+	if _, ok := c.db.GetEntity("raw"); !ok {
+		t.Fatal("raw should exist")
+	}
+	if entity, _ := c.db.GetEntity("raw"); entity == int64(123) {
+		t.Error("non canonical integer should be stored as bytes")
+	}
+	if got := execString(h, c, "GET", "raw"); got != "$4\r\n0123\r\n" {
+		t.Errorf("GET raw: got %q", got)
+	}
+	if got := execString(h, c, "STRLEN", "int"); got != ":4\r\n" {
+		t.Errorf("STRLEN int: got %q", got)
+	}
+}
+
+func TestIncrConcurrent(t *testing.T) {
+	h := NewHandler()
+	defer h.Close()
+
+	const workers, times = 8, 1000
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c := newTestClient(h)
+			for j := 0; j < times; j++ {
+				execString(h, c, "INCR", "counter")
+			}
+		}()
+	}
+	wg.Wait()
+
+	c := newTestClient(h)
+	want := strconv.Itoa(workers * times)
+	if got := execString(h, c, "GET", "counter"); got != "$"+strconv.Itoa(len(want))+"\r\n"+want+"\r\n" {
+		t.Errorf("lost updates: got %q, want %s", got, want)
+	}
+}
@@ -0,0 +1,386 @@
+/*
+ * @Description: logical databases
+ * @Autor: HTmonster
+ * @Date: 2026-10-18 17:20:31
+ */
+
+package server
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/HTmonster/redissgo/datastruct/dict"
+	"github.com/HTmonster/redissgo/internal/reply"
+)
+
+// segments of every keyspace dictionary
+const dataDictSize = 1024
+
+// a logical database, selected by SELECT
+type DB struct {
+	id    int // never changes, orders the locks of transactions
+	index int
+	data  dict.Dict // keys and their TTL, a dict.LockedDict in transactions
+
+	watched      *watchTable // versions of watched keys, shared by locked views
+	expireCursor int         // next segment of the active expire cycle
+}
+
+/**
+ * @description: make an empty database
+ * @param {int} index
+ * @return {*}
+ */
+func makeDB(index int) *DB {
+	return &DB{
+		id:      index,
+		index:   index,
+		data:    dict.MakeConcurrentDict(dataDictSize),
+		watched: makeWatchTable(),
+	}
+}
+
+/**
+ * @description: the dictionary of a database, it must not be a locked view
+ */
+func (db *DB) store() *dict.ConcurrentDict {
+	return db.data.(*dict.ConcurrentDict)
+}
+
+/**
+ * @description: a view of the database for a transaction holding its locks
+ */
+func (db *DB) lockedView() *DB {
+	return &DB{
+		id:      db.id,
+		index:   db.index,
+		data:    dict.MakeLockedDict(db.store()),
+		watched: db.watched,
+	}
+}
+
+/**
+ * @description: get the value of a key
+ * @param {string} key
+ * @return {*} value, exists or not
+ */
+func (db *DB) GetEntity(key string) (interface{}, bool) {
+	return db.data.Get(key)
+}
+
+/**
+ * @description: set the value of a key
+ * @param {string} key
+ * @param {interface{}} entity
+ * @return {*} 1 if the key is new, else 0
+ */
+func (db *DB) PutEntity(key string, entity interface{}) int {
+	return db.data.Put(key, entity)
+}
+
+/**
+ * @description: set the value and replace the TTL of a key
+ * @param {string} key
+ * @param {interface{}} entity
+ * @param {int64} at unix time in milliseconds, 0 means no TTL
+ * @return {*} 1 if the key is new, else 0
+ */
+func (db *DB) PutEntityWithExpire(key string, entity interface{}, at int64) int {
+	return db.data.PutWithExpire(key, entity, at)
+}
+
+/**
+ * @description: set the value only if the key does not exist
+ */
+func (db *DB) PutIfAbsent(key string, entity interface{}) int {
+	return db.data.PutIfAbsent(key, entity)
+}
+
+/**
+ * @description: set the value only if the key exists
+ */
+func (db *DB) PutIfExists(key string, entity interface{}) int {
+	return db.data.PutIfExists(key, entity)
+}
+
+/**
+ * @description: read and modify a key atomically
+ * @param {string} key
+ * @param {dict.Updater} updater
+ * @return {*}
+ */
+func (db *DB) Update(key string, updater dict.Updater) {
+	db.data.Update(key, updater)
+}
+
+/**
+ * @description: read and modify several distinct keys atomically
+ * @param {[]string} keys
+ * @param {dict.MultiUpdater} updater
+ * @return {*}
+ */
+func (db *DB) UpdateMulti(keys []string, updater dict.MultiUpdater) {
+	db.data.UpdateMulti(keys, updater)
+}
+
+/**
+ * @description: lock several keys, they must be accessed with
+ *	GetEntityWithLock and UpdateWithLock until Unlocks
+ * @param {[]string} writeKeys
+ * @param {[]string} readKeys
+ * @return {*}
+ */
+func (db *DB) Locks(writeKeys []string, readKeys []string) {
+	db.data.Locks(writeKeys, readKeys)
+}
+
+/**
+ * @description: unlock the keys locked by Locks
+ */
+func (db *DB) Unlocks(writeKeys []string, readKeys []string) {
+	db.data.Unlocks(writeKeys, readKeys)
+}
+
+/**
+ * @description: get the value of a key locked by Locks
+ */
+func (db *DB) GetEntityWithLock(key string) (interface{}, bool) {
+	return db.data.GetWithLock(key)
+}
+
+/**
+ * @description: read and modify a key write locked by Locks
+ */
+func (db *DB) UpdateWithLock(key string, updater dict.Updater) {
+	db.data.UpdateWithLock(key, updater)
+}
+
+/**
+ * @description: remove a key
+ * @param {string} key
+ * @return {*} 1 if removed, else 0
+ */
+func (db *DB) Remove(key string) int {
+	return db.data.Remove(key)
+}
+
+/**
+ * @description: number of keys
+ */
+func (db *DB) Len() int {
+	return db.data.Len()
+}
+
+/**
+ * @description: traversal the keys, the consumer must not modify the database
+ */
+func (db *DB) ForEach(consumer dict.Consumer) {
+	db.data.ForEach(consumer)
+}
+
+/**
+ * @description: incrementally iterate the keys, see dict.ConcurrentDict.Scan
+ * @param {uint64} cursor 0 to start a new scan
+ * @param {int} count
+ * @param {dict.Consumer} consumer
+ * @return {*} next cursor, 0 when the scan is completed
+ */
+func (db *DB) Scan(cursor uint64, count int, consumer dict.Consumer) uint64 {
+	return db.data.Scan(cursor, count, consumer)
+}
+
+/**
+ * @description: return a random key
+ * @return {*} key, false if the database is empty
+ */
+func (db *DB) RandomKey() (string, bool) {
+	return db.data.RandomKey()
+}
+
+/**
+ * @description: remove all keys
+ */
+func (db *DB) Flush() {
+	db.watched.beginWriteAll()
+	defer db.watched.endWriteAll()
+	db.data.Clear()
+}
+
+//------------ commands --------------
+
+func init() {
+	registerCommand("select", execSelect, 2, flagFast)
+	registerCommand("swapdb", execSwapDB, 3, flagWrite|flagMultiDB|flagFast)
+	registerCommand("move", execMove, 3, flagWrite|flagMultiDB|flagFast).keys(1, 1, 1)
+	registerCommand("dbsize", execDBSize, 1, flagReadonly|flagFast)
+	registerCommand("flushdb", execFlushDB, -1, flagWrite)
+	registerCommand("flushall", execFlushAll, -1, flagWrite|flagMultiDB)
+}
+
+/**
+ * @description: parse a database index, check the range
+ * @param {*Handler} h
+ * @param {[]byte} arg
+ * @return {*} index, error reply
+ */
+func parseDBIndex(h *Handler, arg []byte) (int, reply.ErrorReply) {
+	index, err := strconv.ParseInt(string(arg), 10, 64)
+	if err != nil {
+		return 0, reply.MakeNotIntegerErrReply()
+	}
+	if index < 0 || index >= int64(len(h.dbs)) {
+		return 0, reply.MakeErrReply("ERR DB index is out of range")
+	}
+	return int(index), nil
+}
+
+/**
+ * @description: SELECT index
+ */
+func execSelect(c *Client, args [][]byte) reply.Reply {
+	index, errReply := parseDBIndex(c.handler, args[0])
+	if errReply != nil {
+		return errReply
+	}
+	c.dbIndex = index
+	return reply.MakeOkReply()
+}
+
+/**
+ * @description: SWAPDB index1 index2
+ */
+func execSwapDB(c *Client, args [][]byte) reply.Reply {
+	h := c.handler
+	first, err1 := strconv.ParseInt(string(args[0]), 10, 64)
+	if err1 != nil {
+		return reply.MakeErrReply("ERR invalid first DB index")
+	}
+	second, err2 := strconv.ParseInt(string(args[1]), 10, 64)
+	if err2 != nil {
+		return reply.MakeErrReply("ERR invalid second DB index")
+	}
+	if first < 0 || first >= int64(len(h.dbs)) || second < 0 || second >= int64(len(h.dbs)) {
+		return reply.MakeErrReply("ERR DB index is out of range")
+	}
+	if c.views != nil {
+		// the transaction holds the locks of all databases
+		h.swapDBLocked(int(first), int(second))
+		c.views[int(first)], c.views[int(second)] = c.views[int(second)], c.views[int(first)]
+		c.views[int(first)].index, c.views[int(second)].index = int(first), int(second)
+	} else {
+		h.swapDB(int(first), int(second))
+	}
+	// clients blocked in both databases see new data
+	c.readyDBs = append(c.readyDBs, int(first), int(second))
+	return reply.MakeOkReply()
+}
+
+/**
+ * @description: MOVE key db
+ */
+func execMove(c *Client, args [][]byte) reply.Reply {
+	key := string(args[0])
+	index, errReply := parseDBIndex(c.handler, args[1])
+	if errReply != nil {
+		return errReply
+	}
+	if index == c.dbIndex {
+		return reply.MakeErrReply("ERR source and destination objects are the same")
+	}
+
+	src, dst := c.db, c.selectDB(index)
+	keys := []string{key}
+	// the generic version bump only covers the keys of the selected database
+	dst.watched.beginWrite(keys)
+	moved := false
+	// move value and TTL at once
+	unlock := lockKeysOfDBs(src, key, dst, key)
+	src.UpdateWithLock(key, func(from *dict.Entry) {
+		if !from.Exists {
+			return
+		}
+		dst.UpdateWithLock(key, func(to *dict.Entry) {
+			if !to.Exists {
+				*to = *from
+				moved = true
+			}
+		})
+		from.Exists = !moved
+	})
+	unlock()
+	dst.watched.endWrite(keys, moved)
+	if !moved {
+		return c.changesReply(0)
+	}
+	c.signalKeyReady(dst, key)
+	return reply.MakeIntReply(1)
+}
+
+/**
+ * @description: write lock a key in each of two databases, in the order of
+ *	lockDBs, so MOVE and COPY cannot deadlock with transactions
+ * @param {*DB} src
+ * @param {string} srcKey
+ * @param {*DB} dst
+ * @param {string} dstKey
+ * @return {*} unlock
+ */
+func lockKeysOfDBs(src *DB, srcKey string, dst *DB, dstKey string) func() {
+	first, firstKeys := src, []string{srcKey}
+	second, secondKeys := dst, []string{dstKey}
+	if dst.id < src.id {
+		first, firstKeys, second, secondKeys = second, secondKeys, first, firstKeys
+	}
+	first.Locks(firstKeys, nil)
+	second.Locks(secondKeys, nil)
+	return func() {
+		second.Unlocks(secondKeys, nil)
+		first.Unlocks(firstKeys, nil)
+	}
+}
+
+/**
+ * @description: DBSIZE
+ */
+func execDBSize(c *Client, args [][]byte) reply.Reply {
+	return reply.MakeIntReply(int64(c.db.Len()))
+}
+
+/**
+ * @description: check the optional ASYNC|SYNC argument of FLUSHDB and FLUSHALL
+ */
+func validateFlushArgs(args [][]byte) bool {
+	if len(args) == 0 {
+		return true
+	}
+	if len(args) > 1 {
+		return false
+	}
+	mode := strings.ToLower(string(args[0]))
+	return mode == "async" || mode == "sync"
+}
+
+/**
+ * @description: FLUSHDB [ASYNC|SYNC]
+ */
+func execFlushDB(c *Client, args [][]byte) reply.Reply {
+	if !validateFlushArgs(args) {
+		return reply.MakeSyntaxErrReply()
+	}
+	c.db.Flush()
+	return reply.MakeOkReply()
+}
+
+/**
+ * @description: FLUSHALL [ASYNC|SYNC]
+ */
+func execFlushAll(c *Client, args [][]byte) reply.Reply {
+	if !validateFlushArgs(args) {
+		return reply.MakeSyntaxErrReply()
+	}
+	for i := range c.handler.dbs {
+		c.selectDB(i).Flush()
+	}
+	return reply.MakeOkReply()
+}
@@ -0,0 +1,163 @@
+/*
+ * @Description:
+ * @Autor: HTmonster
+ * @Date: 2026-10-18 17:52:06
+ */
+package server
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSelectAndSwapDB(t *testing.T) {
+	h := NewHandler()
+	defer h.Close()
+	c := newTestClient(h)
+
+	h.selectDB(0).PutEntity("a", []byte("0"))
+	h.selectDB(1).PutEntity("b", []byte("1"))
+	h.selectDB(1).PutEntity("c", []byte("1"))
+
+	if got := execString(h, c, "DBSIZE"); got != ":1\r\n" {
+		t.Errorf("DBSIZE of db 0: got %q", got)
+	}
+	if got := execString(h, c, "SELECT", "1"); got != "+OK\r\n" {
+		t.Errorf("SELECT 1: got %q", got)
+	}
+	if got := execString(h, c, "DBSIZE"); got != ":2\r\n" {
+		t.Errorf("DBSIZE of db 1: got %q", got)
+	}
+	if got := execString(h, c, "SELECT", "16"); got != "-ERR DB index is out of range\r\n" {
+		t.Errorf("SELECT 16: got %q", got)
+	}
+	if got := execString(h, c, "SELECT", "x"); got != "-ERR value is not an integer or out of range\r\n" {
+		t.Errorf("SELECT x: got %q", got)
+	}
+
+	// the client stays on index 1 and sees the data of db 0
+	if got := execString(h, c, "SWAPDB", "0", "1"); got != "+OK\r\n" {
+		t.Errorf("SWAPDB: got %q", got)
+	}
+	if got := execString(h, c, "DBSIZE"); got != ":1\r\n" {
+		t.Errorf("DBSIZE after SWAPDB: got %q", got)
+	}
+	if _, ok := h.selectDB(1).GetEntity("a"); !ok {
+		t.Errorf("key a should be in db 1 after SWAPDB")
+	}
+	if got := execString(h, c, "SWAPDB", "0", "99"); got != "-ERR DB index is out of range\r\n" {
+		t.Errorf("SWAPDB out of range: got %q", got)
+	}
+	if got := execString(h, c, "SWAPDB", "x", "1"); got != "-ERR invalid first DB index\r\n" {
+		t.Errorf("SWAPDB invalid: got %q", got)
+	}
+}
+
+func TestMove(t *testing.T) {
+	h := NewHandler()
+	defer h.Close()
+	c := newTestClient(h)
+
+	h.selectDB(0).PutEntity("a", []byte("0"))
+	h.selectDB(0).PutEntity("b", []byte("0"))
+	h.selectDB(2).PutEntity("b", []byte("2"))
+
+	if got := execString(h, c, "MOVE", "a", "2"); got != ":1\r\n" {
+		t.Errorf("MOVE a 2: got %q", got)
+	}
+	if _, ok := h.selectDB(0).GetEntity("a"); ok {
+		t.Errorf("key a should be moved out of db 0")
+	}
+	if got := execString(h, c, "MOVE", "b", "2"); got != ":0\r\n" {
+		t.Errorf("MOVE to an existing key: got %q", got)
+	}
+	if got := execString(h, c, "MOVE", "none", "2"); got != ":0\r\n" {
+		t.Errorf("MOVE a missing key: got %q", got)
+	}
+	if got := execString(h, c, "MOVE", "b", "0"); got != "-ERR source and destination objects are the same\r\n" {
+		t.Errorf("MOVE to the same db: got %q", got)
+	}
+
+	// the TTL moves with the value
+	at := time.Now().Add(time.Hour).UnixNano() / int64(time.Millisecond)
+	h.selectDB(0).PutEntityWithExpire("t", []byte("0"), at)
+	if got := execString(h, c, "MOVE", "t", "2"); got != ":1\r\n" {
+		t.Errorf("MOVE t 2: got %q", got)
+	}
+	if got, hasTTL, _ := h.selectDB(2).TTL("t"); !hasTTL || got != at {
+		t.Errorf("moved TTL: got %d %v, want %d", got, hasTTL, at)
+	}
+}
+
+func TestMoveConcurrent(t *testing.T) {
+	h := NewHandler()
+	defer h.Close()
+	at := time.Now().Add(time.Hour).UnixNano() / int64(time.Millisecond)
+	h.selectDB(0).PutEntityWithExpire("k", []byte("v"), at)
+
+	// two clients move the key back and forth, others never see it
+	// in both databases or without its TTL
+	var wg sync.WaitGroup
+	stop := make(chan struct{})
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			c := newTestClient(h)
+			c.dbIndex = i
+			for j := 0; j < 1000; j++ {
+				execString(h, c, "MOVE", "k", strconv.Itoa(1-i))
+			}
+		}(i)
+	}
+	var checkers sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		checkers.Add(1)
+		go func() {
+			defer checkers.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				for i := 0; i < 2; i++ {
+					if _, hasTTL, exists := h.selectDB(i).TTL("k"); exists && !hasTTL {
+						t.Errorf("moved key without TTL in db %d", i)
+						return
+					}
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	close(stop)
+	checkers.Wait()
+
+	_, in0 := h.selectDB(0).GetEntity("k")
+	_, in1 := h.selectDB(1).GetEntity("k")
+	if in0 == in1 {
+		t.Errorf("key should be in exactly one db: %v %v", in0, in1)
+	}
+}
+
+func TestFlush(t *testing.T) {
+	h := NewHandler()
+	defer h.Close()
+	c := newTestClient(h)
+
+	h.selectDB(0).PutEntity("a", []byte("0"))
+	h.selectDB(1).PutEntity("b", []byte("1"))
+
+	if got := execString(h, c, "FLUSHDB", "LAZY"); got != "-ERR syntax error\r\n" {
+		t.Errorf("FLUSHDB LAZY: got %q", got)
+	}
+	if got := execString(h, c, "FLUSHDB", "ASYNC"); got != "+OK\r\n" || h.selectDB(0).Len() != 0 || h.selectDB(1).Len() != 1 {
+		t.Errorf("FLUSHDB: got %q", got)
+	}
+	if got := execString(h, c, "FLUSHALL"); got != "+OK\r\n" || h.selectDB(1).Len() != 0 {
+		t.Errorf("FLUSHALL: got %q", got)
+	}
+}
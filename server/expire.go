@@ -0,0 +1,312 @@
+/*
+ * @Description: key expiration
+ * @Autor: HTmonster
+ * @Date: 2026-10-18 18:47:15
+ */
+
+package server
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/HTmonster/redissgo/datastruct/dict"
+	"github.com/HTmonster/redissgo/internal/config"
+	"github.com/HTmonster/redissgo/internal/reply"
+)
+
+// active expire cycle, see activeExpireCycle in redis
+const (
+	activeExpireKeysPerLoop     = 20 // keys with TTL sampled per loop
+	activeExpireAcceptableStale = 10 // percent of expired keys to stop
+	activeExpireCyclePercent    = 25 // percent of cpu time per cron tick
+)
+
+/**
+ * @description: set the TTL of an existing key
+ * @param {string} key
+ * @param {int64} at unix time in milliseconds, in the past deletes the key
+ * @param {dict.ExpireCheck} check optional condition on the current TTL
+ * @return {*} 1 if set, else 0
+ */
+func (db *DB) Expire(key string, at int64, check dict.ExpireCheck) int {
+	return db.data.SetExpire(key, at, check)
+}
+
+/**
+ * @description: get the TTL of a key
+ * @param {string} key
+ * @return {*} unix time in milliseconds, has TTL or not, exists or not
+ */
+func (db *DB) TTL(key string) (int64, bool, bool) {
+	return db.data.GetExpire(key)
+}
+
+/**
+ * @description: remove the TTL of a key
+ * @param {string} key
+ * @return {*} 1 if removed, else 0
+ */
+func (db *DB) Persist(key string) int {
+	return db.data.Persist(key)
+}
+
+/**
+ * @description: sample keys with TTL from the next segments and remove expired ones
+ * @param {int} samples
+ * @return {*} sampled keys, removed keys
+ */
+func (db *DB) activeExpire(samples int) (int, int) {
+	segments := db.data.SegmentCount()
+	sampled, expired := 0, 0
+	for i := 0; i < segments && sampled < samples; i++ {
+		s, e := db.data.ExpireSegment(db.expireCursor, samples-sampled)
+		sampled += s
+		expired += e
+		db.expireCursor = (db.expireCursor + 1) % segments
+	}
+	return sampled, expired
+}
+
+/**
+ * @description: remove expired keys of all databases in limited time
+ */
+func (h *Handler) activeExpireCycle() {
+	hz := config.Properties.Hz
+	if hz <= 0 {
+		hz = 10
+	}
+	timelimit := time.Second / time.Duration(hz) * activeExpireCyclePercent / 100
+	start := time.Now()
+
+	for i := range h.dbs {
+		db := h.selectDB(i)
+		for {
+			sampled, expired := db.activeExpire(activeExpireKeysPerLoop)
+			// most of the sampled keys are alive, try the next database
+			if sampled == 0 || expired*100/sampled <= activeExpireAcceptableStale {
+				break
+			}
+			if time.Since(start) > timelimit {
+				return
+			}
+		}
+	}
+}
+
+/**
+ * @description: run background jobs hz times per second until the handler closed
+ */
+func (h *Handler) serverCron() {
+	hz := config.Properties.Hz
+	if hz <= 0 {
+		hz = 10
+	}
+	ticker := time.NewTicker(time.Second / time.Duration(hz))
+	defer ticker.Stop()
+	for {
+		select {
+		case <-h.closeChan:
+			return
+		case <-ticker.C:
+			h.activeExpireCycle()
+		}
+	}
+}
+
+//------------ commands --------------
+
+func init() {
+	registerCommand("expire", execExpire, -3, flagWrite|flagFast).keys(1, 1, 1)
+	registerCommand("pexpire", execPExpire, -3, flagWrite|flagFast).keys(1, 1, 1)
+	registerCommand("expireat", execExpireAt, -3, flagWrite|flagFast).keys(1, 1, 1)
+	registerCommand("pexpireat", execPExpireAt, -3, flagWrite|flagFast).keys(1, 1, 1)
+	registerCommand("ttl", execTTL, 2, flagReadonly|flagFast).keys(1, 1, 1)
+	registerCommand("pttl", execPTTL, 2, flagReadonly|flagFast).keys(1, 1, 1)
+	registerCommand("expiretime", execExpireTime, 2, flagReadonly|flagFast).keys(1, 1, 1)
+	registerCommand("pexpiretime", execPExpireTime, 2, flagReadonly|flagFast).keys(1, 1, 1)
+	registerCommand("persist", execPersist, 2, flagWrite|flagFast).keys(1, 1, 1)
+}
+
+/**
+ * @description: parse NX|XX|GT|LT options of the EXPIRE family
+ * @param {[][]byte} args
+ * @param {int64} when the new TTL in milliseconds
+ * @return {*} check of the current TTL, error reply
+ */
+func parseExpireFlags(args [][]byte, when int64) (dict.ExpireCheck, reply.ErrorReply) {
+	var nx, xx, gt, lt bool
+	for _, arg := range args {
+		switch strings.ToLower(string(arg)) {
+		case "nx":
+			nx = true
+		case "xx":
+			xx = true
+		case "gt":
+			gt = true
+		case "lt":
+			lt = true
+		default:
+			return nil, reply.MakeErrReply("ERR Unsupported option " + string(arg))
+		}
+	}
+	if nx && (xx || gt || lt) {
+		return nil, reply.MakeErrReply("ERR NX and XX, GT or LT options at the same time are not compatible")
+	}
+	if gt && lt {
+		return nil, reply.MakeErrReply("ERR GT and LT options at the same time are not compatible")
+	}
+	if !nx && !xx && !gt && !lt {
+		return nil, nil
+	}
+
+	// a key without TTL is treated as an infinite TTL
+	return func(old int64, hasTTL bool) bool {
+		if nx && hasTTL {
+			return false
+		}
+		if xx && !hasTTL {
+			return false
+		}
+		if gt && (!hasTTL || when <= old) {
+			return false
+		}
+		if lt && hasTTL && when >= old {
+			return false
+		}
+		return true
+	}, nil
+}
+
+/**
+ * @description: EXPIRE, PEXPIRE, EXPIREAT and PEXPIREAT
+ * @param {*Client} c
+ * @param {[][]byte} args key time [NX|XX|GT|LT]
+ * @param {int64} basetime 0 for absolute time, now for relative time, in milliseconds
+ * @param {bool} seconds the unit of time
+ * @param {string} name command name
+ * @return {*}
+ */
+func expireGeneric(c *Client, args [][]byte, basetime int64, seconds bool, name string) reply.Reply {
+	key := string(args[0])
+	when, err := strconv.ParseInt(string(args[1]), 10, 64)
+	if err != nil {
+		return reply.MakeNotIntegerErrReply()
+	}
+
+	invalid := reply.MakeErrReply("ERR invalid expire time in '" + name + "' command")
+	if seconds {
+		if when > math.MaxInt64/1000 || when < math.MinInt64/1000 {
+			return invalid
+		}
+		when *= 1000
+	}
+	if when > math.MaxInt64-basetime {
+		return invalid
+	}
+	when += basetime
+
+	check, errReply := parseExpireFlags(args[2:], when)
+	if errReply != nil {
+		return errReply
+	}
+	return c.changesReply(int64(c.db.Expire(key, when, check)))
+}
+
+/**
+ * @description: EXPIRE key seconds [NX|XX|GT|LT]
+ */
+func execExpire(c *Client, args [][]byte) reply.Reply {
+	return expireGeneric(c, args, dict.NowMillis(), true, "expire")
+}
+
+/**
+ * @description: PEXPIRE key milliseconds [NX|XX|GT|LT]
+ */
+func execPExpire(c *Client, args [][]byte) reply.Reply {
+	return expireGeneric(c, args, dict.NowMillis(), false, "pexpire")
+}
+
+/**
+ * @description: EXPIREAT key unix-time-seconds [NX|XX|GT|LT]
+ */
+func execExpireAt(c *Client, args [][]byte) reply.Reply {
+	return expireGeneric(c, args, 0, true, "expireat")
+}
+
+/**
+ * @description: PEXPIREAT key unix-time-milliseconds [NX|XX|GT|LT]
+ */
+func execPExpireAt(c *Client, args [][]byte) reply.Reply {
+	return expireGeneric(c, args, 0, false, "pexpireat")
+}
+
+/**
+ * @description: TTL, PTTL, EXPIRETIME and PEXPIRETIME
+ * @param {*Client} c
+ * @param {string} key
+ * @param {bool} seconds the unit of the reply
+ * @param {bool} absolute reply the unix time instead of the remaining time
+ * @return {*} -2 if the key does not exist, -1 if it has no TTL
+ */
+func ttlGeneric(c *Client, key string, seconds bool, absolute bool) reply.Reply {
+	at, hasTTL, exists := c.db.TTL(key)
+	if !exists {
+		return reply.MakeIntReply(-2)
+	}
+	if !hasTTL {
+		return reply.MakeIntReply(-1)
+	}
+
+	if absolute {
+		if seconds {
+			return reply.MakeIntReply(at / 1000)
+		}
+		return reply.MakeIntReply(at)
+	}
+	ttl := at - dict.NowMillis()
+	if ttl < 0 {
+		ttl = 0
+	}
+	if seconds {
+		return reply.MakeIntReply((ttl + 500) / 1000)
+	}
+	return reply.MakeIntReply(ttl)
+}
+
+/**
+ * @description: TTL key
+ */
+func execTTL(c *Client, args [][]byte) reply.Reply {
+	return ttlGeneric(c, string(args[0]), true, false)
+}
+
+/**
+ * @description: PTTL key
+ */
+func execPTTL(c *Client, args [][]byte) reply.Reply {
+	return ttlGeneric(c, string(args[0]), false, false)
+}
+
+/**
+ * @description: EXPIRETIME key
+ */
+func execExpireTime(c *Client, args [][]byte) reply.Reply {
+	return ttlGeneric(c, string(args[0]), true, true)
+}
+
+/**
+ * @description: PEXPIRETIME key
+ */
+func execPExpireTime(c *Client, args [][]byte) reply.Reply {
+	return ttlGeneric(c, string(args[0]), false, true)
+}
+
+/**
+ * @description: PERSIST key
+ */
+func execPersist(c *Client, args [][]byte) reply.Reply {
+	return c.changesReply(int64(c.db.Persist(string(args[0]))))
+}
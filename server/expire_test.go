@@ -0,0 +1,73 @@
+/*
+ * @Description:
+ * @Autor: HTmonster
+ * @Date: 2026-10-18 19:24:50
+ */
+package server
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/HTmonster/redissgo/datastruct/dict"
+)
+
+func TestExpire(t *testing.T) {
+	h := NewHandler()
+	defer h.Close()
+	c := newTestClient(h)
+	db := h.selectDB(0)
+	db.PutEntity("k", []byte("v"))
+
+	cases := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"TTL", "none"}, ":-2\r\n"},
+		{[]string{"TTL", "k"}, ":-1\r\n"},
+		{[]string{"EXPIRE", "k", "100", "XX"}, ":0\r\n"},
+		{[]string{"EXPIRE", "k", "100", "GT"}, ":0\r\n"},
+		{[]string{"EXPIRE", "k", "100", "NX"}, ":1\r\n"},
+		{[]string{"TTL", "k"}, ":100\r\n"},
+		{[]string{"EXPIRE", "k", "50", "GT"}, ":0\r\n"},
+		{[]string{"EXPIRE", "k", "50", "LT"}, ":1\r\n"},
+		{[]string{"TTL", "k"}, ":50\r\n"},
+		{[]string{"EXPIRE", "k", "50", "NX", "XX"}, "-ERR NX and XX, GT or LT options at the same time are not compatible\r\n"},
+		{[]string{"EXPIRE", "k", "50", "GT", "LT"}, "-ERR GT and LT options at the same time are not compatible\r\n"},
+		{[]string{"EXPIRE", "k", "50", "YY"}, "-ERR Unsupported option YY\r\n"},
+		{[]string{"EXPIRE", "k", "x"}, "-ERR value is not an integer or out of range\r\n"},
+		{[]string{"EXPIRE", "k", "9223372036854775807"}, "-ERR invalid expire time in 'expire' command\r\n"},
+		{[]string{"PEXPIREAT", "k", "4102444800000"}, ":1\r\n"},
+		{[]string{"EXPIRETIME", "k"}, ":4102444800\r\n"},
+		{[]string{"PEXPIRETIME", "k"}, ":4102444800000\r\n"},
+		{[]string{"PERSIST", "k"}, ":1\r\n"},
+		{[]string{"PERSIST", "k"}, ":0\r\n"},
+		{[]string{"PTTL", "k"}, ":-1\r\n"},
+		{[]string{"EXPIREAT", "k", "1"}, ":1\r\n"},
+		{[]string{"TTL", "k"}, ":-2\r\n"},
+	}
+	for _, tc := range cases {
+		if got := execString(h, c, tc.args...); got != tc.want {
+			t.Errorf("%q: got %q, want %q", tc.args, got, tc.want)
+		}
+	}
+}
+
+func TestActiveExpireCycle(t *testing.T) {
+	h := NewHandler()
+	// stop the cron, the cycle is run by hand
+	h.Close()
+	now := dict.NowMillis()
+	for i := 0; i < 2000; i++ {
+		h.selectDB(i % 3).PutEntityWithExpire("key"+strconv.Itoa(i), []byte("v"), now-1)
+	}
+	h.selectDB(0).PutEntity("alive", []byte("v"))
+
+	for i := 0; i < 100 && h.selectDB(0).Len()+h.selectDB(1).Len()+h.selectDB(2).Len() > 1; i++ {
+		h.activeExpireCycle()
+	}
+	if h.selectDB(0).Len() != 1 || h.selectDB(1).Len() != 0 || h.selectDB(2).Len() != 0 {
+		t.Errorf("expired keys should be removed by the active expire cycle: %d %d %d",
+			h.selectDB(0).Len(), h.selectDB(1).Len(), h.selectDB(2).Len())
+	}
+}
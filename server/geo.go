@@ -0,0 +1,480 @@
+/*
+ * @Description: geospatial commands over sorted sets, scores are 52 bits geohashes
+ * @Autor: HTmonster
+ * @Date: 2026-10-19 18:46:30
+ */
+
+package server
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/HTmonster/redissgo/datastruct/sortedset"
+	"github.com/HTmonster/redissgo/internal/geohash"
+	"github.com/HTmonster/redissgo/internal/reply"
+)
+
+func init() {
+	registerCommand("geoadd", execGeoAdd, -5, flagWrite).keys(1, 1, 1)
+	registerCommand("geopos", execGeoPos, -2, flagReadonly).keys(1, 1, 1)
+	registerCommand("geodist", execGeoDist, -4, flagReadonly).keys(1, 1, 1)
+	registerCommand("geohash", execGeoHash, -2, flagReadonly).keys(1, 1, 1)
+	registerCommand("geosearch", execGeoSearch, -7, flagReadonly).keys(1, 1, 1)
+	registerCommand("geosearchstore", execGeoSearchStore, -8, flagWrite).keys(1, 2, 1)
+}
+
+//------------ helpers --------------
+
+/**
+ * @description: parse a longitude and a latitude
+ * @param {[][]byte} args longitude, latitude
+ * @return {*}
+ */
+func parseLonLat(args [][]byte) (float64, float64, reply.ErrorReply) {
+	lon, ok1 := parseScore(args[0])
+	lat, ok2 := parseScore(args[1])
+	if !ok1 || !ok2 {
+		return 0, 0, reply.MakeErrReply("ERR value is not a valid float")
+	}
+	if !geohash.Valid(lon, lat) {
+		return 0, 0, reply.MakeErrReply(fmt.Sprintf("ERR invalid longitude,latitude pair %f,%f", lon, lat))
+	}
+	return lon, lat, nil
+}
+
+/**
+ * @description: parse a distance unit
+ * @return {*} meters of the unit
+ */
+func parseUnit(arg []byte) (float64, reply.ErrorReply) {
+	switch strings.ToLower(string(arg)) {
+	case "m":
+		return 1, nil
+	case "km":
+		return 1000, nil
+	case "ft":
+		return 0.3048, nil
+	case "mi":
+		return 1609.34, nil
+	}
+	return 0, reply.MakeErrReply("ERR unsupported unit provided. please use M, KM, FT, MI")
+}
+
+/**
+ * @description: parse a radius, a width or a height
+ * @param {[]byte} arg
+ * @param {string} name name in the error message
+ * @return {*}
+ */
+func parseDistance(arg []byte, name string) (float64, reply.ErrorReply) {
+	distance, ok := parseScore(arg)
+	if !ok {
+		return 0, reply.MakeErrReply("ERR need numeric " + name)
+	}
+	return distance, nil
+}
+
+/**
+ * @description: reply a distance with 4 decimals, like redis
+ */
+func makeDistanceReply(distance float64) reply.Reply {
+	return reply.MakeBulkReply([]byte(strconv.FormatFloat(distance, 'f', 4, 64)))
+}
+
+/**
+ * @description: reply the position of a geohash score
+ */
+func makeCoordReply(score float64) reply.Reply {
+	lon, lat := geohash.Decode52(uint64(score))
+	return reply.MakeArrayReply([]reply.Reply{reply.MakeHumanDoubleReply(lon), reply.MakeHumanDoubleReply(lat)})
+}
+
+//------------ GEOADD GEOPOS GEODIST GEOHASH --------------
+
+/**
+ * @description: GEOADD key [NX|XX] [CH] longitude latitude member [longitude latitude member ...]
+ *	turned into a ZADD with geohash scores
+ */
+func execGeoAdd(c *Client, args [][]byte) reply.Reply {
+	i := 1
+	nx, xx := false, false
+options:
+	for ; i < len(args); i++ {
+		switch strings.ToLower(string(args[i])) {
+		case "nx":
+			nx = true
+		case "xx":
+			xx = true
+		case "ch":
+		default:
+			break options
+		}
+	}
+	triples := args[i:]
+	if len(triples)%3 != 0 || (nx && xx) {
+		return reply.MakeSyntaxErrReply()
+	}
+
+	zaddArgs := make([][]byte, 0, len(args))
+	zaddArgs = append(zaddArgs, args[:i]...)
+	for j := 0; j < len(triples); j += 3 {
+		lon, lat, errReply := parseLonLat(triples[j : j+2])
+		if errReply != nil {
+			return errReply
+		}
+		score := strconv.FormatUint(geohash.Encode52(lon, lat), 10)
+		zaddArgs = append(zaddArgs, []byte(score), triples[j+2])
+	}
+	return execZAdd(c, zaddArgs)
+}
+
+/**
+ * @description: GEOPOS key [member [member ...]]
+ */
+func execGeoPos(c *Client, args [][]byte) reply.Reply {
+	return c.db.updateZSet(string(args[0]), false, func(zs *sortedset.SortedSet) reply.Reply {
+		replies := make([]reply.Reply, len(args)-1)
+		for i, member := range args[1:] {
+			replies[i] = reply.MakeNullArrayReply()
+			if zs == nil {
+				continue
+			}
+			if score, ok := zs.Score(string(member)); ok {
+				replies[i] = makeCoordReply(score)
+			}
+		}
+		return reply.MakeArrayReply(replies)
+	})
+}
+
+/**
+ * @description: GEODIST key member1 member2 [M|KM|FT|MI]
+ */
+func execGeoDist(c *Client, args [][]byte) reply.Reply {
+	if len(args) > 4 {
+		return reply.MakeSyntaxErrReply()
+	}
+	unit := 1.0
+	if len(args) == 4 {
+		var errReply reply.ErrorReply
+		if unit, errReply = parseUnit(args[3]); errReply != nil {
+			return errReply
+		}
+	}
+	return c.db.updateZSet(string(args[0]), false, func(zs *sortedset.SortedSet) reply.Reply {
+		if zs == nil {
+			return reply.MakeNullBulkReply()
+		}
+		score1, ok1 := zs.Score(string(args[1]))
+		score2, ok2 := zs.Score(string(args[2]))
+		if !ok1 || !ok2 {
+			return reply.MakeNullBulkReply()
+		}
+		lon1, lat1 := geohash.Decode52(uint64(score1))
+		lon2, lat2 := geohash.Decode52(uint64(score2))
+		return makeDistanceReply(geohash.Distance(lon1, lat1, lon2, lat2) / unit)
+	})
+}
+
+/**
+ * @description: GEOHASH key [member [member ...]]
+ */
+func execGeoHash(c *Client, args [][]byte) reply.Reply {
+	return c.db.updateZSet(string(args[0]), false, func(zs *sortedset.SortedSet) reply.Reply {
+		replies := make([]reply.Reply, len(args)-1)
+		for i, member := range args[1:] {
+			replies[i] = reply.MakeNullBulkReply()
+			if zs == nil {
+				continue
+			}
+			if score, ok := zs.Score(string(member)); ok {
+				lon, lat := geohash.Decode52(uint64(score))
+				replies[i] = reply.MakeBulkReply([]byte(geohash.String(lon, lat)))
+			}
+		}
+		return reply.MakeArrayReply(replies)
+	})
+}
+
+//------------ GEOSEARCH --------------
+
+// sort orders of search results
+const (
+	geoSortNone = iota
+	geoSortAsc
+	geoSortDesc
+)
+
+// parsed arguments of GEOSEARCH and GEOSEARCHSTORE
+type geoSearchSpec struct {
+	fromMember []byte // nil for FROMLONLAT
+	shape      geohash.Shape
+	unit       float64 // meters of the unit of the distances
+	sort       int
+	count      int64 // 0 means all
+	any        bool
+
+	withCoord, withDist, withHash bool
+	storeDist                     bool
+}
+
+// a member found by a search
+type geoPoint struct {
+	member   string
+	score    float64
+	distance float64 // meters
+}
+
+/**
+ * @description: parse the options of GEOSEARCH and GEOSEARCHSTORE
+ * @param {[][]byte} args options after the source key
+ * @param {string} name command name for the error messages
+ * @param {bool} store GEOSEARCHSTORE
+ * @return {*}
+ */
+func parseGeoSearchSpec(args [][]byte, name string, store bool) (*geoSearchSpec, reply.ErrorReply) {
+	spec := &geoSearchSpec{}
+	fromLonLat, byRadius, byBox := false, false, false
+	for i := 0; i < len(args); i++ {
+		remaining := len(args) - i - 1
+		switch option := strings.ToLower(string(args[i])); {
+		case option == "withdist":
+			spec.withDist = true
+		case option == "withhash":
+			spec.withHash = true
+		case option == "withcoord":
+			spec.withCoord = true
+		case option == "any":
+			spec.any = true
+		case option == "asc":
+			spec.sort = geoSortAsc
+		case option == "desc":
+			spec.sort = geoSortDesc
+		case option == "storedist" && store:
+			spec.storeDist = true
+		case option == "count" && remaining >= 1:
+			count, ok := parseCanonicalInt(args[i+1])
+			if !ok {
+				return nil, reply.MakeNotIntegerErrReply()
+			}
+			if count <= 0 {
+				return nil, reply.MakeErrReply("ERR COUNT must be > 0")
+			}
+			spec.count = count
+			i++
+		case option == "frommember" && remaining >= 1:
+			if fromLonLat {
+				return nil, reply.MakeSyntaxErrReply()
+			}
+			spec.fromMember = args[i+1]
+			i++
+		case option == "fromlonlat" && remaining >= 2:
+			if spec.fromMember != nil {
+				return nil, reply.MakeSyntaxErrReply()
+			}
+			var errReply reply.ErrorReply
+			if spec.shape.Lon, spec.shape.Lat, errReply = parseLonLat(args[i+1 : i+3]); errReply != nil {
+				return nil, errReply
+			}
+			fromLonLat = true
+			i += 2
+		case option == "byradius" && remaining >= 2:
+			if byBox {
+				return nil, reply.MakeSyntaxErrReply()
+			}
+			radius, errReply := parseDistance(args[i+1], "radius")
+			if errReply != nil {
+				return nil, errReply
+			}
+			if radius < 0 {
+				return nil, reply.MakeErrReply("ERR radius cannot be negative")
+			}
+			if spec.unit, errReply = parseUnit(args[i+2]); errReply != nil {
+				return nil, errReply
+			}
+			spec.shape.Radius = radius * spec.unit
+			byRadius = true
+			i += 2
+		case option == "bybox" && remaining >= 3:
+			if byRadius {
+				return nil, reply.MakeSyntaxErrReply()
+			}
+			width, errReply := parseDistance(args[i+1], "width")
+			if errReply != nil {
+				return nil, errReply
+			}
+			height, errReply := parseDistance(args[i+2], "height")
+			if errReply != nil {
+				return nil, errReply
+			}
+			if width < 0 || height < 0 {
+				return nil, reply.MakeErrReply("ERR height or width cannot be negative")
+			}
+			if spec.unit, errReply = parseUnit(args[i+3]); errReply != nil {
+				return nil, errReply
+			}
+			spec.shape.Width, spec.shape.Height, spec.shape.IsBox = width*spec.unit, height*spec.unit, true
+			byBox = true
+			i += 3
+		default:
+			return nil, reply.MakeSyntaxErrReply()
+		}
+	}
+
+	if spec.fromMember == nil && !fromLonLat {
+		return nil, reply.MakeErrReply("ERR exactly one of FROMMEMBER or FROMLONLAT can be specified for " + name)
+	}
+	if !byRadius && !byBox {
+		return nil, reply.MakeErrReply("ERR exactly one of BYRADIUS and BYBOX can be specified for " + name)
+	}
+	if spec.any && spec.count == 0 {
+		return nil, reply.MakeErrReply("ERR the ANY argument requires COUNT argument")
+	}
+	if store && (spec.withDist || spec.withHash || spec.withCoord) {
+		return nil, reply.MakeErrReply("ERR GEOSEARCHSTORE is not compatible with WITHDIST, WITHHASH and WITHCOORD options")
+	}
+	// the closest ones are expected with COUNT
+	if spec.count > 0 && spec.sort == geoSortNone && !spec.any {
+		spec.sort = geoSortAsc
+	}
+	return spec, nil
+}
+
+/**
+ * @description: find the members in the shape of the spec
+ * @param {*sortedset.SortedSet} zs
+ * @return {*} points, error if the FROMMEMBER member does not exist
+ */
+func (spec *geoSearchSpec) search(zs *sortedset.SortedSet) ([]geoPoint, reply.ErrorReply) {
+	shape := spec.shape
+	if spec.fromMember != nil {
+		score, ok := zs.Score(string(spec.fromMember))
+		if !ok {
+			return nil, reply.MakeErrReply("ERR could not decode requested zset member")
+		}
+		shape.Lon, shape.Lat = geohash.Decode52(uint64(score))
+	}
+
+	var points []geoPoint
+	for _, area := range shape.SearchAreas() {
+		min, max := area.ScoreRange()
+		r := &sortedset.ScoreRange{
+			Min: sortedset.ScoreBorder{Value: float64(min)},
+			Max: sortedset.ScoreBorder{Value: float64(max), Exclude: true},
+		}
+		for _, e := range zs.Range(r, 0, -1, false) {
+			lon, lat := geohash.Decode52(uint64(e.Score))
+			distance, ok := shape.Contains(lon, lat)
+			if !ok {
+				continue
+			}
+			points = append(points, geoPoint{member: e.Member, score: e.Score, distance: distance})
+			// any members will do
+			if spec.any && int64(len(points)) >= spec.count {
+				break
+			}
+		}
+		if spec.any && int64(len(points)) >= spec.count {
+			break
+		}
+	}
+
+	switch spec.sort {
+	case geoSortAsc:
+		sort.SliceStable(points, func(i, j int) bool { return points[i].distance < points[j].distance })
+	case geoSortDesc:
+		sort.SliceStable(points, func(i, j int) bool { return points[i].distance > points[j].distance })
+	}
+	if spec.count > 0 && int64(len(points)) > spec.count {
+		points = points[:spec.count]
+	}
+	return points, nil
+}
+
+/**
+ * @description: reply the points, with the options of the spec
+ */
+func (spec *geoSearchSpec) makeReply(points []geoPoint) reply.Reply {
+	if !spec.withDist && !spec.withHash && !spec.withCoord {
+		members := make([][]byte, len(points))
+		for i, p := range points {
+			members[i] = []byte(p.member)
+		}
+		return reply.MakeMultiBulkReply(members)
+	}
+	replies := make([]reply.Reply, len(points))
+	for i, p := range points {
+		fields := []reply.Reply{reply.MakeBulkReply([]byte(p.member))}
+		if spec.withDist {
+			fields = append(fields, makeDistanceReply(p.distance/spec.unit))
+		}
+		if spec.withHash {
+			fields = append(fields, reply.MakeIntReply(int64(p.score)))
+		}
+		if spec.withCoord {
+			fields = append(fields, makeCoordReply(p.score))
+		}
+		replies[i] = reply.MakeArrayReply(fields)
+	}
+	return reply.MakeArrayReply(replies)
+}
+
+/**
+ * @description: GEOSEARCH key FROMMEMBER member|FROMLONLAT longitude latitude
+ *	BYRADIUS radius unit|BYBOX width height unit [ASC|DESC] [COUNT count [ANY]]
+ *	[WITHCOORD] [WITHDIST] [WITHHASH]
+ */
+func execGeoSearch(c *Client, args [][]byte) reply.Reply {
+	spec, errReply := parseGeoSearchSpec(args[1:], "geosearch", false)
+	if errReply != nil {
+		return errReply
+	}
+	return c.db.updateZSet(string(args[0]), false, func(zs *sortedset.SortedSet) reply.Reply {
+		if zs == nil {
+			return reply.MakeEmptyArrayReply()
+		}
+		points, errReply := spec.search(zs)
+		if errReply != nil {
+			return errReply
+		}
+		return spec.makeReply(points)
+	})
+}
+
+/**
+ * @description: GEOSEARCHSTORE destination source FROMMEMBER member|FROMLONLAT longitude latitude
+ *	BYRADIUS radius unit|BYBOX width height unit [ASC|DESC] [COUNT count [ANY]] [STOREDIST]
+ */
+func execGeoSearchStore(c *Client, args [][]byte) reply.Reply {
+	spec, errReply := parseGeoSearchSpec(args[2:], "geosearchstore", true)
+	if errReply != nil {
+		return errReply
+	}
+	var points []geoPoint
+	result := c.db.updateZSet(string(args[1]), false, func(zs *sortedset.SortedSet) reply.Reply {
+		if zs == nil {
+			return nil
+		}
+		var errReply reply.ErrorReply
+		points, errReply = spec.search(zs)
+		if errReply != nil {
+			return errReply
+		}
+		return nil
+	})
+	if result != nil {
+		return result
+	}
+
+	elements := make([]sortedset.Element, len(points))
+	for i, p := range points {
+		elements[i] = sortedset.Element{Member: p.member, Score: p.score}
+		if spec.storeDist {
+			elements[i].Score = p.distance / spec.unit
+		}
+	}
+	return reply.MakeIntReply(c.storeZSet(string(args[0]), elements))
+}
@@ -0,0 +1,119 @@
+/*
+ * @Description:
+ * @Autor: HTmonster
+ * @Date: 2026-10-19 19:58:14
+ */
+package server
+
+import (
+	"testing"
+
+	"github.com/HTmonster/redissgo/internal/reply"
+)
+
+/**
+ * @description: add the members of the examples of redis
+ */
+func addSicily(t *testing.T, h *Handler, c *Client) {
+	t.Helper()
+	runCases(t, h, c, []cmdCase{
+		{[]string{"GEOADD", "Sicily", "13.361389", "38.115556", "Palermo", "15.087269", "37.502669", "Catania"}, ":2\r\n"},
+		{[]string{"GEOADD", "Sicily", "12.758489", "38.788135", "edge1", "17.241510", "38.788135", "edge2"}, ":2\r\n"},
+	})
+}
+
+func TestGeoAddPos(t *testing.T) {
+	h := NewHandler()
+	defer h.Close()
+	c := newTestClient(h)
+	addSicily(t, h, c)
+
+	palermo := "*2\r\n$20\r\n13.36138933897018433\r\n$20\r\n38.11555639549629859\r\n"
+	runCases(t, h, c, []cmdCase{
+		{[]string{"ZSCORE", "Sicily", "Palermo"}, "$16\r\n3479099956230698\r\n"},
+		{[]string{"GEOPOS", "Sicily", "Palermo", "none"}, "*2\r\n" + palermo + "*-1\r\n"},
+		{[]string{"GEOPOS", "none", "Palermo"}, "*1\r\n*-1\r\n"},
+		{[]string{"GEODIST", "Sicily", "Palermo", "Catania"}, "$11\r\n166274.1516\r\n"},
+		{[]string{"GEODIST", "Sicily", "Palermo", "Catania", "km"}, "$8\r\n166.2742\r\n"},
+		{[]string{"GEODIST", "Sicily", "Palermo", "Catania", "MI"}, "$8\r\n103.3182\r\n"},
+		{[]string{"GEODIST", "Sicily", "Palermo", "none"}, "$-1\r\n"},
+		{[]string{"GEODIST", "Sicily", "Palermo", "Catania", "yd"}, "-ERR unsupported unit provided. please use M, KM, FT, MI\r\n"},
+		{[]string{"GEOHASH", "Sicily", "Palermo", "Catania", "none"}, "*3\r\n$11\r\nsqc8b49rny0\r\n$11\r\nsqdtr74hyu0\r\n$-1\r\n"},
+
+		{[]string{"GEOADD", "Sicily", "NX", "0", "0", "Palermo"}, ":0\r\n"},
+		{[]string{"GEOADD", "Sicily", "XX", "CH", "13.361389", "38.115556", "Palermo", "0", "0", "new"}, ":0\r\n"},
+		{[]string{"GEOADD", "Sicily", "NX", "XX", "0", "0", "a"}, "-ERR syntax error\r\n"},
+		{[]string{"GEOADD", "Sicily", "0", "0", "a", "0"}, "-ERR syntax error\r\n"},
+		{[]string{"GEOADD", "Sicily", "x", "0", "a"}, "-ERR value is not a valid float\r\n"},
+		{[]string{"GEOADD", "Sicily", "200", "100", "a"}, "-ERR invalid longitude,latitude pair 200.000000,100.000000\r\n"},
+		{[]string{"ZCARD", "Sicily"}, ":4\r\n"},
+	})
+
+	c.SetProtocol(reply.RESP3)
+	runCases(t, h, c, []cmdCase{
+		{[]string{"GEOPOS", "Sicily", "Palermo"}, "*1\r\n*2\r\n,13.36138933897018433\r\n,38.11555639549629859\r\n"},
+	})
+}
+
+func TestGeoSearch(t *testing.T) {
+	h := NewHandler()
+	defer h.Close()
+	c := newTestClient(h)
+	addSicily(t, h, c)
+
+	runCases(t, h, c, []cmdCase{
+		{[]string{"GEOSEARCH", "Sicily", "FROMLONLAT", "15", "37", "BYRADIUS", "200", "km", "ASC"},
+			"*2\r\n$7\r\nCatania\r\n$7\r\nPalermo\r\n"},
+		{[]string{"GEOSEARCH", "Sicily", "FROMLONLAT", "15", "37", "BYBOX", "400", "400", "km", "ASC", "WITHCOORD", "WITHDIST"},
+			"*4\r\n" +
+				"*3\r\n$7\r\nCatania\r\n$7\r\n56.4413\r\n*2\r\n$20\r\n15.08726745843887329\r\n$20\r\n37.50266842333162032\r\n" +
+				"*3\r\n$7\r\nPalermo\r\n$8\r\n190.4424\r\n*2\r\n$20\r\n13.36138933897018433\r\n$20\r\n38.11555639549629859\r\n" +
+				"*3\r\n$5\r\nedge2\r\n$8\r\n279.7403\r\n*2\r\n$20\r\n17.24151045083999634\r\n$20\r\n38.78813451624225195\r\n" +
+				"*3\r\n$5\r\nedge1\r\n$8\r\n279.7405\r\n*2\r\n$19\r\n12.7584877610206604\r\n$20\r\n38.78813451624225195\r\n"},
+		{[]string{"GEOSEARCH", "Sicily", "FROMMEMBER", "Palermo", "BYRADIUS", "200", "km", "DESC", "WITHDIST", "WITHHASH"},
+			"*3\r\n*3\r\n$7\r\nCatania\r\n$8\r\n166.2742\r\n:3479447370796909\r\n" +
+				"*3\r\n$5\r\nedge1\r\n$7\r\n91.4007\r\n:3479273021651468\r\n*3\r\n$7\r\nPalermo\r\n$6\r\n0.0000\r\n:3479099956230698\r\n"},
+		{[]string{"GEOSEARCH", "Sicily", "FROMLONLAT", "15", "37", "BYRADIUS", "500", "km", "COUNT", "1"}, "*1\r\n$7\r\nCatania\r\n"},
+		{[]string{"GEOSEARCH", "Sicily", "FROMLONLAT", "15", "37", "BYRADIUS", "500", "km", "COUNT", "2", "DESC"}, "*2\r\n$5\r\nedge1\r\n$5\r\nedge2\r\n"},
+		{[]string{"GEOSEARCH", "Sicily", "FROMLONLAT", "15", "37", "BYRADIUS", "10", "km"}, "*0\r\n"},
+		{[]string{"GEOSEARCH", "none", "FROMMEMBER", "a", "BYRADIUS", "10", "km"}, "*0\r\n"},
+
+		{[]string{"GEOSEARCH", "Sicily", "FROMMEMBER", "none", "BYRADIUS", "10", "km"}, "-ERR could not decode requested zset member\r\n"},
+		{[]string{"GEOSEARCH", "Sicily", "BYRADIUS", "10", "km", "ASC", "WITHDIST"}, "-ERR exactly one of FROMMEMBER or FROMLONLAT can be specified for geosearch\r\n"},
+		{[]string{"GEOSEARCH", "Sicily", "FROMLONLAT", "15", "37", "ASC", "WITHDIST"}, "-ERR exactly one of BYRADIUS and BYBOX can be specified for geosearch\r\n"},
+		{[]string{"GEOSEARCH", "Sicily", "FROMLONLAT", "15", "37", "FROMMEMBER", "a", "BYRADIUS", "1", "m"}, "-ERR syntax error\r\n"},
+		{[]string{"GEOSEARCH", "Sicily", "FROMLONLAT", "15", "37", "BYRADIUS", "1", "m", "BYBOX", "1", "1", "m"}, "-ERR syntax error\r\n"},
+		{[]string{"GEOSEARCH", "Sicily", "FROMLONLAT", "15", "37", "BYRADIUS", "-1", "m"}, "-ERR radius cannot be negative\r\n"},
+		{[]string{"GEOSEARCH", "Sicily", "FROMLONLAT", "15", "37", "BYRADIUS", "x", "m"}, "-ERR need numeric radius\r\n"},
+		{[]string{"GEOSEARCH", "Sicily", "FROMLONLAT", "15", "37", "BYBOX", "1", "-1", "m"}, "-ERR height or width cannot be negative\r\n"},
+		{[]string{"GEOSEARCH", "Sicily", "FROMLONLAT", "15", "37", "BYRADIUS", "1", "m", "ANY"}, "-ERR the ANY argument requires COUNT argument\r\n"},
+		{[]string{"GEOSEARCH", "Sicily", "FROMLONLAT", "15", "37", "BYRADIUS", "1", "m", "COUNT", "0"}, "-ERR COUNT must be > 0\r\n"},
+		{[]string{"GEOSEARCH", "Sicily", "FROMLONLAT", "15", "37", "BYRADIUS", "1", "m", "STOREDIST"}, "-ERR syntax error\r\n"},
+		{[]string{"SET", "str", "v"}, "+OK\r\n"},
+		{[]string{"GEOSEARCH", "str", "FROMLONLAT", "15", "37", "BYRADIUS", "1", "m"}, wrongType},
+	})
+
+	// ANY returns as soon as enough members are found
+	members := execMembers(h, c, "GEOSEARCH", "Sicily", "FROMLONLAT", "15", "37", "BYRADIUS", "500", "km", "COUNT", "3", "ANY")
+	if len(members) != 3 {
+		t.Errorf("COUNT 3 ANY: got %v", members)
+	}
+}
+
+func TestGeoSearchStore(t *testing.T) {
+	h := NewHandler()
+	defer h.Close()
+	c := newTestClient(h)
+	addSicily(t, h, c)
+
+	runCases(t, h, c, []cmdCase{
+		{[]string{"GEOSEARCHSTORE", "dst", "Sicily", "FROMLONLAT", "15", "37", "BYRADIUS", "200", "km"}, ":2\r\n"},
+		{[]string{"GEOPOS", "dst", "Catania"}, "*1\r\n*2\r\n$20\r\n15.08726745843887329\r\n$20\r\n37.50266842333162032\r\n"},
+		{[]string{"GEOSEARCHSTORE", "dst", "Sicily", "FROMLONLAT", "15", "37", "BYRADIUS", "200", "km", "STOREDIST", "COUNT", "1"}, ":1\r\n"},
+		{[]string{"ZRANGE", "dst", "0", "-1", "WITHSCORES"}, "*2\r\n$7\r\nCatania\r\n$18\r\n56.441257870158204\r\n"},
+		{[]string{"GEOSEARCHSTORE", "dst", "Sicily", "FROMLONLAT", "15", "37", "BYRADIUS", "1", "km"}, ":0\r\n"},
+		{[]string{"ZCARD", "dst"}, ":0\r\n"},
+		{[]string{"GEOSEARCHSTORE", "dst", "Sicily", "FROMLONLAT", "15", "37", "BYRADIUS", "1", "km", "WITHDIST"},
+			"-ERR GEOSEARCHSTORE is not compatible with WITHDIST, WITHHASH and WITHCOORD options\r\n"},
+	})
+}
@@ -8,25 +8,89 @@ package server
 
 import (
 	"context"
-	"fmt"
 	"net"
+	"sync"
 
+	"github.com/HTmonster/redissgo/internal/config"
 	"github.com/HTmonster/redissgo/internal/logger"
+	"github.com/HTmonster/redissgo/internal/reply"
 	"github.com/HTmonster/redissgo/internal/request"
 )
 
 //------------ handler --------------
 type Handler struct {
-	content string // stub
+	dbs     []*DB
+	dbsLock sync.RWMutex // protects dbs from SWAPDB
+
+	blocking *blockingRegistry
+	clients  sync.Map // connected *Client, closed with the handler
+
+	closeChan chan struct{} // closed when the handler is closed
+	closeOnce sync.Once
 }
 
 /**
  * @description: creat a new Handler instance
  */
 func NewHandler() *Handler {
-	return &Handler{
-		content: "",
+	count := config.Properties.Database
+	if count <= 0 {
+		count = 16
+	}
+	dbs := make([]*DB, count)
+	for i := range dbs {
+		dbs[i] = makeDB(i)
+	}
+	h := &Handler{
+		dbs:       dbs,
+		blocking:  makeBlockingRegistry(),
+		closeChan: make(chan struct{}),
+	}
+	go h.serverCron()
+	return h
+}
+
+/**
+ * @description: get the database by index
+ * @param {int} index
+ * @return {*}
+ */
+func (h *Handler) selectDB(index int) *DB {
+	h.dbsLock.RLock()
+	defer h.dbsLock.RUnlock()
+	return h.dbs[index]
+}
+
+/**
+ * @description: swap two databases, clients see the data of each other at once
+ * @param {int} first
+ * @param {int} second
+ * @return {*}
+ */
+func (h *Handler) swapDB(first, second int) {
+	// wait for the transactions on the two databases
+	plan := map[int]*txLocks{
+		first:  {all: true},
+		second: {all: true},
 	}
+	_, unlock := h.lockDBs(plan)
+	defer unlock()
+	h.swapDBLocked(first, second)
+}
+
+/**
+ * @description: swap two databases whose segments are all locked by the caller
+ * @param {int} first
+ * @param {int} second
+ * @return {*}
+ */
+func (h *Handler) swapDBLocked(first, second int) {
+	h.dbsLock.Lock()
+	defer h.dbsLock.Unlock()
+	h.dbs[first], h.dbs[second] = h.dbs[second], h.dbs[first]
+	h.dbs[first].index, h.dbs[second].index = first, second
+	// watched keys existing in either database are seen as modified
+	swapWatched(h.dbs[first], h.dbs[second])
 }
 
 /**
@@ -35,31 +99,118 @@ func NewHandler() *Handler {
  * @param {net.Conn} conn
  * @return {*}
  */
-func (*Handler) Handle(ctx context.Context, conn net.Conn) error {
+func (h *Handler) Handle(ctx context.Context, conn net.Conn) error {
 	defer conn.Close()
-	// for {
-	// 	var buf [128]byte
-	// 	n, err := conn.Read(buf[:])
-	// 	if err != nil {
-	// 		logger.Log.Error("Read from tcp server failed,err:", err)
-	// 		break
-	// 	}
-	// 	data := string(buf[:n])
-	// 	logger.Log.Info("Read from tcp server:", data)
-	// }
-
-	ch := request.ParseRequest(conn)
-	for request := range ch {
-		if request.Err != nil {
+
+	client := newClient(h, conn)
+	client.authenticated = config.Properties.Requirepass == ""
+	writer := client.writer
+
+	h.clients.Store(client, struct{}{})
+	defer h.clients.Delete(client)
+	defer client.unwatch()
+	select {
+	case <-h.closeChan:
+		// closed before the client was stored
+		return nil
+	default:
+	}
+
+	reader := request.NewReader(conn)
+	defer reader.Release()
+	client.reader = reader
+	for {
+		req, err := reader.Next()
+		if err != nil {
+			// the client may half-close after sending a pipeline
+			_ = writer.Flush()
+			if perr, ok := err.(*request.ProtocolError); ok {
+				// protocol error, reply and close the connection
+				_ = writer.Write(reply.MakeProtocolErrReply(perr.Msg))
+				_ = writer.Flush()
+				return err
+			}
+			logger.Log.Info("connection closed: ", client.RemoteAddr())
+			return nil
+		}
+		if len(req.Params) == 0 {
+			// redis silently ignores empty requests
+			if reader.Buffered() == 0 {
+				_ = writer.Flush()
+			}
 			continue
 		}
-		if len(request.Params) == 0 {
-			logger.Log.Error("empty request parameter")
+
+		// replies are written in request order, pipelined replies are
+		// flushed together once no more input is buffered
+		result := h.exec(client, req.Params)
+		err = writer.Write(result)
+		if err == nil && reader.Buffered() == 0 {
+			err = writer.Flush()
+		}
+		if err != nil {
+			logger.Log.Error("write reply error: ", err)
+			return err
 		}
-		fmt.Println(request)
 	}
+}
 
-	return nil
+/**
+ * @description: look up the command and execute it
+ * @param {*Client} c
+ * @param {[][]byte} params (including the command name)
+ * @return {*} reply
+ */
+func (h *Handler) exec(c *Client, params [][]byte) reply.Reply {
+	cmd, ok := lookupCommand(params[0])
+	if !ok {
+		c.flagMultiError()
+		return unknownCommandReply(params)
+	}
+	if !validateArity(cmd.arity, params) {
+		c.flagMultiError()
+		return reply.MakeArgNumErrReply(cmd.name)
+	}
+	if !c.authenticated && cmd.flags&flagNoAuth == 0 {
+		c.flagMultiError()
+		return reply.MakeErrReply("NOAUTH Authentication required.")
+	}
+	if c.multi && cmd.flags&flagNoQueue == 0 {
+		return c.queueCommand(cmd, params)
+	}
+	c.db = h.selectDB(c.dbIndex)
+	result := h.call(c, cmd, params)
+
+	// clients blocked on written keys see the result of the whole command
+	for _, index := range c.readyDBs {
+		c.readyKeys = append(c.readyKeys, h.blocking.keysOf(index)...)
+	}
+	c.readyDBs = c.readyDBs[:0]
+	if len(c.readyKeys) > 0 {
+		h.blocking.serve(h, c.readyKeys)
+		c.readyKeys = c.readyKeys[:0]
+	}
+	return result
+}
+
+/**
+ * @description: run a command on c.db, the versions of the keys it writes
+ *	are increased for WATCH unless it reports with keepVersions that it
+ *	changed nothing
+ * @param {*Client} c
+ * @param {*command} cmd
+ * @param {[][]byte} params (including the command name)
+ * @return {*} reply
+ */
+func (h *Handler) call(c *Client, cmd *command, params [][]byte) reply.Reply {
+	if cmd.flags&flagWrite == 0 || !cmd.hasKeys() {
+		return cmd.executor(c, params[1:])
+	}
+	c.beginWrite(c.db, cmd.extractKeys(params))
+	c.unchanged = false
+	result := cmd.executor(c, params[1:])
+	c.endWrite(!reply.IsErrorReply(result) && !c.unchanged)
+	return result
 }
 
 /**
@@ -68,7 +219,15 @@ func (*Handler) Handle(ctx context.Context, conn net.Conn) error {
  * @param {*}
  * @return {*}
  */
-func (*Handler) Close() error {
-	logger.Log.Info("handler closed.")
+func (h *Handler) Close() error {
+	h.closeOnce.Do(func() {
+		close(h.closeChan)
+		// wake up connections waiting for requests
+		h.clients.Range(func(key, value interface{}) bool {
+			_ = key.(*Client).conn.Close()
+			return true
+		})
+		logger.Log.Info("handler closed.")
+	})
 	return nil
 }
@@ -0,0 +1,109 @@
+/*
+ * @Description:
+ * @Autor: HTmonster
+ * @Date: 2026-10-18 10:02:16
+ */
+package server
+
+import (
+	"bufio"
+	"context"
+	"io/ioutil"
+	"net"
+	"testing"
+
+	"github.com/HTmonster/redissgo/internal/reply"
+)
+
+/**
+ * @description: make an authenticated client which is not bound to a connection
+ */
+func newTestClient(h *Handler) *Client {
+	return &Client{
+		handler:       h,
+		writer:        reply.NewWriter(ioutil.Discard),
+		protocol:      reply.RESP2,
+		authenticated: true,
+	}
+}
+
+/**
+ * @description: execute a command and return the reply encoded with the client protocol
+ */
+func execString(h *Handler, c *Client, args ...string) string {
+	params := make([][]byte, len(args))
+	for i, arg := range args {
+		params[i] = []byte(arg)
+	}
+	return string(reply.Encode(h.exec(c, params), c.protocol))
+}
+
+/**
+ * @description: send a raw request to a fresh handler and read one reply line
+ */
+func roundTrip(t *testing.T, req string) string {
+	server, client := net.Pipe()
+	defer client.Close()
+
+	h := NewHandler()
+	defer h.Close()
+	go h.Handle(context.Background(), server)
+
+	if _, err := client.Write([]byte(req)); err != nil {
+		t.Fatal(err)
+	}
+	line, err := bufio.NewReader(client).ReadString('\n')
+	if err != nil {
+		t.Fatal(err)
+	}
+	return line
+}
+
+func TestHandlerDispatch(t *testing.T) {
+	cases := []struct {
+		req  string
+		want string
+	}{
+		{"*1\r\n$4\r\nPING\r\n", "+PONG\r\n"},
+		{"*1\r\n$4\r\npInG\r\n", "+PONG\r\n"},
+		{"*2\r\n$4\r\nECHO\r\n$2\r\nhi\r\n", "$2\r\n"},
+		{"*1\r\n$4\r\nECHO\r\n", "-ERR wrong number of arguments for 'echo' command\r\n"},
+		{"*2\r\n$3\r\nFOO\r\n$3\r\nbar\r\n", "-ERR unknown command 'FOO', with args beginning with: 'bar' \r\n"},
+		{"*2\r\n$3\r\nFOO\r\n$6\r\na\r\n+OK\r\n", "-ERR unknown command 'FOO', with args beginning with: 'a  +OK' \r\n"},
+		{"*3\r\n$5\r\nHELLO\r\n$1\r\n3\r\n$4\r\na\r\nb\r\n", "-ERR Syntax error in HELLO option 'a  b'\r\n"},
+		{"*1\r\n$-1\r\n", "-ERR Protocol error: invalid bulk length\r\n"},
+		{"*99999999999\r\n", "-ERR Protocol error: invalid multibulk length\r\n"},
+	}
+	for _, c := range cases {
+		if got := roundTrip(t, c.req); got != c.want {
+			t.Errorf("request %q: got %q, want %q", c.req, got, c.want)
+		}
+	}
+}
+
+func TestHandlerPipeline(t *testing.T) {
+	server, client := net.Pipe()
+	defer client.Close()
+
+	h := NewHandler()
+	defer h.Close()
+	go h.Handle(context.Background(), server)
+
+	go func() {
+		_, _ = client.Write([]byte("*1\r\n$4\r\nPING\r\n" +
+			"*2\r\n$4\r\nECHO\r\n$1\r\na\r\n" +
+			"*2\r\n$4\r\nPING\r\n$1\r\nb\r\n"))
+	}()
+
+	want := []string{"+PONG\r\n", "$1\r\n", "a\r\n", "$1\r\n", "b\r\n"}
+	reader := bufio.NewReader(client)
+	for _, w := range want {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatal(err)
+		}
+		if line != w {
+			t.Errorf("got %q, want %q", line, w)
+		}
+	}
+}
@@ -0,0 +1,451 @@
+/*
+ * @Description: hash commands
+ * @Autor: HTmonster
+ * @Date: 2026-10-19 01:26:35
+ */
+
+package server
+
+import (
+	"math"
+	"math/rand"
+	"strconv"
+	"strings"
+
+	"github.com/HTmonster/redissgo/datastruct/dict"
+	"github.com/HTmonster/redissgo/datastruct/hash"
+	"github.com/HTmonster/redissgo/internal/config"
+	"github.com/HTmonster/redissgo/internal/reply"
+)
+
+func init() {
+	registerCommand("hset", execHSet, -4, flagWrite|flagFast).keys(1, 1, 1)
+	registerCommand("hmset", execHMSet, -4, flagWrite|flagFast).keys(1, 1, 1)
+	registerCommand("hsetnx", execHSetNX, 4, flagWrite|flagFast).keys(1, 1, 1)
+	registerCommand("hget", execHGet, 3, flagReadonly|flagFast).keys(1, 1, 1)
+	registerCommand("hmget", execHMGet, -3, flagReadonly|flagFast).keys(1, 1, 1)
+	registerCommand("hdel", execHDel, -3, flagWrite|flagFast).keys(1, 1, 1)
+	registerCommand("hexists", execHExists, 3, flagReadonly|flagFast).keys(1, 1, 1)
+	registerCommand("hlen", execHLen, 2, flagReadonly|flagFast).keys(1, 1, 1)
+	registerCommand("hstrlen", execHStrLen, 3, flagReadonly|flagFast).keys(1, 1, 1)
+	registerCommand("hkeys", execHKeys, 2, flagReadonly).keys(1, 1, 1)
+	registerCommand("hvals", execHVals, 2, flagReadonly).keys(1, 1, 1)
+	registerCommand("hgetall", execHGetAll, 2, flagReadonly).keys(1, 1, 1)
+	registerCommand("hincrby", execHIncrBy, 4, flagWrite|flagFast).keys(1, 1, 1)
+	registerCommand("hincrbyfloat", execHIncrByFloat, 4, flagWrite|flagFast).keys(1, 1, 1)
+	registerCommand("hrandfield", execHRandField, -2, flagReadonly).keys(1, 1, 1)
+	registerCommand("hscan", execHScan, -3, flagReadonly).keys(1, 1, 1)
+}
+
+/**
+ * @description: run fn on the hash at key under the segment lock.
+ *	Hashes are modified in place, so even readers must hold the lock.
+ *	The key is removed when the hash becomes empty.
+ * @param {string} key
+ * @param {bool} create create an empty hash if the key does not exist
+ * @param {func} fn gets nil if the key does not exist and create is false
+ * @return {*} reply of fn, or WRONGTYPE
+ */
+func (db *DB) updateHash(key string, create bool, fn func(h *hash.Hash) reply.Reply) reply.Reply {
+	var result reply.Reply
+	db.Update(key, func(entry *dict.Entry) {
+		var h *hash.Hash
+		if entry.Exists {
+			var ok bool
+			if h, ok = entry.Value.(*hash.Hash); !ok {
+				result = reply.MakeWrongTypeErrReply()
+				return
+			}
+		} else if create {
+			h = hash.MakeHash(config.Properties.HashMaxListpackEntries, config.Properties.HashMaxListpackValue)
+		}
+		result = fn(h)
+		if h != nil {
+			entry.Value, entry.Exists = h, h.Len() > 0
+		}
+	})
+	return result
+}
+
+/**
+ * @description: set field value pairs
+ * @return {*} number of new fields, or an error reply
+ */
+func hsetGeneric(c *Client, args [][]byte, name string) (int64, reply.Reply) {
+	if len(args)%2 != 1 {
+		return 0, reply.MakeArgNumErrReply(name)
+	}
+	var added int64
+	errReply := c.db.updateHash(string(args[0]), true, func(h *hash.Hash) reply.Reply {
+		for i := 1; i < len(args); i += 2 {
+			added += int64(h.Set(string(args[i]), args[i+1]))
+		}
+		return nil
+	})
+	return added, errReply
+}
+
+/**
+ * @description: HSET key field value [field value ...]
+ */
+func execHSet(c *Client, args [][]byte) reply.Reply {
+	added, errReply := hsetGeneric(c, args, "hset")
+	if errReply != nil {
+		return errReply
+	}
+	return reply.MakeIntReply(added)
+}
+
+/**
+ * @description: HMSET key field value [field value ...]
+ */
+func execHMSet(c *Client, args [][]byte) reply.Reply {
+	if _, errReply := hsetGeneric(c, args, "hmset"); errReply != nil {
+		return errReply
+	}
+	return reply.MakeOkReply()
+}
+
+/**
+ * @description: HSETNX key field value
+ */
+func execHSetNX(c *Client, args [][]byte) reply.Reply {
+	field := string(args[1])
+	return c.db.updateHash(string(args[0]), true, func(h *hash.Hash) reply.Reply {
+		if _, ok := h.Get(field); ok {
+			return c.changesReply(0)
+		}
+		h.Set(field, args[2])
+		return reply.MakeIntReply(1)
+	})
+}
+
+/**
+ * @description: HGET key field
+ */
+func execHGet(c *Client, args [][]byte) reply.Reply {
+	return c.db.updateHash(string(args[0]), false, func(h *hash.Hash) reply.Reply {
+		if h == nil {
+			return reply.MakeNullBulkReply()
+		}
+		return bulkOrNull(h.Get(string(args[1])))
+	})
+}
+
+/**
+ * @description: HMGET key field [field ...]
+ */
+func execHMGet(c *Client, args [][]byte) reply.Reply {
+	return c.db.updateHash(string(args[0]), false, func(h *hash.Hash) reply.Reply {
+		values := make([][]byte, len(args)-1)
+		if h == nil {
+			return reply.MakeMultiBulkReply(values)
+		}
+		for i, field := range args[1:] {
+			values[i], _ = h.Get(string(field))
+		}
+		return reply.MakeMultiBulkReply(values)
+	})
+}
+
+/**
+ * @description: HDEL key field [field ...]
+ */
+func execHDel(c *Client, args [][]byte) reply.Reply {
+	return c.db.updateHash(string(args[0]), false, func(h *hash.Hash) reply.Reply {
+		if h == nil {
+			return c.changesReply(0)
+		}
+		var deleted int64
+		for _, field := range args[1:] {
+			deleted += int64(h.Remove(string(field)))
+		}
+		return c.changesReply(deleted)
+	})
+}
+
+/**
+ * @description: HEXISTS key field
+ */
+func execHExists(c *Client, args [][]byte) reply.Reply {
+	return c.db.updateHash(string(args[0]), false, func(h *hash.Hash) reply.Reply {
+		if h == nil {
+			return reply.MakeIntReply(0)
+		}
+		if _, ok := h.Get(string(args[1])); ok {
+			return reply.MakeIntReply(1)
+		}
+		return reply.MakeIntReply(0)
+	})
+}
+
+/**
+ * @description: HLEN key
+ */
+func execHLen(c *Client, args [][]byte) reply.Reply {
+	return c.db.updateHash(string(args[0]), false, func(h *hash.Hash) reply.Reply {
+		if h == nil {
+			return reply.MakeIntReply(0)
+		}
+		return reply.MakeIntReply(int64(h.Len()))
+	})
+}
+
+/**
+ * @description: HSTRLEN key field
+ */
+func execHStrLen(c *Client, args [][]byte) reply.Reply {
+	return c.db.updateHash(string(args[0]), false, func(h *hash.Hash) reply.Reply {
+		if h == nil {
+			return reply.MakeIntReply(0)
+		}
+		value, _ := h.Get(string(args[1]))
+		return reply.MakeIntReply(int64(len(value)))
+	})
+}
+
+/**
+ * @description: collect fields and/or values of the whole hash
+ */
+func hashGetAllGeneric(c *Client, key string, fields, values bool) reply.Reply {
+	return c.db.updateHash(key, false, func(h *hash.Hash) reply.Reply {
+		if h == nil {
+			return reply.MakeEmptyArrayReply()
+		}
+		result := make([][]byte, 0, h.Len()*2)
+		h.ForEach(func(field string, value []byte) bool {
+			if fields {
+				result = append(result, []byte(field))
+			}
+			if values {
+				result = append(result, value)
+			}
+			return true
+		})
+		return reply.MakeMultiBulkReply(result)
+	})
+}
+
+/**
+ * @description: HKEYS key
+ */
+func execHKeys(c *Client, args [][]byte) reply.Reply {
+	return hashGetAllGeneric(c, string(args[0]), true, false)
+}
+
+/**
+ * @description: HVALS key
+ */
+func execHVals(c *Client, args [][]byte) reply.Reply {
+	return hashGetAllGeneric(c, string(args[0]), false, true)
+}
+
+/**
+ * @description: HGETALL key, a map in RESP3
+ */
+func execHGetAll(c *Client, args [][]byte) reply.Reply {
+	return c.db.updateHash(string(args[0]), false, func(h *hash.Hash) reply.Reply {
+		if h == nil {
+			return reply.MakeMapReply(nil)
+		}
+		pairs := make([]reply.Reply, 0, h.Len()*2)
+		h.ForEach(func(field string, value []byte) bool {
+			pairs = append(pairs, reply.MakeBulkReply([]byte(field)), reply.MakeBulkReply(value))
+			return true
+		})
+		return reply.MakeMapReply(pairs)
+	})
+}
+
+/**
+ * @description: HINCRBY key field increment
+ */
+func execHIncrBy(c *Client, args [][]byte) reply.Reply {
+	incr, ok := parseCanonicalInt(args[2])
+	if !ok {
+		return reply.MakeNotIntegerErrReply()
+	}
+	field := string(args[1])
+	return c.db.updateHash(string(args[0]), true, func(h *hash.Hash) reply.Reply {
+		var value int64
+		if bytes, ok := h.Get(field); ok {
+			if value, ok = parseCanonicalInt(bytes); !ok {
+				return reply.MakeErrReply("ERR hash value is not an integer")
+			}
+		}
+		if (incr < 0 && value < 0 && incr < math.MinInt64-value) ||
+			(incr > 0 && value > 0 && incr > math.MaxInt64-value) {
+			return reply.MakeErrReply("ERR increment or decrement would overflow")
+		}
+		value += incr
+		h.Set(field, strconv.AppendInt(nil, value, 10))
+		return reply.MakeIntReply(value)
+	})
+}
+
+/**
+ * @description: HINCRBYFLOAT key field increment
+ */
+func execHIncrByFloat(c *Client, args [][]byte) reply.Reply {
+	incr, ok := parseFloat(args[2])
+	if !ok {
+		return reply.MakeErrReply("ERR value is not a valid float")
+	}
+	field := string(args[1])
+	return c.db.updateHash(string(args[0]), true, func(h *hash.Hash) reply.Reply {
+		var value float64
+		if bytes, ok := h.Get(field); ok {
+			if value, ok = parseFloat(bytes); !ok {
+				return reply.MakeErrReply("ERR hash value is not a float")
+			}
+		}
+		value += incr
+		if math.IsNaN(value) || math.IsInf(value, 0) {
+			return reply.MakeErrReply("ERR increment would produce NaN or Infinity")
+		}
+		bytes := strconv.AppendFloat(nil, value, 'f', -1, 64)
+		h.Set(field, bytes)
+		return reply.MakeBulkReply(bytes)
+	})
+}
+
+/**
+ * @description: pick count fields of the hash, with repeats if allowRepeats,
+ *	else at most Len() distinct fields
+ * @return {*} fields, values
+ */
+func hashRandomFields(h *hash.Hash, count int, allowRepeats bool) ([]string, [][]byte) {
+	if allowRepeats {
+		fields, values := make([]string, count), make([][]byte, count)
+		for i := range fields {
+			fields[i], values[i], _ = h.RandomEntry()
+		}
+		return fields, values
+	}
+
+	if count*3 > h.Len() {
+		// the sample is big, shuffle all fields and keep the head
+		fields, values := make([]string, 0, h.Len()), make([][]byte, 0, h.Len())
+		h.ForEach(func(field string, value []byte) bool {
+			fields = append(fields, field)
+			values = append(values, value)
+			return true
+		})
+		if count > len(fields) {
+			count = len(fields)
+		}
+		for i := 0; i < count; i++ {
+			j := i + rand.Intn(len(fields)-i)
+			fields[i], fields[j] = fields[j], fields[i]
+			values[i], values[j] = values[j], values[i]
+		}
+		return fields[:count], values[:count]
+	}
+
+	// the sample is small, pick random fields until enough distinct ones
+	seen := make(map[string]struct{}, count)
+	fields, values := make([]string, 0, count), make([][]byte, 0, count)
+	for len(fields) < count {
+		field, value, _ := h.RandomEntry()
+		if _, ok := seen[field]; ok {
+			continue
+		}
+		seen[field] = struct{}{}
+		fields = append(fields, field)
+		values = append(values, value)
+	}
+	return fields, values
+}
+
+/**
+ * @description: HRANDFIELD key [count [WITHVALUES]]
+ */
+func execHRandField(c *Client, args [][]byte) reply.Reply {
+	key := string(args[0])
+	if len(args) == 1 {
+		return c.db.updateHash(key, false, func(h *hash.Hash) reply.Reply {
+			if h == nil {
+				return reply.MakeNullBulkReply()
+			}
+			field, _, _ := h.RandomEntry()
+			return reply.MakeBulkReply([]byte(field))
+		})
+	}
+
+	if len(args) > 3 || (len(args) == 3 && strings.ToLower(string(args[2])) != "withvalues") {
+		return reply.MakeSyntaxErrReply()
+	}
+	withValues := len(args) == 3
+	count, ok := parseCanonicalInt(args[1])
+	if !ok {
+		return reply.MakeNotIntegerErrReply()
+	}
+	if count == math.MinInt64 {
+		return reply.MakeErrReply("ERR value is out of range, must be between -9223372036854775807 and 9223372036854775807")
+	}
+	// the reply length must not overflow
+	if withValues && count < -math.MaxInt64/2 {
+		return reply.MakeErrReply("ERR value is out of range")
+	}
+
+	return c.db.updateHash(key, false, func(h *hash.Hash) reply.Reply {
+		if h == nil || count == 0 {
+			return reply.MakeEmptyArrayReply()
+		}
+		var fields []string
+		var values [][]byte
+		if count < 0 {
+			fields, values = hashRandomFields(h, int(-count), true)
+		} else {
+			if count > int64(h.Len()) {
+				count = int64(h.Len())
+			}
+			fields, values = hashRandomFields(h, int(count), false)
+		}
+
+		if !withValues {
+			result := make([][]byte, len(fields))
+			for i, field := range fields {
+				result[i] = []byte(field)
+			}
+			return reply.MakeMultiBulkReply(result)
+		}
+		// RESP3 replies an array of pairs, RESP2 a flat array
+		result := make([]reply.Reply, 0, len(fields)*2)
+		for i, field := range fields {
+			pair := []reply.Reply{reply.MakeBulkReply([]byte(field)), reply.MakeBulkReply(values[i])}
+			if c.protocol >= reply.RESP3 {
+				result = append(result, reply.MakeArrayReply(pair))
+			} else {
+				result = append(result, pair...)
+			}
+		}
+		return reply.MakeArrayReply(result)
+	})
+}
+
+/**
+ * @description: HSCAN key cursor [MATCH pattern] [COUNT count] [NOVALUES]
+ */
+func execHScan(c *Client, args [][]byte) reply.Reply {
+	opts, errReply := parseScanOptions(args[1:], scanAllowNoValues)
+	if errReply != nil {
+		return errReply
+	}
+	return c.db.updateHash(string(args[0]), false, func(h *hash.Hash) reply.Reply {
+		if h == nil {
+			return makeScanReply(0, nil)
+		}
+		var elements [][]byte
+		cursor := h.Scan(opts.cursor, opts.count, func(field string, value []byte) {
+			if !opts.match(field) {
+				return
+			}
+			elements = append(elements, []byte(field))
+			if !opts.noValues {
+				elements = append(elements, value)
+			}
+		})
+		return makeScanReply(cursor, elements)
+	})
+}
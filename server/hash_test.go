@@ -0,0 +1,209 @@
+/*
+ * @Description:
+ * @Autor: HTmonster
+ * @Date: 2026-10-19 02:03:51
+ */
+package server
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/HTmonster/redissgo/datastruct/hash"
+	"github.com/HTmonster/redissgo/internal/config"
+	"github.com/HTmonster/redissgo/internal/reply"
+)
+
+func TestHashCommands(t *testing.T) {
+	h := NewHandler()
+	defer h.Close()
+	c := newTestClient(h)
+	c.db = h.selectDB(0)
+
+	runCases(t, h, c, []cmdCase{
+		{[]string{"HSET", "h", "a", "1", "b", "2"}, ":2\r\n"},
+		{[]string{"HSET", "h", "a", "3", "c", "4"}, ":1\r\n"},
+		{[]string{"HSET", "h", "a"}, "-ERR wrong number of arguments for 'hset' command\r\n"},
+		{[]string{"HSET", "h", "a", "1", "b"}, "-ERR wrong number of arguments for 'hset' command\r\n"},
+		{[]string{"HMSET", "h", "d", "5"}, "+OK\r\n"},
+		{[]string{"HGET", "h", "a"}, "$1\r\n3\r\n"},
+		{[]string{"HGET", "h", "x"}, "$-1\r\n"},
+		{[]string{"HGET", "none", "a"}, "$-1\r\n"},
+		{[]string{"HMGET", "h", "a", "x", "b"}, "*3\r\n$1\r\n3\r\n$-1\r\n$1\r\n2\r\n"},
+		{[]string{"HMGET", "none", "a"}, "*1\r\n$-1\r\n"},
+		{[]string{"HLEN", "h"}, ":4\r\n"},
+		{[]string{"HEXISTS", "h", "a"}, ":1\r\n"},
+		{[]string{"HEXISTS", "h", "x"}, ":0\r\n"},
+		{[]string{"HSTRLEN", "h", "a"}, ":1\r\n"},
+		{[]string{"HSTRLEN", "h", "x"}, ":0\r\n"},
+		{[]string{"HSETNX", "h", "a", "9"}, ":0\r\n"},
+		{[]string{"HSETNX", "h", "e", "9"}, ":1\r\n"},
+		{[]string{"HDEL", "h", "d", "e", "x"}, ":2\r\n"},
+		{[]string{"HKEYS", "h"}, "*3\r\n$1\r\na\r\n$1\r\nb\r\n$1\r\nc\r\n"},
+		{[]string{"HVALS", "h"}, "*3\r\n$1\r\n3\r\n$1\r\n2\r\n$1\r\n4\r\n"},
+		{[]string{"HGETALL", "h"}, "*6\r\n$1\r\na\r\n$1\r\n3\r\n$1\r\nb\r\n$1\r\n2\r\n$1\r\nc\r\n$1\r\n4\r\n"},
+		{[]string{"HGETALL", "none"}, "*0\r\n"},
+		{[]string{"HDEL", "h", "a", "b", "c"}, ":3\r\n"},
+		{[]string{"HLEN", "h"}, ":0\r\n"},
+		{[]string{"LLEN", "h"}, ":0\r\n"},
+		{[]string{"RPUSH", "list", "a"}, ":1\r\n"},
+		{[]string{"HSET", "list", "a", "1"}, wrongType},
+		{[]string{"HGET", "list", "a"}, wrongType},
+		{[]string{"HGETALL", "list"}, wrongType},
+		{[]string{"HSET", "h", "a", "1"}, ":1\r\n"},
+		{[]string{"LLEN", "h"}, wrongType},
+		{[]string{"GET", "h"}, wrongType},
+	})
+
+	// RESP3 map
+	c.SetProtocol(reply.RESP3)
+	runCases(t, h, c, []cmdCase{
+		{[]string{"HGETALL", "h"}, "%1\r\n$1\r\na\r\n$1\r\n1\r\n"},
+	})
+}
+
+func TestHashIncr(t *testing.T) {
+	h := NewHandler()
+	defer h.Close()
+	c := newTestClient(h)
+	c.db = h.selectDB(0)
+
+	runCases(t, h, c, []cmdCase{
+		{[]string{"HINCRBY", "h", "n", "5"}, ":5\r\n"},
+		{[]string{"HINCRBY", "h", "n", "-7"}, ":-2\r\n"},
+		{[]string{"HINCRBY", "h", "n", "x"}, "-ERR value is not an integer or out of range\r\n"},
+		{[]string{"HSET", "h", "s", "abc", "big", "9223372036854775807"}, ":2\r\n"},
+		{[]string{"HINCRBY", "h", "s", "1"}, "-ERR hash value is not an integer\r\n"},
+		{[]string{"HINCRBY", "h", "big", "1"}, "-ERR increment or decrement would overflow\r\n"},
+		{[]string{"HINCRBYFLOAT", "h", "f", "10.5"}, "$4\r\n10.5\r\n"},
+		{[]string{"HINCRBYFLOAT", "h", "f", "0.1"}, "$4\r\n10.6\r\n"},
+		{[]string{"HINCRBYFLOAT", "h", "n", "1.5"}, "$4\r\n-0.5\r\n"},
+		{[]string{"HINCRBYFLOAT", "h", "f", "x"}, "-ERR value is not a valid float\r\n"},
+		{[]string{"HINCRBYFLOAT", "h", "s", "1"}, "-ERR hash value is not a float\r\n"},
+		{[]string{"HINCRBYFLOAT", "h", "f", "1e308"}, "$309\r\n1" + strings.Repeat("0", 308) + "\r\n"},
+		{[]string{"HGET", "h", "f"}, "$309\r\n1" + strings.Repeat("0", 308) + "\r\n"},
+		{[]string{"HINCRBYFLOAT", "h", "f", "1e308"}, "-ERR increment would produce NaN or Infinity\r\n"},
+		{[]string{"HINCRBY", "none", "n", "x"}, "-ERR value is not an integer or out of range\r\n"},
+		{[]string{"HLEN", "none"}, ":0\r\n"},
+	})
+}
+
+func TestHashEncoding(t *testing.T) {
+	entries, value := config.Properties.HashMaxListpackEntries, config.Properties.HashMaxListpackValue
+	defer func() {
+		config.Properties.HashMaxListpackEntries, config.Properties.HashMaxListpackValue = entries, value
+	}()
+	config.Properties.HashMaxListpackEntries, config.Properties.HashMaxListpackValue = 4, 8
+
+	h := NewHandler()
+	defer h.Close()
+	c := newTestClient(h)
+	c.db = h.selectDB(0)
+
+	encoding := func(key string) string {
+		entity, _ := c.db.GetEntity(key)
+		return entity.(*hash.Hash).Encoding()
+	}
+	execString(h, c, "HSET", "small", "a", "1", "b", "2", "c", "3", "d", "4")
+	if encoding("small") != hash.EncodingListpack {
+		t.Errorf("test small hash encoding: %s", encoding("small"))
+	}
+	execString(h, c, "HSET", "small", "e", "5")
+	if encoding("small") != hash.EncodingHashtable {
+		t.Errorf("test converted by entries: %s", encoding("small"))
+	}
+	execString(h, c, "HSET", "long", "a", "123456789")
+	if encoding("long") != hash.EncodingHashtable {
+		t.Errorf("test converted by value: %s", encoding("long"))
+	}
+	if got := execString(h, c, "HGET", "small", "c"); got != "$1\r\n3\r\n" {
+		t.Errorf("test get after convert: %q", got)
+	}
+}
+
+func TestHashRandField(t *testing.T) {
+	h := NewHandler()
+	defer h.Close()
+	c := newTestClient(h)
+	c.db = h.selectDB(0)
+
+	runCases(t, h, c, []cmdCase{
+		{[]string{"HRANDFIELD", "none"}, "$-1\r\n"},
+		{[]string{"HRANDFIELD", "none", "3"}, "*0\r\n"},
+		{[]string{"HSET", "h", "a", "1"}, ":1\r\n"},
+		{[]string{"HRANDFIELD", "h"}, "$1\r\na\r\n"},
+		{[]string{"HRANDFIELD", "h", "0"}, "*0\r\n"},
+		{[]string{"HRANDFIELD", "h", "5", "WITHVALUES"}, "*2\r\n$1\r\na\r\n$1\r\n1\r\n"},
+		{[]string{"HRANDFIELD", "h", "-2"}, "*2\r\n$1\r\na\r\n$1\r\na\r\n"},
+		{[]string{"HRANDFIELD", "h", "1", "VALUES"}, "-ERR syntax error\r\n"},
+		{[]string{"HRANDFIELD", "h", "x"}, "-ERR value is not an integer or out of range\r\n"},
+		{[]string{"HRANDFIELD", "h", "-9223372036854775808"}, "-ERR value is out of range, must be between -9223372036854775807 and 9223372036854775807\r\n"},
+		{[]string{"HRANDFIELD", "h", "-9223372036854770000", "WITHVALUES"}, "-ERR value is out of range\r\n"},
+	})
+	c.SetProtocol(reply.RESP3)
+	runCases(t, h, c, []cmdCase{
+		{[]string{"HRANDFIELD", "h", "-1", "WITHVALUES"}, "*1\r\n*2\r\n$1\r\na\r\n$1\r\n1\r\n"},
+	})
+	c.SetProtocol(reply.RESP2)
+
+	// distinct fields for small and big samples, over both encodings
+	for _, n := range []int{10, 1000} {
+		key := "h" + strconv.Itoa(n)
+		for i := 0; i < n; i++ {
+			execString(h, c, "HSET", key, strconv.Itoa(i), "v")
+		}
+		for _, count := range []int{3, n / 2, n} {
+			r := h.exec(c, [][]byte{[]byte("HRANDFIELD"), []byte(key), []byte(strconv.Itoa(count))})
+			fields := r.(*reply.MultiBulkReply).Args
+			seen := make(map[string]bool)
+			for _, field := range fields {
+				seen[string(field)] = true
+			}
+			if len(fields) != count || len(seen) != count {
+				t.Errorf("test hrandfield %d of %d: got %d, %d distinct", count, n, len(fields), len(seen))
+			}
+		}
+	}
+}
+
+func TestHashScan(t *testing.T) {
+	h := NewHandler()
+	defer h.Close()
+	c := newTestClient(h)
+	c.db = h.selectDB(0)
+
+	runCases(t, h, c, []cmdCase{
+		{[]string{"HSCAN", "none", "0"}, "*2\r\n$1\r\n0\r\n*0\r\n"},
+		{[]string{"HSET", "small", "a", "1", "b", "2", "ab", "3"}, ":3\r\n"},
+		{[]string{"HSCAN", "small", "0"}, "*2\r\n$1\r\n0\r\n*6\r\n$1\r\na\r\n$1\r\n1\r\n$1\r\nb\r\n$1\r\n2\r\n$2\r\nab\r\n$1\r\n3\r\n"},
+		{[]string{"HSCAN", "small", "0", "MATCH", "a*", "NOVALUES"}, "*2\r\n$1\r\n0\r\n*2\r\n$1\r\na\r\n$2\r\nab\r\n"},
+		{[]string{"HSCAN", "small", "x"}, "-ERR invalid cursor\r\n"},
+		{[]string{"HSCAN", "small", "0", "COUNT", "0"}, "-ERR syntax error\r\n"},
+		{[]string{"HSCAN", "small", "0", "COUNT", "x"}, "-ERR value is not an integer or out of range\r\n"},
+		{[]string{"HSCAN", "small", "0", "MATCH"}, "-ERR syntax error\r\n"},
+	})
+
+	// a big hash is returned in many calls
+	for i := 0; i < 1000; i++ {
+		execString(h, c, "HSET", "big", "f"+strconv.Itoa(i), "v")
+	}
+	seen := make(map[string]bool)
+	cursor, calls := "0", 0
+	for {
+		r := h.exec(c, [][]byte{[]byte("HSCAN"), []byte("big"), []byte(cursor), []byte("COUNT"), []byte("50")})
+		replies := r.(*reply.ArrayReply).Replies
+		cursor = string(replies[0].(*reply.BulkReply).Arg)
+		elements := replies[1].(*reply.MultiBulkReply).Args
+		for i := 0; i < len(elements); i += 2 {
+			seen[string(elements[i])] = true
+		}
+		calls++
+		if cursor == "0" {
+			break
+		}
+	}
+	if len(seen) != 1000 || calls < 2 {
+		t.Errorf("test hscan big hash: %d fields in %d calls", len(seen), calls)
+	}
+}
@@ -0,0 +1,192 @@
+/*
+ * @Description: HyperLogLog commands over string values
+ * @Autor: HTmonster
+ * @Date: 2026-10-19 17:12:45
+ */
+
+package server
+
+import (
+	"github.com/HTmonster/redissgo/datastruct/dict"
+	"github.com/HTmonster/redissgo/datastruct/hyperloglog"
+	"github.com/HTmonster/redissgo/internal/config"
+	"github.com/HTmonster/redissgo/internal/reply"
+)
+
+func init() {
+	registerCommand("pfadd", execPFAdd, -2, flagWrite|flagFast).keys(1, 1, 1)
+	registerCommand("pfcount", execPFCount, -2, flagReadonly).keys(1, -1, 1)
+	registerCommand("pfmerge", execPFMerge, -2, flagWrite).keys(1, -1, 1)
+}
+
+// HLLs are strings, they are only read under the locks of their keys, see readString
+
+/**
+ * @description: get the HLL of a value
+ * @param {interface{}} entity
+ * @return {*} HLL, WRONGTYPE if entity is not a string or not an HLL
+ */
+func asHLL(entity interface{}) ([]byte, reply.ErrorReply) {
+	switch value := entity.(type) {
+	case []byte:
+		if hyperloglog.IsValid(value) {
+			return value, nil
+		}
+	case int64:
+	default:
+		return nil, reply.MakeWrongTypeErrReply()
+	}
+	return nil, reply.MakeErrReply("WRONGTYPE Key is not a valid HyperLogLog string value.")
+}
+
+/**
+ * @description: error of HLLs with invalid registers
+ */
+func makeCorruptedHLLErrReply() reply.ErrorReply {
+	return reply.MakeErrReply("INVALIDOBJ Corrupted HLL object detected")
+}
+
+/**
+ * @description: PFADD key [element [element ...]]
+ */
+func execPFAdd(c *Client, args [][]byte) reply.Reply {
+	var result reply.Reply
+	c.db.Update(string(args[0]), func(entry *dict.Entry) {
+		hll := hyperloglog.New()
+		if entry.Exists {
+			var errReply reply.ErrorReply
+			if hll, errReply = asHLL(entry.Value); errReply != nil {
+				result = errReply
+				return
+			}
+		}
+		updated, changed, err := hyperloglog.Add(hll, args[1:], config.Properties.HllSparseMaxBytes)
+		if err != nil {
+			result = makeCorruptedHLLErrReply()
+			return
+		}
+		if !changed && entry.Exists {
+			result = c.changesReply(0)
+			return
+		}
+		entry.Value, entry.Exists = updated, true
+		result = reply.MakeIntReply(1)
+	})
+	return result
+}
+
+/**
+ * @description: PFCOUNT key [key ...]
+ */
+func execPFCount(c *Client, args [][]byte) reply.Reply {
+	if len(args) == 1 {
+		return c.db.pfcountCached(string(args[0]))
+	}
+
+	// union of all keys, not cached
+	keys := make([]string, len(args))
+	for i, arg := range args {
+		keys[i] = string(arg)
+	}
+	c.db.Locks(nil, keys)
+	defer c.db.Unlocks(nil, keys)
+	raw := make([]uint8, hyperloglog.Registers)
+	for _, key := range keys {
+		entity, ok := c.db.GetEntityWithLock(key)
+		if !ok {
+			continue
+		}
+		hll, errReply := asHLL(entity)
+		if errReply != nil {
+			return errReply
+		}
+		if err := hyperloglog.Merge(raw, hll); err != nil {
+			return makeCorruptedHLLErrReply()
+		}
+	}
+	return reply.MakeIntReply(int64(hyperloglog.Count(raw)))
+}
+
+/**
+ * @description: count a single HLL, the cardinality is cached in its header
+ */
+func (db *DB) pfcountCached(key string) reply.Reply {
+	var result reply.Reply
+	db.Update(key, func(entry *dict.Entry) {
+		if !entry.Exists {
+			result = reply.MakeIntReply(0)
+			return
+		}
+		hll, errReply := asHLL(entry.Value)
+		if errReply != nil {
+			result = errReply
+			return
+		}
+		if count, ok := hyperloglog.CachedCount(hll); ok {
+			result = reply.MakeIntReply(int64(count))
+			return
+		}
+		raw := make([]uint8, hyperloglog.Registers)
+		if err := hyperloglog.Merge(raw, hll); err != nil {
+			result = makeCorruptedHLLErrReply()
+			return
+		}
+		count := hyperloglog.Count(raw)
+		cached := copyBytes(hll)
+		hyperloglog.SetCachedCount(cached, count)
+		entry.Value = cached
+		result = reply.MakeIntReply(int64(count))
+	})
+	return result
+}
+
+/**
+ * @description: PFMERGE destkey [sourcekey [sourcekey ...]]
+ */
+func execPFMerge(c *Client, args [][]byte) reply.Reply {
+	dest := string(args[0])
+	sources := make([]string, len(args)-1)
+	for i, arg := range args[1:] {
+		sources[i] = string(arg)
+	}
+	// no PFADD between reading the sources and writing the destination
+	c.db.Locks([]string{dest}, sources)
+	defer c.db.Unlocks([]string{dest}, sources)
+
+	raw := make([]uint8, hyperloglog.Registers)
+	dense := false
+	for _, key := range sources {
+		entity, ok := c.db.GetEntityWithLock(key)
+		if !ok {
+			continue
+		}
+		hll, errReply := asHLL(entity)
+		if errReply != nil {
+			return errReply
+		}
+		if err := hyperloglog.Merge(raw, hll); err != nil {
+			return makeCorruptedHLLErrReply()
+		}
+		dense = dense || hyperloglog.IsDense(hll)
+	}
+
+	// the destination is merged too, and keeps its TTL
+	var result reply.Reply = reply.MakeOkReply()
+	c.db.UpdateWithLock(dest, func(entry *dict.Entry) {
+		if entry.Exists {
+			hll, errReply := asHLL(entry.Value)
+			if errReply != nil {
+				result = errReply
+				return
+			}
+			if err := hyperloglog.Merge(raw, hll); err != nil {
+				result = makeCorruptedHLLErrReply()
+				return
+			}
+			dense = dense || hyperloglog.IsDense(hll)
+		}
+		entry.Value = hyperloglog.Encode(raw, dense, config.Properties.HllSparseMaxBytes)
+		entry.Exists = true
+	})
+	return result
+}
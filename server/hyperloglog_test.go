@@ -0,0 +1,153 @@
+/*
+ * @Description:
+ * @Autor: HTmonster
+ * @Date: 2026-10-19 17:40:06
+ */
+package server
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestPFAddCount(t *testing.T) {
+	h := NewHandler()
+	defer h.Close()
+	c := newTestClient(h)
+
+	runCases(t, h, c, []cmdCase{
+		{[]string{"PFCOUNT", "hll"}, ":0\r\n"},
+		{[]string{"PFADD", "hll", "a", "b", "c"}, ":1\r\n"},
+		{[]string{"PFADD", "hll", "a", "b"}, ":0\r\n"},
+		{[]string{"PFADD", "hll"}, ":0\r\n"},
+		{[]string{"PFADD", "empty"}, ":1\r\n"},
+		{[]string{"PFCOUNT", "empty"}, ":0\r\n"},
+		{[]string{"PFCOUNT", "hll"}, ":3\r\n"},
+		{[]string{"PFCOUNT", "hll"}, ":3\r\n"},
+		{[]string{"PFADD", "other", "c", "d"}, ":1\r\n"},
+		{[]string{"PFCOUNT", "hll", "other", "none"}, ":4\r\n"},
+	})
+
+	// the cardinality is cached once counted
+	got := execString(h, c, "GET", "hll")
+	if value := got[strings.Index(got, "\r\n")+2:]; value[15]&0x80 != 0 {
+		t.Error("PFCOUNT should cache the cardinality")
+	}
+
+	for i := 0; i < 10000; i++ {
+		execString(h, c, "PFADD", "big", strconv.Itoa(i))
+	}
+	count, _ := strconv.Atoi(strings.TrimSpace(execString(h, c, "PFCOUNT", "big")[1:]))
+	if count < 9800 || count > 10200 {
+		t.Errorf("count of 10000 elements: %d", count)
+	}
+}
+
+func TestPFMerge(t *testing.T) {
+	h := NewHandler()
+	defer h.Close()
+	c := newTestClient(h)
+
+	runCases(t, h, c, []cmdCase{
+		{[]string{"PFADD", "h1", "a", "b", "c"}, ":1\r\n"},
+		{[]string{"PFADD", "h2", "c", "d", "e"}, ":1\r\n"},
+		{[]string{"PFADD", "dst", "f"}, ":1\r\n"},
+		{[]string{"PFMERGE", "dst", "h1", "h2", "none"}, "+OK\r\n"},
+		{[]string{"PFCOUNT", "dst"}, ":6\r\n"},
+		{[]string{"PFMERGE", "new"}, "+OK\r\n"},
+		{[]string{"PFCOUNT", "new"}, ":0\r\n"},
+		{[]string{"PFMERGE", "h1", "h2"}, "+OK\r\n"},
+		{[]string{"PFCOUNT", "h1"}, ":5\r\n"},
+	})
+}
+
+func TestPFMergeConcurrent(t *testing.T) {
+	h := NewHandler()
+	defer h.Close()
+
+	// PFADD runs while the sources are merged and counted together
+	var wg sync.WaitGroup
+	for w := 0; w < 4; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			c := newTestClient(h)
+			for i := 0; i < 300; i++ {
+				switch w {
+				case 0, 1:
+					execString(h, c, "PFADD", "h"+strconv.Itoa(w), strconv.Itoa(w*1000+i))
+				case 2:
+					execString(h, c, "PFMERGE", "dst", "h0", "h1")
+				default:
+					execString(h, c, "PFCOUNT", "h0", "h1", "dst")
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	c := newTestClient(h)
+	execString(h, c, "PFMERGE", "dst", "h0", "h1")
+	if got, want := execString(h, c, "PFCOUNT", "dst"), execString(h, c, "PFCOUNT", "h0", "h1"); got != want {
+		t.Errorf("merged count: got %q, want %q", got, want)
+	}
+}
+
+func TestHLLExchange(t *testing.T) {
+	h := NewHandler()
+	defer h.Close()
+	c := newTestClient(h)
+
+	// sparse HLL as written by Redis: registers 0-3 are 2, the others 0
+	sparse := "HYLL\x01\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x80" + "\x87\x7f\xfb"
+	// registers cover only 16383 registers
+	corrupted := "HYLL\x01\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x80" + "\x87\x7f\xfa"
+
+	runCases(t, h, c, []cmdCase{
+		{[]string{"SET", "sparse", sparse}, "+OK\r\n"},
+		{[]string{"PFCOUNT", "sparse"}, ":4\r\n"},
+		{[]string{"PFADD", "sparse", "a"}, ":1\r\n"},
+		{[]string{"PFCOUNT", "sparse"}, ":5\r\n"},
+		{[]string{"SET", "bad", corrupted}, "+OK\r\n"},
+		{[]string{"PFCOUNT", "bad"}, "-INVALIDOBJ Corrupted HLL object detected\r\n"},
+		{[]string{"PFADD", "bad", "a"}, "-INVALIDOBJ Corrupted HLL object detected\r\n"},
+		{[]string{"PFMERGE", "dst", "bad"}, "-INVALIDOBJ Corrupted HLL object detected\r\n"},
+	})
+
+	// dense HLLs survive GET and SET
+	for i := 0; i < 3000; i++ {
+		execString(h, c, "PFADD", "dense", strconv.Itoa(i))
+	}
+	value := execString(h, c, "GET", "dense")
+	value = value[len("$12304\r\n") : len(value)-2]
+	if len(value) != 12304 || value[4] != 0 {
+		t.Fatalf("HLL of 3000 elements should be dense, got %d bytes", len(value))
+	}
+	execString(h, c, "SET", "copy", value)
+	if got, want := execString(h, c, "PFCOUNT", "copy"), execString(h, c, "PFCOUNT", "dense"); got != want {
+		t.Errorf("exchanged dense HLL: got %q, want %q", got, want)
+	}
+}
+
+func TestHLLTypeCheck(t *testing.T) {
+	h := NewHandler()
+	defer h.Close()
+	c := newTestClient(h)
+	notHLL := "-WRONGTYPE Key is not a valid HyperLogLog string value.\r\n"
+
+	runCases(t, h, c, []cmdCase{
+		{[]string{"SET", "str", "abc"}, "+OK\r\n"},
+		{[]string{"SET", "int", "100"}, "+OK\r\n"},
+		{[]string{"SET", "dense", "HYLL\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00"}, "+OK\r\n"},
+		{[]string{"RPUSH", "list", "a"}, ":1\r\n"},
+		{[]string{"PFADD", "str", "a"}, notHLL},
+		{[]string{"PFADD", "int", "a"}, notHLL},
+		{[]string{"PFCOUNT", "dense"}, notHLL},
+		{[]string{"PFADD", "list", "a"}, wrongType},
+		{[]string{"PFCOUNT", "none", "str"}, notHLL},
+		{[]string{"PFMERGE", "str", "none"}, notHLL},
+		{[]string{"PFMERGE", "dst", "list"}, wrongType},
+	})
+}
@@ -0,0 +1,294 @@
+/*
+ * @Description: generic keyspace commands
+ * @Autor: HTmonster
+ * @Date: 2026-10-19 22:41:17
+ */
+
+package server
+
+import (
+	"strings"
+
+	"github.com/HTmonster/redissgo/datastruct/dict"
+	"github.com/HTmonster/redissgo/datastruct/hash"
+	"github.com/HTmonster/redissgo/datastruct/list"
+	"github.com/HTmonster/redissgo/datastruct/set"
+	"github.com/HTmonster/redissgo/datastruct/sortedset"
+	"github.com/HTmonster/redissgo/datastruct/stream"
+	"github.com/HTmonster/redissgo/internal/reply"
+	"github.com/HTmonster/redissgo/internal/wildcard"
+)
+
+func init() {
+	registerCommand("del", execDel, -2, flagWrite).keys(1, -1, 1)
+	registerCommand("unlink", execDel, -2, flagWrite|flagFast).keys(1, -1, 1)
+	registerCommand("exists", execExists, -2, flagReadonly|flagFast).keys(1, -1, 1)
+	registerCommand("type", execType, 2, flagReadonly|flagFast).keys(1, 1, 1)
+	registerCommand("rename", execRename, 3, flagWrite).keys(1, 2, 1)
+	registerCommand("renamenx", execRenameNX, 3, flagWrite|flagFast).keys(1, 2, 1)
+	registerCommand("copy", execCopy, -3, flagWrite|flagMultiDB).keys(1, 2, 1)
+	registerCommand("keys", execKeys, 2, flagReadonly)
+	registerCommand("randomkey", execRandomKey, 1, flagReadonly)
+	registerCommand("touch", execTouch, -2, flagReadonly|flagFast).keys(1, -1, 1)
+}
+
+// type names of TYPE, accepted by the TYPE option of SCAN
+var knownTypeNames = map[string]bool{
+	"string": true,
+	"list":   true,
+	"set":    true,
+	"zset":   true,
+	"hash":   true,
+	"stream": true,
+}
+
+/**
+ * @description: name of the type of a value, as replied by TYPE
+ * @param {interface{}} entity
+ * @return {*}
+ */
+func typeName(entity interface{}) string {
+	switch entity.(type) {
+	case []byte, int64:
+		return "string"
+	case *list.QuickList:
+		return "list"
+	case *set.Set:
+		return "set"
+	case *sortedset.SortedSet:
+		return "zset"
+	case *hash.Hash:
+		return "hash"
+	case *stream.Stream:
+		return "stream"
+	}
+	return "none"
+}
+
+/**
+ * @description: make an independent copy of a value for COPY,
+ *	it must be called under the lock of its key
+ * @param {interface{}} entity
+ * @return {*}
+ */
+func cloneEntity(entity interface{}) interface{} {
+	switch value := entity.(type) {
+	case []byte:
+		// APPEND may write after the end of a stored string
+		return copyBytes(value)
+	case *list.QuickList:
+		return value.Clone()
+	case *set.Set:
+		return value.Clone()
+	case *sortedset.SortedSet:
+		return value.Clone()
+	case *hash.Hash:
+		return value.Clone()
+	case *stream.Stream:
+		return value.Clone()
+	}
+	return entity
+}
+
+/**
+ * @description: DEL key [key ...] and UNLINK key [key ...]
+ */
+func execDel(c *Client, args [][]byte) reply.Reply {
+	keys := make([]string, len(args))
+	for i, arg := range args {
+		keys[i] = string(arg)
+	}
+	// all keys are removed at once
+	c.db.Locks(keys, nil)
+	defer c.db.Unlocks(keys, nil)
+	deleted := 0
+	for _, key := range keys {
+		c.db.UpdateWithLock(key, func(entry *dict.Entry) {
+			if entry.Exists {
+				entry.Exists = false
+				deleted++
+			}
+		})
+	}
+	return c.changesReply(int64(deleted))
+}
+
+/**
+ * @description: EXISTS key [key ...], a key given twice is counted twice
+ */
+func execExists(c *Client, args [][]byte) reply.Reply {
+	count := 0
+	for _, key := range args {
+		if _, ok := c.db.GetEntity(string(key)); ok {
+			count++
+		}
+	}
+	return reply.MakeIntReply(int64(count))
+}
+
+/**
+ * @description: TYPE key
+ */
+func execType(c *Client, args [][]byte) reply.Reply {
+	entity, ok := c.db.GetEntity(string(args[0]))
+	if !ok {
+		return reply.MakeStatusReply("none")
+	}
+	return reply.MakeStatusReply(typeName(entity))
+}
+
+/**
+ * @description: move the value and the TTL of a key to another key atomically
+ * @param {*Client} c
+ * @param {string} src
+ * @param {string} dest
+ * @param {bool} nx do not overwrite an existing dest
+ * @return {*} renamed or not, ERR if src does not exist
+ */
+func renameGeneric(c *Client, src, dest string, nx bool) (bool, reply.ErrorReply) {
+	if src == dest {
+		if _, ok := c.db.GetEntity(src); !ok {
+			return false, reply.MakeErrReply("ERR no such key")
+		}
+		c.keepVersions()
+		return !nx, nil
+	}
+
+	var errReply reply.ErrorReply
+	renamed := false
+	c.db.UpdateMulti([]string{src, dest}, func(entries []*dict.Entry) {
+		if !entries[0].Exists {
+			errReply = reply.MakeErrReply("ERR no such key")
+			return
+		}
+		if nx && entries[1].Exists {
+			return
+		}
+		*entries[1] = *entries[0]
+		entries[0].Exists = false
+		renamed = true
+	})
+	if renamed {
+		c.signalKeyReady(c.db, dest)
+	} else {
+		c.keepVersions()
+	}
+	return renamed, errReply
+}
+
+/**
+ * @description: RENAME key newkey
+ */
+func execRename(c *Client, args [][]byte) reply.Reply {
+	if _, errReply := renameGeneric(c, string(args[0]), string(args[1]), false); errReply != nil {
+		return errReply
+	}
+	return reply.MakeOkReply()
+}
+
+/**
+ * @description: RENAMENX key newkey
+ */
+func execRenameNX(c *Client, args [][]byte) reply.Reply {
+	renamed, errReply := renameGeneric(c, string(args[0]), string(args[1]), true)
+	if errReply != nil {
+		return errReply
+	}
+	if !renamed {
+		return reply.MakeIntReply(0)
+	}
+	return reply.MakeIntReply(1)
+}
+
+/**
+ * @description: COPY source destination [DB destination-db] [REPLACE]
+ */
+func execCopy(c *Client, args [][]byte) reply.Reply {
+	src, dest := string(args[0]), string(args[1])
+	dst := c.db
+	replace := false
+	for i := 2; i < len(args); i++ {
+		option := strings.ToLower(string(args[i]))
+		switch {
+		case option == "replace":
+			replace = true
+		case option == "db" && i+1 < len(args):
+			index, errReply := parseDBIndex(c.handler, args[i+1])
+			if errReply != nil {
+				return errReply
+			}
+			dst = c.selectDB(index)
+			i++
+		default:
+			return reply.MakeSyntaxErrReply()
+		}
+	}
+
+	copied := false
+	put := func(entry *dict.Entry, value interface{}, at int64) {
+		if entry.Exists && !replace {
+			return
+		}
+		entry.Value, entry.Exists, entry.ExpireAt = value, true, at
+		copied = true
+	}
+	if dst == c.db {
+		if src == dest {
+			return reply.MakeErrReply("ERR source and destination objects are the same")
+		}
+		c.db.UpdateMulti([]string{src, dest}, func(entries []*dict.Entry) {
+			if entries[0].Exists {
+				put(entries[1], cloneEntity(entries[0].Value), entries[0].ExpireAt)
+			}
+		})
+	} else {
+		// the generic version bump only covers the keys of the selected database
+		dst.watched.beginWrite([]string{dest})
+		unlock := lockKeysOfDBs(c.db, src, dst, dest)
+		c.db.UpdateWithLock(src, func(from *dict.Entry) {
+			if from.Exists {
+				dst.UpdateWithLock(dest, func(to *dict.Entry) {
+					put(to, cloneEntity(from.Value), from.ExpireAt)
+				})
+			}
+		})
+		unlock()
+		dst.watched.endWrite([]string{dest}, copied)
+	}
+	if !copied {
+		return c.changesReply(0)
+	}
+	c.signalKeyReady(dst, dest)
+	return reply.MakeIntReply(1)
+}
+
+/**
+ * @description: KEYS pattern
+ */
+func execKeys(c *Client, args [][]byte) reply.Reply {
+	pattern := args[0]
+	matchAll := string(pattern) == "*"
+	keys := make([][]byte, 0)
+	c.db.ForEach(func(key string, value interface{}) bool {
+		if matchAll || wildcard.Match(pattern, []byte(key)) {
+			keys = append(keys, []byte(key))
+		}
+		return true
+	})
+	return reply.MakeMultiBulkReply(keys)
+}
+
+/**
+ * @description: RANDOMKEY
+ */
+func execRandomKey(c *Client, args [][]byte) reply.Reply {
+	key, ok := c.db.RandomKey()
+	return bulkOrNull([]byte(key), ok)
+}
+
+/**
+ * @description: TOUCH key [key ...]
+ */
+func execTouch(c *Client, args [][]byte) reply.Reply {
+	return execExists(c, args)
+}
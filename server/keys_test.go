@@ -0,0 +1,283 @@
+/*
+ * @Description:
+ * @Autor: HTmonster
+ * @Date: 2026-10-19 23:02:36
+ */
+package server
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestDelExists(t *testing.T) {
+	h := NewHandler()
+	defer h.Close()
+	c := newTestClient(h)
+
+	runCases(t, h, c, []cmdCase{
+		{[]string{"MSET", "a", "1", "b", "2", "c", "3"}, "+OK\r\n"},
+		{[]string{"EXISTS", "a", "a", "none"}, ":2\r\n"},
+		{[]string{"TOUCH", "a", "b", "none"}, ":2\r\n"},
+		{[]string{"DEL", "a", "none", "a"}, ":1\r\n"},
+		{[]string{"UNLINK", "b", "c"}, ":2\r\n"},
+		{[]string{"EXISTS", "a", "b", "c"}, ":0\r\n"},
+		{[]string{"DEL"}, "-ERR wrong number of arguments for 'del' command\r\n"},
+	})
+}
+
+func TestDelAtomic(t *testing.T) {
+	h := NewHandler()
+	defer h.Close()
+	keys := []string{"a", "b"}
+
+	// readers never see one of the keys deleted and not the other
+	var wg sync.WaitGroup
+	stop := make(chan struct{})
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		c := newTestClient(h)
+		for i := 0; i < 1000; i++ {
+			execString(h, c, "MSET", "a", "1", "b", "1")
+			execString(h, c, "DEL", "a", "b")
+		}
+		close(stop)
+	}()
+	db := h.selectDB(0)
+	for {
+		select {
+		case <-stop:
+			wg.Wait()
+			return
+		default:
+		}
+		db.Locks(nil, keys)
+		_, a := db.GetEntityWithLock("a")
+		_, b := db.GetEntityWithLock("b")
+		db.Unlocks(nil, keys)
+		if a != b {
+			t.Fatalf("DEL removed one key only: %v %v", a, b)
+		}
+	}
+}
+
+func TestType(t *testing.T) {
+	h := NewHandler()
+	defer h.Close()
+	c := newTestClient(h)
+
+	runCases(t, h, c, []cmdCase{
+		{[]string{"SET", "str", "v"}, "+OK\r\n"},
+		{[]string{"INCR", "int"}, ":1\r\n"},
+		{[]string{"RPUSH", "list", "a"}, ":1\r\n"},
+		{[]string{"SADD", "set", "a"}, ":1\r\n"},
+		{[]string{"ZADD", "zset", "1", "a"}, ":1\r\n"},
+		{[]string{"HSET", "hash", "f", "v"}, ":1\r\n"},
+		{[]string{"XADD", "stream", "1-1", "f", "v"}, "$3\r\n1-1\r\n"},
+		{[]string{"TYPE", "str"}, "+string\r\n"},
+		{[]string{"TYPE", "int"}, "+string\r\n"},
+		{[]string{"TYPE", "list"}, "+list\r\n"},
+		{[]string{"TYPE", "set"}, "+set\r\n"},
+		{[]string{"TYPE", "zset"}, "+zset\r\n"},
+		{[]string{"TYPE", "hash"}, "+hash\r\n"},
+		{[]string{"TYPE", "stream"}, "+stream\r\n"},
+		{[]string{"TYPE", "none"}, "+none\r\n"},
+	})
+}
+
+func TestRename(t *testing.T) {
+	h := NewHandler()
+	defer h.Close()
+	c := newTestClient(h)
+
+	runCases(t, h, c, []cmdCase{
+		{[]string{"RENAME", "none", "b"}, "-ERR no such key\r\n"},
+		{[]string{"RENAMENX", "none", "b"}, "-ERR no such key\r\n"},
+		{[]string{"SET", "a", "1", "EX", "100"}, "+OK\r\n"},
+		{[]string{"SET", "b", "2"}, "+OK\r\n"},
+		{[]string{"RENAME", "a", "b"}, "+OK\r\n"},
+		{[]string{"GET", "b"}, "$1\r\n1\r\n"},
+		{[]string{"TTL", "b"}, ":100\r\n"},
+		{[]string{"EXISTS", "a"}, ":0\r\n"},
+		{[]string{"RENAME", "b", "b"}, "+OK\r\n"},
+		{[]string{"RENAMENX", "b", "b"}, ":0\r\n"},
+		{[]string{"SET", "c", "3"}, "+OK\r\n"},
+		{[]string{"RENAMENX", "b", "c"}, ":0\r\n"},
+		{[]string{"GET", "c"}, "$1\r\n3\r\n"},
+		{[]string{"RENAMENX", "b", "d"}, ":1\r\n"},
+		{[]string{"TTL", "d"}, ":100\r\n"},
+		{[]string{"DBSIZE"}, ":2\r\n"},
+	})
+}
+
+func TestCopy(t *testing.T) {
+	h := NewHandler()
+	defer h.Close()
+	c := newTestClient(h)
+
+	runCases(t, h, c, []cmdCase{
+		{[]string{"COPY", "none", "b"}, ":0\r\n"},
+		{[]string{"RPUSH", "list", "a", "b"}, ":2\r\n"},
+		{[]string{"PEXPIRE", "list", "100000"}, ":1\r\n"},
+		{[]string{"COPY", "list", "copy"}, ":1\r\n"},
+		{[]string{"TTL", "copy"}, ":100\r\n"},
+		// the copy is independent
+		{[]string{"RPUSH", "copy", "c"}, ":3\r\n"},
+		{[]string{"LRANGE", "list", "0", "-1"}, "*2\r\n$1\r\na\r\n$1\r\nb\r\n"},
+		{[]string{"COPY", "list", "copy"}, ":0\r\n"},
+		{[]string{"COPY", "list", "copy", "REPLACE"}, ":1\r\n"},
+		{[]string{"LLEN", "copy"}, ":2\r\n"},
+		{[]string{"COPY", "list", "list"}, "-ERR source and destination objects are the same\r\n"},
+		{[]string{"COPY", "list", "list", "DB", "1"}, ":1\r\n"},
+		{[]string{"COPY", "list", "list", "DB", "1"}, ":0\r\n"},
+		{[]string{"COPY", "list", "list", "DB", "16"}, "-ERR DB index is out of range\r\n"},
+		{[]string{"COPY", "list", "list", "DB"}, "-ERR syntax error\r\n"},
+		{[]string{"COPY", "list", "x", "NX"}, "-ERR syntax error\r\n"},
+		{[]string{"SELECT", "1"}, "+OK\r\n"},
+		{[]string{"LRANGE", "list", "0", "-1"}, "*2\r\n$1\r\na\r\n$1\r\nb\r\n"},
+		{[]string{"TTL", "list"}, ":100\r\n"},
+		// strings are copied too
+		{[]string{"SET", "s", "abc"}, "+OK\r\n"},
+		{[]string{"COPY", "s", "s2"}, ":1\r\n"},
+		{[]string{"APPEND", "s", "d"}, ":4\r\n"},
+		{[]string{"APPEND", "s2", "e"}, ":4\r\n"},
+		{[]string{"GET", "s"}, "$4\r\nabcd\r\n"},
+		{[]string{"GET", "s2"}, "$4\r\nabce\r\n"},
+	})
+}
+
+func TestCopyConcurrent(t *testing.T) {
+	h := NewHandler()
+	defer h.Close()
+
+	// COPY in both directions and transactions on both databases never
+	// deadlock, a copy always has the TTL of its source
+	var wg sync.WaitGroup
+	for w := 0; w < 4; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			c := newTestClient(h)
+			c.dbIndex = w % 2
+			other := strconv.Itoa(1 - w%2)
+			for i := 0; i < 300; i++ {
+				switch w {
+				case 0, 1:
+					execString(h, c, "SET", "k", strconv.Itoa(i), "PX", "100000")
+					execString(h, c, "COPY", "k", "k", "DB", other, "REPLACE")
+				default:
+					execString(h, c, "MULTI")
+					execString(h, c, "GET", "k")
+					execString(h, c, "SELECT", other)
+					execString(h, c, "GET", "k")
+					execString(h, c, "EXEC")
+				}
+				if got := execString(h, c, "PTTL", "k"); got == ":-1\r\n" {
+					t.Errorf("copied key without TTL")
+					return
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+}
+
+func TestCopyTypes(t *testing.T) {
+	h := NewHandler()
+	defer h.Close()
+	c := newTestClient(h)
+
+	runCases(t, h, c, []cmdCase{
+		{[]string{"SADD", "set", "a"}, ":1\r\n"},
+		{[]string{"ZADD", "zset", "1", "a"}, ":1\r\n"},
+		{[]string{"HSET", "hash", "f", "v"}, ":1\r\n"},
+		{[]string{"XADD", "stream", "1-1", "f", "v"}, "$3\r\n1-1\r\n"},
+		{[]string{"XGROUP", "CREATE", "stream", "g", "0"}, "+OK\r\n"},
+		{[]string{"COPY", "set", "set2"}, ":1\r\n"},
+		{[]string{"COPY", "zset", "zset2"}, ":1\r\n"},
+		{[]string{"COPY", "hash", "hash2"}, ":1\r\n"},
+		{[]string{"COPY", "stream", "stream2"}, ":1\r\n"},
+		{[]string{"SADD", "set2", "b"}, ":1\r\n"},
+		{[]string{"ZADD", "zset2", "2", "b"}, ":1\r\n"},
+		{[]string{"HSET", "hash2", "g", "v"}, ":1\r\n"},
+		{[]string{"XGROUP", "DESTROY", "stream2", "g"}, ":1\r\n"},
+		{[]string{"SCARD", "set"}, ":1\r\n"},
+		{[]string{"ZCARD", "zset"}, ":1\r\n"},
+		{[]string{"HLEN", "hash"}, ":1\r\n"},
+		{[]string{"XGROUP", "CREATECONSUMER", "stream", "g", "c"}, ":1\r\n"},
+		{[]string{"TYPE", "stream2"}, "+stream\r\n"},
+	})
+}
+
+func TestKeys(t *testing.T) {
+	h := NewHandler()
+	defer h.Close()
+	c := newTestClient(h)
+
+	execString(h, c, "MSET", "hello", "1", "hallo", "2", "hxllo", "3", "hllo", "4", "h*llo", "5")
+	cases := []struct {
+		pattern string
+		want    []string
+	}{
+		{"*", []string{"h*llo", "hallo", "hello", "hllo", "hxllo"}},
+		{"h?llo", []string{"h*llo", "hallo", "hello", "hxllo"}},
+		{"h*llo", []string{"h*llo", "hallo", "hello", "hllo", "hxllo"}},
+		{"h[ae]llo", []string{"hallo", "hello"}},
+		{"h[^e]llo", []string{"h*llo", "hallo", "hxllo"}},
+		{"h[a-b]llo", []string{"hallo"}},
+		{`h\*llo`, []string{"h*llo"}},
+		{"nothing*", []string{}},
+	}
+	for _, tc := range cases {
+		keys := execMembers(h, c, "KEYS", tc.pattern)
+		sort.Strings(keys)
+		if strings.Join(keys, ",") != strings.Join(tc.want, ",") {
+			t.Errorf("KEYS %s: got %v, want %v", tc.pattern, keys, tc.want)
+		}
+	}
+}
+
+func TestRandomKey(t *testing.T) {
+	h := NewHandler()
+	defer h.Close()
+	c := newTestClient(h)
+
+	if got := execString(h, c, "RANDOMKEY"); got != "$-1\r\n" {
+		t.Errorf("RANDOMKEY of an empty db: got %q", got)
+	}
+	execString(h, c, "MSET", "a", "1", "b", "2")
+	for i := 0; i < 10; i++ {
+		if got := execString(h, c, "RANDOMKEY"); got != "$1\r\na\r\n" && got != "$1\r\nb\r\n" {
+			t.Fatalf("RANDOMKEY: got %q", got)
+		}
+	}
+}
+
+func TestRenameServesBlocked(t *testing.T) {
+	h, addr, stop := startTestServer(t)
+	defer stop()
+	waiting, writer := dial(t, addr), dial(t, addr)
+	defer waiting.Close()
+	defer writer.Close()
+
+	r := doAsync(waiting, "BLPOP", "dst", "0")
+	waitBlocked(t, h, 0, "dst", 1)
+	if _, err := writer.Do("RPUSH", "src", "a", "b"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := writer.Do("COPY", "src", "dst"); err != nil {
+		t.Fatal(err)
+	}
+	expectReply(t, r, keyValue("dst", "a"))
+
+	r = doAsync(waiting, "BLPOP", "other", "0")
+	waitBlocked(t, h, 0, "other", 1)
+	if _, err := writer.Do("RENAME", "src", "other"); err != nil {
+		t.Fatal(err)
+	}
+	expectReply(t, r, keyValue("other", "a"))
+}
@@ -0,0 +1,805 @@
+/*
+ * @Description: list commands
+ * @Autor: HTmonster
+ * @Date: 2026-10-18 23:48:02
+ */
+
+package server
+
+import (
+	"strings"
+
+	"github.com/HTmonster/redissgo/datastruct/dict"
+	"github.com/HTmonster/redissgo/datastruct/list"
+	"github.com/HTmonster/redissgo/internal/reply"
+)
+
+func init() {
+	registerCommand("lpush", execLPush, -3, flagWrite|flagFast).keys(1, 1, 1)
+	registerCommand("rpush", execRPush, -3, flagWrite|flagFast).keys(1, 1, 1)
+	registerCommand("lpushx", execLPushX, -3, flagWrite|flagFast).keys(1, 1, 1)
+	registerCommand("rpushx", execRPushX, -3, flagWrite|flagFast).keys(1, 1, 1)
+	registerCommand("lpop", execLPop, -2, flagWrite|flagFast).keys(1, 1, 1)
+	registerCommand("rpop", execRPop, -2, flagWrite|flagFast).keys(1, 1, 1)
+	registerCommand("lrange", execLRange, 4, flagReadonly).keys(1, 1, 1)
+	registerCommand("lindex", execLIndex, 3, flagReadonly).keys(1, 1, 1)
+	registerCommand("lset", execLSet, 4, flagWrite).keys(1, 1, 1)
+	registerCommand("linsert", execLInsert, 5, flagWrite).keys(1, 1, 1)
+	registerCommand("lrem", execLRem, 4, flagWrite).keys(1, 1, 1)
+	registerCommand("ltrim", execLTrim, 4, flagWrite).keys(1, 1, 1)
+	registerCommand("llen", execLLen, 2, flagReadonly|flagFast).keys(1, 1, 1)
+	registerCommand("lpos", execLPos, -3, flagReadonly).keys(1, 1, 1)
+	registerCommand("lmove", execLMove, 5, flagWrite).keys(1, 2, 1)
+	registerCommand("lmpop", execLMPop, -4, flagWrite).keysBy(numKeysFinder(1, false))
+	registerCommand("blpop", execBLPop, -3, flagWrite).keys(1, -2, 1)
+	registerCommand("brpop", execBRPop, -3, flagWrite).keys(1, -2, 1)
+	registerCommand("blmove", execBLMove, 6, flagWrite).keys(1, 2, 1)
+	registerCommand("blmpop", execBLMPop, -5, flagWrite).keysBy(numKeysFinder(2, false))
+}
+
+/**
+ * @description: run fn on the list at key under the segment lock.
+ *	Lists are modified in place, so even readers must hold the lock.
+ *	The key is removed when the list becomes empty.
+ * @param {string} key
+ * @param {bool} create create an empty list if the key does not exist
+ * @param {func} fn gets nil if the key does not exist and create is false
+ * @return {*} reply of fn, or WRONGTYPE
+ */
+func (db *DB) updateList(key string, create bool, fn func(l *list.QuickList) reply.Reply) reply.Reply {
+	var result reply.Reply
+	db.Update(key, func(entry *dict.Entry) {
+		var l *list.QuickList
+		if entry.Exists {
+			var ok bool
+			if l, ok = entry.Value.(*list.QuickList); !ok {
+				result = reply.MakeWrongTypeErrReply()
+				return
+			}
+		} else if create {
+			l = list.MakeQuickList()
+		}
+		result = fn(l)
+		if l != nil {
+			entry.Value, entry.Exists = l, l.Len() > 0
+		}
+	})
+	return result
+}
+
+/**
+ * @description: parse an index or a count of a list command
+ */
+func parseListInt(arg []byte) (int64, reply.ErrorReply) {
+	n, ok := parseCanonicalInt(arg)
+	if !ok {
+		return 0, reply.MakeNotIntegerErrReply()
+	}
+	return n, nil
+}
+
+/**
+ * @description: parse LEFT or RIGHT
+ * @return {*} left or not
+ */
+func parseListSide(arg []byte) (bool, reply.ErrorReply) {
+	switch strings.ToLower(string(arg)) {
+	case "left":
+		return true, nil
+	case "right":
+		return false, nil
+	}
+	return false, reply.MakeSyntaxErrReply()
+}
+
+/**
+ * @description: push at the head or the tail
+ */
+func listPush(l *list.QuickList, left bool, data []byte) {
+	if left {
+		l.PushFront(data)
+	} else {
+		l.PushBack(data)
+	}
+}
+
+/**
+ * @description: pop at most count elements from the head or the tail
+ */
+func listPop(l *list.QuickList, left bool, count int64) [][]byte {
+	if count > int64(l.Len()) {
+		count = int64(l.Len())
+	}
+	result := make([][]byte, count)
+	for i := range result {
+		if left {
+			result[i], _ = l.PopFront()
+		} else {
+			result[i], _ = l.PopBack()
+		}
+	}
+	return result
+}
+
+/**
+ * @description: convert negative indexes of [start, stop] like LRANGE
+ * @return {*} range, empty or not
+ */
+func listRange(start, stop int64, size int) (int, int, bool) {
+	n := int64(size)
+	if start < 0 {
+		start += n
+	}
+	if stop < 0 {
+		stop += n
+	}
+	if start < 0 {
+		start = 0
+	}
+	if start > stop || start >= n {
+		return 0, 0, true
+	}
+	if stop >= n {
+		stop = n - 1
+	}
+	return int(start), int(stop), false
+}
+
+//------------ push pop --------------
+
+/**
+ * @description: LPUSH RPUSH LPUSHX RPUSHX
+ */
+func pushGeneric(c *Client, args [][]byte, left bool, create bool) reply.Reply {
+	key := string(args[0])
+	pushed := false
+	result := c.db.updateList(key, create, func(l *list.QuickList) reply.Reply {
+		if l == nil {
+			return c.changesReply(0)
+		}
+		for _, arg := range args[1:] {
+			listPush(l, left, arg)
+		}
+		pushed = true
+		return reply.MakeIntReply(int64(l.Len()))
+	})
+	if pushed {
+		c.signalKeyReady(c.db, key)
+	}
+	return result
+}
+
+/**
+ * @description: LPUSH key element [element ...]
+ */
+func execLPush(c *Client, args [][]byte) reply.Reply {
+	return pushGeneric(c, args, true, true)
+}
+
+/**
+ * @description: RPUSH key element [element ...]
+ */
+func execRPush(c *Client, args [][]byte) reply.Reply {
+	return pushGeneric(c, args, false, true)
+}
+
+/**
+ * @description: LPUSHX key element [element ...]
+ */
+func execLPushX(c *Client, args [][]byte) reply.Reply {
+	return pushGeneric(c, args, true, false)
+}
+
+/**
+ * @description: RPUSHX key element [element ...]
+ */
+func execRPushX(c *Client, args [][]byte) reply.Reply {
+	return pushGeneric(c, args, false, false)
+}
+
+/**
+ * @description: LPOP and RPOP
+ */
+func popGeneric(c *Client, args [][]byte, left bool, name string) reply.Reply {
+	if len(args) > 2 {
+		return reply.MakeArgNumErrReply(name)
+	}
+	hasCount := len(args) == 2
+	var count int64 = 1
+	if hasCount {
+		n, ok := parseCanonicalInt(args[1])
+		if !ok || n < 0 {
+			return reply.MakeErrReply("ERR value is out of range, must be positive")
+		}
+		count = n
+	}
+
+	return c.db.updateList(string(args[0]), false, func(l *list.QuickList) reply.Reply {
+		if l == nil {
+			c.keepVersions()
+			if hasCount {
+				return reply.MakeNullArrayReply()
+			}
+			return reply.MakeNullBulkReply()
+		}
+		elements := listPop(l, left, count)
+		if len(elements) == 0 {
+			c.keepVersions()
+		}
+		if !hasCount {
+			return reply.MakeBulkReply(elements[0])
+		}
+		return reply.MakeMultiBulkReply(elements)
+	})
+}
+
+/**
+ * @description: LPOP key [count]
+ */
+func execLPop(c *Client, args [][]byte) reply.Reply {
+	return popGeneric(c, args, true, "lpop")
+}
+
+/**
+ * @description: RPOP key [count]
+ */
+func execRPop(c *Client, args [][]byte) reply.Reply {
+	return popGeneric(c, args, false, "rpop")
+}
+
+//------------ read --------------
+
+/**
+ * @description: LRANGE key start stop
+ */
+func execLRange(c *Client, args [][]byte) reply.Reply {
+	start, errReply := parseListInt(args[1])
+	if errReply != nil {
+		return errReply
+	}
+	stop, errReply := parseListInt(args[2])
+	if errReply != nil {
+		return errReply
+	}
+	return c.db.updateList(string(args[0]), false, func(l *list.QuickList) reply.Reply {
+		if l == nil {
+			return reply.MakeEmptyArrayReply()
+		}
+		from, to, empty := listRange(start, stop, l.Len())
+		if empty {
+			return reply.MakeEmptyArrayReply()
+		}
+		return reply.MakeMultiBulkReply(l.Range(from, to))
+	})
+}
+
+/**
+ * @description: LINDEX key index
+ */
+func execLIndex(c *Client, args [][]byte) reply.Reply {
+	index, errReply := parseListInt(args[1])
+	if errReply != nil {
+		return errReply
+	}
+	return c.db.updateList(string(args[0]), false, func(l *list.QuickList) reply.Reply {
+		if l == nil {
+			return reply.MakeNullBulkReply()
+		}
+		if index < 0 {
+			index += int64(l.Len())
+		}
+		if index < 0 || index >= int64(l.Len()) {
+			return reply.MakeNullBulkReply()
+		}
+		return reply.MakeBulkReply(l.Get(int(index)))
+	})
+}
+
+/**
+ * @description: LLEN key
+ */
+func execLLen(c *Client, args [][]byte) reply.Reply {
+	return c.db.updateList(string(args[0]), false, func(l *list.QuickList) reply.Reply {
+		if l == nil {
+			return reply.MakeIntReply(0)
+		}
+		return reply.MakeIntReply(int64(l.Len()))
+	})
+}
+
+/**
+ * @description: LPOS key element [RANK rank] [COUNT num-matches] [MAXLEN len]
+ */
+func execLPos(c *Client, args [][]byte) reply.Reply {
+	element := args[1]
+	var rank int64 = 1
+	var count, maxLen int64
+	hasCount := false
+	for i := 2; i < len(args); i += 2 {
+		opt := strings.ToLower(string(args[i]))
+		if i+1 >= len(args) {
+			return reply.MakeSyntaxErrReply()
+		}
+		n, ok := parseCanonicalInt(args[i+1])
+		switch opt {
+		case "rank":
+			if !ok {
+				return reply.MakeNotIntegerErrReply()
+			}
+			if n == -1<<63 {
+				return reply.MakeErrReply("ERR value is out of range, value must between -9223372036854775807 and 9223372036854775807")
+			}
+			if n == 0 {
+				return reply.MakeErrReply("ERR RANK can't be zero: use 1 to start from the first match, 2 from the second ... or use negative to start from the end of the list")
+			}
+			rank = n
+		case "count":
+			if !ok || n < 0 {
+				return reply.MakeErrReply("ERR COUNT can't be negative")
+			}
+			count, hasCount = n, true
+		case "maxlen":
+			if !ok || n < 0 {
+				return reply.MakeErrReply("ERR MAXLEN can't be negative")
+			}
+			maxLen = n
+		default:
+			return reply.MakeSyntaxErrReply()
+		}
+	}
+
+	return c.db.updateList(string(args[0]), false, func(l *list.QuickList) reply.Reply {
+		var matches []reply.Reply
+		if l != nil {
+			// a negative rank searches from the tail
+			it := l.Iterator(0)
+			skip := rank - 1
+			if rank < 0 {
+				it = l.Iterator(l.Len() - 1)
+				skip = -rank - 1
+			}
+			for compared := int64(0); it.Valid() && (maxLen == 0 || compared < maxLen); compared++ {
+				if it.Equal(element) {
+					if skip > 0 {
+						skip--
+					} else {
+						matches = append(matches, reply.MakeIntReply(int64(it.Index())))
+						if !hasCount || (count != 0 && int64(len(matches)) >= count) {
+							break
+						}
+					}
+				}
+				if rank > 0 {
+					it.Next()
+				} else {
+					it.Prev()
+				}
+			}
+		}
+		if !hasCount {
+			if len(matches) == 0 {
+				return reply.MakeNullBulkReply()
+			}
+			return matches[0]
+		}
+		if len(matches) == 0 {
+			return reply.MakeEmptyArrayReply()
+		}
+		return reply.MakeArrayReply(matches)
+	})
+}
+
+//------------ modify --------------
+
+/**
+ * @description: LSET key index element
+ */
+func execLSet(c *Client, args [][]byte) reply.Reply {
+	index, errReply := parseListInt(args[1])
+	if errReply != nil {
+		return errReply
+	}
+	return c.db.updateList(string(args[0]), false, func(l *list.QuickList) reply.Reply {
+		if l == nil {
+			return reply.MakeErrReply("ERR no such key")
+		}
+		if index < 0 {
+			index += int64(l.Len())
+		}
+		if index < 0 || index >= int64(l.Len()) {
+			return reply.MakeErrReply("ERR index out of range")
+		}
+		l.Set(int(index), args[2])
+		return reply.MakeOkReply()
+	})
+}
+
+/**
+ * @description: LINSERT key BEFORE|AFTER pivot element
+ */
+func execLInsert(c *Client, args [][]byte) reply.Reply {
+	var after bool
+	switch strings.ToLower(string(args[1])) {
+	case "before":
+	case "after":
+		after = true
+	default:
+		return reply.MakeSyntaxErrReply()
+	}
+	pivot, element := args[2], args[3]
+
+	return c.db.updateList(string(args[0]), false, func(l *list.QuickList) reply.Reply {
+		if l == nil {
+			return c.changesReply(0)
+		}
+		for it := l.Iterator(0); it.Valid(); it.Next() {
+			if !it.Equal(pivot) {
+				continue
+			}
+			index := it.Index()
+			if after {
+				index++
+			}
+			l.Insert(index, element)
+			return reply.MakeIntReply(int64(l.Len()))
+		}
+		c.keepVersions()
+		return reply.MakeIntReply(-1)
+	})
+}
+
+/**
+ * @description: LREM key count element
+ */
+func execLRem(c *Client, args [][]byte) reply.Reply {
+	count, errReply := parseListInt(args[1])
+	if errReply != nil {
+		return errReply
+	}
+	return c.db.updateList(string(args[0]), false, func(l *list.QuickList) reply.Reply {
+		if l == nil {
+			return c.changesReply(0)
+		}
+		// |count| larger than the list means all of them
+		if count > int64(l.Len()) || -count > int64(l.Len()) {
+			count = 0
+		}
+		return c.changesReply(int64(l.RemoveValue(args[2], int(count))))
+	})
+}
+
+/**
+ * @description: LTRIM key start stop
+ */
+func execLTrim(c *Client, args [][]byte) reply.Reply {
+	start, errReply := parseListInt(args[1])
+	if errReply != nil {
+		return errReply
+	}
+	stop, errReply := parseListInt(args[2])
+	if errReply != nil {
+		return errReply
+	}
+	return c.db.updateList(string(args[0]), false, func(l *list.QuickList) reply.Reply {
+		if l == nil {
+			return reply.MakeOkReply()
+		}
+		from, to, empty := listRange(start, stop, l.Len())
+		if empty {
+			l.Trim(1, 0)
+		} else {
+			l.Trim(from, to)
+		}
+		return reply.MakeOkReply()
+	})
+}
+
+//------------ move --------------
+
+/**
+ * @description: pop an element from src and push it into dest
+ * @param {*DB} db
+ * @param {string} src
+ * @param {string} dest
+ * @param {bool} fromLeft
+ * @param {bool} toLeft
+ * @return {*} moved element, nil if src does not exist, WRONGTYPE
+ */
+func (db *DB) moveListElement(src, dest string, fromLeft, toLeft bool) ([]byte, reply.ErrorReply) {
+	var element []byte
+	var errReply reply.ErrorReply
+	if src == dest {
+		result := db.updateList(src, false, func(l *list.QuickList) reply.Reply {
+			if l != nil {
+				element = listPop(l, fromLeft, 1)[0]
+				listPush(l, toLeft, element)
+			}
+			return nil
+		})
+		errReply, _ = result.(reply.ErrorReply)
+		return element, errReply
+	}
+
+	// check the types before removing anything from src
+	entity, exists := db.GetEntity(src)
+	if !exists {
+		return nil, nil
+	}
+	if _, ok := entity.(*list.QuickList); !ok {
+		return nil, reply.MakeWrongTypeErrReply()
+	}
+	if entity, exists := db.GetEntity(dest); exists {
+		if _, ok := entity.(*list.QuickList); !ok {
+			return nil, reply.MakeWrongTypeErrReply()
+		}
+	}
+	result := db.updateList(src, false, func(l *list.QuickList) reply.Reply {
+		if l != nil {
+			element = listPop(l, fromLeft, 1)[0]
+		}
+		return nil
+	})
+	if errReply, ok := result.(reply.ErrorReply); ok {
+		return nil, errReply
+	}
+	if element == nil {
+		return nil, nil
+	}
+	db.updateList(dest, true, func(l *list.QuickList) reply.Reply {
+		listPush(l, toLeft, element)
+		return nil
+	})
+	return element, nil
+}
+
+/**
+ * @description: LMOVE source destination LEFT|RIGHT LEFT|RIGHT
+ */
+func execLMove(c *Client, args [][]byte) reply.Reply {
+	fromLeft, errReply := parseListSide(args[2])
+	if errReply != nil {
+		return errReply
+	}
+	toLeft, errReply := parseListSide(args[3])
+	if errReply != nil {
+		return errReply
+	}
+	dest := string(args[1])
+	element, errReply := c.db.moveListElement(string(args[0]), dest, fromLeft, toLeft)
+	if errReply != nil {
+		return errReply
+	}
+	if element == nil {
+		c.keepVersions()
+		return reply.MakeNullBulkReply()
+	}
+	c.signalKeyReady(c.db, dest)
+	return reply.MakeBulkReply(element)
+}
+
+// parsed arguments of LMPOP and BLMPOP
+type mpopArgs struct {
+	keys  []string
+	left  bool
+	count int64
+}
+
+/**
+ * @description: parse numkeys key [key ...] LEFT|RIGHT [COUNT count]
+ */
+func parseMPopArgs(args [][]byte) (*mpopArgs, reply.ErrorReply) {
+	numKeys, ok := parseCanonicalInt(args[0])
+	if !ok || numKeys <= 0 {
+		return nil, reply.MakeErrReply("ERR numkeys should be greater than 0")
+	}
+	if numKeys > int64(len(args)-2) {
+		return nil, reply.MakeSyntaxErrReply()
+	}
+	m := &mpopArgs{count: 1}
+	for _, key := range args[1 : numKeys+1] {
+		m.keys = append(m.keys, string(key))
+	}
+	rest := args[numKeys+1:]
+	var errReply reply.ErrorReply
+	if m.left, errReply = parseListSide(rest[0]); errReply != nil {
+		return nil, errReply
+	}
+	rest = rest[1:]
+	if len(rest) == 0 {
+		return m, nil
+	}
+	if len(rest) != 2 || strings.ToLower(string(rest[0])) != "count" {
+		return nil, reply.MakeSyntaxErrReply()
+	}
+	if m.count, ok = parseCanonicalInt(rest[1]); !ok || m.count <= 0 {
+		return nil, reply.MakeErrReply("ERR count should be greater than 0")
+	}
+	return m, nil
+}
+
+/**
+ * @description: pop from the first non empty list of keys
+ * @param {[]string} keys
+ * @param {bool} left
+ * @param {int64} count
+ * @return {*} key, popped elements (nil if all lists are empty), WRONGTYPE
+ */
+func (db *DB) popFirst(keys []string, left bool, count int64) (string, [][]byte, reply.ErrorReply) {
+	for _, key := range keys {
+		var elements [][]byte
+		result := db.updateList(key, false, func(l *list.QuickList) reply.Reply {
+			if l != nil {
+				elements = listPop(l, left, count)
+			}
+			return nil
+		})
+		if errReply, ok := result.(reply.ErrorReply); ok {
+			return "", nil, errReply
+		}
+		if len(elements) > 0 {
+			return key, elements, nil
+		}
+	}
+	return "", nil, nil
+}
+
+/**
+ * @description: make the reply of LMPOP and BLMPOP
+ */
+func makeMPopReply(key string, elements [][]byte) reply.Reply {
+	return reply.MakeArrayReply([]reply.Reply{
+		reply.MakeBulkReply([]byte(key)),
+		reply.MakeMultiBulkReply(elements),
+	})
+}
+
+/**
+ * @description: LMPOP numkeys key [key ...] LEFT|RIGHT [COUNT count]
+ */
+func execLMPop(c *Client, args [][]byte) reply.Reply {
+	m, errReply := parseMPopArgs(args)
+	if errReply != nil {
+		return errReply
+	}
+	key, elements, errReply := c.db.popFirst(m.keys, m.left, m.count)
+	if errReply != nil {
+		return errReply
+	}
+	if elements == nil {
+		c.keepVersions()
+		return reply.MakeNullArrayReply()
+	}
+	return makeMPopReply(key, elements)
+}
+
+//------------ blocking --------------
+
+/**
+ * @description: BLPOP and BRPOP
+ */
+func blockingPopGeneric(c *Client, args [][]byte, left bool) reply.Reply {
+	timeout, errReply := parseTimeout(args[len(args)-1])
+	if errReply != nil {
+		return errReply
+	}
+	keys := make([]string, len(args)-1)
+	for i, arg := range args[:len(args)-1] {
+		keys[i] = string(arg)
+	}
+
+	key, elements, errReply := c.db.popFirst(keys, left, 1)
+	if errReply != nil {
+		return errReply
+	}
+	if elements != nil {
+		return reply.MakeMultiBulkReply([][]byte{[]byte(key), elements[0]})
+	}
+
+	w := &waiter{
+		db:   c.dbIndex,
+		keys: keys,
+		serve: func(db *DB, key string) (reply.Reply, bool) {
+			_, elements, _ := db.popFirst([]string{key}, left, 1)
+			if elements == nil {
+				return nil, false
+			}
+			return reply.MakeMultiBulkReply([][]byte{[]byte(key), elements[0]}), true
+		},
+	}
+	return c.block(w, timeout, reply.MakeNullArrayReply())
+}
+
+/**
+ * @description: BLPOP key [key ...] timeout
+ */
+func execBLPop(c *Client, args [][]byte) reply.Reply {
+	return blockingPopGeneric(c, args, true)
+}
+
+/**
+ * @description: BRPOP key [key ...] timeout
+ */
+func execBRPop(c *Client, args [][]byte) reply.Reply {
+	return blockingPopGeneric(c, args, false)
+}
+
+/**
+ * @description: BLMOVE source destination LEFT|RIGHT LEFT|RIGHT timeout
+ */
+func execBLMove(c *Client, args [][]byte) reply.Reply {
+	fromLeft, errReply := parseListSide(args[2])
+	if errReply != nil {
+		return errReply
+	}
+	toLeft, errReply := parseListSide(args[3])
+	if errReply != nil {
+		return errReply
+	}
+	timeout, errReply := parseTimeout(args[4])
+	if errReply != nil {
+		return errReply
+	}
+	src, dest := string(args[0]), string(args[1])
+
+	element, errReply := c.db.moveListElement(src, dest, fromLeft, toLeft)
+	if errReply != nil {
+		return errReply
+	}
+	if element != nil {
+		c.signalKeyReady(c.db, dest)
+		return reply.MakeBulkReply(element)
+	}
+
+	w := &waiter{
+		db:   c.dbIndex,
+		keys: []string{src},
+		dest: dest,
+		serve: func(db *DB, key string) (reply.Reply, bool) {
+			element, errReply := db.moveListElement(key, dest, fromLeft, toLeft)
+			if errReply != nil {
+				return errReply, true
+			}
+			if element == nil {
+				return nil, false
+			}
+			return reply.MakeBulkReply(element), true
+		},
+	}
+	return c.block(w, timeout, reply.MakeNullBulkReply())
+}
+
+/**
+ * @description: BLMPOP timeout numkeys key [key ...] LEFT|RIGHT [COUNT count]
+ */
+func execBLMPop(c *Client, args [][]byte) reply.Reply {
+	timeout, errReply := parseTimeout(args[0])
+	if errReply != nil {
+		return errReply
+	}
+	m, errReply := parseMPopArgs(args[1:])
+	if errReply != nil {
+		return errReply
+	}
+
+	key, elements, errReply := c.db.popFirst(m.keys, m.left, m.count)
+	if errReply != nil {
+		return errReply
+	}
+	if elements != nil {
+		return makeMPopReply(key, elements)
+	}
+
+	w := &waiter{
+		db:   c.dbIndex,
+		keys: m.keys,
+		serve: func(db *DB, key string) (reply.Reply, bool) {
+			_, elements, _ := db.popFirst([]string{key}, m.left, m.count)
+			if elements == nil {
+				return nil, false
+			}
+			return makeMPopReply(key, elements), true
+		},
+	}
+	return c.block(w, timeout, reply.MakeNullArrayReply())
+}
+
@@ -0,0 +1,138 @@
+/*
+ * @Description:
+ * @Autor: HTmonster
+ * @Date: 2026-10-19 00:15:37
+ */
+package server
+
+import (
+	"testing"
+)
+
+const wrongType = "-WRONGTYPE Operation against a key holding the wrong kind of value\r\n"
+
+func TestListPushPop(t *testing.T) {
+	h := NewHandler()
+	defer h.Close()
+	c := newTestClient(h)
+
+	runCases(t, h, c, []cmdCase{
+		{[]string{"RPUSH", "l", "a", "b"}, ":2\r\n"},
+		{[]string{"LPUSH", "l", "c", "d"}, ":4\r\n"},
+		{[]string{"LRANGE", "l", "0", "-1"}, "*4\r\n$1\r\nd\r\n$1\r\nc\r\n$1\r\na\r\n$1\r\nb\r\n"},
+		{[]string{"LPUSHX", "none", "a"}, ":0\r\n"},
+		{[]string{"RPUSHX", "l", "e"}, ":5\r\n"},
+		{[]string{"LPOP", "l"}, "$1\r\nd\r\n"},
+		{[]string{"RPOP", "l"}, "$1\r\ne\r\n"},
+		{[]string{"LPOP", "l", "2"}, "*2\r\n$1\r\nc\r\n$1\r\na\r\n"},
+		{[]string{"RPOP", "l", "0"}, "*0\r\n"},
+		{[]string{"RPOP", "l", "10"}, "*1\r\n$1\r\nb\r\n"},
+		{[]string{"LLEN", "l"}, ":0\r\n"},
+		{[]string{"GET", "l"}, "$-1\r\n"},
+		{[]string{"LPOP", "l"}, "$-1\r\n"},
+		{[]string{"LPOP", "l", "1"}, "*-1\r\n"},
+		{[]string{"LPOP", "l", "-1"}, "-ERR value is out of range, must be positive\r\n"},
+		{[]string{"LPOP", "l", "1", "2"}, "-ERR wrong number of arguments for 'lpop' command\r\n"},
+		{[]string{"SET", "s", "v"}, "+OK\r\n"},
+		{[]string{"LPUSH", "s", "a"}, wrongType},
+		{[]string{"LPOP", "s"}, wrongType},
+		{[]string{"LLEN", "s"}, wrongType},
+		{[]string{"GET", "l"}, "$-1\r\n"},
+	})
+}
+
+func TestListIndexes(t *testing.T) {
+	h := NewHandler()
+	defer h.Close()
+	c := newTestClient(h)
+
+	runCases(t, h, c, []cmdCase{
+		{[]string{"RPUSH", "l", "a", "b", "c", "d", "e"}, ":5\r\n"},
+		{[]string{"LRANGE", "l", "1", "2"}, "*2\r\n$1\r\nb\r\n$1\r\nc\r\n"},
+		{[]string{"LRANGE", "l", "-2", "100"}, "*2\r\n$1\r\nd\r\n$1\r\ne\r\n"},
+		{[]string{"LRANGE", "l", "0", "-100"}, "*0\r\n"},
+		{[]string{"LRANGE", "l", "5", "10"}, "*0\r\n"},
+		{[]string{"LRANGE", "none", "0", "-1"}, "*0\r\n"},
+		{[]string{"LRANGE", "l", "a", "1"}, "-ERR value is not an integer or out of range\r\n"},
+		{[]string{"LINDEX", "l", "0"}, "$1\r\na\r\n"},
+		{[]string{"LINDEX", "l", "-1"}, "$1\r\ne\r\n"},
+		{[]string{"LINDEX", "l", "5"}, "$-1\r\n"},
+		{[]string{"LSET", "l", "-2", "D"}, "+OK\r\n"},
+		{[]string{"LSET", "l", "5", "x"}, "-ERR index out of range\r\n"},
+		{[]string{"LSET", "none", "0", "x"}, "-ERR no such key\r\n"},
+		{[]string{"LINSERT", "l", "BEFORE", "c", "x"}, ":6\r\n"},
+		{[]string{"LINSERT", "l", "after", "e", "y"}, ":7\r\n"},
+		{[]string{"LINSERT", "l", "AFTER", "none", "y"}, ":-1\r\n"},
+		{[]string{"LINSERT", "none", "AFTER", "a", "y"}, ":0\r\n"},
+		{[]string{"LINSERT", "l", "AROUND", "a", "y"}, "-ERR syntax error\r\n"},
+		{[]string{"LRANGE", "l", "0", "-1"}, "*7\r\n$1\r\na\r\n$1\r\nb\r\n$1\r\nx\r\n$1\r\nc\r\n$1\r\nD\r\n$1\r\ne\r\n$1\r\ny\r\n"},
+		{[]string{"LTRIM", "l", "1", "-2"}, "+OK\r\n"},
+		{[]string{"LRANGE", "l", "0", "-1"}, "*5\r\n$1\r\nb\r\n$1\r\nx\r\n$1\r\nc\r\n$1\r\nD\r\n$1\r\ne\r\n"},
+		{[]string{"LTRIM", "l", "2", "1"}, "+OK\r\n"},
+		{[]string{"LLEN", "l"}, ":0\r\n"},
+	})
+}
+
+func TestListRemPos(t *testing.T) {
+	h := NewHandler()
+	defer h.Close()
+	c := newTestClient(h)
+
+	runCases(t, h, c, []cmdCase{
+		{[]string{"RPUSH", "l", "a", "b", "c", "1", "2", "3", "c", "c"}, ":8\r\n"},
+		{[]string{"LPOS", "l", "c"}, ":2\r\n"},
+		{[]string{"LPOS", "l", "c", "RANK", "2"}, ":6\r\n"},
+		{[]string{"LPOS", "l", "c", "RANK", "-1"}, ":7\r\n"},
+		{[]string{"LPOS", "l", "c", "COUNT", "2"}, "*2\r\n:2\r\n:6\r\n"},
+		{[]string{"LPOS", "l", "c", "COUNT", "0"}, "*3\r\n:2\r\n:6\r\n:7\r\n"},
+		{[]string{"LPOS", "l", "c", "RANK", "-1", "COUNT", "2"}, "*2\r\n:7\r\n:6\r\n"},
+		{[]string{"LPOS", "l", "c", "COUNT", "0", "MAXLEN", "3"}, "*1\r\n:2\r\n"},
+		{[]string{"LPOS", "l", "c", "RANK", "4"}, "$-1\r\n"},
+		{[]string{"LPOS", "l", "x", "COUNT", "1"}, "*0\r\n"},
+		{[]string{"LPOS", "none", "x"}, "$-1\r\n"},
+		{[]string{"LPOS", "l", "c", "RANK", "0"}, "-ERR RANK can't be zero: use 1 to start from the first match, 2 from the second ... or use negative to start from the end of the list\r\n"},
+		{[]string{"LPOS", "l", "c", "COUNT", "-1"}, "-ERR COUNT can't be negative\r\n"},
+		{[]string{"LPOS", "l", "c", "MAXLEN", "-1"}, "-ERR MAXLEN can't be negative\r\n"},
+		{[]string{"LPOS", "l", "c", "COUNT"}, "-ERR syntax error\r\n"},
+		{[]string{"LREM", "l", "-1", "c"}, ":1\r\n"},
+		{[]string{"LREM", "l", "1", "c"}, ":1\r\n"},
+		{[]string{"LRANGE", "l", "0", "-1"}, "*6\r\n$1\r\na\r\n$1\r\nb\r\n$1\r\n1\r\n$1\r\n2\r\n$1\r\n3\r\n$1\r\nc\r\n"},
+		{[]string{"RPUSH", "l", "a", "a"}, ":8\r\n"},
+		{[]string{"LREM", "l", "0", "a"}, ":3\r\n"},
+		{[]string{"LREM", "none", "0", "a"}, ":0\r\n"},
+		{[]string{"LLEN", "l"}, ":5\r\n"},
+	})
+}
+
+func TestListMove(t *testing.T) {
+	h := NewHandler()
+	defer h.Close()
+	c := newTestClient(h)
+
+	runCases(t, h, c, []cmdCase{
+		{[]string{"RPUSH", "src", "a", "b", "c"}, ":3\r\n"},
+		{[]string{"LMOVE", "src", "dst", "RIGHT", "LEFT"}, "$1\r\nc\r\n"},
+		{[]string{"LMOVE", "src", "dst", "LEFT", "RIGHT"}, "$1\r\na\r\n"},
+		{[]string{"LRANGE", "dst", "0", "-1"}, "*2\r\n$1\r\nc\r\n$1\r\na\r\n"},
+		{[]string{"LMOVE", "dst", "dst", "LEFT", "RIGHT"}, "$1\r\nc\r\n"},
+		{[]string{"LRANGE", "dst", "0", "-1"}, "*2\r\n$1\r\na\r\n$1\r\nc\r\n"},
+		{[]string{"LMOVE", "src", "dst", "LEFT", "LEFT"}, "$1\r\nb\r\n"},
+		{[]string{"LLEN", "src"}, ":0\r\n"},
+		{[]string{"LMOVE", "src", "dst", "LEFT", "LEFT"}, "$-1\r\n"},
+		{[]string{"LMOVE", "dst", "dst", "UP", "LEFT"}, "-ERR syntax error\r\n"},
+		{[]string{"SET", "s", "v"}, "+OK\r\n"},
+		{[]string{"LMOVE", "dst", "s", "LEFT", "LEFT"}, wrongType},
+		{[]string{"LMOVE", "s", "dst", "LEFT", "LEFT"}, wrongType},
+		{[]string{"LLEN", "dst"}, ":3\r\n"},
+
+		{[]string{"LMPOP", "2", "none", "dst", "LEFT"}, "*2\r\n$3\r\ndst\r\n*1\r\n$1\r\nb\r\n"},
+		{[]string{"LMPOP", "1", "dst", "RIGHT", "COUNT", "10"}, "*2\r\n$3\r\ndst\r\n*2\r\n$1\r\nc\r\n$1\r\na\r\n"},
+		{[]string{"LMPOP", "1", "dst", "RIGHT"}, "*-1\r\n"},
+		{[]string{"LMPOP", "2", "none", "s", "LEFT"}, wrongType},
+		{[]string{"LMPOP", "0", "dst", "LEFT"}, "-ERR numkeys should be greater than 0\r\n"},
+		{[]string{"LMPOP", "3", "a", "b", "LEFT"}, "-ERR syntax error\r\n"},
+		{[]string{"LMPOP", "1", "a", "UP"}, "-ERR syntax error\r\n"},
+		{[]string{"LMPOP", "1", "a", "LEFT", "COUNT", "0"}, "-ERR count should be greater than 0\r\n"},
+		{[]string{"LMPOP", "1", "a", "LEFT", "COUNT", "1", "COUNT", "1"}, "-ERR syntax error\r\n"},
+	})
+}
@@ -0,0 +1,582 @@
+/*
+ * @Description: transactions, MULTI EXEC DISCARD WATCH UNWATCH
+ * @Autor: HTmonster
+ * @Date: 2026-10-19 23:58:36
+ */
+
+package server
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/HTmonster/redissgo/internal/reply"
+)
+
+func init() {
+	registerCommand("multi", execMulti, 1, flagNoQueue|flagFast)
+	registerCommand("exec", execExec, 1, flagNoQueue)
+	registerCommand("discard", execDiscard, 1, flagNoQueue|flagFast)
+	registerCommand("watch", execWatch, -2, flagNoQueue|flagFast)
+	registerCommand("unwatch", execUnwatch, 1, flagFast)
+}
+
+//------------ watched keys --------------
+
+// version of a key, increased by every write
+type keyVersion struct {
+	version  uint64
+	watchers int         // clients watching the key, and follows of other tables
+	writers  int         // commands writing the key now
+	held     int         // watchers which are follows of other tables
+	follows  []keyFollow // the key in the databases swapped in, see swapWatched
+}
+
+// the version of a key in the table of a database swapped in place of the
+// watched one, writes of the key there change the watched key too
+type keyFollow struct {
+	table   *watchTable
+	version uint64
+}
+
+// versions of the keys of a database which are watched or being written.
+// A write runs between beginWrite and endWrite, so a transaction holding the
+// lock of a watched key sees every write of it: either finished, with a new
+// version, or still running.
+type watchTable struct {
+	mu       sync.Mutex
+	keys     map[string]*keyVersion
+	epoch    uint64 // increased when all keys are removed, by FLUSHDB and FLUSHALL
+	flushing int    // whole database writes running now
+}
+
+/**
+ * @description: make an empty table
+ */
+func makeWatchTable() *watchTable {
+	return &watchTable{
+		keys: make(map[string]*keyVersion),
+	}
+}
+
+/**
+ * @description: get the version of a key, the lock must be held
+ */
+func (t *watchTable) getLocked(key string) *keyVersion {
+	v, ok := t.keys[key]
+	if !ok {
+		v = &keyVersion{}
+		t.keys[key] = v
+	}
+	return v
+}
+
+/**
+ * @description: drop a version nobody uses, the lock must be held
+ */
+func (t *watchTable) releaseLocked(key string, v *keyVersion) {
+	if v.watchers == 0 && v.writers == 0 {
+		delete(t.keys, key)
+	}
+}
+
+/**
+ * @description: start watching a key
+ * @param {string} key
+ * @return {*} version of the key, epoch of the database
+ */
+func (t *watchTable) watch(key string) (uint64, uint64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	v := t.getLocked(key)
+	v.watchers++
+	return v.version, t.epoch
+}
+
+/**
+ * @description: stop watching a key watched by watch, the follows are
+ *	dropped with the last client
+ */
+func (t *watchTable) unwatch(key string) {
+	t.mu.Lock()
+	v := t.keys[key]
+	v.watchers--
+	var follows []keyFollow
+	if v.watchers == v.held {
+		follows, v.follows = v.follows, nil
+	}
+	t.releaseLocked(key, v)
+	t.mu.Unlock()
+
+	// the tables are never locked together, except by swapWatched
+	for _, f := range follows {
+		f.table.unfollow(key)
+	}
+}
+
+/**
+ * @description: drop a follow of another table made by swapLocked
+ */
+func (t *watchTable) unfollow(key string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	v := t.keys[key]
+	v.watchers--
+	v.held--
+	t.releaseLocked(key, v)
+}
+
+/**
+ * @description: whether a watched key has been written since watch, or is being written
+ * @param {string} key
+ * @param {uint64} version
+ * @param {uint64} epoch
+ * @param {bool} existed FLUSHDB and FLUSHALL only change the keys existing
+ *	at watch, the keys created later have a new version anyway
+ * @return {*}
+ */
+func (t *watchTable) changed(key string, version, epoch uint64, existed bool) bool {
+	t.mu.Lock()
+	v := t.keys[key]
+	flushed := existed && (t.flushing > 0 || t.epoch != epoch)
+	if flushed || v.writers > 0 || v.version != version {
+		t.mu.Unlock()
+		return true
+	}
+	follows := append([]keyFollow(nil), v.follows...)
+	t.mu.Unlock()
+
+	for _, f := range follows {
+		if f.table.written(key, f.version) {
+			return true
+		}
+	}
+	return false
+}
+
+/**
+ * @description: whether a followed key has been written since version, or is being written
+ */
+func (t *watchTable) written(key string, version uint64) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	v := t.keys[key]
+	return v.writers > 0 || v.version != version
+}
+
+/**
+ * @description: start writing keys, called before their segments are locked
+ * @param {[]string} keys
+ * @return {*}
+ */
+func (t *watchTable) beginWrite(keys []string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, key := range keys {
+		t.getLocked(key).writers++
+	}
+}
+
+/**
+ * @description: end writing the keys given to beginWrite, after their segments are unlocked
+ * @param {[]string} keys
+ * @param {bool} modified increase the versions
+ * @return {*}
+ */
+func (t *watchTable) endWrite(keys []string, modified bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, key := range keys {
+		v := t.keys[key]
+		v.writers--
+		if modified {
+			v.version++
+		}
+		t.releaseLocked(key, v)
+	}
+}
+
+/**
+ * @description: start writing all keys, like beginWrite
+ */
+func (t *watchTable) beginWriteAll() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.flushing++
+}
+
+/**
+ * @description: end writing all keys, every watched key existing at watch is changed
+ */
+func (t *watchTable) endWriteAll() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.flushing--
+	t.epoch++
+}
+
+/**
+ * @description: SWAPDB changes the watched keys existing in either database,
+ *	the others follow the key in the database swapped in, so a later write
+ *	of it there changes them too. All segments of both databases must be locked.
+ * @param {*DB} first
+ * @param {*DB} second
+ * @return {*}
+ */
+func swapWatched(first, second *DB) {
+	if first == second {
+		return
+	}
+	if second.id < first.id {
+		first, second = second, first
+	}
+	first.watched.mu.Lock()
+	defer first.watched.mu.Unlock()
+	second.watched.mu.Lock()
+	defer second.watched.mu.Unlock()
+
+	exists := func(key string) bool {
+		_, inFirst := first.store().GetWithLock(key)
+		_, inSecond := second.store().GetWithLock(key)
+		return inFirst || inSecond
+	}
+	first.watched.swapLocked(second.watched, exists)
+	second.watched.swapLocked(first.watched, exists)
+}
+
+/**
+ * @description: see swapWatched, the locks of both tables must be held
+ * @param {*watchTable} other the table of the database swapped in
+ * @param {func(string) bool} exists
+ * @return {*}
+ */
+func (t *watchTable) swapLocked(other *watchTable, exists func(string) bool) {
+	for key, v := range t.keys {
+		if v.watchers == 0 {
+			continue
+		}
+		if exists(key) {
+			v.version++
+			continue
+		}
+		// follows are not followed again, a key only held by other
+		// tables is changed by the swaps above
+		if v.watchers == v.held || v.following(other) {
+			continue
+		}
+		f := other.getLocked(key)
+		f.watchers++
+		f.held++
+		v.follows = append(v.follows, keyFollow{table: other, version: f.version})
+	}
+}
+
+/**
+ * @description: whether the key follows the key of a table already
+ */
+func (v *keyVersion) following(table *watchTable) bool {
+	for _, f := range v.follows {
+		if f.table == table {
+			return true
+		}
+	}
+	return false
+}
+
+// a key watched by a client
+type watchedKey struct {
+	db      *DB // the database watched, not a locked view
+	index   int
+	key     string
+	version uint64
+	epoch   uint64
+	existed bool // an expired key is changed too
+}
+
+/**
+ * @description: stop watching all keys
+ */
+func (c *Client) unwatch() {
+	for _, w := range c.watched {
+		w.db.watched.unwatch(w.key)
+	}
+	c.watched = nil
+}
+
+/**
+ * @description: whether a watched key has changed, the keys must be locked
+ *	by the running transaction
+ */
+func (c *Client) watchedKeyChanged() bool {
+	for _, w := range c.watched {
+		db := c.selectDB(w.index)
+		// a database swapped in is followed by the watched table
+		if w.db.watched.changed(w.key, w.version, w.epoch, w.existed) {
+			return true
+		}
+		if _, ok := db.GetEntity(w.key); w.existed && !ok {
+			return true
+		}
+	}
+	return false
+}
+
+//------------ queue --------------
+
+// a command queued by MULTI
+type queuedCommand struct {
+	cmd    *command
+	params [][]byte // including the command name
+}
+
+/**
+ * @description: queue a command until EXEC
+ * @param {*command} cmd
+ * @param {[][]byte} params reused after the call, they are copied
+ * @return {*}
+ */
+func (c *Client) queueCommand(cmd *command, params [][]byte) reply.Reply {
+	copied := make([][]byte, len(params))
+	for i, param := range params {
+		copied[i] = copyBytes(param)
+	}
+	c.queued = append(c.queued, &queuedCommand{cmd: cmd, params: copied})
+	return reply.MakeQueuedReply()
+}
+
+/**
+ * @description: a command is rejected, EXEC discards the transaction
+ */
+func (c *Client) flagMultiError() {
+	if c.multi {
+		c.dirty = true
+	}
+}
+
+/**
+ * @description: leave the MULTI state, the watched keys are kept
+ */
+func (c *Client) discardMulti() {
+	c.multi = false
+	c.dirty = false
+	c.queued = nil
+}
+
+//------------ locks --------------
+
+// locks of a transaction in a database
+type txLocks struct {
+	all       bool // lock all segments, for commands without given keys
+	writeKeys []string
+	readKeys  []string
+}
+
+/**
+ * @description: locks of every database used by the queued commands and the
+ *	watched keys, SELECT is followed to find the database of each command
+ * @return {*} locks by database index
+ */
+func (c *Client) txPlan() map[int]*txLocks {
+	plan := make(map[int]*txLocks)
+	locksOf := func(index int) *txLocks {
+		locks, ok := plan[index]
+		if !ok {
+			locks = &txLocks{}
+			plan[index] = locks
+		}
+		return locks
+	}
+
+	for _, w := range c.watched {
+		locks := locksOf(w.index)
+		locks.readKeys = append(locks.readKeys, w.key)
+	}
+	index := c.dbIndex
+	for _, q := range c.queued {
+		cmd := q.cmd
+		switch {
+		case cmd.flags&flagMultiDB != 0:
+			for i := range c.handler.dbs {
+				locksOf(i).all = true
+			}
+		case cmd.name == "select":
+			if selected, errReply := parseDBIndex(c.handler, q.params[1]); errReply == nil {
+				index = selected
+			}
+		case cmd.hasKeys():
+			// some readers go through Update too, keys are always write locked
+			locks := locksOf(index)
+			locks.writeKeys = append(locks.writeKeys, cmd.extractKeys(q.params)...)
+		case cmd.flags&(flagWrite|flagReadonly) != 0:
+			// KEYS, SCAN, DBSIZE, FLUSHDB and so on
+			locksOf(index).all = true
+		}
+	}
+	return plan
+}
+
+/**
+ * @description: lock the databases of a plan. Databases are locked in the
+ *	order of their ids which never change, and their segments in index order,
+ *	so that transactions and SWAPDB cannot deadlock.
+ * @param {map[int]*txLocks} plan
+ * @return {*} locked views by database index, function to unlock them
+ */
+func (h *Handler) lockDBs(plan map[int]*txLocks) (map[int]*DB, func()) {
+	for {
+		dbs := make(map[int]*DB, len(plan))
+		h.dbsLock.RLock()
+		for index := range plan {
+			dbs[index] = h.dbs[index]
+		}
+		h.dbsLock.RUnlock()
+
+		indexes := make([]int, 0, len(plan))
+		for index := range plan {
+			indexes = append(indexes, index)
+		}
+		sort.Slice(indexes, func(i, j int) bool {
+			return dbs[indexes[i]].id < dbs[indexes[j]].id
+		})
+		for _, index := range indexes {
+			locks := plan[index]
+			if locks.all {
+				dbs[index].store().LockAll()
+			} else {
+				dbs[index].store().Locks(locks.writeKeys, locks.readKeys)
+			}
+		}
+		unlock := func() {
+			for i := len(indexes) - 1; i >= 0; i-- {
+				locks := plan[indexes[i]]
+				if locks.all {
+					dbs[indexes[i]].store().UnlockAll()
+				} else {
+					dbs[indexes[i]].store().Unlocks(locks.writeKeys, locks.readKeys)
+				}
+			}
+		}
+
+		// SWAPDB may have moved a database before it was locked
+		swapped := false
+		h.dbsLock.RLock()
+		for index, db := range dbs {
+			if h.dbs[index] != db {
+				swapped = true
+			}
+		}
+		h.dbsLock.RUnlock()
+		if swapped {
+			unlock()
+			continue
+		}
+
+		views := make(map[int]*DB, len(dbs))
+		for index, db := range dbs {
+			views[index] = db.lockedView()
+		}
+		return views, unlock
+	}
+}
+
+//------------ commands --------------
+
+/**
+ * @description: MULTI
+ */
+func execMulti(c *Client, args [][]byte) reply.Reply {
+	if c.multi {
+		c.flagMultiError()
+		return reply.MakeErrReply("ERR MULTI calls can not be nested")
+	}
+	c.multi = true
+	return reply.MakeOkReply()
+}
+
+/**
+ * @description: EXEC, the queued commands run with all their keys locked,
+ *	null if a watched key has changed
+ */
+func execExec(c *Client, args [][]byte) reply.Reply {
+	if !c.multi {
+		return reply.MakeErrReply("ERR EXEC without MULTI")
+	}
+	defer c.unwatch()
+	defer c.discardMulti()
+	if c.dirty {
+		return reply.MakeErrReply("EXECABORT Transaction discarded because of previous errors.")
+	}
+
+	h := c.handler
+	views, unlock := h.lockDBs(c.txPlan())
+	c.views = views
+	defer func() {
+		c.views = nil
+		unlock()
+	}()
+
+	if c.watchedKeyChanged() {
+		return reply.MakeNullArrayReply()
+	}
+	replies := make([]reply.Reply, len(c.queued))
+	for i, q := range c.queued {
+		c.db = c.selectDB(c.dbIndex)
+		replies[i] = h.call(c, q.cmd, q.params)
+	}
+	return reply.MakeArrayReply(replies)
+}
+
+/**
+ * @description: DISCARD
+ */
+func execDiscard(c *Client, args [][]byte) reply.Reply {
+	if !c.multi {
+		return reply.MakeErrReply("ERR DISCARD without MULTI")
+	}
+	c.discardMulti()
+	c.unwatch()
+	return reply.MakeOkReply()
+}
+
+/**
+ * @description: WATCH key [key ...]
+ */
+func execWatch(c *Client, args [][]byte) reply.Reply {
+	if c.multi {
+		c.flagMultiError()
+		return reply.MakeErrReply("ERR WATCH inside MULTI is not allowed")
+	}
+	for _, arg := range args {
+		key := string(arg)
+		watched := false
+		for _, w := range c.watched {
+			if w.db == c.db && w.key == key {
+				watched = true
+				break
+			}
+		}
+		if watched {
+			continue
+		}
+		// the version is taken first, a later write changes it
+		version, epoch := c.db.watched.watch(key)
+		_, existed := c.db.GetEntity(key)
+		c.watched = append(c.watched, &watchedKey{
+			db:      c.db,
+			index:   c.dbIndex,
+			key:     key,
+			version: version,
+			epoch:   epoch,
+			existed: existed,
+		})
+	}
+	return reply.MakeOkReply()
+}
+
+/**
+ * @description: UNWATCH
+ */
+func execUnwatch(c *Client, args [][]byte) reply.Reply {
+	c.unwatch()
+	return reply.MakeOkReply()
+}
@@ -0,0 +1,390 @@
+/*
+ * @Description:
+ * @Autor: HTmonster
+ * @Date: 2026-10-19 23:59:47
+ */
+package server
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/HTmonster/redissgo/client"
+)
+
+/**
+ * @description: integers in the bulk strings of an encoded reply
+ */
+func bulkInts(encoded string) []int {
+	var ints []int
+	lines := strings.Split(encoded, "\r\n")
+	for i, line := range lines {
+		if strings.HasPrefix(line, "$") && i+1 < len(lines) {
+			n, _ := strconv.Atoi(lines[i+1])
+			ints = append(ints, n)
+		}
+	}
+	return ints
+}
+
+func TestMultiExec(t *testing.T) {
+	h := NewHandler()
+	defer h.Close()
+	c := newTestClient(h)
+
+	runCases(t, h, c, []cmdCase{
+		{[]string{"EXEC"}, "-ERR EXEC without MULTI\r\n"},
+		{[]string{"DISCARD"}, "-ERR DISCARD without MULTI\r\n"},
+		{[]string{"MULTI"}, "+OK\r\n"},
+		{[]string{"SET", "k", "v"}, "+QUEUED\r\n"},
+		{[]string{"INCR", "k"}, "+QUEUED\r\n"},
+		{[]string{"GET", "k"}, "+QUEUED\r\n"},
+		{[]string{"EXEC"}, "*3\r\n+OK\r\n-ERR value is not an integer or out of range\r\n$1\r\nv\r\n"},
+		{[]string{"EXEC"}, "-ERR EXEC without MULTI\r\n"},
+		// an empty transaction
+		{[]string{"MULTI"}, "+OK\r\n"},
+		{[]string{"EXEC"}, "*0\r\n"},
+		// discarded commands never run
+		{[]string{"MULTI"}, "+OK\r\n"},
+		{[]string{"DEL", "k"}, "+QUEUED\r\n"},
+		{[]string{"DISCARD"}, "+OK\r\n"},
+		{[]string{"GET", "k"}, "$1\r\nv\r\n"},
+		// errors while queueing abort the transaction
+		{[]string{"MULTI"}, "+OK\r\n"},
+		{[]string{"SET", "k", "v2"}, "+QUEUED\r\n"},
+		{[]string{"NOSUCHCOMMAND"}, "-ERR unknown command 'NOSUCHCOMMAND', with args beginning with: \r\n"},
+		{[]string{"EXEC"}, "-EXECABORT Transaction discarded because of previous errors.\r\n"},
+		{[]string{"MULTI"}, "+OK\r\n"},
+		{[]string{"GET"}, "-ERR wrong number of arguments for 'get' command\r\n"},
+		{[]string{"SET", "k", "v2"}, "+QUEUED\r\n"},
+		{[]string{"EXEC"}, "-EXECABORT Transaction discarded because of previous errors.\r\n"},
+		{[]string{"MULTI"}, "+OK\r\n"},
+		{[]string{"MULTI"}, "-ERR MULTI calls can not be nested\r\n"},
+		{[]string{"SET", "k", "v2"}, "+QUEUED\r\n"},
+		{[]string{"EXEC"}, "-EXECABORT Transaction discarded because of previous errors.\r\n"},
+		{[]string{"MULTI"}, "+OK\r\n"},
+		{[]string{"WATCH", "k"}, "-ERR WATCH inside MULTI is not allowed\r\n"},
+		{[]string{"SET", "k", "v2"}, "+QUEUED\r\n"},
+		{[]string{"EXEC"}, "-EXECABORT Transaction discarded because of previous errors.\r\n"},
+		{[]string{"GET", "k"}, "$1\r\nv\r\n"},
+	})
+}
+
+func TestMultiExecDatabases(t *testing.T) {
+	h := NewHandler()
+	defer h.Close()
+	c := newTestClient(h)
+
+	runCases(t, h, c, []cmdCase{
+		{[]string{"SET", "a", "1"}, "+OK\r\n"},
+		{[]string{"MULTI"}, "+OK\r\n"},
+		{[]string{"KEYS", "*"}, "+QUEUED\r\n"},
+		{[]string{"SELECT", "1"}, "+QUEUED\r\n"},
+		{[]string{"SET", "b", "2"}, "+QUEUED\r\n"},
+		{[]string{"DBSIZE"}, "+QUEUED\r\n"},
+		{[]string{"EXEC"}, "*4\r\n*1\r\n$1\r\na\r\n+OK\r\n+OK\r\n:1\r\n"},
+		// the selected database is kept after EXEC
+		{[]string{"GET", "b"}, "$1\r\n2\r\n"},
+		{[]string{"MULTI"}, "+OK\r\n"},
+		{[]string{"MOVE", "b", "0"}, "+QUEUED\r\n"},
+		{[]string{"SWAPDB", "0", "1"}, "+QUEUED\r\n"},
+		{[]string{"COPY", "b", "c", "DB", "0"}, "+QUEUED\r\n"},
+		{[]string{"EXEC"}, "*3\r\n:1\r\n+OK\r\n:1\r\n"},
+		{[]string{"MGET", "a", "b"}, "*2\r\n$1\r\n1\r\n$1\r\n2\r\n"},
+		{[]string{"SELECT", "0"}, "+OK\r\n"},
+		{[]string{"KEYS", "*"}, "*1\r\n$1\r\nc\r\n"},
+		// commands of a transaction never block
+		{[]string{"MULTI"}, "+OK\r\n"},
+		{[]string{"BLPOP", "list", "0"}, "+QUEUED\r\n"},
+		{[]string{"FLUSHALL"}, "+QUEUED\r\n"},
+		{[]string{"EXEC"}, "*2\r\n*-1\r\n+OK\r\n"},
+		{[]string{"DBSIZE"}, ":0\r\n"},
+	})
+}
+
+func TestWatch(t *testing.T) {
+	h := NewHandler()
+	defer h.Close()
+	c := newTestClient(h)
+	other := newTestClient(h)
+
+	// run a transaction setting k after other runs its commands
+	checkAndSet := func(watched []string, others ...[]string) string {
+		t.Helper()
+		execString(h, c, append([]string{"WATCH"}, watched...)...)
+		for _, args := range others {
+			execString(h, other, args...)
+		}
+		execString(h, c, "MULTI")
+		execString(h, c, "SET", "k", "mine")
+		return execString(h, c, "EXEC")
+	}
+
+	execString(h, c, "SET", "ttl", "v", "EX", "100")
+	execString(h, c, "SADD", "set", "a")
+	execString(h, c, "HSET", "hash", "a", "1")
+	execString(h, c, "ZADD", "zset", "1", "a")
+	execString(h, c, "SET", "x", "1")
+	execString(h, other, "SELECT", "1")
+	execString(h, other, "SET", "x", "1")
+	execString(h, other, "SELECT", "0")
+
+	cases := []struct {
+		name    string
+		watched []string
+		others  [][]string
+		want    string
+	}{
+		{"unchanged", []string{"k"}, nil, "*1\r\n+OK\r\n"},
+		{"read by others", []string{"k"}, [][]string{{"GET", "k"}, {"EXISTS", "k"}}, "*1\r\n+OK\r\n"},
+		{"written", []string{"k"}, [][]string{{"SET", "k", "theirs"}}, "*-1\r\n"},
+		{"other key written", []string{"k"}, [][]string{{"SET", "x", "1"}}, "*1\r\n+OK\r\n"},
+		{"one of keys written", []string{"x", "k"}, [][]string{{"INCR", "x"}}, "*-1\r\n"},
+		{"created", []string{"new"}, [][]string{{"LPUSH", "new", "a"}}, "*-1\r\n"},
+		{"deleted", []string{"k"}, [][]string{{"DEL", "k"}}, "*-1\r\n"},
+		{"failed write", []string{"x"}, [][]string{{"LPUSH", "x", "a"}}, "*1\r\n+OK\r\n"},
+		// writes changing nothing
+		{"set if not exists", []string{"k"}, [][]string{{"SETNX", "k", "theirs"}, {"SET", "k", "theirs", "NX"}, {"MSETNX", "k", "theirs"}}, "*1\r\n+OK\r\n"},
+		{"missing key deleted", []string{"none"}, [][]string{{"DEL", "none"}, {"UNLINK", "none"}, {"GETDEL", "none"}}, "*1\r\n+OK\r\n"},
+		{"expire not set", []string{"ttl"}, [][]string{{"EXPIRE", "ttl", "200", "NX"}, {"EXPIRE", "ttl", "200", "LT"}, {"PERSIST", "k"}}, "*1\r\n+OK\r\n"},
+		{"missing member removed", []string{"set", "hash", "zset"}, [][]string{{"SREM", "set", "b"}, {"HDEL", "hash", "b"}, {"ZREM", "zset", "b"}, {"SADD", "set", "a"}, {"ZADD", "zset", "NX", "2", "a"}}, "*1\r\n+OK\r\n"},
+		{"missing list popped", []string{"none"}, [][]string{{"LPOP", "none"}, {"RPUSHX", "none", "a"}, {"LMOVE", "none", "none", "LEFT", "LEFT"}}, "*1\r\n+OK\r\n"},
+		{"not renamed", []string{"k", "x"}, [][]string{{"RENAMENX", "k", "x"}, {"COPY", "k", "x"}, {"MOVE", "x", "1"}}, "*1\r\n+OK\r\n"},
+		{"member removed", []string{"set"}, [][]string{{"SREM", "set", "a", "b"}}, "*-1\r\n"},
+		{"flushed", []string{"k"}, [][]string{{"FLUSHDB"}}, "*-1\r\n"},
+		{"missing key flushed", []string{"k"}, [][]string{{"FLUSHDB"}}, "*1\r\n+OK\r\n"},
+		{"flushed all", []string{"k"}, [][]string{{"FLUSHALL"}}, "*-1\r\n"},
+		{"missing key swapped", []string{"k"}, [][]string{{"SWAPDB", "0", "1"}}, "*1\r\n+OK\r\n"},
+		{"swapped", []string{"k"}, [][]string{{"SWAPDB", "0", "1"}}, "*-1\r\n"},
+		{"swapped back", []string{"k"}, [][]string{{"SWAPDB", "0", "1"}, {"SWAPDB", "1", "0"}}, "*-1\r\n"},
+		{"moved in", []string{"k"}, [][]string{{"SELECT", "1"}, {"SET", "k", "1"}, {"MOVE", "k", "0"}, {"SELECT", "0"}}, "*-1\r\n"},
+		{"copied in", []string{"k"}, [][]string{{"SELECT", "1"}, {"SET", "k", "1"}, {"COPY", "k", "k", "DB", "0", "REPLACE"}, {"SELECT", "0"}}, "*-1\r\n"},
+		{"written after swap", []string{"new2"}, [][]string{{"SWAPDB", "0", "1"}, {"SET", "new2", "1"}, {"DEL", "new2"}}, "*-1\r\n"},
+	}
+	for _, tc := range cases {
+		if got := checkAndSet(tc.watched, tc.others...); got != tc.want {
+			t.Errorf("%s: got %q, want %q", tc.name, got, tc.want)
+		}
+	}
+
+	// UNWATCH, DISCARD and EXEC forget the watched keys
+	execString(h, c, "WATCH", "k")
+	execString(h, c, "UNWATCH")
+	execString(h, other, "SET", "k", "theirs")
+	execString(h, c, "MULTI")
+	if got := execString(h, c, "EXEC"); got != "*0\r\n" {
+		t.Errorf("unwatch: got %q", got)
+	}
+	execString(h, c, "WATCH", "k")
+	execString(h, c, "MULTI")
+	execString(h, c, "DISCARD")
+	execString(h, other, "SET", "k", "theirs")
+	execString(h, c, "MULTI")
+	if got := execString(h, c, "EXEC"); got != "*0\r\n" {
+		t.Errorf("discard: got %q", got)
+	}
+	for i := 0; i < 2; i++ {
+		if len(h.selectDB(i).watched.keys) != 0 {
+			t.Errorf("watch table %d should be empty: %d", i, len(h.selectDB(i).watched.keys))
+		}
+	}
+
+	// an expired key is changed
+	execString(h, c, "SET", "k", "v", "PX", "20")
+	execString(h, c, "WATCH", "k")
+	time.Sleep(40 * time.Millisecond)
+	execString(h, c, "MULTI")
+	if got := execString(h, c, "EXEC"); got != "*-1\r\n" {
+		t.Errorf("expired: got %q", got)
+	}
+}
+
+func TestWatchCheckAndSetConcurrent(t *testing.T) {
+	h := NewHandler()
+	defer h.Close()
+	c := newTestClient(h)
+	stock := 200
+	execString(h, c, "SET", "stock", strconv.Itoa(stock))
+
+	// clients take items with WATCH GET MULTI SET EXEC and retry on conflicts,
+	// no item is taken twice
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	taken := 0
+	for w := 0; w < 10; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c := newTestClient(h)
+			for {
+				execString(h, c, "WATCH", "stock")
+				n := bulkInts(execString(h, c, "GET", "stock"))[0]
+				if n == 0 {
+					execString(h, c, "UNWATCH")
+					return
+				}
+				execString(h, c, "MULTI")
+				execString(h, c, "SET", "stock", strconv.Itoa(n-1))
+				execString(h, c, "LPUSH", "log", strconv.Itoa(n))
+				if execString(h, c, "EXEC") != "*-1\r\n" {
+					mu.Lock()
+					taken++
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if taken != stock {
+		t.Errorf("taken %d items of %d", taken, stock)
+	}
+	if got := execString(h, c, "LLEN", "log"); got != ":"+strconv.Itoa(stock)+"\r\n" {
+		t.Errorf("log length: %q", got)
+	}
+}
+
+func TestMultiExecIsolation(t *testing.T) {
+	h := NewHandler()
+	defer h.Close()
+	c := newTestClient(h)
+	accounts := 10
+	for i := 0; i < accounts; i++ {
+		execString(h, c, "SET", "account"+strconv.Itoa(i), "100")
+	}
+
+	// transfers run in transactions, readers see all or nothing of them
+	var wg sync.WaitGroup
+	for w := 0; w < 10; w++ {
+		wg.Add(2)
+		go func(w int) {
+			defer wg.Done()
+			c := newTestClient(h)
+			for i := 0; i < 50; i++ {
+				from := "account" + strconv.Itoa((w+i)%accounts)
+				to := "account" + strconv.Itoa((w+i+1)%accounts)
+				execString(h, c, "MULTI")
+				execString(h, c, "DECRBY", from, "7")
+				execString(h, c, "INCRBY", to, "7")
+				execString(h, c, "EXEC")
+			}
+		}(w)
+		go func() {
+			defer wg.Done()
+			c := newTestClient(h)
+			for i := 0; i < 50; i++ {
+				execString(h, c, "MULTI")
+				for j := 0; j < accounts; j++ {
+					execString(h, c, "GET", "account"+strconv.Itoa(j))
+				}
+				total := 0
+				for _, n := range bulkInts(execString(h, c, "EXEC")) {
+					total += n
+				}
+				if total != 100*accounts {
+					t.Errorf("transaction saw a partial transfer: %d", total)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestMultiExecServesBlocked(t *testing.T) {
+	h, addr, stop := startTestServer(t)
+	defer stop()
+	blocked, tx := dial(t, addr), dial(t, addr)
+	defer blocked.Close()
+	defer tx.Close()
+
+	r := doAsync(blocked, "BLPOP", "q", "0")
+	waitBlocked(t, h, 0, "q", 1)
+
+	// the blocked client is served after EXEC, with the result of all commands
+	for _, args := range [][]interface{}{{"MULTI"}, {"RPUSH", "q", "a"}, {"RPUSH", "q", "b"}} {
+		if _, err := tx.Do(args[0].(string), args[1:]...); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := tx.Do("EXEC"); err != nil {
+		t.Fatal(err)
+	}
+	expectReply(t, r, keyValue("q", "a"))
+	if n, err := client.Int64(tx.Do("LLEN", "q")); err != nil || n != 1 {
+		t.Errorf("LLEN: %d %v", n, err)
+	}
+}
+
+func TestMultiExecSwapDBConcurrent(t *testing.T) {
+	h := NewHandler()
+	defer h.Close()
+
+	// transactions on several databases and SWAPDB never deadlock
+	var wg sync.WaitGroup
+	for w := 0; w < 8; w++ {
+		wg.Add(2)
+		go func(w int) {
+			defer wg.Done()
+			c := newTestClient(h)
+			for i := 0; i < 50; i++ {
+				execString(h, c, "SWAPDB", strconv.Itoa(w%4), strconv.Itoa((w+i)%4))
+			}
+		}(w)
+		go func(w int) {
+			defer wg.Done()
+			c := newTestClient(h)
+			key := "k" + strconv.Itoa(w)
+			for i := 0; i < 50; i++ {
+				execString(h, c, "MULTI")
+				execString(h, c, "SELECT", strconv.Itoa(i%4))
+				execString(h, c, "SET", key, "v")
+				execString(h, c, "SELECT", strconv.Itoa((i+1)%4))
+				execString(h, c, "DEL", key)
+				execString(h, c, "SELECT", strconv.Itoa(i%4))
+				if got := execString(h, c, "EXEC"); got != "*5\r\n+OK\r\n+OK\r\n+OK\r\n:0\r\n+OK\r\n" && got != "*5\r\n+OK\r\n+OK\r\n+OK\r\n:1\r\n+OK\r\n" {
+					t.Errorf("EXEC: %q", got)
+					return
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+}
+
+func TestCommandKeys(t *testing.T) {
+	cases := []struct {
+		args []string
+		want []string
+	}{
+		{[]string{"GET", "a"}, []string{"a"}},
+		{[]string{"MSET", "a", "1", "b", "2"}, []string{"a", "b"}},
+		{[]string{"BLPOP", "a", "b", "0"}, []string{"a", "b"}},
+		{[]string{"BITOP", "AND", "d", "a", "b"}, []string{"d", "a", "b"}},
+		{[]string{"LMPOP", "2", "a", "b", "LEFT"}, []string{"a", "b"}},
+		{[]string{"LMPOP", "3", "a", "LEFT"}, nil},
+		{[]string{"BLMPOP", "0", "1", "a", "LEFT"}, []string{"a"}},
+		{[]string{"ZUNIONSTORE", "d", "2", "a", "b", "WEIGHTS", "1", "2"}, []string{"d", "a", "b"}},
+		{[]string{"XREAD", "COUNT", "2", "STREAMS", "a", "b", "0", "0"}, []string{"a", "b"}},
+		{[]string{"XREADGROUP", "GROUP", "streams", "c", "STREAMS", "k", "0"}, []string{"k"}},
+		{[]string{"XREADGROUP", "GROUP", "g", "streams", "NOACK", "STREAMS", "k", "0"}, []string{"k"}},
+		{[]string{"XREAD", "BLOCK", "0", "COUNT", "streams", "STREAMS", "a", "0"}, []string{"a"}},
+		{[]string{"XREAD", "STREAMX", "a", "0"}, nil},
+		{[]string{"XGROUP", "HELP"}, nil},
+		{[]string{"KEYS", "*"}, nil},
+	}
+	for _, tc := range cases {
+		params := make([][]byte, len(tc.args))
+		for i, arg := range tc.args {
+			params[i] = []byte(arg)
+		}
+		cmd, _ := lookupCommand(params[0])
+		if got := cmd.extractKeys(params); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("%q: got %q, want %q", tc.args, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+/*
+ * @Description: cursor based iteration commands
+ * @Autor: HTmonster
+ * @Date: 2026-10-19 01:14:09
+ */
+
+package server
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/HTmonster/redissgo/internal/reply"
+	"github.com/HTmonster/redissgo/internal/wildcard"
+)
+
+// default number of elements visited by one call
+const defaultScanCount = 10
+
+// options accepted besides MATCH and COUNT
+const (
+	scanAllowNoValues = 1 << iota // HSCAN NOVALUES
+	scanAllowType                 // SCAN TYPE
+)
+
+// options of the SCAN family
+type scanOptions struct {
+	cursor   uint64
+	pattern  []byte // nil matches everything
+	count    int
+	noValues bool   // HSCAN NOVALUES
+	typeName string // SCAN TYPE, empty matches every type
+}
+
+/**
+ * @description: parse "cursor [MATCH pattern] [COUNT count]" and extra options
+ * @param {[][]byte} args starting at the cursor
+ * @param {int} allowed extra options, scanAllowNoValues or scanAllowType
+ * @return {*}
+ */
+func parseScanOptions(args [][]byte, allowed int) (*scanOptions, reply.ErrorReply) {
+	cursor, err := strconv.ParseUint(string(args[0]), 10, 64)
+	if err != nil {
+		return nil, reply.MakeErrReply("ERR invalid cursor")
+	}
+	opts := &scanOptions{cursor: cursor, count: defaultScanCount}
+	for i := 1; i < len(args); i++ {
+		option := strings.ToLower(string(args[i]))
+		switch {
+		case option == "count" && i+1 < len(args):
+			count, ok := parseCanonicalInt(args[i+1])
+			if !ok {
+				return nil, reply.MakeNotIntegerErrReply()
+			}
+			if count < 1 {
+				return nil, reply.MakeSyntaxErrReply()
+			}
+			opts.count = int(count)
+			i++
+		case option == "match" && i+1 < len(args):
+			opts.pattern = copyBytes(args[i+1])
+			// match all, skip matching
+			if string(opts.pattern) == "*" {
+				opts.pattern = nil
+			}
+			i++
+		case option == "novalues" && allowed&scanAllowNoValues != 0:
+			opts.noValues = true
+		case option == "type" && i+1 < len(args) && allowed&scanAllowType != 0:
+			opts.typeName = strings.ToLower(string(args[i+1]))
+			if !knownTypeNames[opts.typeName] {
+				return nil, reply.MakeErrReply("ERR unknown type name '" + string(args[i+1]) + "'")
+			}
+			i++
+		default:
+			return nil, reply.MakeSyntaxErrReply()
+		}
+	}
+	return opts, nil
+}
+
+/**
+ * @description: check whether a key or member matches the MATCH pattern
+ */
+func (opts *scanOptions) match(s string) bool {
+	return opts.pattern == nil || wildcard.Match(opts.pattern, []byte(s))
+}
+
+/**
+ * @description: check whether a value matches the TYPE option
+ */
+func (opts *scanOptions) matchType(entity interface{}) bool {
+	return opts.typeName == "" || typeName(entity) == opts.typeName
+}
+
+/**
+ * @description: reply of the SCAN family, the next cursor and the elements
+ * @param {uint64} cursor
+ * @param {[][]byte} elements
+ * @return {*}
+ */
+func makeScanReply(cursor uint64, elements [][]byte) reply.Reply {
+	return reply.MakeArrayReply([]reply.Reply{
+		reply.MakeBulkReply([]byte(strconv.FormatUint(cursor, 10))),
+		reply.MakeMultiBulkReply(elements),
+	})
+}
+
+func init() {
+	registerCommand("scan", execScan, -2, flagReadonly)
+}
+
+/**
+ * @description: SCAN cursor [MATCH pattern] [COUNT count] [TYPE type]
+ */
+func execScan(c *Client, args [][]byte) reply.Reply {
+	opts, errReply := parseScanOptions(args, scanAllowType)
+	if errReply != nil {
+		return errReply
+	}
+	var keys [][]byte
+	cursor := c.db.Scan(opts.cursor, opts.count, func(key string, value interface{}) bool {
+		if opts.match(key) && opts.matchType(value) {
+			keys = append(keys, []byte(key))
+		}
+		return true
+	})
+	return makeScanReply(cursor, keys)
+}
@@ -0,0 +1,125 @@
+/*
+ * @Description:
+ * @Autor: HTmonster
+ * @Date: 2026-10-19 23:48:51
+ */
+package server
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/HTmonster/redissgo/internal/reply"
+)
+
+/**
+ * @description: run a SCAN family command from cursor 0 until the end
+ * @param {[]string} args the command, the cursor is inserted after the first cursorAt args
+ * @return {*} elements of every call, number of calls
+ */
+func scanAll(t *testing.T, h *Handler, c *Client, cursorAt int, args ...string) ([]string, int) {
+	t.Helper()
+	var elements []string
+	cursor, calls := "0", 0
+	for {
+		params := make([][]byte, 0, len(args)+1)
+		for i, arg := range args {
+			if i == cursorAt {
+				params = append(params, []byte(cursor))
+			}
+			params = append(params, []byte(arg))
+		}
+		if cursorAt == len(args) {
+			params = append(params, []byte(cursor))
+		}
+		r, ok := h.exec(c, params).(*reply.ArrayReply)
+		if !ok {
+			t.Fatalf("%q: got %q", args, h.exec(c, params).ToBytes())
+		}
+		cursor = string(r.Replies[0].(*reply.BulkReply).Arg)
+		for _, arg := range r.Replies[1].(*reply.MultiBulkReply).Args {
+			elements = append(elements, string(arg))
+		}
+		calls++
+		if cursor == "0" {
+			return elements, calls
+		}
+	}
+}
+
+func TestScan(t *testing.T) {
+	h := NewHandler()
+	defer h.Close()
+	c := newTestClient(h)
+
+	for i := 0; i < 1000; i++ {
+		execString(h, c, "SET", "key:"+strconv.Itoa(i), "v")
+	}
+	execString(h, c, "RPUSH", "key:list", "a")
+	execString(h, c, "SADD", "other", "a")
+
+	keys, calls := scanAll(t, h, c, 1, "SCAN", "COUNT", "100")
+	seen := make(map[string]bool)
+	for _, key := range keys {
+		seen[key] = true
+	}
+	if len(seen) != 1002 || calls < 2 {
+		t.Errorf("SCAN: %d keys in %d calls", len(seen), calls)
+	}
+
+	keys, _ = scanAll(t, h, c, 1, "SCAN", "MATCH", "key:1?")
+	sort.Strings(keys)
+	if strings.Join(keys, ",") != "key:10,key:11,key:12,key:13,key:14,key:15,key:16,key:17,key:18,key:19" {
+		t.Errorf("SCAN MATCH: %v", keys)
+	}
+	keys, _ = scanAll(t, h, c, 1, "SCAN", "TYPE", "LIST")
+	if strings.Join(keys, ",") != "key:list" {
+		t.Errorf("SCAN TYPE: %v", keys)
+	}
+	keys, _ = scanAll(t, h, c, 1, "SCAN", "MATCH", "key:*", "TYPE", "set")
+	if len(keys) != 0 {
+		t.Errorf("SCAN MATCH TYPE: %v", keys)
+	}
+
+	runCases(t, h, c, []cmdCase{
+		{[]string{"SCAN", "x"}, "-ERR invalid cursor\r\n"},
+		{[]string{"SCAN", "0", "TYPE", "nothing"}, "-ERR unknown type name 'nothing'\r\n"},
+		{[]string{"SCAN", "0", "NOVALUES"}, "-ERR syntax error\r\n"},
+		{[]string{"HSCAN", "h", "0", "TYPE", "hash"}, "-ERR syntax error\r\n"},
+	})
+}
+
+func TestScanWhileWriting(t *testing.T) {
+	h := NewHandler()
+	defer h.Close()
+	c := newTestClient(h)
+
+	for i := 0; i < 500; i++ {
+		execString(h, c, "SET", strconv.Itoa(i), "v")
+	}
+	// keys kept during the scan are returned, whatever is added or removed
+	seen := make(map[string]bool)
+	cursor, round := "0", 0
+	for {
+		r := h.exec(c, [][]byte{[]byte("SCAN"), []byte(cursor), []byte("COUNT"), []byte("20")}).(*reply.ArrayReply)
+		cursor = string(r.Replies[0].(*reply.BulkReply).Arg)
+		for _, key := range r.Replies[1].(*reply.MultiBulkReply).Args {
+			seen[string(key)] = true
+		}
+		for i := 0; i < 10; i++ {
+			execString(h, c, "SET", "new:"+strconv.Itoa(round)+":"+strconv.Itoa(i), "v")
+			execString(h, c, "DEL", "new:"+strconv.Itoa(round-1)+":"+strconv.Itoa(i))
+		}
+		round++
+		if cursor == "0" {
+			break
+		}
+	}
+	for i := 0; i < 500; i++ {
+		if !seen[strconv.Itoa(i)] {
+			t.Errorf("SCAN missed key %d", i)
+		}
+	}
+}
@@ -31,7 +31,7 @@ func SetupAndListen(addr string, port int) error {
 	ListenAndServe<────[closeChan]<────────┘*/
 
 	// close signal
-	signalChan := make(chan os.Signal)
+	signalChan := make(chan os.Signal, 1)
 	signal.Notify(signalChan,
 		syscall.SIGHUP,  //hong up
 		syscall.SIGINT,  //interrupt ctrl-C
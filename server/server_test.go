@@ -6,12 +6,12 @@
 package server
 
 import (
-	"fmt"
-	"net"
 	"os"
 	"syscall"
 	"testing"
 	"time"
+
+	"github.com/HTmonster/redissgo/client"
 )
 
 func TestServer(t *testing.T) {
@@ -34,13 +34,17 @@ func TestServer(t *testing.T) {
 
 	time.Sleep(1 * time.Second)
 	// client
-	conn, err := net.Dial("tcp", ":6379")
+	conn, err := client.Dial(":6379")
 	if err != nil {
-		t.Error("error connecting to server: ", err)
+		t.Fatal("error connecting to server: ", err)
 	}
 	defer conn.Close()
 
-	if _, err := fmt.Fprint(conn, msg); err != nil {
+	reply, err := client.String(conn.Do("ECHO", msg))
+	if err != nil {
 		t.Fatal(err)
 	}
+	if reply != msg {
+		t.Errorf("got %q, want %q", reply, msg)
+	}
 }
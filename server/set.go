@@ -0,0 +1,600 @@
+/*
+ * @Description: set commands
+ * @Autor: HTmonster
+ * @Date: 2026-10-19 10:24:58
+ */
+
+package server
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"strings"
+
+	"github.com/HTmonster/redissgo/datastruct/dict"
+	"github.com/HTmonster/redissgo/datastruct/set"
+	"github.com/HTmonster/redissgo/internal/config"
+	"github.com/HTmonster/redissgo/internal/reply"
+)
+
+func init() {
+	registerCommand("sadd", execSAdd, -3, flagWrite|flagFast).keys(1, 1, 1)
+	registerCommand("srem", execSRem, -3, flagWrite|flagFast).keys(1, 1, 1)
+	registerCommand("sismember", execSIsMember, 3, flagReadonly|flagFast).keys(1, 1, 1)
+	registerCommand("smismember", execSMIsMember, -3, flagReadonly|flagFast).keys(1, 1, 1)
+	registerCommand("smembers", execSMembers, 2, flagReadonly).keys(1, 1, 1)
+	registerCommand("scard", execSCard, 2, flagReadonly|flagFast).keys(1, 1, 1)
+	registerCommand("spop", execSPop, -2, flagWrite|flagFast).keys(1, 1, 1)
+	registerCommand("srandmember", execSRandMember, -2, flagReadonly).keys(1, 1, 1)
+	registerCommand("smove", execSMove, 4, flagWrite|flagFast).keys(1, 2, 1)
+	registerCommand("sinter", execSInter, -2, flagReadonly).keys(1, -1, 1)
+	registerCommand("sinterstore", execSInterStore, -3, flagWrite).keys(1, -1, 1)
+	registerCommand("sintercard", execSInterCard, -3, flagReadonly).keysBy(numKeysFinder(1, false))
+	registerCommand("sunion", execSUnion, -2, flagReadonly).keys(1, -1, 1)
+	registerCommand("sunionstore", execSUnionStore, -3, flagWrite).keys(1, -1, 1)
+	registerCommand("sdiff", execSDiff, -2, flagReadonly).keys(1, -1, 1)
+	registerCommand("sdiffstore", execSDiffStore, -3, flagWrite).keys(1, -1, 1)
+	registerCommand("sscan", execSScan, -3, flagReadonly).keys(1, 1, 1)
+}
+
+/**
+ * @description: make an empty set with the configured thresholds
+ */
+func makeSet() *set.Set {
+	return set.MakeSet(config.Properties.SetMaxIntsetEntries)
+}
+
+/**
+ * @description: run fn on the set at key under the segment lock.
+ *	Sets are modified in place, so even readers must hold the lock.
+ *	The key is removed when the set becomes empty.
+ * @param {string} key
+ * @param {bool} create create an empty set if the key does not exist
+ * @param {func} fn gets nil if the key does not exist and create is false
+ * @return {*} reply of fn, or WRONGTYPE
+ */
+func (db *DB) updateSet(key string, create bool, fn func(s *set.Set) reply.Reply) reply.Reply {
+	var result reply.Reply
+	db.Update(key, func(entry *dict.Entry) {
+		var s *set.Set
+		if entry.Exists {
+			var ok bool
+			if s, ok = entry.Value.(*set.Set); !ok {
+				result = reply.MakeWrongTypeErrReply()
+				return
+			}
+		} else if create {
+			s = makeSet()
+		}
+		result = fn(s)
+		if s != nil {
+			entry.Value, entry.Exists = s, s.Len() > 0
+		}
+	})
+	return result
+}
+
+/**
+ * @description: get a copy of the set at key, which can be read without locks
+ * @return {*} nil if the key does not exist, WRONGTYPE
+ */
+func (db *DB) getSetCopy(key string) (*set.Set, reply.ErrorReply) {
+	var clone *set.Set
+	result := db.updateSet(key, false, func(s *set.Set) reply.Reply {
+		if s != nil {
+			clone = s.Clone()
+		}
+		return nil
+	})
+	if errReply, ok := result.(reply.ErrorReply); ok {
+		return nil, errReply
+	}
+	return clone, nil
+}
+
+/**
+ * @description: reply members as a set, an array in RESP2
+ */
+func makeMembersReply(members []string) reply.Reply {
+	replies := make([]reply.Reply, len(members))
+	for i, member := range members {
+		replies[i] = reply.MakeBulkReply([]byte(member))
+	}
+	return reply.MakeSetReply(replies)
+}
+
+/**
+ * @description: SADD key member [member ...]
+ */
+func execSAdd(c *Client, args [][]byte) reply.Reply {
+	return c.db.updateSet(string(args[0]), true, func(s *set.Set) reply.Reply {
+		var added int64
+		for _, member := range args[1:] {
+			added += int64(s.Add(string(member)))
+		}
+		return c.changesReply(added)
+	})
+}
+
+/**
+ * @description: SREM key member [member ...]
+ */
+func execSRem(c *Client, args [][]byte) reply.Reply {
+	return c.db.updateSet(string(args[0]), false, func(s *set.Set) reply.Reply {
+		if s == nil {
+			return c.changesReply(0)
+		}
+		var removed int64
+		for _, member := range args[1:] {
+			removed += int64(s.Remove(string(member)))
+		}
+		return c.changesReply(removed)
+	})
+}
+
+/**
+ * @description: SISMEMBER key member
+ */
+func execSIsMember(c *Client, args [][]byte) reply.Reply {
+	return c.db.updateSet(string(args[0]), false, func(s *set.Set) reply.Reply {
+		if s != nil && s.Contains(string(args[1])) {
+			return reply.MakeIntReply(1)
+		}
+		return reply.MakeIntReply(0)
+	})
+}
+
+/**
+ * @description: SMISMEMBER key member [member ...]
+ */
+func execSMIsMember(c *Client, args [][]byte) reply.Reply {
+	return c.db.updateSet(string(args[0]), false, func(s *set.Set) reply.Reply {
+		replies := make([]reply.Reply, len(args)-1)
+		for i, member := range args[1:] {
+			if s != nil && s.Contains(string(member)) {
+				replies[i] = reply.MakeIntReply(1)
+			} else {
+				replies[i] = reply.MakeIntReply(0)
+			}
+		}
+		return reply.MakeArrayReply(replies)
+	})
+}
+
+/**
+ * @description: SMEMBERS key
+ */
+func execSMembers(c *Client, args [][]byte) reply.Reply {
+	return c.db.updateSet(string(args[0]), false, func(s *set.Set) reply.Reply {
+		if s == nil {
+			return makeMembersReply(nil)
+		}
+		return makeMembersReply(s.Members())
+	})
+}
+
+/**
+ * @description: SCARD key
+ */
+func execSCard(c *Client, args [][]byte) reply.Reply {
+	return c.db.updateSet(string(args[0]), false, func(s *set.Set) reply.Reply {
+		if s == nil {
+			return reply.MakeIntReply(0)
+		}
+		return reply.MakeIntReply(int64(s.Len()))
+	})
+}
+
+/**
+ * @description: SPOP key [count]
+ */
+func execSPop(c *Client, args [][]byte) reply.Reply {
+	key := string(args[0])
+	if len(args) == 1 {
+		return c.db.updateSet(key, false, func(s *set.Set) reply.Reply {
+			if s == nil {
+				c.keepVersions()
+				return reply.MakeNullBulkReply()
+			}
+			member, _ := s.RandomMember()
+			s.Remove(member)
+			return reply.MakeBulkReply([]byte(member))
+		})
+	}
+
+	if len(args) > 2 {
+		return reply.MakeSyntaxErrReply()
+	}
+	count, ok := parseCanonicalInt(args[1])
+	if !ok || count < 0 {
+		return reply.MakeErrReply("ERR value is out of range, must be positive")
+	}
+	return c.db.updateSet(key, false, func(s *set.Set) reply.Reply {
+		if s == nil || count == 0 {
+			c.keepVersions()
+			return makeMembersReply(nil)
+		}
+		if count >= int64(s.Len()) {
+			// pop the whole set, the key is removed
+			members := s.Members()
+			for _, member := range members {
+				s.Remove(member)
+			}
+			return makeMembersReply(members)
+		}
+		members := make([]string, count)
+		for i := range members {
+			members[i], _ = s.RandomMember()
+			s.Remove(members[i])
+		}
+		return makeMembersReply(members)
+	})
+}
+
+/**
+ * @description: pick count members of the set, with repeats if allowRepeats,
+ *	else at most Len() distinct members
+ */
+func setRandomMembers(s *set.Set, count int, allowRepeats bool) []string {
+	if allowRepeats {
+		members := make([]string, count)
+		for i := range members {
+			members[i], _ = s.RandomMember()
+		}
+		return members
+	}
+
+	if count*3 > s.Len() {
+		// the sample is big, shuffle all members and keep the head
+		members := s.Members()
+		if count > len(members) {
+			count = len(members)
+		}
+		for i := 0; i < count; i++ {
+			j := i + rand.Intn(len(members)-i)
+			members[i], members[j] = members[j], members[i]
+		}
+		return members[:count]
+	}
+
+	// the sample is small, pick random members until enough distinct ones
+	seen := make(map[string]struct{}, count)
+	members := make([]string, 0, count)
+	for len(members) < count {
+		member, _ := s.RandomMember()
+		if _, ok := seen[member]; ok {
+			continue
+		}
+		seen[member] = struct{}{}
+		members = append(members, member)
+	}
+	return members
+}
+
+/**
+ * @description: SRANDMEMBER key [count]
+ */
+func execSRandMember(c *Client, args [][]byte) reply.Reply {
+	key := string(args[0])
+	if len(args) == 1 {
+		return c.db.updateSet(key, false, func(s *set.Set) reply.Reply {
+			if s == nil {
+				return reply.MakeNullBulkReply()
+			}
+			member, _ := s.RandomMember()
+			return reply.MakeBulkReply([]byte(member))
+		})
+	}
+
+	if len(args) > 2 {
+		return reply.MakeSyntaxErrReply()
+	}
+	count, ok := parseCanonicalInt(args[1])
+	if !ok {
+		return reply.MakeNotIntegerErrReply()
+	}
+	if count == math.MinInt64 {
+		return reply.MakeErrReply("ERR value is out of range, must be between -9223372036854775807 and 9223372036854775807")
+	}
+	return c.db.updateSet(key, false, func(s *set.Set) reply.Reply {
+		if s == nil || count == 0 {
+			return reply.MakeEmptyArrayReply()
+		}
+		var members []string
+		if count < 0 {
+			members = setRandomMembers(s, int(-count), true)
+		} else {
+			if count > int64(s.Len()) {
+				count = int64(s.Len())
+			}
+			members = setRandomMembers(s, int(count), false)
+		}
+		result := make([][]byte, len(members))
+		for i, member := range members {
+			result[i] = []byte(member)
+		}
+		return reply.MakeMultiBulkReply(result)
+	})
+}
+
+/**
+ * @description: SMOVE source destination member
+ */
+func execSMove(c *Client, args [][]byte) reply.Reply {
+	src, dest, member := string(args[0]), string(args[1]), string(args[2])
+	keys := []string{src, dest}
+	c.db.Locks(keys, nil)
+	defer c.db.Unlocks(keys, nil)
+
+	// check the types before removing anything from src
+	entity, exists := c.db.GetEntityWithLock(src)
+	if !exists {
+		return c.changesReply(0)
+	}
+	srcSet, ok := entity.(*set.Set)
+	if !ok {
+		return reply.MakeWrongTypeErrReply()
+	}
+	var destSet *set.Set
+	if entity, exists := c.db.GetEntityWithLock(dest); exists {
+		if destSet, ok = entity.(*set.Set); !ok {
+			return reply.MakeWrongTypeErrReply()
+		}
+	}
+	if src == dest {
+		c.keepVersions()
+		if srcSet.Contains(member) {
+			return reply.MakeIntReply(1)
+		}
+		return reply.MakeIntReply(0)
+	}
+
+	if srcSet.Remove(member) == 0 {
+		return c.changesReply(0)
+	}
+	if srcSet.Len() == 0 {
+		c.db.UpdateWithLock(src, func(entry *dict.Entry) {
+			entry.Exists = false
+		})
+	}
+	if destSet == nil {
+		destSet = makeSet()
+		c.db.UpdateWithLock(dest, func(entry *dict.Entry) {
+			entry.Value, entry.Exists = destSet, true
+		})
+	}
+	destSet.Add(member)
+	return reply.MakeIntReply(1)
+}
+
+/**
+ * @description: copy the sets of keys, a missing key is an empty set
+ * @return {*} sets, nil for missing keys; WRONGTYPE if any key is not a set
+ */
+func (db *DB) getSetCopies(keys [][]byte) ([]*set.Set, reply.ErrorReply) {
+	sets := make([]*set.Set, len(keys))
+	for i, key := range keys {
+		s, errReply := db.getSetCopy(string(key))
+		if errReply != nil {
+			return nil, errReply
+		}
+		sets[i] = s
+	}
+	return sets, nil
+}
+
+/**
+ * @description: intersect sets, stop when limit members are found
+ * @param {[]*set.Set} sets nil is an empty set
+ * @param {int} limit 0 means no limit
+ * @return {*}
+ */
+func intersectSets(sets []*set.Set, limit int) *set.Set {
+	result := makeSet()
+	for _, s := range sets {
+		if s == nil {
+			return result
+		}
+	}
+	// test the members of the smallest set against the others
+	sort.Slice(sets, func(i, j int) bool { return sets[i].Len() < sets[j].Len() })
+	sets[0].ForEach(func(member string) bool {
+		for _, s := range sets[1:] {
+			if !s.Contains(member) {
+				return true
+			}
+		}
+		result.Add(member)
+		return limit == 0 || result.Len() < limit
+	})
+	return result
+}
+
+/**
+ * @description: union sets
+ */
+func unionSets(sets []*set.Set) *set.Set {
+	result := makeSet()
+	for _, s := range sets {
+		if s == nil {
+			continue
+		}
+		s.ForEach(func(member string) bool {
+			result.Add(member)
+			return true
+		})
+	}
+	return result
+}
+
+/**
+ * @description: members of the first set which are not in the others
+ */
+func diffSets(sets []*set.Set) *set.Set {
+	result := makeSet()
+	if sets[0] == nil {
+		return result
+	}
+	sets[0].ForEach(func(member string) bool {
+		for _, s := range sets[1:] {
+			if s != nil && s.Contains(member) {
+				return true
+			}
+		}
+		result.Add(member)
+		return true
+	})
+	return result
+}
+
+// set algebra operations
+const (
+	setOpInter = iota
+	setOpUnion
+	setOpDiff
+)
+
+/**
+ * @description: apply the operation on the sets of keys
+ * @return {*} result set, WRONGTYPE
+ */
+func (db *DB) setAlgebra(keys [][]byte, op int) (*set.Set, reply.ErrorReply) {
+	sets, errReply := db.getSetCopies(keys)
+	if errReply != nil {
+		return nil, errReply
+	}
+	switch op {
+	case setOpInter:
+		return intersectSets(sets, 0), nil
+	case setOpUnion:
+		return unionSets(sets), nil
+	default:
+		return diffSets(sets), nil
+	}
+}
+
+/**
+ * @description: SINTER, SUNION and SDIFF
+ */
+func setAlgebraGeneric(c *Client, keys [][]byte, op int) reply.Reply {
+	result, errReply := c.db.setAlgebra(keys, op)
+	if errReply != nil {
+		return errReply
+	}
+	return makeMembersReply(result.Members())
+}
+
+/**
+ * @description: SINTERSTORE, SUNIONSTORE and SDIFFSTORE, dest is overwritten
+ */
+func setAlgebraStoreGeneric(c *Client, args [][]byte, op int) reply.Reply {
+	dest := string(args[0])
+	result, errReply := c.db.setAlgebra(args[1:], op)
+	if errReply != nil {
+		return errReply
+	}
+	if result.Len() == 0 {
+		// an empty result only removes dest
+		if c.db.Remove(dest) == 0 {
+			c.keepVersions()
+		}
+		return reply.MakeIntReply(0)
+	}
+	c.db.PutEntityWithExpire(dest, result, 0)
+	return reply.MakeIntReply(int64(result.Len()))
+}
+
+/**
+ * @description: SINTER key [key ...]
+ */
+func execSInter(c *Client, args [][]byte) reply.Reply {
+	return setAlgebraGeneric(c, args, setOpInter)
+}
+
+/**
+ * @description: SINTERSTORE destination key [key ...]
+ */
+func execSInterStore(c *Client, args [][]byte) reply.Reply {
+	return setAlgebraStoreGeneric(c, args, setOpInter)
+}
+
+/**
+ * @description: SUNION key [key ...]
+ */
+func execSUnion(c *Client, args [][]byte) reply.Reply {
+	return setAlgebraGeneric(c, args, setOpUnion)
+}
+
+/**
+ * @description: SUNIONSTORE destination key [key ...]
+ */
+func execSUnionStore(c *Client, args [][]byte) reply.Reply {
+	return setAlgebraStoreGeneric(c, args, setOpUnion)
+}
+
+/**
+ * @description: SDIFF key [key ...]
+ */
+func execSDiff(c *Client, args [][]byte) reply.Reply {
+	return setAlgebraGeneric(c, args, setOpDiff)
+}
+
+/**
+ * @description: SDIFFSTORE destination key [key ...]
+ */
+func execSDiffStore(c *Client, args [][]byte) reply.Reply {
+	return setAlgebraStoreGeneric(c, args, setOpDiff)
+}
+
+/**
+ * @description: SINTERCARD numkeys key [key ...] [LIMIT limit]
+ */
+func execSInterCard(c *Client, args [][]byte) reply.Reply {
+	numKeys, ok := parseCanonicalInt(args[0])
+	if !ok || numKeys <= 0 {
+		return reply.MakeErrReply("ERR numkeys should be greater than 0")
+	}
+	if numKeys > int64(len(args)-1) {
+		return reply.MakeErrReply("ERR Number of keys can't be greater than number of args")
+	}
+	keys, rest := args[1:numKeys+1], args[numKeys+1:]
+	var limit int64
+	for len(rest) > 0 {
+		if len(rest) < 2 || strings.ToLower(string(rest[0])) != "limit" {
+			return reply.MakeSyntaxErrReply()
+		}
+		if limit, ok = parseCanonicalInt(rest[1]); !ok || limit < 0 {
+			return reply.MakeErrReply("ERR LIMIT can't be negative")
+		}
+		rest = rest[2:]
+	}
+
+	sets, errReply := c.db.getSetCopies(keys)
+	if errReply != nil {
+		return errReply
+	}
+	return reply.MakeIntReply(int64(intersectSets(sets, int(limit)).Len()))
+}
+
+/**
+ * @description: SSCAN key cursor [MATCH pattern] [COUNT count]
+ */
+func execSScan(c *Client, args [][]byte) reply.Reply {
+	opts, errReply := parseScanOptions(args[1:], 0)
+	if errReply != nil {
+		return errReply
+	}
+	return c.db.updateSet(string(args[0]), false, func(s *set.Set) reply.Reply {
+		if s == nil {
+			return makeScanReply(0, nil)
+		}
+		var members [][]byte
+		cursor := s.Scan(opts.cursor, opts.count, func(member string) {
+			if opts.match(member) {
+				members = append(members, []byte(member))
+			}
+		})
+		return makeScanReply(cursor, members)
+	})
+}
@@ -0,0 +1,307 @@
+/*
+ * @Description:
+ * @Autor: HTmonster
+ * @Date: 2026-10-19 11:02:40
+ */
+package server
+
+import (
+	"sort"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/HTmonster/redissgo/datastruct/set"
+	"github.com/HTmonster/redissgo/internal/config"
+	"github.com/HTmonster/redissgo/internal/reply"
+)
+
+/**
+ * @description: execute a command replying members, return them sorted
+ */
+func execMembers(h *Handler, c *Client, args ...string) []string {
+	params := make([][]byte, len(args))
+	for i, arg := range args {
+		params[i] = []byte(arg)
+	}
+	var members []string
+	switch r := h.exec(c, params).(type) {
+	case *reply.SetReply:
+		for _, member := range r.Members {
+			members = append(members, string(member.(*reply.BulkReply).Arg))
+		}
+	case *reply.MultiBulkReply:
+		for _, member := range r.Args {
+			members = append(members, string(member))
+		}
+	}
+	sort.Strings(members)
+	return members
+}
+
+func TestSetCommands(t *testing.T) {
+	h := NewHandler()
+	defer h.Close()
+	c := newTestClient(h)
+	c.db = h.selectDB(0)
+
+	runCases(t, h, c, []cmdCase{
+		{[]string{"SADD", "s", "3", "1", "2", "1"}, ":3\r\n"},
+		{[]string{"SADD", "s", "2", "a"}, ":1\r\n"},
+		{[]string{"SCARD", "s"}, ":4\r\n"},
+		{[]string{"SCARD", "none"}, ":0\r\n"},
+		{[]string{"SISMEMBER", "s", "a"}, ":1\r\n"},
+		{[]string{"SISMEMBER", "s", "b"}, ":0\r\n"},
+		{[]string{"SISMEMBER", "none", "a"}, ":0\r\n"},
+		{[]string{"SMISMEMBER", "s", "a", "b", "1"}, "*3\r\n:1\r\n:0\r\n:1\r\n"},
+		{[]string{"SMISMEMBER", "none", "a"}, "*1\r\n:0\r\n"},
+		{[]string{"SREM", "s", "a", "b", "3"}, ":2\r\n"},
+		{[]string{"SCARD", "s"}, ":2\r\n"},
+		{[]string{"SMEMBERS", "none"}, "*0\r\n"},
+		{[]string{"SREM", "s", "1", "2"}, ":2\r\n"},
+		{[]string{"LLEN", "s"}, ":0\r\n"},
+		{[]string{"RPUSH", "list", "a"}, ":1\r\n"},
+		{[]string{"SADD", "list", "a"}, wrongType},
+		{[]string{"SMEMBERS", "list"}, wrongType},
+		{[]string{"SISMEMBER", "list", "a"}, wrongType},
+	})
+
+	// RESP3 set
+	c.SetProtocol(reply.RESP3)
+	runCases(t, h, c, []cmdCase{
+		{[]string{"SADD", "s3", "2", "1"}, ":2\r\n"},
+		{[]string{"SMEMBERS", "s3"}, "~2\r\n$1\r\n1\r\n$1\r\n2\r\n"},
+	})
+}
+
+func TestSetEncoding(t *testing.T) {
+	entries := config.Properties.SetMaxIntsetEntries
+	defer func() { config.Properties.SetMaxIntsetEntries = entries }()
+	config.Properties.SetMaxIntsetEntries = 4
+
+	h := NewHandler()
+	defer h.Close()
+	c := newTestClient(h)
+	c.db = h.selectDB(0)
+
+	encoding := func(key string) string {
+		entity, _ := c.db.GetEntity(key)
+		return entity.(*set.Set).Encoding()
+	}
+	execString(h, c, "SADD", "ids", "1", "2", "3", "4")
+	if encoding("ids") != set.EncodingIntset {
+		t.Errorf("test integer set encoding: %s", encoding("ids"))
+	}
+	execString(h, c, "SADD", "ids", "5")
+	if encoding("ids") != set.EncodingHashtable {
+		t.Errorf("test converted by entries: %s", encoding("ids"))
+	}
+	execString(h, c, "SADD", "mixed", "1", "a")
+	if encoding("mixed") != set.EncodingHashtable {
+		t.Errorf("test converted by string: %s", encoding("mixed"))
+	}
+}
+
+func TestSetPopRandom(t *testing.T) {
+	h := NewHandler()
+	defer h.Close()
+	c := newTestClient(h)
+	c.db = h.selectDB(0)
+
+	runCases(t, h, c, []cmdCase{
+		{[]string{"SPOP", "none"}, "$-1\r\n"},
+		{[]string{"SPOP", "none", "2"}, "*0\r\n"},
+		{[]string{"SRANDMEMBER", "none"}, "$-1\r\n"},
+		{[]string{"SRANDMEMBER", "none", "2"}, "*0\r\n"},
+		{[]string{"SADD", "s", "a"}, ":1\r\n"},
+		{[]string{"SRANDMEMBER", "s"}, "$1\r\na\r\n"},
+		{[]string{"SRANDMEMBER", "s", "-3"}, "*3\r\n$1\r\na\r\n$1\r\na\r\n$1\r\na\r\n"},
+		{[]string{"SRANDMEMBER", "s", "3"}, "*1\r\n$1\r\na\r\n"},
+		{[]string{"SRANDMEMBER", "s", "0"}, "*0\r\n"},
+		{[]string{"SRANDMEMBER", "s", "x"}, "-ERR value is not an integer or out of range\r\n"},
+		{[]string{"SRANDMEMBER", "s", "1", "2"}, "-ERR syntax error\r\n"},
+		{[]string{"SPOP", "s", "-1"}, "-ERR value is out of range, must be positive\r\n"},
+		{[]string{"SPOP", "s", "0"}, "*0\r\n"},
+		{[]string{"SPOP", "s"}, "$1\r\na\r\n"},
+		{[]string{"SCARD", "s"}, ":0\r\n"},
+	})
+
+	for _, n := range []int{10, 1000} {
+		key := "s" + strconv.Itoa(n)
+		for i := 0; i < n; i++ {
+			execString(h, c, "SADD", key, "m"+strconv.Itoa(i))
+		}
+		for _, count := range []int{3, n / 2, n} {
+			members := execMembers(h, c, "SRANDMEMBER", key, strconv.Itoa(count))
+			seen := make(map[string]bool)
+			for _, member := range members {
+				seen[member] = true
+			}
+			if len(members) != count || len(seen) != count {
+				t.Errorf("test srandmember %d of %d: got %d, %d distinct", count, n, len(members), len(seen))
+			}
+		}
+
+		popped := execMembers(h, c, "SPOP", key, "3")
+		popped = append(popped, execMembers(h, c, "SPOP", key, strconv.Itoa(n))...)
+		seen := make(map[string]bool)
+		for _, member := range popped {
+			seen[member] = true
+		}
+		if len(seen) != n {
+			t.Errorf("test spop %d: got %d distinct", n, len(seen))
+		}
+		if _, ok := c.db.GetEntity(key); ok {
+			t.Errorf("test spop should remove the empty set")
+		}
+	}
+}
+
+func TestSetMove(t *testing.T) {
+	h := NewHandler()
+	defer h.Close()
+	c := newTestClient(h)
+	c.db = h.selectDB(0)
+
+	runCases(t, h, c, []cmdCase{
+		{[]string{"SMOVE", "none", "dst", "a"}, ":0\r\n"},
+		{[]string{"SADD", "src", "a", "b"}, ":2\r\n"},
+		{[]string{"SMOVE", "src", "dst", "x"}, ":0\r\n"},
+		{[]string{"SMOVE", "src", "dst", "a"}, ":1\r\n"},
+		{[]string{"SMOVE", "src", "src", "b"}, ":1\r\n"},
+		{[]string{"SMOVE", "src", "src", "a"}, ":0\r\n"},
+		{[]string{"SMOVE", "src", "dst", "b"}, ":1\r\n"},
+		{[]string{"SCARD", "src"}, ":0\r\n"},
+		{[]string{"SET", "str", "v"}, "+OK\r\n"},
+		{[]string{"SMOVE", "dst", "str", "a"}, wrongType},
+		{[]string{"SMOVE", "str", "dst", "a"}, wrongType},
+		{[]string{"SISMEMBER", "dst", "a"}, ":1\r\n"},
+	})
+	if members := execMembers(h, c, "SMEMBERS", "dst"); len(members) != 2 || members[0] != "a" || members[1] != "b" {
+		t.Errorf("test smove members: %v", members)
+	}
+}
+
+func TestSetAlgebra(t *testing.T) {
+	h := NewHandler()
+	defer h.Close()
+	c := newTestClient(h)
+	c.db = h.selectDB(0)
+
+	execString(h, c, "SADD", "s1", "a", "b", "c", "1")
+	execString(h, c, "SADD", "s2", "b", "c", "d", "1")
+	execString(h, c, "SADD", "s3", "c", "1", "2")
+	execString(h, c, "SET", "str", "v")
+
+	cases := []struct {
+		args []string
+		want []string
+	}{
+		{[]string{"SINTER", "s1", "s2", "s3"}, []string{"1", "c"}},
+		{[]string{"SINTER", "s1", "none"}, nil},
+		{[]string{"SUNION", "s1", "s2", "none"}, []string{"1", "a", "b", "c", "d"}},
+		{[]string{"SDIFF", "s1", "s2"}, []string{"a"}},
+		{[]string{"SDIFF", "s1", "none"}, []string{"1", "a", "b", "c"}},
+		{[]string{"SDIFF", "none", "s1"}, nil},
+	}
+	for _, tc := range cases {
+		got := execMembers(h, c, tc.args...)
+		if len(got) != len(tc.want) {
+			t.Errorf("%q: got %v, want %v", tc.args, got, tc.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tc.want[i] {
+				t.Errorf("%q: got %v, want %v", tc.args, got, tc.want)
+				break
+			}
+		}
+	}
+
+	runCases(t, h, c, []cmdCase{
+		{[]string{"SINTER", "s1", "str"}, wrongType},
+		{[]string{"SINTER", "none", "str"}, wrongType},
+		{[]string{"SUNION", "str"}, wrongType},
+		{[]string{"SINTERSTORE", "dst", "s1", "s2"}, ":3\r\n"},
+		{[]string{"SCARD", "dst"}, ":3\r\n"},
+		{[]string{"SUNIONSTORE", "dst", "s3", "s3"}, ":3\r\n"},
+		{[]string{"EXPIRE", "dst", "100"}, ":1\r\n"},
+		{[]string{"SDIFFSTORE", "dst", "s1", "s2"}, ":1\r\n"},
+		{[]string{"TTL", "dst"}, ":-1\r\n"},
+		{[]string{"SDIFFSTORE", "dst", "s1", "s1"}, ":0\r\n"},
+		{[]string{"SCARD", "dst"}, ":0\r\n"},
+		{[]string{"SINTERSTORE", "str", "s1", "s2"}, ":3\r\n"},
+		{[]string{"SCARD", "str"}, ":3\r\n"},
+		{[]string{"SINTERCARD", "2", "s1", "s2"}, ":3\r\n"},
+		{[]string{"SINTERCARD", "2", "s1", "s2", "LIMIT", "2"}, ":2\r\n"},
+		{[]string{"SINTERCARD", "2", "s1", "s2", "LIMIT", "0"}, ":3\r\n"},
+		{[]string{"SINTERCARD", "2", "s1", "none"}, ":0\r\n"},
+		{[]string{"SINTERCARD", "0", "s1"}, "-ERR numkeys should be greater than 0\r\n"},
+		{[]string{"SINTERCARD", "3", "s1", "s2"}, "-ERR Number of keys can't be greater than number of args\r\n"},
+		{[]string{"SINTERCARD", "1", "s1", "LIMIT", "-1"}, "-ERR LIMIT can't be negative\r\n"},
+		{[]string{"SINTERCARD", "1", "s1", "LIMIT"}, "-ERR syntax error\r\n"},
+	})
+}
+
+func TestSScan(t *testing.T) {
+	h := NewHandler()
+	defer h.Close()
+	c := newTestClient(h)
+
+	runCases(t, h, c, []cmdCase{
+		{[]string{"SSCAN", "none", "0"}, "*2\r\n$1\r\n0\r\n*0\r\n"},
+		{[]string{"SADD", "small", "1", "2", "3"}, ":3\r\n"},
+		{[]string{"SSCAN", "small", "0", "MATCH", "[12]"}, "*2\r\n$1\r\n0\r\n*2\r\n$1\r\n1\r\n$1\r\n2\r\n"},
+		{[]string{"SSCAN", "small", "0", "NOVALUES"}, "-ERR syntax error\r\n"},
+		{[]string{"SET", "str", "v"}, "+OK\r\n"},
+		{[]string{"SSCAN", "str", "0"}, "-WRONGTYPE Operation against a key holding the wrong kind of value\r\n"},
+	})
+
+	for i := 0; i < 1000; i++ {
+		execString(h, c, "SADD", "big", "m"+strconv.Itoa(i))
+	}
+	members, calls := scanAll(t, h, c, 2, "SSCAN", "big", "COUNT", "50")
+	seen := make(map[string]bool)
+	for _, member := range members {
+		seen[member] = true
+	}
+	if len(seen) != 1000 || calls < 2 {
+		t.Errorf("SSCAN big set: %d members in %d calls", len(seen), calls)
+	}
+}
+
+func TestSetMoveConcurrent(t *testing.T) {
+	h := NewHandler()
+	defer h.Close()
+	c := newTestClient(h)
+	for i := 0; i < 100; i++ {
+		execString(h, c, "SADD", "a", "m"+strconv.Itoa(i))
+	}
+
+	// members move between overlapping pairs of keys, none is lost
+	keys := []string{"a", "b", "c"}
+	var wg sync.WaitGroup
+	for w := 0; w < 10; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			c := newTestClient(h)
+			for i := 0; i < 300; i++ {
+				src, dest := keys[(w+i)%3], keys[(w+i+1+w%2)%3]
+				execString(h, c, "SMOVE", src, dest, "m"+strconv.Itoa(i%100))
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	if members := execMembers(h, c, "SUNION", "a", "b", "c"); len(members) != 100 {
+		t.Errorf("test concurrent smove union: %d", len(members))
+	}
+	total := 0
+	for _, key := range keys {
+		total += len(execMembers(h, c, "SMEMBERS", key))
+	}
+	if total != 100 {
+		t.Errorf("test concurrent smove total: %d", total)
+	}
+}
@@ -0,0 +1,1015 @@
+/*
+ * @Description: sorted set commands
+ * @Autor: HTmonster
+ * @Date: 2026-10-19 15:06:11
+ */
+
+package server
+
+import (
+	"math"
+	"math/rand"
+	"strconv"
+	"strings"
+
+	"github.com/HTmonster/redissgo/datastruct/dict"
+	"github.com/HTmonster/redissgo/datastruct/set"
+	"github.com/HTmonster/redissgo/datastruct/sortedset"
+	"github.com/HTmonster/redissgo/internal/reply"
+)
+
+func init() {
+	registerCommand("zadd", execZAdd, -4, flagWrite|flagFast).keys(1, 1, 1)
+	registerCommand("zincrby", execZIncrBy, 4, flagWrite|flagFast).keys(1, 1, 1)
+	registerCommand("zrem", execZRem, -3, flagWrite|flagFast).keys(1, 1, 1)
+	registerCommand("zscore", execZScore, 3, flagReadonly|flagFast).keys(1, 1, 1)
+	registerCommand("zmscore", execZMScore, -3, flagReadonly|flagFast).keys(1, 1, 1)
+	registerCommand("zcard", execZCard, 2, flagReadonly|flagFast).keys(1, 1, 1)
+	registerCommand("zcount", execZCount, 4, flagReadonly|flagFast).keys(1, 1, 1)
+	registerCommand("zrank", execZRank, -3, flagReadonly|flagFast).keys(1, 1, 1)
+	registerCommand("zrevrank", execZRevRank, -3, flagReadonly|flagFast).keys(1, 1, 1)
+	registerCommand("zrange", execZRange, -4, flagReadonly).keys(1, 1, 1)
+	registerCommand("zrangestore", execZRangeStore, -5, flagWrite).keys(1, 2, 1)
+	registerCommand("zpopmin", execZPopMin, -2, flagWrite|flagFast).keys(1, 1, 1)
+	registerCommand("zpopmax", execZPopMax, -2, flagWrite|flagFast).keys(1, 1, 1)
+	registerCommand("bzpopmin", execBZPopMin, -3, flagWrite|flagFast).keys(1, -2, 1)
+	registerCommand("bzpopmax", execBZPopMax, -3, flagWrite|flagFast).keys(1, -2, 1)
+	registerCommand("zunionstore", execZUnionStore, -4, flagWrite).keysBy(numKeysFinder(2, true))
+	registerCommand("zinterstore", execZInterStore, -4, flagWrite).keysBy(numKeysFinder(2, true))
+	registerCommand("zrandmember", execZRandMember, -2, flagReadonly).keys(1, 1, 1)
+	registerCommand("zscan", execZScan, -3, flagReadonly).keys(1, 1, 1)
+}
+
+/**
+ * @description: run fn on the sorted set at key under the segment lock.
+ *	Sorted sets are modified in place, so even readers must hold the lock.
+ *	The key is removed when the sorted set becomes empty.
+ * @param {string} key
+ * @param {bool} create create an empty sorted set if the key does not exist
+ * @param {func} fn gets nil if the key does not exist and create is false
+ * @return {*} reply of fn, or WRONGTYPE
+ */
+func (db *DB) updateZSet(key string, create bool, fn func(zs *sortedset.SortedSet) reply.Reply) reply.Reply {
+	var result reply.Reply
+	db.Update(key, func(entry *dict.Entry) {
+		var zs *sortedset.SortedSet
+		if entry.Exists {
+			var ok bool
+			if zs, ok = entry.Value.(*sortedset.SortedSet); !ok {
+				result = reply.MakeWrongTypeErrReply()
+				return
+			}
+		} else if create {
+			zs = sortedset.Make()
+		}
+		result = fn(zs)
+		if zs != nil {
+			entry.Value, entry.Exists = zs, zs.Len() > 0
+		}
+	})
+	return result
+}
+
+/**
+ * @description: parse a score, inf is allowed but nan is not
+ * @param {[]byte} arg
+ * @return {*} score, ok or not
+ */
+func parseScore(arg []byte) (float64, bool) {
+	score, err := strconv.ParseFloat(string(arg), 64)
+	if err != nil && !isRangeErr(err) || math.IsNaN(score) {
+		return 0, false
+	}
+	return score, true
+}
+
+/**
+ * @description: overflowed floats are parsed as inf like strtod
+ */
+func isRangeErr(err error) bool {
+	numErr, ok := err.(*strconv.NumError)
+	return ok && numErr.Err == strconv.ErrRange
+}
+
+/**
+ * @description: parse a score border, e.g. 1.5 (1.5 -inf +inf
+ */
+func parseScoreBorder(arg []byte) (sortedset.ScoreBorder, bool) {
+	var border sortedset.ScoreBorder
+	if len(arg) > 0 && arg[0] == '(' {
+		border.Exclude = true
+		arg = arg[1:]
+	}
+	var ok bool
+	border.Value, ok = parseScore(arg)
+	return border, ok
+}
+
+/**
+ * @description: parse a score range
+ */
+func parseScoreRange(min, max []byte) (*sortedset.ScoreRange, reply.ErrorReply) {
+	minBorder, ok1 := parseScoreBorder(min)
+	maxBorder, ok2 := parseScoreBorder(max)
+	if !ok1 || !ok2 {
+		return nil, reply.MakeErrReply("ERR min or max is not a float")
+	}
+	return &sortedset.ScoreRange{Min: minBorder, Max: maxBorder}, nil
+}
+
+/**
+ * @description: parse a member border, e.g. [a (a - +
+ */
+func parseLexBorder(arg []byte) (sortedset.LexBorder, bool) {
+	if len(arg) == 1 && arg[0] == '-' {
+		return sortedset.LexBorder{Inf: -1}, true
+	}
+	if len(arg) == 1 && arg[0] == '+' {
+		return sortedset.LexBorder{Inf: 1}, true
+	}
+	if len(arg) > 0 && (arg[0] == '(' || arg[0] == '[') {
+		return sortedset.LexBorder{Value: string(arg[1:]), Exclude: arg[0] == '('}, true
+	}
+	return sortedset.LexBorder{}, false
+}
+
+/**
+ * @description: parse a lexicographical range
+ */
+func parseLexRange(min, max []byte) (*sortedset.LexRange, reply.ErrorReply) {
+	minBorder, ok1 := parseLexBorder(min)
+	maxBorder, ok2 := parseLexBorder(max)
+	if !ok1 || !ok2 {
+		return nil, reply.MakeErrReply("ERR min or max not valid string range item")
+	}
+	return &sortedset.LexRange{Min: minBorder, Max: maxBorder}, nil
+}
+
+/**
+ * @description: reply elements, scores are doubles in RESP3.
+ *	With scores, RESP3 replies an array of pairs, RESP2 a flat array.
+ */
+func makeElementsReply(c *Client, elements []sortedset.Element, withScores bool) reply.Reply {
+	if !withScores {
+		members := make([][]byte, len(elements))
+		for i, e := range elements {
+			members[i] = []byte(e.Member)
+		}
+		return reply.MakeMultiBulkReply(members)
+	}
+	result := make([]reply.Reply, 0, len(elements)*2)
+	for _, e := range elements {
+		pair := []reply.Reply{reply.MakeBulkReply([]byte(e.Member)), reply.MakeDoubleReply(e.Score)}
+		if c.protocol >= reply.RESP3 {
+			result = append(result, reply.MakeArrayReply(pair))
+		} else {
+			result = append(result, pair...)
+		}
+	}
+	return reply.MakeArrayReply(result)
+}
+
+//------------ ZADD --------------
+
+// flags of ZADD
+type zaddFlags struct {
+	nx, xx, gt, lt, ch, incr bool
+}
+
+/**
+ * @description: ZADD key [NX|XX] [GT|LT] [CH] [INCR] score member [score member ...]
+ */
+func execZAdd(c *Client, args [][]byte) reply.Reply {
+	key := string(args[0])
+	var flags zaddFlags
+	i := 1
+	for ; i < len(args); i++ {
+		switch strings.ToLower(string(args[i])) {
+		case "nx":
+			flags.nx = true
+		case "xx":
+			flags.xx = true
+		case "gt":
+			flags.gt = true
+		case "lt":
+			flags.lt = true
+		case "ch":
+			flags.ch = true
+		case "incr":
+			flags.incr = true
+		default:
+			goto pairs
+		}
+	}
+pairs:
+	pairs := args[i:]
+	if len(pairs) == 0 || len(pairs)%2 != 0 {
+		return reply.MakeSyntaxErrReply()
+	}
+	if flags.nx && flags.xx {
+		return reply.MakeErrReply("ERR XX and NX options at the same time are not compatible")
+	}
+	if (flags.gt && flags.nx) || (flags.lt && flags.nx) || (flags.gt && flags.lt) {
+		return reply.MakeErrReply("ERR GT, LT, and/or NX options at the same time are not compatible")
+	}
+	if flags.incr && len(pairs) > 2 {
+		return reply.MakeErrReply("ERR INCR option supports a single increment-element pair")
+	}
+	scores := make([]float64, len(pairs)/2)
+	for j := range scores {
+		score, ok := parseScore(pairs[2*j])
+		if !ok {
+			return reply.MakeErrReply("ERR value is not a valid float")
+		}
+		scores[j] = score
+	}
+
+	var added, updated int64
+	result := c.db.updateZSet(key, !flags.xx, func(zs *sortedset.SortedSet) reply.Reply {
+		if zs == nil {
+			// XX on a missing key
+			if flags.incr {
+				return reply.MakeNullBulkReply()
+			}
+			return reply.MakeIntReply(0)
+		}
+		var incrResult reply.Reply = reply.MakeNullBulkReply()
+		for j, score := range scores {
+			member := string(pairs[2*j+1])
+			cur, exists := zs.Score(member)
+			if exists {
+				if flags.nx {
+					continue
+				}
+				if flags.incr {
+					score += cur
+					if math.IsNaN(score) {
+						return reply.MakeErrReply("ERR resulting score is not a number (NaN)")
+					}
+				}
+				if (flags.gt && score <= cur) || (flags.lt && score >= cur) {
+					continue
+				}
+				if score != cur {
+					zs.Add(member, score)
+					updated++
+				}
+			} else {
+				if flags.xx {
+					continue
+				}
+				zs.Add(member, score)
+				added++
+			}
+			incrResult = reply.MakeDoubleReply(score)
+		}
+		if flags.incr {
+			return incrResult
+		}
+		if flags.ch {
+			return reply.MakeIntReply(added + updated)
+		}
+		return reply.MakeIntReply(added)
+	})
+	if added > 0 {
+		c.signalKeyReady(c.db, key)
+	} else if updated == 0 {
+		c.keepVersions()
+	}
+	return result
+}
+
+/**
+ * @description: ZINCRBY key increment member
+ */
+func execZIncrBy(c *Client, args [][]byte) reply.Reply {
+	incr, ok := parseScore(args[1])
+	if !ok {
+		return reply.MakeErrReply("ERR value is not a valid float")
+	}
+	key, member := string(args[0]), string(args[2])
+	var added bool
+	result := c.db.updateZSet(key, true, func(zs *sortedset.SortedSet) reply.Reply {
+		score, _ := zs.Score(member)
+		score += incr
+		if math.IsNaN(score) {
+			return reply.MakeErrReply("ERR resulting score is not a number (NaN)")
+		}
+		added = zs.Add(member, score)
+		return reply.MakeDoubleReply(score)
+	})
+	if added {
+		c.signalKeyReady(c.db, key)
+	}
+	return result
+}
+
+//------------ read and remove --------------
+
+/**
+ * @description: ZREM key member [member ...]
+ */
+func execZRem(c *Client, args [][]byte) reply.Reply {
+	return c.db.updateZSet(string(args[0]), false, func(zs *sortedset.SortedSet) reply.Reply {
+		if zs == nil {
+			return c.changesReply(0)
+		}
+		var removed int64
+		for _, member := range args[1:] {
+			if zs.Remove(string(member)) {
+				removed++
+			}
+		}
+		return c.changesReply(removed)
+	})
+}
+
+/**
+ * @description: ZSCORE key member
+ */
+func execZScore(c *Client, args [][]byte) reply.Reply {
+	return c.db.updateZSet(string(args[0]), false, func(zs *sortedset.SortedSet) reply.Reply {
+		if zs == nil {
+			return reply.MakeNullBulkReply()
+		}
+		score, ok := zs.Score(string(args[1]))
+		if !ok {
+			return reply.MakeNullBulkReply()
+		}
+		return reply.MakeDoubleReply(score)
+	})
+}
+
+/**
+ * @description: ZMSCORE key member [member ...]
+ */
+func execZMScore(c *Client, args [][]byte) reply.Reply {
+	return c.db.updateZSet(string(args[0]), false, func(zs *sortedset.SortedSet) reply.Reply {
+		replies := make([]reply.Reply, len(args)-1)
+		for i, member := range args[1:] {
+			replies[i] = reply.MakeNullBulkReply()
+			if zs == nil {
+				continue
+			}
+			if score, ok := zs.Score(string(member)); ok {
+				replies[i] = reply.MakeDoubleReply(score)
+			}
+		}
+		return reply.MakeArrayReply(replies)
+	})
+}
+
+/**
+ * @description: ZCARD key
+ */
+func execZCard(c *Client, args [][]byte) reply.Reply {
+	return c.db.updateZSet(string(args[0]), false, func(zs *sortedset.SortedSet) reply.Reply {
+		if zs == nil {
+			return reply.MakeIntReply(0)
+		}
+		return reply.MakeIntReply(zs.Len())
+	})
+}
+
+/**
+ * @description: ZCOUNT key min max
+ */
+func execZCount(c *Client, args [][]byte) reply.Reply {
+	r, errReply := parseScoreRange(args[1], args[2])
+	if errReply != nil {
+		return errReply
+	}
+	return c.db.updateZSet(string(args[0]), false, func(zs *sortedset.SortedSet) reply.Reply {
+		if zs == nil {
+			return reply.MakeIntReply(0)
+		}
+		return reply.MakeIntReply(zs.Count(r))
+	})
+}
+
+/**
+ * @description: ZRANK and ZREVRANK key member [WITHSCORE]
+ */
+func zrankGeneric(c *Client, args [][]byte, desc bool) reply.Reply {
+	if len(args) > 3 || (len(args) == 3 && strings.ToLower(string(args[2])) != "withscore") {
+		return reply.MakeSyntaxErrReply()
+	}
+	withScore := len(args) == 3
+	return c.db.updateZSet(string(args[0]), false, func(zs *sortedset.SortedSet) reply.Reply {
+		member := string(args[1])
+		var rank int64
+		ok := false
+		if zs != nil {
+			rank, ok = zs.Rank(member, desc)
+		}
+		if !ok {
+			if withScore {
+				return reply.MakeNullArrayReply()
+			}
+			return reply.MakeNullBulkReply()
+		}
+		if withScore {
+			score, _ := zs.Score(member)
+			return reply.MakeArrayReply([]reply.Reply{reply.MakeIntReply(rank), reply.MakeDoubleReply(score)})
+		}
+		return reply.MakeIntReply(rank)
+	})
+}
+
+/**
+ * @description: ZRANK key member [WITHSCORE]
+ */
+func execZRank(c *Client, args [][]byte) reply.Reply {
+	return zrankGeneric(c, args, false)
+}
+
+/**
+ * @description: ZREVRANK key member [WITHSCORE]
+ */
+func execZRevRank(c *Client, args [][]byte) reply.Reply {
+	return zrankGeneric(c, args, true)
+}
+
+//------------ ZRANGE --------------
+
+// parsed arguments of ZRANGE and ZRANGESTORE
+type zrangeSpec struct {
+	byScore, byLex bool
+	rev            bool
+	withScores     bool
+	offset, limit  int64 // limit < 0 means no limit
+
+	// by rank
+	start, stop int64
+	// by score or lex
+	r sortedset.Range
+}
+
+/**
+ * @description: parse "min max [BYSCORE|BYLEX] [REV] [LIMIT offset count] [WITHSCORES]"
+ * @param {[][]byte} args starting at min
+ * @param {bool} store ZRANGESTORE, WITHSCORES is not allowed
+ * @return {*}
+ */
+func parseZRangeSpec(args [][]byte, store bool) (*zrangeSpec, reply.ErrorReply) {
+	spec := &zrangeSpec{limit: -1}
+	hasLimit := false
+	for i := 2; i < len(args); i++ {
+		switch option := strings.ToLower(string(args[i])); {
+		case option == "withscores" && !store:
+			spec.withScores = true
+		case option == "limit" && i+2 < len(args):
+			offset, ok1 := parseCanonicalInt(args[i+1])
+			limit, ok2 := parseCanonicalInt(args[i+2])
+			if !ok1 || !ok2 {
+				return nil, reply.MakeNotIntegerErrReply()
+			}
+			spec.offset, spec.limit, hasLimit = offset, limit, true
+			i += 2
+		case option == "byscore":
+			spec.byScore = true
+		case option == "bylex":
+			spec.byLex = true
+		case option == "rev":
+			spec.rev = true
+		default:
+			return nil, reply.MakeSyntaxErrReply()
+		}
+	}
+	if spec.byScore && spec.byLex {
+		return nil, reply.MakeSyntaxErrReply()
+	}
+	if hasLimit && !spec.byScore && !spec.byLex {
+		return nil, reply.MakeErrReply("ERR syntax error, LIMIT is only supported in combination with either BYSCORE or BYLEX")
+	}
+	if spec.withScores && spec.byLex {
+		return nil, reply.MakeErrReply("ERR syntax error, WITHSCORES not supported in combination with BYLEX")
+	}
+
+	min, max := args[0], args[1]
+	if spec.rev && (spec.byScore || spec.byLex) {
+		// REV takes max before min
+		min, max = max, min
+	}
+	var errReply reply.ErrorReply
+	switch {
+	case spec.byScore:
+		spec.r, errReply = parseScoreRange(min, max)
+	case spec.byLex:
+		spec.r, errReply = parseLexRange(min, max)
+	default:
+		var ok1, ok2 bool
+		spec.start, ok1 = parseCanonicalInt(min)
+		spec.stop, ok2 = parseCanonicalInt(max)
+		if !ok1 || !ok2 {
+			errReply = reply.MakeNotIntegerErrReply()
+		}
+	}
+	if errReply != nil {
+		return nil, errReply
+	}
+	return spec, nil
+}
+
+/**
+ * @description: elements of the sorted set selected by the spec
+ */
+func (spec *zrangeSpec) elements(zs *sortedset.SortedSet) []sortedset.Element {
+	if spec.r != nil {
+		return zs.Range(spec.r, spec.offset, spec.limit, spec.rev)
+	}
+	size := zs.Len()
+	start, stop := spec.start, spec.stop
+	if start < 0 {
+		start += size
+	}
+	if stop < 0 {
+		stop += size
+	}
+	if start < 0 {
+		start = 0
+	}
+	if start > stop || start >= size {
+		return nil
+	}
+	if stop >= size {
+		stop = size - 1
+	}
+	return zs.RangeByRank(start, stop, spec.rev)
+}
+
+/**
+ * @description: ZRANGE key start stop [BYSCORE|BYLEX] [REV] [LIMIT offset count] [WITHSCORES]
+ */
+func execZRange(c *Client, args [][]byte) reply.Reply {
+	spec, errReply := parseZRangeSpec(args[1:], false)
+	if errReply != nil {
+		return errReply
+	}
+	return c.db.updateZSet(string(args[0]), false, func(zs *sortedset.SortedSet) reply.Reply {
+		if zs == nil {
+			return reply.MakeEmptyArrayReply()
+		}
+		return makeElementsReply(c, spec.elements(zs), spec.withScores)
+	})
+}
+
+/**
+ * @description: replace dest with a sorted set of the elements
+ * @return {*} number of elements
+ */
+func (c *Client) storeZSet(dest string, elements []sortedset.Element) int64 {
+	if len(elements) == 0 {
+		// an empty result only removes dest
+		if c.db.Remove(dest) == 0 {
+			c.keepVersions()
+		}
+		return 0
+	}
+	zs := sortedset.Make()
+	for _, e := range elements {
+		zs.Add(e.Member, e.Score)
+	}
+	c.db.PutEntityWithExpire(dest, zs, 0)
+	c.signalKeyReady(c.db, dest)
+	return zs.Len()
+}
+
+/**
+ * @description: ZRANGESTORE dst src min max [BYSCORE|BYLEX] [REV] [LIMIT offset count]
+ */
+func execZRangeStore(c *Client, args [][]byte) reply.Reply {
+	spec, errReply := parseZRangeSpec(args[2:], true)
+	if errReply != nil {
+		return errReply
+	}
+	var elements []sortedset.Element
+	result := c.db.updateZSet(string(args[1]), false, func(zs *sortedset.SortedSet) reply.Reply {
+		if zs != nil {
+			elements = spec.elements(zs)
+		}
+		return nil
+	})
+	if result != nil {
+		return result
+	}
+	return reply.MakeIntReply(c.storeZSet(string(args[0]), elements))
+}
+
+//------------ pop --------------
+
+/**
+ * @description: ZPOPMIN and ZPOPMAX key [count]
+ */
+func zpopGeneric(c *Client, args [][]byte, max bool) reply.Reply {
+	if len(args) > 2 {
+		return reply.MakeSyntaxErrReply()
+	}
+	count := int64(1)
+	if len(args) == 2 {
+		var ok bool
+		if count, ok = parseCanonicalInt(args[1]); !ok || count < 0 {
+			return reply.MakeErrReply("ERR value is out of range, must be positive")
+		}
+	}
+	return c.db.updateZSet(string(args[0]), false, func(zs *sortedset.SortedSet) reply.Reply {
+		if zs == nil || count == 0 {
+			c.keepVersions()
+			return reply.MakeEmptyArrayReply()
+		}
+		elements := zs.Pop(count, max)
+		if len(args) == 1 {
+			// a single element is always a flat pair
+			e := elements[0]
+			return reply.MakeArrayReply([]reply.Reply{reply.MakeBulkReply([]byte(e.Member)), reply.MakeDoubleReply(e.Score)})
+		}
+		return makeElementsReply(c, elements, true)
+	})
+}
+
+/**
+ * @description: ZPOPMIN key [count]
+ */
+func execZPopMin(c *Client, args [][]byte) reply.Reply {
+	return zpopGeneric(c, args, false)
+}
+
+/**
+ * @description: ZPOPMAX key [count]
+ */
+func execZPopMax(c *Client, args [][]byte) reply.Reply {
+	return zpopGeneric(c, args, true)
+}
+
+/**
+ * @description: pop one element from the first non empty sorted set of keys
+ * @return {*} reply of the key, member and score, nil if all are empty; WRONGTYPE
+ */
+func (db *DB) zpopFirst(keys []string, max bool) (reply.Reply, reply.ErrorReply) {
+	for _, key := range keys {
+		var element *sortedset.Element
+		result := db.updateZSet(key, false, func(zs *sortedset.SortedSet) reply.Reply {
+			if zs != nil {
+				element = &zs.Pop(1, max)[0]
+			}
+			return nil
+		})
+		if errReply, ok := result.(reply.ErrorReply); ok {
+			return nil, errReply
+		}
+		if element != nil {
+			return reply.MakeArrayReply([]reply.Reply{
+				reply.MakeBulkReply([]byte(key)),
+				reply.MakeBulkReply([]byte(element.Member)),
+				reply.MakeDoubleReply(element.Score),
+			}), nil
+		}
+	}
+	return nil, nil
+}
+
+/**
+ * @description: BZPOPMIN and BZPOPMAX key [key ...] timeout
+ */
+func blockingZPopGeneric(c *Client, args [][]byte, max bool) reply.Reply {
+	timeout, errReply := parseTimeout(args[len(args)-1])
+	if errReply != nil {
+		return errReply
+	}
+	keys := make([]string, len(args)-1)
+	for i, arg := range args[:len(args)-1] {
+		keys[i] = string(arg)
+	}
+
+	result, errReply := c.db.zpopFirst(keys, max)
+	if errReply != nil {
+		return errReply
+	}
+	if result != nil {
+		return result
+	}
+
+	w := &waiter{
+		db:   c.dbIndex,
+		keys: keys,
+		serve: func(db *DB, key string) (reply.Reply, bool) {
+			result, _ := db.zpopFirst([]string{key}, max)
+			return result, result != nil
+		},
+	}
+	return c.block(w, timeout, reply.MakeNullArrayReply())
+}
+
+/**
+ * @description: BZPOPMIN key [key ...] timeout
+ */
+func execBZPopMin(c *Client, args [][]byte) reply.Reply {
+	return blockingZPopGeneric(c, args, false)
+}
+
+/**
+ * @description: BZPOPMAX key [key ...] timeout
+ */
+func execBZPopMax(c *Client, args [][]byte) reply.Reply {
+	return blockingZPopGeneric(c, args, true)
+}
+
+//------------ ZUNIONSTORE and ZINTERSTORE --------------
+
+// aggregate functions
+const (
+	aggregateSum = iota
+	aggregateMin
+	aggregateMax
+)
+
+/**
+ * @description: combine two scores of a member
+ */
+func aggregateScores(aggregate int, a, b float64) float64 {
+	switch aggregate {
+	case aggregateMin:
+		return math.Min(a, b)
+	case aggregateMax:
+		return math.Max(a, b)
+	}
+	sum := a + b
+	// inf + -inf
+	if math.IsNaN(sum) {
+		return 0
+	}
+	return sum
+}
+
+/**
+ * @description: read the members and scores of a sorted set or a set,
+ *	members of a set have the score 1
+ * @return {*} elements, nil if the key does not exist; WRONGTYPE
+ */
+func (db *DB) getZSetSource(key string) ([]sortedset.Element, reply.ErrorReply) {
+	var elements []sortedset.Element
+	var errReply reply.ErrorReply
+	db.Update(key, func(entry *dict.Entry) {
+		if !entry.Exists {
+			return
+		}
+		switch value := entry.Value.(type) {
+		case *sortedset.SortedSet:
+			elements = make([]sortedset.Element, 0, value.Len())
+			value.ForEach(func(e sortedset.Element) bool {
+				elements = append(elements, e)
+				return true
+			})
+		case *set.Set:
+			elements = make([]sortedset.Element, 0, value.Len())
+			value.ForEach(func(member string) bool {
+				elements = append(elements, sortedset.Element{Member: member, Score: 1})
+				return true
+			})
+		default:
+			errReply = reply.MakeWrongTypeErrReply()
+		}
+	})
+	return elements, errReply
+}
+
+/**
+ * @description: ZUNIONSTORE and ZINTERSTORE
+ *	destination numkeys key [key ...] [WEIGHTS weight [weight ...]] [AGGREGATE SUM|MIN|MAX]
+ */
+func zsetStoreGeneric(c *Client, args [][]byte, name string, union bool) reply.Reply {
+	dest := string(args[0])
+	numKeys, ok := parseCanonicalInt(args[1])
+	if !ok {
+		return reply.MakeNotIntegerErrReply()
+	}
+	if numKeys < 1 {
+		return reply.MakeErrReply("ERR at least 1 input key is needed for '" + name + "' command")
+	}
+	if numKeys > int64(len(args)-2) {
+		return reply.MakeSyntaxErrReply()
+	}
+	keys, rest := args[2:numKeys+2], args[numKeys+2:]
+
+	weights := make([]float64, numKeys)
+	for i := range weights {
+		weights[i] = 1
+	}
+	aggregate := aggregateSum
+	for len(rest) > 0 {
+		option := strings.ToLower(string(rest[0]))
+		switch {
+		case option == "weights" && int64(len(rest)-1) >= numKeys:
+			for i := range weights {
+				weight, ok := parseScore(rest[i+1])
+				if !ok {
+					return reply.MakeErrReply("ERR weight value is not a float")
+				}
+				weights[i] = weight
+			}
+			rest = rest[numKeys+1:]
+		case option == "aggregate" && len(rest) > 1:
+			switch strings.ToLower(string(rest[1])) {
+			case "sum":
+				aggregate = aggregateSum
+			case "min":
+				aggregate = aggregateMin
+			case "max":
+				aggregate = aggregateMax
+			default:
+				return reply.MakeSyntaxErrReply()
+			}
+			rest = rest[2:]
+		default:
+			return reply.MakeSyntaxErrReply()
+		}
+	}
+
+	sources := make([][]sortedset.Element, numKeys)
+	for i, key := range keys {
+		elements, errReply := c.db.getZSetSource(string(key))
+		if errReply != nil {
+			return errReply
+		}
+		sources[i] = elements
+	}
+
+	// members in the order they are first seen
+	var members []string
+	scores := make(map[string]float64)
+	for i, elements := range sources {
+		var seen map[string]bool
+		if !union {
+			seen = make(map[string]bool, len(elements))
+		}
+		for _, e := range elements {
+			score := e.Score * weights[i]
+			// 0 * inf
+			if math.IsNaN(score) {
+				score = 0
+			}
+			cur, exists := scores[e.Member]
+			switch {
+			case i == 0:
+				members = append(members, e.Member)
+				scores[e.Member] = score
+			case exists:
+				scores[e.Member] = aggregateScores(aggregate, cur, score)
+			case union:
+				members = append(members, e.Member)
+				scores[e.Member] = score
+			}
+			if seen != nil {
+				seen[e.Member] = true
+			}
+		}
+		if !union && i > 0 {
+			// keep members which are in all sources so far
+			kept := members[:0]
+			for _, member := range members {
+				if seen[member] {
+					kept = append(kept, member)
+				} else {
+					delete(scores, member)
+				}
+			}
+			members = kept
+		}
+	}
+
+	elements := make([]sortedset.Element, len(members))
+	for i, member := range members {
+		elements[i] = sortedset.Element{Member: member, Score: scores[member]}
+	}
+	return reply.MakeIntReply(c.storeZSet(dest, elements))
+}
+
+/**
+ * @description: ZUNIONSTORE destination numkeys key [key ...] [WEIGHTS weight [weight ...]] [AGGREGATE SUM|MIN|MAX]
+ */
+func execZUnionStore(c *Client, args [][]byte) reply.Reply {
+	return zsetStoreGeneric(c, args, "zunionstore", true)
+}
+
+/**
+ * @description: ZINTERSTORE destination numkeys key [key ...] [WEIGHTS weight [weight ...]] [AGGREGATE SUM|MIN|MAX]
+ */
+func execZInterStore(c *Client, args [][]byte) reply.Reply {
+	return zsetStoreGeneric(c, args, "zinterstore", false)
+}
+
+//------------ ZRANDMEMBER --------------
+
+/**
+ * @description: pick count elements of the sorted set, with repeats if allowRepeats,
+ *	else at most Len() distinct elements
+ */
+func zsetRandomElements(zs *sortedset.SortedSet, count int, allowRepeats bool) []sortedset.Element {
+	if allowRepeats {
+		elements := make([]sortedset.Element, count)
+		for i := range elements {
+			elements[i], _ = zs.RandomElement()
+		}
+		return elements
+	}
+
+	if int64(count)*3 > zs.Len() {
+		// the sample is big, shuffle all elements and keep the head
+		elements := make([]sortedset.Element, 0, zs.Len())
+		zs.ForEach(func(e sortedset.Element) bool {
+			elements = append(elements, e)
+			return true
+		})
+		if count > len(elements) {
+			count = len(elements)
+		}
+		for i := 0; i < count; i++ {
+			j := i + rand.Intn(len(elements)-i)
+			elements[i], elements[j] = elements[j], elements[i]
+		}
+		return elements[:count]
+	}
+
+	// the sample is small, pick random elements until enough distinct ones
+	seen := make(map[string]struct{}, count)
+	elements := make([]sortedset.Element, 0, count)
+	for len(elements) < count {
+		e, _ := zs.RandomElement()
+		if _, ok := seen[e.Member]; ok {
+			continue
+		}
+		seen[e.Member] = struct{}{}
+		elements = append(elements, e)
+	}
+	return elements
+}
+
+/**
+ * @description: ZRANDMEMBER key [count [WITHSCORES]]
+ */
+func execZRandMember(c *Client, args [][]byte) reply.Reply {
+	key := string(args[0])
+	if len(args) == 1 {
+		return c.db.updateZSet(key, false, func(zs *sortedset.SortedSet) reply.Reply {
+			if zs == nil {
+				return reply.MakeNullBulkReply()
+			}
+			e, _ := zs.RandomElement()
+			return reply.MakeBulkReply([]byte(e.Member))
+		})
+	}
+
+	if len(args) > 3 || (len(args) == 3 && strings.ToLower(string(args[2])) != "withscores") {
+		return reply.MakeSyntaxErrReply()
+	}
+	withScores := len(args) == 3
+	count, ok := parseCanonicalInt(args[1])
+	if !ok {
+		return reply.MakeNotIntegerErrReply()
+	}
+	if count == math.MinInt64 {
+		return reply.MakeErrReply("ERR value is out of range, must be between -9223372036854775807 and 9223372036854775807")
+	}
+	// the reply length must not overflow
+	if withScores && count < -math.MaxInt64/2 {
+		return reply.MakeErrReply("ERR value is out of range")
+	}
+
+	return c.db.updateZSet(key, false, func(zs *sortedset.SortedSet) reply.Reply {
+		if zs == nil || count == 0 {
+			return reply.MakeEmptyArrayReply()
+		}
+		var elements []sortedset.Element
+		if count < 0 {
+			elements = zsetRandomElements(zs, int(-count), true)
+		} else {
+			if count > zs.Len() {
+				count = zs.Len()
+			}
+			elements = zsetRandomElements(zs, int(count), false)
+		}
+		return makeElementsReply(c, elements, withScores)
+	})
+}
+
+/**
+ * @description: ZSCAN key cursor [MATCH pattern] [COUNT count]
+ */
+func execZScan(c *Client, args [][]byte) reply.Reply {
+	opts, errReply := parseScanOptions(args[1:], 0)
+	if errReply != nil {
+		return errReply
+	}
+	return c.db.updateZSet(string(args[0]), false, func(zs *sortedset.SortedSet) reply.Reply {
+		if zs == nil {
+			return makeScanReply(0, nil)
+		}
+		var elements [][]byte
+		cursor := zs.Scan(opts.cursor, opts.count, func(e sortedset.Element) {
+			if opts.match(e.Member) {
+				elements = append(elements, []byte(e.Member), []byte(reply.FormatFloat(e.Score)))
+			}
+		})
+		return makeScanReply(cursor, elements)
+	})
+}
@@ -0,0 +1,270 @@
+/*
+ * @Description:
+ * @Autor: HTmonster
+ * @Date: 2026-10-19 15:48:02
+ */
+package server
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/HTmonster/redissgo/client"
+	"github.com/HTmonster/redissgo/internal/reply"
+)
+
+func TestZAdd(t *testing.T) {
+	h := NewHandler()
+	defer h.Close()
+	c := newTestClient(h)
+
+	runCases(t, h, c, []cmdCase{
+		{[]string{"ZADD", "z", "1", "a", "2", "b", "3", "c"}, ":3\r\n"},
+		{[]string{"ZADD", "z", "5", "a", "4", "d"}, ":1\r\n"},
+		{[]string{"ZADD", "z", "CH", "5", "a", "6", "b", "7", "e"}, ":2\r\n"},
+		{[]string{"ZADD", "z", "NX", "0", "a", "8", "f"}, ":1\r\n"},
+		{[]string{"ZSCORE", "z", "a"}, "$1\r\n5\r\n"},
+		{[]string{"ZADD", "z", "XX", "CH", "1", "a", "1", "none"}, ":1\r\n"},
+		{[]string{"ZSCORE", "z", "none"}, "$-1\r\n"},
+		{[]string{"ZADD", "z", "GT", "CH", "0", "a", "10", "b"}, ":1\r\n"},
+		{[]string{"ZADD", "z", "LT", "CH", "0", "a", "20", "b"}, ":1\r\n"},
+		{[]string{"ZMSCORE", "z", "a", "b", "none"}, "*3\r\n$1\r\n0\r\n$2\r\n10\r\n$-1\r\n"},
+		{[]string{"ZADD", "z", "INCR", "2.5", "a"}, "$3\r\n2.5\r\n"},
+		{[]string{"ZADD", "z", "NX", "INCR", "1", "a"}, "$-1\r\n"},
+		{[]string{"ZADD", "z", "GT", "INCR", "-1", "a"}, "$-1\r\n"},
+		{[]string{"ZADD", "none", "XX", "1", "a"}, ":0\r\n"},
+		{[]string{"ZADD", "none", "XX", "INCR", "1", "a"}, "$-1\r\n"},
+		{[]string{"ZCARD", "none"}, ":0\r\n"},
+		{[]string{"ZCARD", "z"}, ":6\r\n"},
+		{[]string{"ZCARD", "none"}, ":0\r\n"},
+		{[]string{"ZINCRBY", "z", "-0.5", "a"}, "$1\r\n2\r\n"},
+		{[]string{"ZINCRBY", "z", "1e400", "new"}, "$3\r\ninf\r\n"},
+		{[]string{"ZINCRBY", "z", "-inf", "new"}, "-ERR resulting score is not a number (NaN)\r\n"},
+		{[]string{"ZADD", "z", "INCR", "-inf", "new"}, "-ERR resulting score is not a number (NaN)\r\n"},
+		{[]string{"ZREM", "z", "new", "none", "f"}, ":2\r\n"},
+
+		{[]string{"ZADD", "z", "1", "a", "2"}, "-ERR syntax error\r\n"},
+		{[]string{"ZADD", "z", "CH", "NX"}, "-ERR syntax error\r\n"},
+		{[]string{"ZADD", "z", "x", "a"}, "-ERR value is not a valid float\r\n"},
+		{[]string{"ZADD", "z", "nan", "a"}, "-ERR value is not a valid float\r\n"},
+		{[]string{"ZADD", "z", "NX", "XX", "1", "a"}, "-ERR XX and NX options at the same time are not compatible\r\n"},
+		{[]string{"ZADD", "z", "GT", "LT", "1", "a"}, "-ERR GT, LT, and/or NX options at the same time are not compatible\r\n"},
+		{[]string{"ZADD", "z", "INCR", "1", "a", "2", "b"}, "-ERR INCR option supports a single increment-element pair\r\n"},
+		{[]string{"RPUSH", "list", "a"}, ":1\r\n"},
+		{[]string{"ZADD", "list", "1", "a"}, wrongType},
+		{[]string{"ZSCORE", "list", "a"}, wrongType},
+	})
+
+	// RESP3 doubles
+	c.SetProtocol(reply.RESP3)
+	runCases(t, h, c, []cmdCase{
+		{[]string{"ZSCORE", "z", "a"}, ",2\r\n"},
+		{[]string{"ZMSCORE", "z", "b", "none"}, "*2\r\n,10\r\n_\r\n"},
+	})
+}
+
+func TestZRankCount(t *testing.T) {
+	h := NewHandler()
+	defer h.Close()
+	c := newTestClient(h)
+
+	runCases(t, h, c, []cmdCase{
+		{[]string{"ZADD", "z", "1", "a", "2", "b", "2", "c", "3", "d"}, ":4\r\n"},
+		{[]string{"ZRANK", "z", "a"}, ":0\r\n"},
+		{[]string{"ZRANK", "z", "c"}, ":2\r\n"},
+		{[]string{"ZREVRANK", "z", "a"}, ":3\r\n"},
+		{[]string{"ZRANK", "z", "d", "WITHSCORE"}, "*2\r\n:3\r\n$1\r\n3\r\n"},
+		{[]string{"ZRANK", "z", "none"}, "$-1\r\n"},
+		{[]string{"ZRANK", "z", "none", "WITHSCORE"}, "*-1\r\n"},
+		{[]string{"ZRANK", "z", "a", "WITHSCORES"}, "-ERR syntax error\r\n"},
+		{[]string{"ZCOUNT", "z", "-inf", "+inf"}, ":4\r\n"},
+		{[]string{"ZCOUNT", "z", "2", "3"}, ":3\r\n"},
+		{[]string{"ZCOUNT", "z", "(1", "(3"}, ":2\r\n"},
+		{[]string{"ZCOUNT", "z", "3", "1"}, ":0\r\n"},
+		{[]string{"ZCOUNT", "none", "1", "3"}, ":0\r\n"},
+		{[]string{"ZCOUNT", "z", "x", "3"}, "-ERR min or max is not a float\r\n"},
+	})
+}
+
+func TestZRange(t *testing.T) {
+	h := NewHandler()
+	defer h.Close()
+	c := newTestClient(h)
+
+	runCases(t, h, c, []cmdCase{
+		{[]string{"ZADD", "z", "1", "a", "2", "b", "3", "c", "4", "d"}, ":4\r\n"},
+		{[]string{"ZRANGE", "z", "0", "-1"}, "*4\r\n$1\r\na\r\n$1\r\nb\r\n$1\r\nc\r\n$1\r\nd\r\n"},
+		{[]string{"ZRANGE", "z", "1", "2", "WITHSCORES"}, "*4\r\n$1\r\nb\r\n$1\r\n2\r\n$1\r\nc\r\n$1\r\n3\r\n"},
+		{[]string{"ZRANGE", "z", "0", "1", "REV"}, "*2\r\n$1\r\nd\r\n$1\r\nc\r\n"},
+		{[]string{"ZRANGE", "z", "-2", "100"}, "*2\r\n$1\r\nc\r\n$1\r\nd\r\n"},
+		{[]string{"ZRANGE", "z", "3", "1"}, "*0\r\n"},
+		{[]string{"ZRANGE", "z", "(1", "3", "BYSCORE"}, "*2\r\n$1\r\nb\r\n$1\r\nc\r\n"},
+		{[]string{"ZRANGE", "z", "+inf", "2", "BYSCORE", "REV", "LIMIT", "1", "2"}, "*2\r\n$1\r\nc\r\n$1\r\nb\r\n"},
+		{[]string{"ZRANGE", "z", "-inf", "+inf", "BYSCORE", "LIMIT", "1", "-1"}, "*3\r\n$1\r\nb\r\n$1\r\nc\r\n$1\r\nd\r\n"},
+		{[]string{"ZRANGE", "none", "0", "-1"}, "*0\r\n"},
+		{[]string{"ZRANGE", "z", "0", "1", "LIMIT", "0", "1"}, "-ERR syntax error, LIMIT is only supported in combination with either BYSCORE or BYLEX\r\n"},
+		{[]string{"ZRANGE", "z", "-", "+", "BYLEX", "WITHSCORES"}, "-ERR syntax error, WITHSCORES not supported in combination with BYLEX\r\n"},
+		{[]string{"ZRANGE", "z", "a", "b", "BYSCORE"}, "-ERR min or max is not a float\r\n"},
+		{[]string{"ZRANGE", "z", "a", "b", "BYLEX"}, "-ERR min or max not valid string range item\r\n"},
+		{[]string{"ZRANGE", "z", "a", "1"}, "-ERR value is not an integer or out of range\r\n"},
+		{[]string{"ZRANGE", "z", "0", "1", "UP"}, "-ERR syntax error\r\n"},
+
+		{[]string{"ZADD", "lex", "0", "a", "0", "b", "0", "c", "0", "d"}, ":4\r\n"},
+		{[]string{"ZRANGE", "lex", "[b", "(d", "BYLEX"}, "*2\r\n$1\r\nb\r\n$1\r\nc\r\n"},
+		{[]string{"ZRANGE", "lex", "+", "-", "BYLEX", "REV", "LIMIT", "0", "2"}, "*2\r\n$1\r\nd\r\n$1\r\nc\r\n"},
+
+		{[]string{"ZRANGESTORE", "dst", "z", "2", "+inf", "BYSCORE"}, ":3\r\n"},
+		{[]string{"ZRANGE", "dst", "0", "-1"}, "*3\r\n$1\r\nb\r\n$1\r\nc\r\n$1\r\nd\r\n"},
+		{[]string{"ZRANGESTORE", "dst", "z", "5", "6", "BYSCORE"}, ":0\r\n"},
+		{[]string{"ZCARD", "dst"}, ":0\r\n"},
+		{[]string{"ZRANGESTORE", "dst", "z", "0", "1", "WITHSCORES"}, "-ERR syntax error\r\n"},
+	})
+
+	// RESP3 pairs
+	c.SetProtocol(reply.RESP3)
+	runCases(t, h, c, []cmdCase{
+		{[]string{"ZRANGE", "z", "0", "0", "WITHSCORES"}, "*1\r\n*2\r\n$1\r\na\r\n,1\r\n"},
+	})
+}
+
+func TestZPop(t *testing.T) {
+	h := NewHandler()
+	defer h.Close()
+	c := newTestClient(h)
+
+	runCases(t, h, c, []cmdCase{
+		{[]string{"ZADD", "z", "1", "a", "2", "b", "3", "c", "4", "d"}, ":4\r\n"},
+		{[]string{"ZPOPMIN", "z"}, "*2\r\n$1\r\na\r\n$1\r\n1\r\n"},
+		{[]string{"ZPOPMAX", "z", "2"}, "*4\r\n$1\r\nd\r\n$1\r\n4\r\n$1\r\nc\r\n$1\r\n3\r\n"},
+		{[]string{"ZPOPMIN", "z", "0"}, "*0\r\n"},
+		{[]string{"ZPOPMIN", "z", "-1"}, "-ERR value is out of range, must be positive\r\n"},
+		{[]string{"ZPOPMIN", "z", "10"}, "*2\r\n$1\r\nb\r\n$1\r\n2\r\n"},
+		{[]string{"ZCARD", "z"}, ":0\r\n"},
+		{[]string{"ZPOPMIN", "z"}, "*0\r\n"},
+
+		{[]string{"ZADD", "b", "1", "x", "2", "y"}, ":2\r\n"},
+		{[]string{"BZPOPMIN", "a", "b", "0"}, "*3\r\n$1\r\nb\r\n$1\r\nx\r\n$1\r\n1\r\n"},
+		{[]string{"BZPOPMAX", "a", "b", "0"}, "*3\r\n$1\r\nb\r\n$1\r\ny\r\n$1\r\n2\r\n"},
+		{[]string{"BZPOPMIN", "a", "0.01"}, "*-1\r\n"},
+		{[]string{"SET", "s", "v"}, "+OK\r\n"},
+		{[]string{"BZPOPMIN", "s", "0"}, wrongType},
+	})
+
+	// RESP3 pairs only with count
+	c.SetProtocol(reply.RESP3)
+	runCases(t, h, c, []cmdCase{
+		{[]string{"ZADD", "z", "1", "a", "2", "b"}, ":2\r\n"},
+		{[]string{"ZPOPMIN", "z"}, "*2\r\n$1\r\na\r\n,1\r\n"},
+		{[]string{"ZPOPMIN", "z", "1"}, "*1\r\n*2\r\n$1\r\nb\r\n,2\r\n"},
+	})
+}
+
+func TestBZPopMinServed(t *testing.T) {
+	h, addr, stop := startTestServer(t)
+	defer stop()
+	popper, adder := dial(t, addr), dial(t, addr)
+	defer popper.Close()
+	defer adder.Close()
+
+	popped := doAsync(popper, "BZPOPMIN", "z", "0")
+	waitBlocked(t, h, 0, "z", 1)
+	if n, err := client.Int64(adder.Do("ZADD", "z", "2", "b", "1", "a")); err != nil || n != 2 {
+		t.Fatalf("ZADD: %d %v", n, err)
+	}
+	expectReply(t, popped, []interface{}{[]byte("z"), []byte("a"), []byte("1")})
+	if n, err := client.Int64(adder.Do("ZCARD", "z")); err != nil || n != 1 {
+		t.Errorf("ZCARD: %d %v", n, err)
+	}
+}
+
+func TestZStore(t *testing.T) {
+	h := NewHandler()
+	defer h.Close()
+	c := newTestClient(h)
+
+	runCases(t, h, c, []cmdCase{
+		{[]string{"ZADD", "z1", "1", "a", "2", "b", "3", "c"}, ":3\r\n"},
+		{[]string{"ZADD", "z2", "10", "b", "20", "c", "30", "d"}, ":3\r\n"},
+		{[]string{"SADD", "s", "c", "e"}, ":2\r\n"},
+
+		{[]string{"ZUNIONSTORE", "dst", "2", "z1", "z2"}, ":4\r\n"},
+		{[]string{"ZRANGE", "dst", "0", "-1", "WITHSCORES"}, "*8\r\n$1\r\na\r\n$1\r\n1\r\n$1\r\nb\r\n$2\r\n12\r\n$1\r\nc\r\n$2\r\n23\r\n$1\r\nd\r\n$2\r\n30\r\n"},
+		{[]string{"ZUNIONSTORE", "dst", "2", "z1", "s", "WEIGHTS", "2", "3", "AGGREGATE", "MAX"}, ":4\r\n"},
+		{[]string{"ZMSCORE", "dst", "a", "c", "e"}, "*3\r\n$1\r\n2\r\n$1\r\n6\r\n$1\r\n3\r\n"},
+		{[]string{"ZINTERSTORE", "dst", "3", "z1", "z2", "s"}, ":1\r\n"},
+		{[]string{"ZRANGE", "dst", "0", "-1", "WITHSCORES"}, "*2\r\n$1\r\nc\r\n$2\r\n24\r\n"},
+		{[]string{"ZINTERSTORE", "dst", "2", "z1", "z2", "AGGREGATE", "MIN"}, ":2\r\n"},
+		{[]string{"ZMSCORE", "dst", "b", "c"}, "*2\r\n$1\r\n2\r\n$1\r\n3\r\n"},
+		{[]string{"ZINTERSTORE", "dst", "2", "z1", "none"}, ":0\r\n"},
+		{[]string{"ZCARD", "dst"}, ":0\r\n"},
+		{[]string{"ZUNIONSTORE", "dst", "1", "z1", "WEIGHTS", "inf"}, ":3\r\n"},
+		{[]string{"ZUNIONSTORE", "dst", "2", "dst", "dst", "WEIGHTS", "1", "-1"}, ":3\r\n"},
+		{[]string{"ZSCORE", "dst", "a"}, "$1\r\n0\r\n"},
+
+		{[]string{"ZUNIONSTORE", "dst", "0", "z1"}, "-ERR at least 1 input key is needed for 'zunionstore' command\r\n"},
+		{[]string{"ZINTERSTORE", "dst", "3", "z1", "z2"}, "-ERR syntax error\r\n"},
+		{[]string{"ZUNIONSTORE", "dst", "2", "z1", "z2", "WEIGHTS", "1"}, "-ERR syntax error\r\n"},
+		{[]string{"ZUNIONSTORE", "dst", "1", "z1", "WEIGHTS", "x"}, "-ERR weight value is not a float\r\n"},
+		{[]string{"ZUNIONSTORE", "dst", "1", "z1", "AGGREGATE", "AVG"}, "-ERR syntax error\r\n"},
+		{[]string{"SET", "str", "v"}, "+OK\r\n"},
+		{[]string{"ZUNIONSTORE", "dst", "2", "z1", "str"}, wrongType},
+	})
+}
+
+func TestZRandMember(t *testing.T) {
+	h := NewHandler()
+	defer h.Close()
+	c := newTestClient(h)
+
+	runCases(t, h, c, []cmdCase{
+		{[]string{"ZRANDMEMBER", "none"}, "$-1\r\n"},
+		{[]string{"ZRANDMEMBER", "none", "3"}, "*0\r\n"},
+		{[]string{"ZADD", "z", "1", "a"}, ":1\r\n"},
+		{[]string{"ZRANDMEMBER", "z"}, "$1\r\na\r\n"},
+		{[]string{"ZRANDMEMBER", "z", "-3"}, "*3\r\n$1\r\na\r\n$1\r\na\r\n$1\r\na\r\n"},
+		{[]string{"ZRANDMEMBER", "z", "5", "WITHSCORES"}, "*2\r\n$1\r\na\r\n$1\r\n1\r\n"},
+		{[]string{"ZRANDMEMBER", "z", "1", "SCORES"}, "-ERR syntax error\r\n"},
+		{[]string{"ZRANDMEMBER", "z", "x"}, "-ERR value is not an integer or out of range\r\n"},
+		{[]string{"ZRANDMEMBER", "z", "-9223372036854775808"}, "-ERR value is out of range, must be between -9223372036854775807 and 9223372036854775807\r\n"},
+	})
+
+	for i := 0; i < 100; i++ {
+		execString(h, c, "ZADD", "big", "1", "m"+string(rune('a'+i%26))+string(rune('a'+i/26)))
+	}
+	for _, count := range []string{"3", "50", "100", "200"} {
+		members := execMembers(h, c, "ZRANDMEMBER", "big", count)
+		for i := 1; i < len(members); i++ {
+			if members[i] == members[i-1] {
+				t.Fatalf("ZRANDMEMBER %s: repeated member %s", count, members[i])
+			}
+		}
+		if want := map[string]int{"3": 3, "50": 50, "100": 100, "200": 100}[count]; len(members) != want {
+			t.Errorf("ZRANDMEMBER %s: got %d members", count, len(members))
+		}
+	}
+}
+
+func TestZScan(t *testing.T) {
+	h := NewHandler()
+	defer h.Close()
+	c := newTestClient(h)
+
+	runCases(t, h, c, []cmdCase{
+		{[]string{"ZSCAN", "none", "0"}, "*2\r\n$1\r\n0\r\n*0\r\n"},
+		{[]string{"ZADD", "small", "1.5", "a", "inf", "b"}, ":2\r\n"},
+		{[]string{"ZSCAN", "small", "0", "MATCH", "a"}, "*2\r\n$1\r\n0\r\n*2\r\n$1\r\na\r\n$3\r\n1.5\r\n"},
+		{[]string{"ZSCAN", "small", "0", "MATCH", "b"}, "*2\r\n$1\r\n0\r\n*2\r\n$1\r\nb\r\n$3\r\ninf\r\n"},
+	})
+
+	for i := 0; i < 1000; i++ {
+		execString(h, c, "ZADD", "big", strconv.Itoa(i), "m"+strconv.Itoa(i))
+	}
+	elements, calls := scanAll(t, h, c, 2, "ZSCAN", "big", "COUNT", "50")
+	scores := make(map[string]string)
+	for i := 0; i+1 < len(elements); i += 2 {
+		scores[elements[i]] = elements[i+1]
+	}
+	if len(scores) != 1000 || calls < 2 || scores["m42"] != "42" {
+		t.Errorf("ZSCAN big zset: %d members in %d calls", len(scores), calls)
+	}
+}
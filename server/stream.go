@@ -0,0 +1,1572 @@
+/*
+ * @Description: stream commands
+ * @Autor: HTmonster
+ * @Date: 2026-10-19 22:14:08
+ */
+
+package server
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/HTmonster/redissgo/datastruct/dict"
+	"github.com/HTmonster/redissgo/datastruct/stream"
+	"github.com/HTmonster/redissgo/internal/config"
+	"github.com/HTmonster/redissgo/internal/reply"
+)
+
+func init() {
+	registerCommand("xadd", execXAdd, -5, flagWrite|flagFast).keys(1, 1, 1)
+	registerCommand("xrange", execXRange, -4, flagReadonly).keys(1, 1, 1)
+	registerCommand("xrevrange", execXRevRange, -4, flagReadonly).keys(1, 1, 1)
+	registerCommand("xlen", execXLen, 2, flagReadonly|flagFast).keys(1, 1, 1)
+	registerCommand("xdel", execXDel, -3, flagWrite|flagFast).keys(1, 1, 1)
+	registerCommand("xtrim", execXTrim, -4, flagWrite).keys(1, 1, 1)
+	registerCommand("xread", execXRead, -4, flagReadonly).keysBy(streamsKeyFinder)
+	registerCommand("xreadgroup", execXReadGroup, -7, flagWrite).keysBy(streamsKeyFinder)
+	registerCommand("xgroup", execXGroup, -2, flagWrite).keys(2, 2, 1)
+	registerCommand("xack", execXAck, -4, flagWrite|flagFast).keys(1, 1, 1)
+	registerCommand("xpending", execXPending, -3, flagReadonly).keys(1, 1, 1)
+	registerCommand("xclaim", execXClaim, -6, flagWrite|flagFast).keys(1, 1, 1)
+	registerCommand("xautoclaim", execXAutoClaim, -6, flagWrite|flagFast).keys(1, 1, 1)
+	registerCommand("xinfo", execXInfo, -2, flagReadonly).keys(2, 2, 1)
+}
+
+/**
+ * @description: run fn on the stream at key under the segment lock.
+ *	Streams are modified in place, so even readers must hold the lock.
+ *	Unlike other types, empty streams are kept.
+ * @param {string} key
+ * @param {bool} create create an empty stream if the key does not exist,
+ *	it is only stored if fn does not fail
+ * @param {func} fn gets nil if the key does not exist and create is false
+ * @return {*} reply of fn, or WRONGTYPE
+ */
+func (db *DB) updateStream(key string, create bool, fn func(s *stream.Stream) reply.Reply) reply.Reply {
+	var result reply.Reply
+	db.Update(key, func(entry *dict.Entry) {
+		var s *stream.Stream
+		if entry.Exists {
+			var ok bool
+			if s, ok = entry.Value.(*stream.Stream); !ok {
+				result = reply.MakeWrongTypeErrReply()
+				return
+			}
+		} else if create {
+			s = stream.Make(config.Properties.StreamNodeMaxEntries, config.Properties.StreamNodeMaxBytes)
+		}
+		result = fn(s)
+		if s != nil && (entry.Exists || !reply.IsErrorReply(result)) {
+			entry.Value, entry.Exists = s, true
+		}
+	})
+	return result
+}
+
+//------------ IDs --------------
+
+/**
+ * @description: ERR Invalid stream ID
+ */
+func makeInvalidStreamIDErrReply() reply.ErrorReply {
+	return reply.MakeErrReply("ERR Invalid stream ID specified as stream command argument")
+}
+
+/**
+ * @description: parse an ID <ms>-<seq> or <ms>
+ * @param {[]byte} arg
+ * @param {uint64} missingSeq sequence number of <ms>
+ * @return {*}
+ */
+func parseStreamID(arg []byte, missingSeq uint64) (stream.ID, reply.ErrorReply) {
+	msPart, seqPart, hasSeq := strings.Cut(string(arg), "-")
+	ms, err := strconv.ParseUint(msPart, 10, 64)
+	if err != nil {
+		return stream.ID{}, makeInvalidStreamIDErrReply()
+	}
+	seq := missingSeq
+	if hasSeq {
+		if seq, err = strconv.ParseUint(seqPart, 10, 64); err != nil {
+			return stream.ID{}, makeInvalidStreamIDErrReply()
+		}
+	}
+	return stream.ID{Ms: ms, Seq: seq}, nil
+}
+
+/**
+ * @description: parse IDs, all of them must be valid
+ */
+func parseStreamIDs(args [][]byte) ([]stream.ID, reply.ErrorReply) {
+	ids := make([]stream.ID, len(args))
+	for i, arg := range args {
+		id, errReply := parseStreamID(arg, 0)
+		if errReply != nil {
+			return nil, errReply
+		}
+		ids[i] = id
+	}
+	return ids, nil
+}
+
+/**
+ * @description: parse a border of an interval, - + or an ID,
+ *	an ID starting with ( is excluded
+ * @return {*} ID, excluded or not
+ */
+func parseIntervalID(arg []byte, missingSeq uint64) (stream.ID, bool, reply.ErrorReply) {
+	if len(arg) > 1 && arg[0] == '(' {
+		id, errReply := parseStreamID(arg[1:], missingSeq)
+		return id, true, errReply
+	}
+	switch string(arg) {
+	case "-":
+		return stream.ID{}, false, nil
+	case "+":
+		return stream.MaxID, false, nil
+	}
+	id, errReply := parseStreamID(arg, missingSeq)
+	return id, false, errReply
+}
+
+/**
+ * @description: parse the start of an interval, an excluded start becomes the next ID
+ */
+func parseIntervalStart(arg []byte) (stream.ID, reply.ErrorReply) {
+	start, exclude, errReply := parseIntervalID(arg, 0)
+	if errReply != nil {
+		return start, errReply
+	}
+	if exclude {
+		var ok bool
+		if start, ok = start.Incr(); !ok {
+			return start, reply.MakeErrReply("ERR invalid start ID for the interval")
+		}
+	}
+	return start, nil
+}
+
+/**
+ * @description: parse the end of an interval, an excluded end becomes the previous ID
+ */
+func parseIntervalEnd(arg []byte) (stream.ID, reply.ErrorReply) {
+	end, exclude, errReply := parseIntervalID(arg, math.MaxUint64)
+	if errReply != nil {
+		return end, errReply
+	}
+	if exclude {
+		var ok bool
+		if end, ok = end.Decr(); !ok {
+			return end, reply.MakeErrReply("ERR invalid end ID for the interval")
+		}
+	}
+	return end, nil
+}
+
+//------------ replies --------------
+
+/**
+ * @description: reply of an ID
+ */
+func makeStreamIDReply(id stream.ID) reply.Reply {
+	return reply.MakeBulkReply([]byte(id.String()))
+}
+
+/**
+ * @description: reply of an entry, [id, [field, value ...]],
+ *	the fields of a deleted entry are null
+ */
+func makeStreamEntryReply(e stream.Entry) reply.Reply {
+	var fields reply.Reply = reply.MakeNullArrayReply()
+	if e.Fields != nil {
+		fields = reply.MakeMultiBulkReply(e.Fields)
+	}
+	return reply.MakeArrayReply([]reply.Reply{makeStreamIDReply(e.ID), fields})
+}
+
+/**
+ * @description: reply of entries
+ */
+func makeStreamEntriesReply(entries []stream.Entry) reply.Reply {
+	result := make([]reply.Reply, len(entries))
+	for i, e := range entries {
+		result[i] = makeStreamEntryReply(e)
+	}
+	return reply.MakeArrayReply(result)
+}
+
+/**
+ * @description: reply of XREAD, a map from keys to entries in RESP3,
+ *	an array of [key, entries] in RESP2
+ */
+func makeStreamsReply(c *Client, keys []string, entries [][]stream.Entry) reply.Reply {
+	result := make([]reply.Reply, 0, 2*len(keys))
+	for i, key := range keys {
+		pair := []reply.Reply{reply.MakeBulkReply([]byte(key)), makeStreamEntriesReply(entries[i])}
+		if c.protocol >= reply.RESP3 {
+			result = append(result, pair...)
+		} else {
+			result = append(result, reply.MakeArrayReply(pair))
+		}
+	}
+	if c.protocol >= reply.RESP3 {
+		return reply.MakeMapReply(result)
+	}
+	return reply.MakeArrayReply(result)
+}
+
+/**
+ * @description: integer reply, or null if not ok
+ */
+func intOrNull(n int64, ok bool) reply.Reply {
+	if !ok {
+		return reply.MakeNullBulkReply()
+	}
+	return reply.MakeIntReply(n)
+}
+
+//------------ XADD and XTRIM --------------
+
+// trimming of XADD and XTRIM
+type streamTrimSpec struct {
+	byLen   bool
+	byMinID bool
+	maxLen  int64
+	minID   stream.ID
+	approx  bool
+	limit   int64 // 0 for no limit
+}
+
+/**
+ * @description: trim the stream
+ * @return {*} number of removed entries
+ */
+func (spec *streamTrimSpec) trim(s *stream.Stream) int64 {
+	switch {
+	case spec.byLen:
+		return s.TrimByLen(spec.maxLen, spec.approx, spec.limit)
+	case spec.byMinID:
+		return s.TrimByMinID(spec.minID, spec.approx, spec.limit)
+	}
+	return 0
+}
+
+/**
+ * @description: parse the options of XADD or XTRIM after the key
+ * @param {[][]byte} args
+ * @param {bool} xadd options stop at the ID of XADD
+ * @return {*} trimming, NOMKSTREAM or not, position of the ID of XADD
+ */
+func parseStreamAddArgs(args [][]byte, xadd bool) (*streamTrimSpec, bool, int, reply.ErrorReply) {
+	spec := &streamTrimSpec{}
+	noMkStream, limitGiven := false, false
+	i := 1
+	for ; i < len(args); i++ {
+		moreArgs := len(args) - 1 - i
+		opt := strings.ToUpper(string(args[i]))
+		if xadd && opt == "*" {
+			break
+		}
+		switch {
+		case (opt == "MAXLEN" || opt == "MINID") && moreArgs > 0:
+			if spec.byLen || spec.byMinID {
+				return nil, false, 0, reply.MakeErrReply("ERR syntax error, MAXLEN and MINID options at the same time are not compatible")
+			}
+			spec.approx = false
+			if next := string(args[i+1]); moreArgs >= 2 && (next == "~" || next == "=") {
+				spec.approx = next == "~"
+				i++
+			}
+			i++
+			if opt == "MAXLEN" {
+				maxLen, ok := parseCanonicalInt(args[i])
+				if !ok {
+					return nil, false, 0, reply.MakeNotIntegerErrReply()
+				}
+				if maxLen < 0 {
+					return nil, false, 0, reply.MakeErrReply("ERR The MAXLEN argument must be >= 0.")
+				}
+				spec.byLen, spec.maxLen = true, maxLen
+			} else {
+				minID, errReply := parseStreamID(args[i], 0)
+				if errReply != nil {
+					return nil, false, 0, errReply
+				}
+				spec.byMinID, spec.minID = true, minID
+			}
+		case opt == "LIMIT" && moreArgs > 0:
+			limit, ok := parseCanonicalInt(args[i+1])
+			if !ok {
+				return nil, false, 0, reply.MakeNotIntegerErrReply()
+			}
+			if limit < 0 {
+				return nil, false, 0, reply.MakeErrReply("ERR The LIMIT argument must be >= 0.")
+			}
+			spec.limit, limitGiven = limit, true
+			i++
+		case xadd && opt == "NOMKSTREAM":
+			noMkStream = true
+		case xadd:
+			// the ID
+			return spec, noMkStream, i, spec.check(xadd, limitGiven)
+		default:
+			return nil, false, 0, reply.MakeSyntaxErrReply()
+		}
+	}
+	return spec, noMkStream, i, spec.check(xadd, limitGiven)
+}
+
+/**
+ * @description: check the trimming options and set the default limit
+ */
+func (spec *streamTrimSpec) check(xadd bool, limitGiven bool) reply.ErrorReply {
+	trimming := spec.byLen || spec.byMinID
+	if limitGiven && !trimming {
+		return reply.MakeErrReply("ERR syntax error, LIMIT cannot be used without specifying a trimming strategy")
+	}
+	if !xadd && !trimming {
+		return reply.MakeErrReply("ERR syntax error, XTRIM must be called with a trimming strategy")
+	}
+	if limitGiven {
+		if !spec.approx {
+			return reply.MakeErrReply("ERR syntax error, LIMIT cannot be used without the special ~ option")
+		}
+		return nil
+	}
+	spec.limit = 0
+	if spec.approx {
+		// do not spend too long trimming
+		spec.limit = 100 * int64(config.Properties.StreamNodeMaxEntries)
+		if spec.limit <= 0 {
+			spec.limit = 10000
+		}
+		if spec.limit > 1000000 {
+			spec.limit = 1000000
+		}
+	}
+	return nil
+}
+
+/**
+ * @description: XADD key [NOMKSTREAM] [MAXLEN|MINID [=|~] threshold [LIMIT count]] *|id field value [field value ...]
+ */
+func execXAdd(c *Client, args [][]byte) reply.Reply {
+	key := string(args[0])
+	spec, noMkStream, pos, errReply := parseStreamAddArgs(args, true)
+	if errReply != nil {
+		return errReply
+	}
+	if pos >= len(args) {
+		return reply.MakeArgNumErrReply("xadd")
+	}
+
+	// *, <ms>-* or an explicit ID
+	idArg := string(args[pos])
+	auto, autoSeq := idArg == "*", false
+	var id stream.ID
+	if !auto {
+		if strings.HasSuffix(idArg, "-*") {
+			autoSeq = true
+			idArg = strings.TrimSuffix(idArg, "-*")
+		}
+		if id, errReply = parseStreamID([]byte(idArg), 0); errReply != nil {
+			return errReply
+		}
+		if !autoSeq && id.IsZero() {
+			return reply.MakeErrReply("ERR The ID specified in XADD must be greater than 0-0")
+		}
+	}
+	fields := args[pos+1:]
+	if len(fields) < 2 || len(fields)%2 == 1 {
+		return reply.MakeArgNumErrReply("xadd")
+	}
+
+	added := false
+	result := c.db.updateStream(key, !noMkStream, func(s *stream.Stream) reply.Reply {
+		if s == nil {
+			c.keepVersions()
+			return reply.MakeNullBulkReply()
+		}
+		if s.LastID() == stream.MaxID {
+			return reply.MakeErrReply("ERR The stream has exhausted the last possible ID, unable to add more items")
+		}
+		ok := true
+		switch {
+		case auto:
+			id, ok = s.AutoID(uint64(dict.NowMillis()))
+		case autoSeq:
+			id, ok = s.AutoSeqID(id.Ms)
+		}
+		if !ok || !s.Add(id, fields) {
+			return reply.MakeErrReply("ERR The ID specified in XADD is equal or smaller than the target stream top item")
+		}
+		spec.trim(s)
+		added = true
+		return makeStreamIDReply(id)
+	})
+	if added {
+		c.signalKeyReady(c.db, key)
+	}
+	return result
+}
+
+/**
+ * @description: XTRIM key MAXLEN|MINID [=|~] threshold [LIMIT count]
+ */
+func execXTrim(c *Client, args [][]byte) reply.Reply {
+	spec, _, _, errReply := parseStreamAddArgs(args, false)
+	if errReply != nil {
+		return errReply
+	}
+	return c.db.updateStream(string(args[0]), false, func(s *stream.Stream) reply.Reply {
+		if s == nil {
+			return c.changesReply(0)
+		}
+		return c.changesReply(spec.trim(s))
+	})
+}
+
+//------------ XRANGE, XLEN and XDEL --------------
+
+/**
+ * @description: XRANGE and XREVRANGE
+ */
+func xrangeGeneric(c *Client, args [][]byte, rev bool) reply.Reply {
+	startArg, endArg := args[1], args[2]
+	if rev {
+		startArg, endArg = endArg, startArg
+	}
+	start, errReply := parseIntervalStart(startArg)
+	if errReply != nil {
+		return errReply
+	}
+	end, errReply := parseIntervalEnd(endArg)
+	if errReply != nil {
+		return errReply
+	}
+
+	count := int64(-1)
+	for i := 3; i < len(args); i++ {
+		if strings.ToUpper(string(args[i])) == "COUNT" && i+1 < len(args) {
+			var ok bool
+			if count, ok = parseCanonicalInt(args[i+1]); !ok {
+				return reply.MakeNotIntegerErrReply()
+			}
+			if count < 0 {
+				count = 0
+			}
+			i++
+		} else {
+			return reply.MakeSyntaxErrReply()
+		}
+	}
+
+	return c.db.updateStream(string(args[0]), false, func(s *stream.Stream) reply.Reply {
+		if s == nil {
+			return reply.MakeEmptyArrayReply()
+		}
+		if count == 0 {
+			return reply.MakeNullArrayReply()
+		}
+		if count < 0 {
+			count = 0
+		}
+		return makeStreamEntriesReply(s.Range(start, end, int(count), rev))
+	})
+}
+
+/**
+ * @description: XRANGE key start end [COUNT count]
+ */
+func execXRange(c *Client, args [][]byte) reply.Reply {
+	return xrangeGeneric(c, args, false)
+}
+
+/**
+ * @description: XREVRANGE key end start [COUNT count]
+ */
+func execXRevRange(c *Client, args [][]byte) reply.Reply {
+	return xrangeGeneric(c, args, true)
+}
+
+/**
+ * @description: XLEN key
+ */
+func execXLen(c *Client, args [][]byte) reply.Reply {
+	return c.db.updateStream(string(args[0]), false, func(s *stream.Stream) reply.Reply {
+		if s == nil {
+			return reply.MakeIntReply(0)
+		}
+		return reply.MakeIntReply(s.Len())
+	})
+}
+
+/**
+ * @description: XDEL key id [id ...]
+ */
+func execXDel(c *Client, args [][]byte) reply.Reply {
+	ids, errReply := parseStreamIDs(args[1:])
+	if errReply != nil {
+		return errReply
+	}
+	return c.db.updateStream(string(args[0]), false, func(s *stream.Stream) reply.Reply {
+		deleted := int64(0)
+		if s == nil {
+			return c.changesReply(0)
+		}
+		for _, id := range ids {
+			if s.Delete(id) {
+				deleted++
+			}
+		}
+		return c.changesReply(deleted)
+	})
+}
+
+//------------ XREAD and XREADGROUP --------------
+
+// options of XREAD and XREADGROUP
+type xreadSpec struct {
+	count    int // 0 for no limit
+	block    bool
+	timeout  time.Duration
+	group    string // empty for XREAD
+	consumer string
+	noAck    bool
+	keys     []string
+	ids      [][]byte
+}
+
+/**
+ * @description: parse a timeout in milliseconds
+ */
+func parseMillisTimeout(arg []byte) (time.Duration, reply.ErrorReply) {
+	ms, ok := parseCanonicalInt(arg)
+	if !ok {
+		return 0, reply.MakeErrReply("ERR timeout is not an integer or out of range")
+	}
+	if ms < 0 {
+		return 0, reply.MakeErrReply("ERR timeout is negative")
+	}
+	if ms > math.MaxInt64/int64(time.Millisecond) {
+		return 0, reply.MakeErrReply("ERR timeout is out of range")
+	}
+	return time.Duration(ms) * time.Millisecond, nil
+}
+
+/**
+ * @description: parse the options of XREAD or XREADGROUP
+ */
+func parseXReadSpec(args [][]byte, group bool) (*xreadSpec, reply.ErrorReply) {
+	spec := &xreadSpec{}
+	name := "xread"
+	if group {
+		name = "xreadgroup"
+	}
+	streamsArg := -1
+	for i := 0; i < len(args) && streamsArg < 0; i++ {
+		moreArgs := len(args) - 1 - i
+		switch opt := strings.ToUpper(string(args[i])); {
+		case opt == "COUNT" && moreArgs > 0:
+			count, ok := parseCanonicalInt(args[i+1])
+			if !ok {
+				return nil, reply.MakeNotIntegerErrReply()
+			}
+			if count < 0 {
+				count = 0
+			}
+			if count > math.MaxInt32 {
+				count = math.MaxInt32
+			}
+			spec.count = int(count)
+			i++
+		case opt == "BLOCK" && moreArgs > 0:
+			timeout, errReply := parseMillisTimeout(args[i+1])
+			if errReply != nil {
+				return nil, errReply
+			}
+			spec.block, spec.timeout = true, timeout
+			i++
+		case opt == "STREAMS" && moreArgs > 0:
+			streamsArg = i + 1
+		case opt == "GROUP" && moreArgs >= 2:
+			if !group {
+				return nil, reply.MakeErrReply("ERR The GROUP option is only supported by XREADGROUP. You called XREAD instead.")
+			}
+			spec.group, spec.consumer = string(args[i+1]), string(args[i+2])
+			i += 2
+		case opt == "NOACK":
+			if !group {
+				return nil, reply.MakeErrReply("ERR The NOACK option is only supported by XREADGROUP. You called XREAD instead.")
+			}
+			spec.noAck = true
+		default:
+			return nil, reply.MakeSyntaxErrReply()
+		}
+	}
+	if streamsArg < 0 {
+		return nil, reply.MakeSyntaxErrReply()
+	}
+	streams := args[streamsArg:]
+	if len(streams)%2 != 0 {
+		return nil, reply.MakeErrReply("ERR Unbalanced '" + name + "' list of streams: for each stream key an ID or '$' must be specified.")
+	}
+	if group && spec.group == "" {
+		return nil, reply.MakeErrReply("ERR Missing GROUP option for XREADGROUP")
+	}
+	n := len(streams) / 2
+	spec.keys = make([]string, n)
+	for i, key := range streams[:n] {
+		spec.keys[i] = string(key)
+	}
+	spec.ids = streams[n:]
+	return spec, nil
+}
+
+/**
+ * @description: XREAD [COUNT count] [BLOCK milliseconds] STREAMS key [key ...] id [id ...]
+ */
+func execXRead(c *Client, args [][]byte) reply.Reply {
+	spec, errReply := parseXReadSpec(args, false)
+	if errReply != nil {
+		return errReply
+	}
+
+	// resolve $ to the last ID of each stream
+	after := make(map[string]stream.ID, len(spec.keys))
+	for i, key := range spec.keys {
+		switch string(spec.ids[i]) {
+		case "$":
+			result := c.db.updateStream(key, false, func(s *stream.Stream) reply.Reply {
+				if s != nil {
+					after[key] = s.LastID()
+				} else {
+					after[key] = stream.ID{}
+				}
+				return nil
+			})
+			if result != nil {
+				return result
+			}
+		case ">":
+			return reply.MakeErrReply("ERR The > ID can be specified only when calling XREADGROUP using the GROUP <group> <consumer> option.")
+		default:
+			id, errReply := parseStreamID(spec.ids[i], 0)
+			if errReply != nil {
+				return errReply
+			}
+			after[key] = id
+		}
+	}
+
+	// entries after the ID of a stream, nil if there are none
+	read := func(db *DB, key string) (reply.Reply, []stream.Entry) {
+		var entries []stream.Entry
+		result := db.updateStream(key, false, func(s *stream.Stream) reply.Reply {
+			if s == nil {
+				return nil
+			}
+			if start, ok := after[key].Incr(); ok {
+				entries = s.Range(start, stream.MaxID, spec.count, false)
+			}
+			return nil
+		})
+		return result, entries
+	}
+
+	var keys []string
+	var entries [][]stream.Entry
+	for _, key := range spec.keys {
+		errReply, found := read(c.db, key)
+		if errReply != nil {
+			return errReply
+		}
+		if len(found) > 0 {
+			keys = append(keys, key)
+			entries = append(entries, found)
+		}
+	}
+	if len(keys) > 0 {
+		return makeStreamsReply(c, keys, entries)
+	}
+	if !spec.block {
+		return reply.MakeNullArrayReply()
+	}
+
+	w := &waiter{
+		db:       c.dbIndex,
+		keys:     spec.keys,
+		readonly: true,
+		serve: func(db *DB, key string) (reply.Reply, bool) {
+			errReply, found := read(db, key)
+			if errReply != nil {
+				return errReply, true
+			}
+			if len(found) == 0 {
+				return nil, false
+			}
+			return makeStreamsReply(c, []string{key}, [][]stream.Entry{found}), true
+		},
+	}
+	return c.block(w, spec.timeout, reply.MakeNullArrayReply())
+}
+
+/**
+ * @description: NOGROUP error of commands reading a group
+ */
+func makeNoGroupErrReply(key, group string) reply.ErrorReply {
+	return reply.MakeErrReply("NOGROUP No such key '" + key + "' or consumer group '" + group + "'")
+}
+
+/**
+ * @description: get or create a consumer of the group, it is seen now
+ */
+func seeConsumer(g *stream.Group, name string, now int64) *stream.Consumer {
+	consumer, _ := g.CreateConsumer(name, now)
+	consumer.SeenTime = now
+	return consumer
+}
+
+/**
+ * @description: deliver the entries never delivered to the group, for the ID >
+ * @return {*} delivered entries
+ */
+func deliverNewEntries(s *stream.Stream, g *stream.Group, consumer *stream.Consumer, spec *xreadSpec, now int64) []stream.Entry {
+	start, ok := g.LastID.Incr()
+	if !ok {
+		return nil
+	}
+	entries := s.Range(start, stream.MaxID, spec.count, false)
+	for _, e := range entries {
+		s.Advance(g, e.ID)
+		if !spec.noAck {
+			p := g.Assign(e.ID, consumer, now)
+			p.DeliveryCount = 1
+		}
+	}
+	if len(entries) > 0 {
+		consumer.ActiveTime = now
+	}
+	return entries
+}
+
+/**
+ * @description: deliver again the pending entries of the consumer after an ID,
+ *	deleted entries have no fields
+ * @return {*} delivered entries
+ */
+func deliverHistory(s *stream.Stream, consumer *stream.Consumer, after stream.ID, count int, now int64) []stream.Entry {
+	start, ok := after.Incr()
+	if !ok {
+		return []stream.Entry{}
+	}
+	pending := consumer.PendingRange(start, stream.MaxID, count)
+	entries := make([]stream.Entry, 0, len(pending))
+	for _, p := range pending {
+		e, ok := s.Get(p.ID)
+		if !ok {
+			entries = append(entries, stream.Entry{ID: p.ID})
+			continue
+		}
+		p.DeliveryTime = now
+		p.DeliveryCount++
+		entries = append(entries, e)
+	}
+	if len(entries) > 0 {
+		consumer.ActiveTime = now
+	}
+	return entries
+}
+
+/**
+ * @description: XREADGROUP GROUP group consumer [COUNT count] [BLOCK milliseconds] [NOACK] STREAMS key [key ...] id [id ...]
+ */
+func execXReadGroup(c *Client, args [][]byte) reply.Reply {
+	spec, errReply := parseXReadSpec(args, true)
+	if errReply != nil {
+		return errReply
+	}
+
+	// check the groups and the IDs first
+	history := make([]bool, len(spec.keys))
+	after := make([]stream.ID, len(spec.keys))
+	for i, key := range spec.keys {
+		result := c.db.updateStream(key, false, func(s *stream.Stream) reply.Reply {
+			if s == nil || s.Group(spec.group) == nil {
+				return reply.MakeErrReply("NOGROUP No such key '" + key + "' or consumer group '" + spec.group + "' in XREADGROUP with GROUP option")
+			}
+			return nil
+		})
+		if result != nil {
+			return result
+		}
+		switch string(spec.ids[i]) {
+		case "$":
+			return reply.MakeErrReply("ERR The $ ID is meaningless in the context of XREADGROUP: you want to read the history of this consumer by specifying a proper ID, or use the > ID to get new messages. The $ ID would just return an empty result set.")
+		case ">":
+		default:
+			id, errReply := parseStreamID(spec.ids[i], 0)
+			if errReply != nil {
+				return errReply
+			}
+			history[i], after[i] = true, id
+		}
+	}
+
+	// read a stream, the pending entries of the consumer or the new entries
+	read := func(db *DB, key string, history bool, after stream.ID) (reply.Reply, []stream.Entry) {
+		var entries []stream.Entry
+		result := db.updateStream(key, false, func(s *stream.Stream) reply.Reply {
+			var g *stream.Group
+			if s != nil {
+				g = s.Group(spec.group)
+			}
+			if g == nil {
+				return reply.MakeErrReply("NOGROUP No such key '" + key + "' or consumer group '" + spec.group + "' in XREADGROUP with GROUP option")
+			}
+			now := dict.NowMillis()
+			consumer := seeConsumer(g, spec.consumer, now)
+			if history {
+				entries = deliverHistory(s, consumer, after, spec.count, now)
+			} else {
+				entries = deliverNewEntries(s, g, consumer, spec, now)
+			}
+			return nil
+		})
+		return result, entries
+	}
+
+	var keys []string
+	var entries [][]stream.Entry
+	for i, key := range spec.keys {
+		errReply, found := read(c.db, key, history[i], after[i])
+		if errReply != nil {
+			return errReply
+		}
+		// the history is always replied, even if it is empty
+		if len(found) > 0 || history[i] {
+			keys = append(keys, key)
+			entries = append(entries, found)
+		}
+	}
+	if len(keys) > 0 {
+		return makeStreamsReply(c, keys, entries)
+	}
+	if !spec.block {
+		return reply.MakeNullArrayReply()
+	}
+
+	w := &waiter{
+		db:   c.dbIndex,
+		keys: spec.keys,
+		serve: func(db *DB, key string) (reply.Reply, bool) {
+			errReply, found := read(db, key, false, stream.ID{})
+			if errReply != nil {
+				return errReply, true
+			}
+			if len(found) == 0 {
+				return nil, false
+			}
+			return makeStreamsReply(c, []string{key}, [][]stream.Entry{found}), true
+		},
+	}
+	return c.block(w, spec.timeout, reply.MakeNullArrayReply())
+}
+
+//------------ XGROUP --------------
+
+var xgroupHelp = []string{
+	"XGROUP <subcommand> [<arg> [value] [opt] ...]. Subcommands are:",
+	"CREATE <key> <groupname> <id|$> [option]",
+	"    Create a new consumer group. Options are:",
+	"    * MKSTREAM",
+	"      Create the empty stream if it does not exist.",
+	"    * ENTRIESREAD entries_read",
+	"      Set the group's entries_read counter (internal use).",
+	"CREATECONSUMER <key> <groupname> <consumer>",
+	"    Create a new consumer in the specified group.",
+	"DELCONSUMER <key> <groupname> <consumer>",
+	"    Remove the specified consumer.",
+	"DESTROY <key> <groupname>",
+	"    Remove the specified group.",
+	"SETID <key> <groupname> <id|$> [ENTRIESREAD entries_read]",
+	"    Set the current group ID and entries_read counter.",
+	"HELP",
+	"    Print this help.",
+}
+
+var xinfoHelp = []string{
+	"XINFO <subcommand> [<arg> [value] [opt] ...]. Subcommands are:",
+	"CONSUMERS <key> <groupname>",
+	"    Show consumers of <groupname>.",
+	"GROUPS <key>",
+	"    Show the stream consumer groups.",
+	"STREAM <key> [FULL [COUNT <count>]",
+	"    Show information about the stream.",
+	"HELP",
+	"    Print this help.",
+}
+
+/**
+ * @description: reply of HELP subcommands
+ */
+func makeHelpReply(lines []string) reply.Reply {
+	result := make([]reply.Reply, len(lines))
+	for i, line := range lines {
+		result[i] = reply.MakeStatusReply(line)
+	}
+	return reply.MakeArrayReply(result)
+}
+
+/**
+ * @description: ERR unknown subcommand
+ */
+func makeUnknownSubcommandErrReply(cmd string, sub []byte) reply.ErrorReply {
+	return reply.MakeErrReply("ERR unknown subcommand '" + string(sub) + "'. Try " + cmd + " HELP.")
+}
+
+/**
+ * @description: parse the ID of XGROUP CREATE and SETID, $ is the last ID
+ */
+func parseGroupID(arg []byte, s *stream.Stream) (stream.ID, reply.ErrorReply) {
+	if string(arg) == "$" {
+		if s == nil {
+			return stream.ID{}, nil
+		}
+		return s.LastID(), nil
+	}
+	return parseStreamID(arg, 0)
+}
+
+/**
+ * @description: parse the options of XGROUP CREATE and SETID
+ * @return {*} MKSTREAM or not, entries read
+ */
+func parseGroupOptions(args [][]byte, create bool) (bool, int64, reply.ErrorReply) {
+	mkStream, entriesRead := false, int64(stream.InvalidEntriesRead)
+	for i := 0; i < len(args); i++ {
+		switch opt := strings.ToUpper(string(args[i])); {
+		case opt == "MKSTREAM" && create:
+			mkStream = true
+		case opt == "ENTRIESREAD" && i+1 < len(args):
+			n, ok := parseCanonicalInt(args[i+1])
+			if !ok {
+				return false, 0, reply.MakeNotIntegerErrReply()
+			}
+			if n < 0 && n != stream.InvalidEntriesRead {
+				return false, 0, reply.MakeErrReply("ERR value for ENTRIESREAD must be positive or -1")
+			}
+			entriesRead = n
+			i++
+		default:
+			return false, 0, reply.MakeSyntaxErrReply()
+		}
+	}
+	return mkStream, entriesRead, nil
+}
+
+/**
+ * @description: XGROUP CREATE|SETID|DESTROY|CREATECONSUMER|DELCONSUMER|HELP ...
+ */
+func execXGroup(c *Client, args [][]byte) reply.Reply {
+	sub := strings.ToUpper(string(args[0]))
+	arities := map[string][2]int{
+		"CREATE":         {4, 7},
+		"SETID":          {4, 6},
+		"DESTROY":        {3, 3},
+		"CREATECONSUMER": {4, 4},
+		"DELCONSUMER":    {4, 4},
+		"HELP":           {1, 1},
+	}
+	arity, ok := arities[sub]
+	if !ok {
+		return makeUnknownSubcommandErrReply("XGROUP", args[0])
+	}
+	if len(args) < arity[0] || len(args) > arity[1] {
+		return reply.MakeArgNumErrReply("xgroup|" + strings.ToLower(sub))
+	}
+	if sub == "HELP" {
+		return makeHelpReply(xgroupHelp)
+	}
+
+	key, name := string(args[1]), string(args[2])
+	mkStream, entriesRead := false, int64(stream.InvalidEntriesRead)
+	if sub == "CREATE" || sub == "SETID" {
+		var errReply reply.ErrorReply
+		if mkStream, entriesRead, errReply = parseGroupOptions(args[4:], sub == "CREATE"); errReply != nil {
+			return errReply
+		}
+	}
+	now := dict.NowMillis()
+	return c.db.updateStream(key, mkStream, func(s *stream.Stream) reply.Reply {
+		if s == nil {
+			return reply.MakeErrReply("ERR The XGROUP subcommand requires the key to exist. " +
+				"Note that for CREATE you may want to use the MKSTREAM option to create an empty stream automatically.")
+		}
+		g := s.Group(name)
+		if g == nil && sub != "CREATE" && sub != "DESTROY" {
+			return reply.MakeErrReply("NOGROUP No such consumer group '" + name + "' for key name '" + key + "'")
+		}
+		switch sub {
+		case "CREATE":
+			id, errReply := parseGroupID(args[3], s)
+			if errReply != nil {
+				return errReply
+			}
+			if _, created := s.CreateGroup(name, id, entriesRead); !created {
+				return reply.MakeErrReply("BUSYGROUP Consumer Group name already exists")
+			}
+			return reply.MakeOkReply()
+		case "SETID":
+			id, errReply := parseGroupID(args[3], s)
+			if errReply != nil {
+				return errReply
+			}
+			g.LastID, g.EntriesRead = id, entriesRead
+			return reply.MakeOkReply()
+		case "DESTROY":
+			if s.DestroyGroup(name) {
+				return reply.MakeIntReply(1)
+			}
+			return c.changesReply(0)
+		case "CREATECONSUMER":
+			if _, created := g.CreateConsumer(string(args[3]), now); created {
+				return reply.MakeIntReply(1)
+			}
+			return c.changesReply(0)
+		default:
+			pending, _ := g.DeleteConsumer(string(args[3]))
+			return reply.MakeIntReply(pending)
+		}
+	})
+}
+
+//------------ XACK and XPENDING --------------
+
+/**
+ * @description: run fn on a group of the stream at key
+ * @return {*} reply of fn, or NOGROUP
+ */
+func (db *DB) updateGroup(key, name string, fn func(s *stream.Stream, g *stream.Group) reply.Reply) reply.Reply {
+	return db.updateStream(key, false, func(s *stream.Stream) reply.Reply {
+		var g *stream.Group
+		if s != nil {
+			g = s.Group(name)
+		}
+		if g == nil {
+			return makeNoGroupErrReply(key, name)
+		}
+		return fn(s, g)
+	})
+}
+
+/**
+ * @description: XACK key group id [id ...]
+ */
+func execXAck(c *Client, args [][]byte) reply.Reply {
+	ids, errReply := parseStreamIDs(args[2:])
+	if errReply != nil {
+		return errReply
+	}
+	return c.db.updateStream(string(args[0]), false, func(s *stream.Stream) reply.Reply {
+		acked := int64(0)
+		if s == nil {
+			return c.changesReply(0)
+		}
+		g := s.Group(string(args[1]))
+		if g == nil {
+			return c.changesReply(0)
+		}
+		for _, id := range ids {
+			if g.Ack(id) {
+				acked++
+			}
+		}
+		return c.changesReply(acked)
+	})
+}
+
+/**
+ * @description: XPENDING key group [[IDLE min-idle-time] start end count [consumer]]
+ */
+func execXPending(c *Client, args [][]byte) reply.Reply {
+	key, name := string(args[0]), string(args[1])
+	if len(args) == 2 {
+		return c.db.updateGroup(key, name, func(s *stream.Stream, g *stream.Group) reply.Reply {
+			if g.PendingLen() == 0 {
+				return reply.MakeArrayReply([]reply.Reply{
+					reply.MakeIntReply(0), reply.MakeNullBulkReply(), reply.MakeNullBulkReply(), reply.MakeNullArrayReply(),
+				})
+			}
+			first, last := g.PendingBounds()
+			var consumers []reply.Reply
+			for _, consumer := range g.Consumers() {
+				if n := consumer.PendingLen(); n > 0 {
+					consumers = append(consumers, reply.MakeMultiBulkReply([][]byte{
+						[]byte(consumer.Name), []byte(strconv.FormatInt(n, 10)),
+					}))
+				}
+			}
+			return reply.MakeArrayReply([]reply.Reply{
+				reply.MakeIntReply(g.PendingLen()), makeStreamIDReply(first), makeStreamIDReply(last),
+				reply.MakeArrayReply(consumers),
+			})
+		})
+	}
+
+	// extended form
+	i := 2
+	minIdle := int64(0)
+	if strings.ToUpper(string(args[i])) == "IDLE" && len(args) >= 7 {
+		var ok bool
+		if minIdle, ok = parseCanonicalInt(args[i+1]); !ok {
+			return reply.MakeNotIntegerErrReply()
+		}
+		i += 2
+	}
+	if rest := len(args) - i; rest != 3 && rest != 4 {
+		return reply.MakeSyntaxErrReply()
+	}
+	start, errReply := parseIntervalStart(args[i])
+	if errReply != nil {
+		return errReply
+	}
+	end, errReply := parseIntervalEnd(args[i+1])
+	if errReply != nil {
+		return errReply
+	}
+	count, ok := parseCanonicalInt(args[i+2])
+	if !ok {
+		return reply.MakeNotIntegerErrReply()
+	}
+	consumerName, filter := "", len(args)-i == 4
+	if filter {
+		consumerName = string(args[i+3])
+	}
+
+	return c.db.updateGroup(key, name, func(s *stream.Stream, g *stream.Group) reply.Reply {
+		result := make([]reply.Reply, 0)
+		if count <= 0 || end.Less(start) {
+			return reply.MakeArrayReply(result)
+		}
+		var pending []*stream.PendingEntry
+		if filter {
+			if consumer := g.Consumer(consumerName); consumer != nil {
+				pending = consumer.PendingRange(start, end, 0)
+			}
+		} else {
+			pending = g.PendingRange(start, end, 0)
+		}
+		now := dict.NowMillis()
+		for _, p := range pending {
+			idle := now - p.DeliveryTime
+			if idle < minIdle {
+				continue
+			}
+			result = append(result, reply.MakeArrayReply([]reply.Reply{
+				makeStreamIDReply(p.ID), reply.MakeBulkReply([]byte(p.Consumer.Name)),
+				reply.MakeIntReply(idle), reply.MakeIntReply(p.DeliveryCount),
+			}))
+			if int64(len(result)) == count {
+				break
+			}
+		}
+		return reply.MakeArrayReply(result)
+	})
+}
+
+//------------ XCLAIM and XAUTOCLAIM --------------
+
+/**
+ * @description: XCLAIM key group consumer min-idle-time id [id ...] [IDLE ms] [TIME unix-time-milliseconds]
+ *	[RETRYCOUNT count] [FORCE] [JUSTID] [LASTID lastid]
+ */
+func execXClaim(c *Client, args [][]byte) reply.Reply {
+	key, name, consumerName := string(args[0]), string(args[1]), string(args[2])
+	minIdle, ok := parseCanonicalInt(args[3])
+	if !ok {
+		return reply.MakeErrReply("ERR Invalid min-idle-time argument for XCLAIM")
+	}
+	if minIdle < 0 {
+		minIdle = 0
+	}
+
+	// IDs until the first option
+	var ids []stream.ID
+	i := 4
+	for ; i < len(args); i++ {
+		id, errReply := parseStreamID(args[i], 0)
+		if errReply != nil {
+			break
+		}
+		ids = append(ids, id)
+	}
+
+	now := dict.NowMillis()
+	deliveryTime, retryCount := int64(-1), int64(-1)
+	force, justID := false, false
+	var lastID *stream.ID
+	for ; i < len(args); i++ {
+		moreArgs := len(args) - 1 - i
+		switch opt := strings.ToUpper(string(args[i])); {
+		case opt == "FORCE":
+			force = true
+		case opt == "JUSTID":
+			justID = true
+		case opt == "IDLE" && moreArgs > 0:
+			idle, ok := parseCanonicalInt(args[i+1])
+			if !ok {
+				return reply.MakeErrReply("ERR Invalid IDLE option argument for XCLAIM")
+			}
+			deliveryTime = now - idle
+			i++
+		case opt == "TIME" && moreArgs > 0:
+			if deliveryTime, ok = parseCanonicalInt(args[i+1]); !ok {
+				return reply.MakeErrReply("ERR Invalid TIME option argument for XCLAIM")
+			}
+			i++
+		case opt == "RETRYCOUNT" && moreArgs > 0:
+			if retryCount, ok = parseCanonicalInt(args[i+1]); !ok {
+				return reply.MakeErrReply("ERR Invalid RETRYCOUNT option argument for XCLAIM")
+			}
+			i++
+		case opt == "LASTID" && moreArgs > 0:
+			id, errReply := parseStreamID(args[i+1], 0)
+			if errReply != nil {
+				return errReply
+			}
+			lastID = &id
+			i++
+		default:
+			return reply.MakeErrReply("ERR Unrecognized XCLAIM option '" + string(args[i]) + "'")
+		}
+	}
+	if deliveryTime < 0 || deliveryTime > now {
+		deliveryTime = now
+	}
+
+	return c.db.updateGroup(key, name, func(s *stream.Stream, g *stream.Group) reply.Reply {
+		if lastID != nil && g.LastID.Less(*lastID) {
+			g.LastID = *lastID
+		}
+		consumer := seeConsumer(g, consumerName, now)
+		result := make([]reply.Reply, 0, len(ids))
+		for _, id := range ids {
+			p := g.Pending(id)
+			e, exists := s.Get(id)
+			if !exists {
+				// the entry has been deleted, so it is not pending anymore
+				if p != nil {
+					g.Ack(id)
+				}
+				continue
+			}
+			if p == nil && !force {
+				continue
+			}
+			if p != nil && minIdle > 0 && now-p.DeliveryTime < minIdle {
+				continue
+			}
+			p = g.Assign(id, consumer, deliveryTime)
+			if retryCount >= 0 {
+				p.DeliveryCount = retryCount
+			} else if !justID {
+				p.DeliveryCount++
+			}
+			if justID {
+				result = append(result, makeStreamIDReply(id))
+			} else {
+				result = append(result, makeStreamEntryReply(e))
+			}
+			consumer.ActiveTime = now
+		}
+		return reply.MakeArrayReply(result)
+	})
+}
+
+/**
+ * @description: XAUTOCLAIM key group consumer min-idle-time start [COUNT count] [JUSTID]
+ */
+func execXAutoClaim(c *Client, args [][]byte) reply.Reply {
+	key, name, consumerName := string(args[0]), string(args[1]), string(args[2])
+	minIdle, ok := parseCanonicalInt(args[3])
+	if !ok {
+		return reply.MakeErrReply("ERR Invalid min-idle-time argument for XAUTOCLAIM")
+	}
+	if minIdle < 0 {
+		minIdle = 0
+	}
+	start, errReply := parseIntervalStart(args[4])
+	if errReply != nil {
+		return errReply
+	}
+
+	const attemptsFactor = 10
+	count, justID := int64(100), false
+	for i := 5; i < len(args); i++ {
+		switch opt := strings.ToUpper(string(args[i])); {
+		case opt == "COUNT" && i+1 < len(args):
+			if count, ok = parseCanonicalInt(args[i+1]); !ok || count < 1 || count > math.MaxInt32/attemptsFactor {
+				return reply.MakeErrReply("ERR COUNT must be > 0")
+			}
+			i++
+		case opt == "JUSTID":
+			justID = true
+		default:
+			return reply.MakeSyntaxErrReply()
+		}
+	}
+
+	now := dict.NowMillis()
+	return c.db.updateGroup(key, name, func(s *stream.Stream, g *stream.Group) reply.Reply {
+		consumer := seeConsumer(g, consumerName, now)
+		attempts := int(count * attemptsFactor)
+		// one more entry for the cursor
+		pending := g.PendingRange(start, stream.MaxID, attempts+1)
+		claimed := make([]reply.Reply, 0)
+		deleted := make([]reply.Reply, 0)
+		next := 0
+		for ; next < len(pending) && next < attempts && count > 0; next++ {
+			p := pending[next]
+			e, exists := s.Get(p.ID)
+			if !exists {
+				g.Ack(p.ID)
+				deleted = append(deleted, makeStreamIDReply(p.ID))
+				continue
+			}
+			if minIdle > 0 && now-p.DeliveryTime < minIdle {
+				continue
+			}
+			g.Assign(p.ID, consumer, now)
+			if justID {
+				claimed = append(claimed, makeStreamIDReply(p.ID))
+			} else {
+				p.DeliveryCount++
+				claimed = append(claimed, makeStreamEntryReply(e))
+			}
+			consumer.ActiveTime = now
+			count--
+		}
+
+		cursor := stream.ID{}
+		if next < len(pending) {
+			cursor = pending[next].ID
+		}
+		return reply.MakeArrayReply([]reply.Reply{
+			makeStreamIDReply(cursor), reply.MakeArrayReply(claimed), reply.MakeArrayReply(deleted),
+		})
+	})
+}
+
+//------------ XINFO --------------
+
+/**
+ * @description: first or last entry of a stream, null if empty
+ */
+func makeEdgeEntryReply(s *stream.Stream, last bool) reply.Reply {
+	entries := s.Range(stream.ID{}, stream.MaxID, 1, last)
+	if len(entries) == 0 {
+		return reply.MakeNullBulkReply()
+	}
+	return makeStreamEntryReply(entries[0])
+}
+
+/**
+ * @description: fields of XINFO STREAM shared by the full form
+ */
+func streamInfoHeader(s *stream.Stream) []reply.Reply {
+	return []reply.Reply{
+		reply.MakeBulkReply([]byte("length")), reply.MakeIntReply(s.Len()),
+		reply.MakeBulkReply([]byte("radix-tree-keys")), reply.MakeIntReply(int64(s.Nodes())),
+		reply.MakeBulkReply([]byte("radix-tree-nodes")), reply.MakeIntReply(int64(s.Nodes())),
+		reply.MakeBulkReply([]byte("last-generated-id")), makeStreamIDReply(s.LastID()),
+		reply.MakeBulkReply([]byte("max-deleted-entry-id")), makeStreamIDReply(s.MaxDeletedID()),
+		reply.MakeBulkReply([]byte("entries-added")), reply.MakeIntReply(s.EntriesAdded()),
+		reply.MakeBulkReply([]byte("recorded-first-entry-id")), makeStreamIDReply(s.FirstID()),
+	}
+}
+
+/**
+ * @description: entries read and lag of a group
+ */
+func groupProgress(s *stream.Stream, g *stream.Group) []reply.Reply {
+	lag, ok := s.Lag(g)
+	return []reply.Reply{
+		reply.MakeBulkReply([]byte("entries-read")), intOrNull(g.EntriesRead, g.EntriesRead != stream.InvalidEntriesRead),
+		reply.MakeBulkReply([]byte("lag")), intOrNull(lag, ok),
+	}
+}
+
+/**
+ * @description: XINFO STREAM key FULL [COUNT count]
+ */
+func streamFullInfo(s *stream.Stream, count int) reply.Reply {
+	pairs := streamInfoHeader(s)
+	pairs = append(pairs, reply.MakeBulkReply([]byte("entries")),
+		makeStreamEntriesReply(s.Range(stream.ID{}, stream.MaxID, count, false)))
+
+	var groups []reply.Reply
+	for _, g := range s.Groups() {
+		var pending []reply.Reply
+		for _, p := range g.PendingRange(stream.ID{}, stream.MaxID, count) {
+			pending = append(pending, reply.MakeArrayReply([]reply.Reply{
+				makeStreamIDReply(p.ID), reply.MakeBulkReply([]byte(p.Consumer.Name)),
+				reply.MakeIntReply(p.DeliveryTime), reply.MakeIntReply(p.DeliveryCount),
+			}))
+		}
+		var consumers []reply.Reply
+		for _, consumer := range g.Consumers() {
+			var consumerPending []reply.Reply
+			for _, p := range consumer.PendingRange(stream.ID{}, stream.MaxID, count) {
+				consumerPending = append(consumerPending, reply.MakeArrayReply([]reply.Reply{
+					makeStreamIDReply(p.ID), reply.MakeIntReply(p.DeliveryTime), reply.MakeIntReply(p.DeliveryCount),
+				}))
+			}
+			consumers = append(consumers, reply.MakeMapReply([]reply.Reply{
+				reply.MakeBulkReply([]byte("name")), reply.MakeBulkReply([]byte(consumer.Name)),
+				reply.MakeBulkReply([]byte("seen-time")), reply.MakeIntReply(consumer.SeenTime),
+				reply.MakeBulkReply([]byte("active-time")), reply.MakeIntReply(consumer.ActiveTime),
+				reply.MakeBulkReply([]byte("pel-count")), reply.MakeIntReply(consumer.PendingLen()),
+				reply.MakeBulkReply([]byte("pending")), reply.MakeArrayReply(consumerPending),
+			}))
+		}
+		group := []reply.Reply{
+			reply.MakeBulkReply([]byte("name")), reply.MakeBulkReply([]byte(g.Name)),
+			reply.MakeBulkReply([]byte("last-delivered-id")), makeStreamIDReply(g.LastID),
+		}
+		group = append(group, groupProgress(s, g)...)
+		group = append(group,
+			reply.MakeBulkReply([]byte("pel-count")), reply.MakeIntReply(g.PendingLen()),
+			reply.MakeBulkReply([]byte("pending")), reply.MakeArrayReply(pending),
+			reply.MakeBulkReply([]byte("consumers")), reply.MakeArrayReply(consumers),
+		)
+		groups = append(groups, reply.MakeMapReply(group))
+	}
+	pairs = append(pairs, reply.MakeBulkReply([]byte("groups")), reply.MakeArrayReply(groups))
+	return reply.MakeMapReply(pairs)
+}
+
+/**
+ * @description: XINFO STREAM key [FULL [COUNT count]]
+ */
+func xinfoStream(c *Client, args [][]byte) reply.Reply {
+	full, count := false, int64(10)
+	switch {
+	case len(args) == 1:
+	case len(args) == 2 && strings.ToUpper(string(args[1])) == "FULL":
+		full = true
+	case len(args) == 4 && strings.ToUpper(string(args[1])) == "FULL" && strings.ToUpper(string(args[2])) == "COUNT":
+		var ok bool
+		if count, ok = parseCanonicalInt(args[3]); !ok {
+			return reply.MakeNotIntegerErrReply()
+		}
+		if count < 0 {
+			count = 10
+		}
+		if count > math.MaxInt32 {
+			count = math.MaxInt32
+		}
+		full = true
+	default:
+		return reply.MakeSyntaxErrReply()
+	}
+
+	return c.db.updateStream(string(args[0]), false, func(s *stream.Stream) reply.Reply {
+		if s == nil {
+			return reply.MakeErrReply("ERR no such key")
+		}
+		if full {
+			return streamFullInfo(s, int(count))
+		}
+		pairs := streamInfoHeader(s)
+		pairs = append(pairs,
+			reply.MakeBulkReply([]byte("groups")), reply.MakeIntReply(int64(len(s.Groups()))),
+			reply.MakeBulkReply([]byte("first-entry")), makeEdgeEntryReply(s, false),
+			reply.MakeBulkReply([]byte("last-entry")), makeEdgeEntryReply(s, true),
+		)
+		return reply.MakeMapReply(pairs)
+	})
+}
+
+/**
+ * @description: XINFO GROUPS key
+ */
+func xinfoGroups(c *Client, key string) reply.Reply {
+	return c.db.updateStream(key, false, func(s *stream.Stream) reply.Reply {
+		if s == nil {
+			return reply.MakeErrReply("ERR no such key")
+		}
+		groups := make([]reply.Reply, 0)
+		for _, g := range s.Groups() {
+			pairs := []reply.Reply{
+				reply.MakeBulkReply([]byte("name")), reply.MakeBulkReply([]byte(g.Name)),
+				reply.MakeBulkReply([]byte("consumers")), reply.MakeIntReply(int64(g.ConsumerCount())),
+				reply.MakeBulkReply([]byte("pending")), reply.MakeIntReply(g.PendingLen()),
+				reply.MakeBulkReply([]byte("last-delivered-id")), makeStreamIDReply(g.LastID),
+			}
+			groups = append(groups, reply.MakeMapReply(append(pairs, groupProgress(s, g)...)))
+		}
+		return reply.MakeArrayReply(groups)
+	})
+}
+
+/**
+ * @description: XINFO CONSUMERS key group
+ */
+func xinfoConsumers(c *Client, key, name string) reply.Reply {
+	return c.db.updateStream(key, false, func(s *stream.Stream) reply.Reply {
+		if s == nil {
+			return reply.MakeErrReply("ERR no such key")
+		}
+		g := s.Group(name)
+		if g == nil {
+			return reply.MakeErrReply("NOGROUP No such consumer group '" + name + "' for key name '" + key + "'")
+		}
+		now := dict.NowMillis()
+		consumers := make([]reply.Reply, 0)
+		for _, consumer := range g.Consumers() {
+			inactive := int64(-1)
+			if consumer.ActiveTime >= 0 {
+				inactive = now - consumer.ActiveTime
+			}
+			consumers = append(consumers, reply.MakeMapReply([]reply.Reply{
+				reply.MakeBulkReply([]byte("name")), reply.MakeBulkReply([]byte(consumer.Name)),
+				reply.MakeBulkReply([]byte("pending")), reply.MakeIntReply(consumer.PendingLen()),
+				reply.MakeBulkReply([]byte("idle")), reply.MakeIntReply(now - consumer.SeenTime),
+				reply.MakeBulkReply([]byte("inactive")), reply.MakeIntReply(inactive),
+			}))
+		}
+		return reply.MakeArrayReply(consumers)
+	})
+}
+
+/**
+ * @description: XINFO STREAM|GROUPS|CONSUMERS|HELP ...
+ */
+func execXInfo(c *Client, args [][]byte) reply.Reply {
+	switch sub := strings.ToUpper(string(args[0])); sub {
+	case "STREAM":
+		if len(args) < 2 {
+			return reply.MakeArgNumErrReply("xinfo|stream")
+		}
+		return xinfoStream(c, args[1:])
+	case "GROUPS":
+		if len(args) != 2 {
+			return reply.MakeArgNumErrReply("xinfo|groups")
+		}
+		return xinfoGroups(c, string(args[1]))
+	case "CONSUMERS":
+		if len(args) != 3 {
+			return reply.MakeArgNumErrReply("xinfo|consumers")
+		}
+		return xinfoConsumers(c, string(args[1]), string(args[2]))
+	case "HELP":
+		if len(args) != 1 {
+			return reply.MakeArgNumErrReply("xinfo|help")
+		}
+		return makeHelpReply(xinfoHelp)
+	}
+	return makeUnknownSubcommandErrReply("XINFO", args[0])
+}
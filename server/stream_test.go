@@ -0,0 +1,334 @@
+/*
+ * @Description:
+ * @Autor: HTmonster
+ * @Date: 2026-10-19 23:20:45
+ */
+package server
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/HTmonster/redissgo/client"
+	"github.com/HTmonster/redissgo/internal/reply"
+)
+
+/**
+ * @description: bulk string in RESP
+ */
+func bulk(s string) string {
+	return "$" + strconv.Itoa(len(s)) + "\r\n" + s + "\r\n"
+}
+
+/**
+ * @description: reply of a stream entry
+ */
+func streamEntry(id string, fields ...string) string {
+	s := "*2\r\n" + bulk(id) + "*" + strconv.Itoa(len(fields)) + "\r\n"
+	for _, f := range fields {
+		s += bulk(f)
+	}
+	return s
+}
+
+/**
+ * @description: array header
+ */
+func arrayOf(n int) string {
+	return "*" + strconv.Itoa(n) + "\r\n"
+}
+
+func TestXAddRange(t *testing.T) {
+	h := NewHandler()
+	defer h.Close()
+	c := newTestClient(h)
+
+	runCases(t, h, c, []cmdCase{
+		{[]string{"XADD", "s", "1-1", "a", "1"}, bulk("1-1")},
+		{[]string{"XADD", "s", "1-*", "b", "2"}, bulk("1-2")},
+		{[]string{"XADD", "s", "2", "c", "3", "d", "4"}, bulk("2-0")},
+		{[]string{"XADD", "s", "2-0", "x", "y"}, "-ERR The ID specified in XADD is equal or smaller than the target stream top item\r\n"},
+		{[]string{"XADD", "s", "1-*", "x", "y"}, "-ERR The ID specified in XADD is equal or smaller than the target stream top item\r\n"},
+		{[]string{"XADD", "new", "0-0", "x", "y"}, "-ERR The ID specified in XADD must be greater than 0-0\r\n"},
+		{[]string{"XADD", "s", "abc", "x", "y"}, "-ERR Invalid stream ID specified as stream command argument\r\n"},
+		{[]string{"XADD", "s", "*", "x"}, "-ERR wrong number of arguments for 'xadd' command\r\n"},
+		{[]string{"XADD", "none", "NOMKSTREAM", "*", "x", "y"}, "$-1\r\n"},
+		{[]string{"XLEN", "none"}, ":0\r\n"},
+		{[]string{"XLEN", "new"}, ":0\r\n"},
+		{[]string{"XLEN", "s"}, ":3\r\n"},
+
+		{[]string{"XRANGE", "s", "-", "+"}, arrayOf(3) + streamEntry("1-1", "a", "1") + streamEntry("1-2", "b", "2") + streamEntry("2-0", "c", "3", "d", "4")},
+		{[]string{"XRANGE", "s", "1", "1"}, arrayOf(2) + streamEntry("1-1", "a", "1") + streamEntry("1-2", "b", "2")},
+		{[]string{"XRANGE", "s", "(1-1", "+", "COUNT", "1"}, arrayOf(1) + streamEntry("1-2", "b", "2")},
+		{[]string{"XRANGE", "s", "-", "+", "COUNT", "0"}, "*-1\r\n"},
+		{[]string{"XRANGE", "s", "(18446744073709551615-18446744073709551615", "+"}, "-ERR invalid start ID for the interval\r\n"},
+		{[]string{"XRANGE", "s", "-", "(0-0"}, "-ERR invalid end ID for the interval\r\n"},
+		{[]string{"XRANGE", "s", "(-", "+"}, "-ERR Invalid stream ID specified as stream command argument\r\n"},
+		{[]string{"XRANGE", "none", "-", "+"}, "*0\r\n"},
+		{[]string{"XREVRANGE", "s", "+", "-", "COUNT", "2"}, arrayOf(2) + streamEntry("2-0", "c", "3", "d", "4") + streamEntry("1-2", "b", "2")},
+		{[]string{"XREVRANGE", "s", "(2-0", "1-2"}, arrayOf(1) + streamEntry("1-2", "b", "2")},
+
+		{[]string{"XDEL", "s", "1-2", "5-5"}, ":1\r\n"},
+		{[]string{"XDEL", "s", "bad"}, "-ERR Invalid stream ID specified as stream command argument\r\n"},
+		{[]string{"XRANGE", "s", "-", "+"}, arrayOf(2) + streamEntry("1-1", "a", "1") + streamEntry("2-0", "c", "3", "d", "4")},
+		{[]string{"SET", "str", "v"}, "+OK\r\n"},
+		{[]string{"XADD", "str", "*", "a", "1"}, wrongType},
+		{[]string{"XRANGE", "str", "-", "+"}, wrongType},
+	})
+
+	// automatic IDs grow
+	first := execString(h, c, "XADD", "auto", "*", "a", "1")
+	second := execString(h, c, "XADD", "auto", "*", "a", "1")
+	if first == second {
+		t.Errorf("IDs should grow: %q %q", first, second)
+	}
+}
+
+func TestXTrim(t *testing.T) {
+	h := NewHandler()
+	defer h.Close()
+	c := newTestClient(h)
+
+	for i := 1; i <= 10; i++ {
+		execString(h, c, "XADD", "s", strconv.Itoa(i), "f", "v")
+	}
+	runCases(t, h, c, []cmdCase{
+		{[]string{"XTRIM", "s", "MAXLEN", "8"}, ":2\r\n"},
+		{[]string{"XTRIM", "s", "MINID", "=", "5"}, ":2\r\n"},
+		{[]string{"XRANGE", "s", "-", "+", "COUNT", "1"}, arrayOf(1) + streamEntry("5-0", "f", "v")},
+		// the entries fit in a single node, so nothing is removed
+		{[]string{"XTRIM", "s", "MAXLEN", "~", "1"}, ":0\r\n"},
+		{[]string{"XADD", "s", "MAXLEN", "2", "11", "f", "v"}, bulk("11-0")},
+		{[]string{"XLEN", "s"}, ":2\r\n"},
+		{[]string{"XTRIM", "none", "MAXLEN", "0"}, ":0\r\n"},
+
+		{[]string{"XTRIM", "s", "MAXLEN", "-1"}, "-ERR The MAXLEN argument must be >= 0.\r\n"},
+		{[]string{"XTRIM", "s", "MAXLEN", "1", "LIMIT", "10"}, "-ERR syntax error, LIMIT cannot be used without the special ~ option\r\n"},
+		{[]string{"XTRIM", "s", "MAXLEN", "~", "1", "LIMIT", "-1"}, "-ERR The LIMIT argument must be >= 0.\r\n"},
+		{[]string{"XTRIM", "s", "MAXLEN", "1", "MINID", "1"}, "-ERR syntax error, MAXLEN and MINID options at the same time are not compatible\r\n"},
+		{[]string{"XTRIM", "s", "LIMIT", "1"}, "-ERR syntax error, LIMIT cannot be used without specifying a trimming strategy\r\n"},
+		{[]string{"XTRIM", "s", "FOO", "1"}, "-ERR syntax error\r\n"},
+		{[]string{"XTRIM", "s", "MAXLEN", "x"}, "-ERR value is not an integer or out of range\r\n"},
+	})
+}
+
+func TestXRead(t *testing.T) {
+	h := NewHandler()
+	defer h.Close()
+	c := newTestClient(h)
+
+	runCases(t, h, c, []cmdCase{
+		{[]string{"XADD", "a", "1-0", "f", "1"}, bulk("1-0")},
+		{[]string{"XADD", "a", "2-0", "f", "2"}, bulk("2-0")},
+		{[]string{"XADD", "b", "3-0", "f", "3"}, bulk("3-0")},
+		{[]string{"XREAD", "STREAMS", "a", "b", "1-0", "0"},
+			arrayOf(2) + arrayOf(2) + bulk("a") + arrayOf(1) + streamEntry("2-0", "f", "2") +
+				arrayOf(2) + bulk("b") + arrayOf(1) + streamEntry("3-0", "f", "3")},
+		{[]string{"XREAD", "COUNT", "1", "STREAMS", "a", "0"}, arrayOf(1) + arrayOf(2) + bulk("a") + arrayOf(1) + streamEntry("1-0", "f", "1")},
+		{[]string{"XREAD", "STREAMS", "a", "none", "$", "$"}, "*-1\r\n"},
+		{[]string{"XREAD", "BLOCK", "10", "STREAMS", "a", "$"}, "*-1\r\n"},
+		{[]string{"XREAD", "STREAMS", "a", "b", "0"}, "-ERR Unbalanced 'xread' list of streams: for each stream key an ID or '$' must be specified.\r\n"},
+		{[]string{"XREAD", "STREAMS", "a", ">"}, "-ERR The > ID can be specified only when calling XREADGROUP using the GROUP <group> <consumer> option.\r\n"},
+		{[]string{"XREAD", "GROUP", "g", "c", "STREAMS", "a", "0"}, "-ERR The GROUP option is only supported by XREADGROUP. You called XREAD instead.\r\n"},
+		{[]string{"XREAD", "BLOCK", "-1", "STREAMS", "a", "0"}, "-ERR timeout is negative\r\n"},
+		{[]string{"XREAD", "COUNT", "1", "a", "0"}, "-ERR syntax error\r\n"},
+	})
+
+	c.SetProtocol(reply.RESP3)
+	runCases(t, h, c, []cmdCase{
+		{[]string{"XREAD", "STREAMS", "a", "1"}, "%1\r\n" + bulk("a") + arrayOf(1) + streamEntry("2-0", "f", "2")},
+		{[]string{"XREAD", "STREAMS", "a", "2"}, "_\r\n"},
+	})
+}
+
+func TestXReadBlocked(t *testing.T) {
+	h, addr, stop := startTestServer(t)
+	defer stop()
+	reader, groupReader, writer := dial(t, addr), dial(t, addr), dial(t, addr)
+	defer reader.Close()
+	defer groupReader.Close()
+	defer writer.Close()
+
+	if _, err := writer.Do("XGROUP", "CREATE", "s", "g", "$", "MKSTREAM"); err != nil {
+		t.Fatal(err)
+	}
+	read := doAsync(reader, "XREAD", "BLOCK", "0", "STREAMS", "other", "s", "$", "$")
+	waitBlocked(t, h, 0, "s", 1)
+	readGroup := doAsync(groupReader, "XREADGROUP", "GROUP", "g", "alice", "BLOCK", "0", "STREAMS", "s", ">")
+	waitBlocked(t, h, 0, "s", 2)
+
+	if _, err := writer.Do("XADD", "s", "5-0", "f", "v"); err != nil {
+		t.Fatal(err)
+	}
+	entries := []interface{}{[]interface{}{[]byte("5-0"), []interface{}{[]byte("f"), []byte("v")}}}
+	expectReply(t, read, []interface{}{[]interface{}{[]byte("s"), entries}})
+	expectReply(t, readGroup, []interface{}{[]interface{}{[]byte("s"), entries}})
+	if blockedOn(h, 0, "other") != 0 {
+		t.Error("served clients should be unregistered from all keys")
+	}
+	if n, err := client.Int64(writer.Do("XACK", "s", "g", "5-0")); err != nil || n != 1 {
+		t.Errorf("the entry should be pending: %d %v", n, err)
+	}
+}
+
+func TestXGroup(t *testing.T) {
+	h := NewHandler()
+	defer h.Close()
+	c := newTestClient(h)
+
+	runCases(t, h, c, []cmdCase{
+		{[]string{"XGROUP", "CREATE", "s", "g", "$"}, "-ERR The XGROUP subcommand requires the key to exist. Note that for CREATE you may want to use the MKSTREAM option to create an empty stream automatically.\r\n"},
+		{[]string{"XGROUP", "CREATE", "s", "g", "bad", "MKSTREAM"}, "-ERR Invalid stream ID specified as stream command argument\r\n"},
+		{[]string{"XLEN", "s"}, ":0\r\n"},
+		{[]string{"XGROUP", "CREATE", "s", "g", "$", "MKSTREAM"}, "+OK\r\n"},
+		{[]string{"XGROUP", "CREATE", "s", "g", "0"}, "-BUSYGROUP Consumer Group name already exists\r\n"},
+		{[]string{"XGROUP", "CREATE", "s", "g2", "0", "ENTRIESREAD", "-2"}, "-ERR value for ENTRIESREAD must be positive or -1\r\n"},
+		{[]string{"XGROUP", "CREATECONSUMER", "s", "g", "alice"}, ":1\r\n"},
+		{[]string{"XGROUP", "CREATECONSUMER", "s", "g", "alice"}, ":0\r\n"},
+		{[]string{"XGROUP", "CREATECONSUMER", "s", "none", "alice"}, "-NOGROUP No such consumer group 'none' for key name 's'\r\n"},
+		{[]string{"XGROUP", "SETID", "s", "g", "0", "ENTRIESREAD", "0"}, "+OK\r\n"},
+		{[]string{"XADD", "s", "1-0", "f", "v"}, bulk("1-0")},
+		{[]string{"XREADGROUP", "GROUP", "g", "alice", "STREAMS", "s", ">"}, arrayOf(1) + arrayOf(2) + bulk("s") + arrayOf(1) + streamEntry("1-0", "f", "v")},
+		{[]string{"XGROUP", "DELCONSUMER", "s", "g", "alice"}, ":1\r\n"},
+		{[]string{"XGROUP", "DELCONSUMER", "s", "g", "alice"}, ":0\r\n"},
+		{[]string{"XGROUP", "DESTROY", "s", "g"}, ":1\r\n"},
+		{[]string{"XGROUP", "DESTROY", "s", "g"}, ":0\r\n"},
+		{[]string{"XGROUP", "FOO"}, "-ERR unknown subcommand 'FOO'. Try XGROUP HELP.\r\n"},
+		{[]string{"XGROUP", "CREATE", "s"}, "-ERR wrong number of arguments for 'xgroup|create' command\r\n"},
+		{[]string{"XGROUP", "CREATE", "s", "g", "$", "FOO"}, "-ERR syntax error\r\n"},
+	})
+	if got := execString(h, c, "XGROUP", "HELP"); !strings.HasPrefix(got, "*") {
+		t.Errorf("help: %q", got)
+	}
+}
+
+func TestXReadGroup(t *testing.T) {
+	h := NewHandler()
+	defer h.Close()
+	c := newTestClient(h)
+
+	runCases(t, h, c, []cmdCase{
+		{[]string{"XADD", "s", "1-0", "f", "1"}, bulk("1-0")},
+		{[]string{"XADD", "s", "2-0", "f", "2"}, bulk("2-0")},
+		{[]string{"XADD", "s", "3-0", "f", "3"}, bulk("3-0")},
+		{[]string{"XGROUP", "CREATE", "s", "g", "0"}, "+OK\r\n"},
+		{[]string{"XREADGROUP", "GROUP", "g", "alice", "COUNT", "2", "STREAMS", "s", ">"},
+			arrayOf(1) + arrayOf(2) + bulk("s") + arrayOf(2) + streamEntry("1-0", "f", "1") + streamEntry("2-0", "f", "2")},
+		{[]string{"XREADGROUP", "GROUP", "g", "bob", "NOACK", "STREAMS", "s", ">"},
+			arrayOf(1) + arrayOf(2) + bulk("s") + arrayOf(1) + streamEntry("3-0", "f", "3")},
+		{[]string{"XREADGROUP", "GROUP", "g", "bob", "STREAMS", "s", ">"}, "*-1\r\n"},
+
+		// history of the consumer, deleted entries have no fields
+		{[]string{"XDEL", "s", "1-0"}, ":1\r\n"},
+		{[]string{"XREADGROUP", "GROUP", "g", "alice", "STREAMS", "s", "0"},
+			arrayOf(1) + arrayOf(2) + bulk("s") + arrayOf(2) + "*2\r\n" + bulk("1-0") + "*-1\r\n" + streamEntry("2-0", "f", "2")},
+		{[]string{"XREADGROUP", "GROUP", "g", "bob", "STREAMS", "s", "0"}, arrayOf(1) + arrayOf(2) + bulk("s") + "*0\r\n"},
+
+		{[]string{"XACK", "s", "g", "1-0", "2-0", "3-0"}, ":2\r\n"},
+		{[]string{"XACK", "s", "none", "1-0"}, ":0\r\n"},
+		{[]string{"XACK", "s", "g", "bad"}, "-ERR Invalid stream ID specified as stream command argument\r\n"},
+
+		{[]string{"XREADGROUP", "GROUP", "none", "alice", "STREAMS", "s", ">"}, "-NOGROUP No such key 's' or consumer group 'none' in XREADGROUP with GROUP option\r\n"},
+		{[]string{"XREADGROUP", "GROUP", "g", "alice", "STREAMS", "s", "$"}, "-ERR The $ ID is meaningless in the context of XREADGROUP: you want to read the history of this consumer by specifying a proper ID, or use the > ID to get new messages. The $ ID would just return an empty result set.\r\n"},
+		{[]string{"XREADGROUP", "COUNT", "1", "NOACK", "STREAMS", "s", ">"}, "-ERR Missing GROUP option for XREADGROUP\r\n"},
+	})
+}
+
+func TestXPendingClaim(t *testing.T) {
+	h := NewHandler()
+	defer h.Close()
+	c := newTestClient(h)
+
+	for i := 1; i <= 4; i++ {
+		execString(h, c, "XADD", "s", strconv.Itoa(i), "f", strconv.Itoa(i))
+	}
+	runCases(t, h, c, []cmdCase{
+		{[]string{"XGROUP", "CREATE", "s", "g", "0"}, "+OK\r\n"},
+		{[]string{"XPENDING", "s", "g"}, "*4\r\n:0\r\n$-1\r\n$-1\r\n*-1\r\n"},
+		{[]string{"XREADGROUP", "GROUP", "g", "alice", "COUNT", "3", "STREAMS", "s", ">"},
+			arrayOf(1) + arrayOf(2) + bulk("s") + arrayOf(3) + streamEntry("1-0", "f", "1") + streamEntry("2-0", "f", "2") + streamEntry("3-0", "f", "3")},
+		{[]string{"XREADGROUP", "GROUP", "g", "bob", "STREAMS", "s", ">"},
+			arrayOf(1) + arrayOf(2) + bulk("s") + arrayOf(1) + streamEntry("4-0", "f", "4")},
+		{[]string{"XPENDING", "s", "g"}, "*4\r\n:4\r\n" + bulk("1-0") + bulk("4-0") +
+			arrayOf(2) + arrayOf(2) + bulk("alice") + bulk("3") + arrayOf(2) + bulk("bob") + bulk("1")},
+		{[]string{"XPENDING", "s", "g", "IDLE", "1000000", "-", "+", "10"}, "*0\r\n"},
+		{[]string{"XPENDING", "s", "none"}, "-NOGROUP No such key 's' or consumer group 'none'\r\n"},
+		{[]string{"XPENDING", "s", "g", "-", "+"}, "-ERR syntax error\r\n"},
+
+		// claim with the delivery counter set
+		{[]string{"XCLAIM", "s", "g", "bob", "0", "1-0", "RETRYCOUNT", "5"}, arrayOf(1) + streamEntry("1-0", "f", "1")},
+		{[]string{"XCLAIM", "s", "g", "bob", "0", "2-0", "9-0", "JUSTID"}, arrayOf(1) + bulk("2-0")},
+		{[]string{"XCLAIM", "s", "g", "bob", "1000000", "3-0"}, "*0\r\n"},
+		{[]string{"XCLAIM", "s", "g", "bob", "0", "3-0", "FOO"}, "-ERR Unrecognized XCLAIM option 'FOO'\r\n"},
+		{[]string{"XCLAIM", "s", "g", "bob", "x", "3-0"}, "-ERR Invalid min-idle-time argument for XCLAIM\r\n"},
+		{[]string{"XCLAIM", "s", "none", "bob", "0", "3-0"}, "-NOGROUP No such key 's' or consumer group 'none'\r\n"},
+		{[]string{"XACK", "s", "g", "4-0"}, ":1\r\n"},
+		{[]string{"XCLAIM", "s", "g", "bob", "0", "4-0", "JUSTID"}, "*0\r\n"},
+		{[]string{"XCLAIM", "s", "g", "bob", "0", "4-0", "FORCE", "JUSTID"}, arrayOf(1) + bulk("4-0")},
+	})
+
+	// the extended form lists the entries of bob with their delivery counters
+	got := execString(h, c, "XPENDING", "s", "g", "-", "+", "10", "bob")
+	for _, want := range []string{bulk("1-0") + bulk("bob"), ":5\r\n", bulk("2-0") + bulk("bob"), bulk("4-0") + bulk("bob")} {
+		if !strings.Contains(got, want) {
+			t.Errorf("XPENDING of bob: %q should contain %q", got, want)
+		}
+	}
+	if !strings.HasPrefix(got, arrayOf(3)) {
+		t.Errorf("XPENDING of bob: %q", got)
+	}
+
+	runCases(t, h, c, []cmdCase{
+		{[]string{"XDEL", "s", "2-0"}, ":1\r\n"},
+		{[]string{"XAUTOCLAIM", "s", "g", "alice", "0", "0", "COUNT", "1"},
+			arrayOf(3) + bulk("2-0") + arrayOf(1) + streamEntry("1-0", "f", "1") + "*0\r\n"},
+		{[]string{"XAUTOCLAIM", "s", "g", "alice", "0", "2-0", "JUSTID"},
+			arrayOf(3) + bulk("0-0") + arrayOf(2) + bulk("3-0") + bulk("4-0") + arrayOf(1) + bulk("2-0")},
+		{[]string{"XAUTOCLAIM", "s", "g", "alice", "0", "0", "COUNT", "0"}, "-ERR COUNT must be > 0\r\n"},
+		{[]string{"XPENDING", "s", "g", "-", "+", "10", "bob"}, "*0\r\n"},
+	})
+}
+
+func TestXInfo(t *testing.T) {
+	h := NewHandler()
+	defer h.Close()
+	c := newTestClient(h)
+
+	execString(h, c, "XADD", "s", "1-0", "f", "1")
+	execString(h, c, "XADD", "s", "2-0", "f", "2")
+	execString(h, c, "XGROUP", "CREATE", "s", "g", "0")
+	execString(h, c, "XREADGROUP", "GROUP", "g", "alice", "COUNT", "1", "STREAMS", "s", ">")
+
+	runCases(t, h, c, []cmdCase{
+		{[]string{"XINFO", "STREAM", "s"}, arrayOf(20) +
+			bulk("length") + ":2\r\n" + bulk("radix-tree-keys") + ":1\r\n" + bulk("radix-tree-nodes") + ":1\r\n" +
+			bulk("last-generated-id") + bulk("2-0") + bulk("max-deleted-entry-id") + bulk("0-0") +
+			bulk("entries-added") + ":2\r\n" + bulk("recorded-first-entry-id") + bulk("1-0") +
+			bulk("groups") + ":1\r\n" + bulk("first-entry") + streamEntry("1-0", "f", "1") +
+			bulk("last-entry") + streamEntry("2-0", "f", "2")},
+		{[]string{"XINFO", "GROUPS", "s"}, arrayOf(1) + arrayOf(12) +
+			bulk("name") + bulk("g") + bulk("consumers") + ":1\r\n" + bulk("pending") + ":1\r\n" +
+			bulk("last-delivered-id") + bulk("1-0") + bulk("entries-read") + ":1\r\n" + bulk("lag") + ":1\r\n"},
+		{[]string{"XINFO", "STREAM", "none"}, "-ERR no such key\r\n"},
+		{[]string{"XINFO", "CONSUMERS", "s", "none"}, "-NOGROUP No such consumer group 'none' for key name 's'\r\n"},
+		{[]string{"XINFO", "STREAM", "s", "FOO"}, "-ERR syntax error\r\n"},
+		{[]string{"XINFO", "FOO"}, "-ERR unknown subcommand 'FOO'. Try XINFO HELP.\r\n"},
+	})
+
+	got := execString(h, c, "XINFO", "CONSUMERS", "s", "g")
+	if !strings.HasPrefix(got, arrayOf(1)+arrayOf(8)+bulk("name")+bulk("alice")+bulk("pending")+":1\r\n"+bulk("idle")) {
+		t.Errorf("XINFO CONSUMERS: %q", got)
+	}
+	got = execString(h, c, "XINFO", "STREAM", "s", "FULL", "COUNT", "1")
+	for _, want := range []string{bulk("entries") + arrayOf(1) + streamEntry("1-0", "f", "1"), bulk("pel-count") + ":1\r\n", bulk("seen-time")} {
+		if !strings.Contains(got, want) {
+			t.Errorf("XINFO STREAM FULL: %q should contain %q", got, want)
+		}
+	}
+
+	c.SetProtocol(reply.RESP3)
+	if got := execString(h, c, "XINFO", "GROUPS", "s"); !strings.HasPrefix(got, arrayOf(1)+"%6\r\n") {
+		t.Errorf("RESP3 XINFO GROUPS: %q", got)
+	}
+}
@@ -0,0 +1,624 @@
+/*
+ * @Description: string commands
+ * @Autor: HTmonster
+ * @Date: 2026-10-18 20:10:27
+ */
+
+package server
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/HTmonster/redissgo/datastruct/dict"
+	"github.com/HTmonster/redissgo/internal/config"
+	"github.com/HTmonster/redissgo/internal/reply"
+)
+
+func init() {
+	registerCommand("get", execGet, 2, flagReadonly|flagFast).keys(1, 1, 1)
+	registerCommand("set", execSet, -3, flagWrite).keys(1, 1, 1)
+	registerCommand("setnx", execSetNX, 3, flagWrite|flagFast).keys(1, 1, 1)
+	registerCommand("setex", execSetEX, 4, flagWrite).keys(1, 1, 1)
+	registerCommand("psetex", execPSetEX, 4, flagWrite).keys(1, 1, 1)
+	registerCommand("getset", execGetSet, 3, flagWrite|flagFast).keys(1, 1, 1)
+	registerCommand("getdel", execGetDel, 2, flagWrite|flagFast).keys(1, 1, 1)
+	registerCommand("getex", execGetEX, -2, flagWrite|flagFast).keys(1, 1, 1)
+	registerCommand("mget", execMGet, -2, flagReadonly|flagFast).keys(1, -1, 1)
+	registerCommand("mset", execMSet, -3, flagWrite).keys(1, -1, 2)
+	registerCommand("msetnx", execMSetNX, -3, flagWrite).keys(1, -1, 2)
+	registerCommand("append", execAppend, 3, flagWrite|flagFast).keys(1, 1, 1)
+	registerCommand("strlen", execStrLen, 2, flagReadonly|flagFast).keys(1, 1, 1)
+	registerCommand("getrange", execGetRange, 4, flagReadonly).keys(1, 1, 1)
+	registerCommand("setrange", execSetRange, 4, flagWrite).keys(1, 1, 1)
+}
+
+// longest decimal representation of an int64
+const maxInt64Digits = 20
+
+/**
+ * @description: parse a canonical base 10 integer like redis string2ll,
+ *	no sign '+', no leading zeros, no spaces
+ * @param {[]byte} b
+ * @return {*} number, ok or not
+ */
+func parseCanonicalInt(b []byte) (int64, bool) {
+	if len(b) == 0 || len(b) > maxInt64Digits {
+		return 0, false
+	}
+	n, err := strconv.ParseInt(string(b), 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	// rejects "+1", "01", "-0"
+	var buf [maxInt64Digits]byte
+	if string(strconv.AppendInt(buf[:0], n, 10)) != string(b) {
+		return 0, false
+	}
+	return n, true
+}
+
+/**
+ * @description: make the value to store for a string argument,
+ *	integers are kept as int64 instead of bytes
+ * @param {[]byte} arg
+ * @return {*} int64 or a copy of arg
+ */
+func makeString(arg []byte) interface{} {
+	if n, ok := parseCanonicalInt(arg); ok {
+		return n
+	}
+	return copyBytes(arg)
+}
+
+/**
+ * @description: get the bytes of a string value
+ * @param {interface{}} entity
+ * @return {*} bytes, is string or not
+ */
+func asString(entity interface{}) ([]byte, bool) {
+	switch value := entity.(type) {
+	case []byte:
+		return value, true
+	case int64:
+		return strconv.AppendInt(nil, value, 10), true
+	}
+	return nil, false
+}
+
+/**
+ * @description: read a string value under the read lock of its key. Stored
+ *	strings are written in place by APPEND, SETRANGE, SETBIT and BITFIELD
+ *	under the write lock, so reader must not keep the bytes after it returns.
+ * @param {string} key
+ * @param {func([]byte)} reader bytes are nil if the key does not exist
+ * @return {*} WRONGTYPE error
+ */
+func (db *DB) readString(key string, reader func(bytes []byte)) reply.ErrorReply {
+	keys := []string{key}
+	db.Locks(nil, keys)
+	defer db.Unlocks(nil, keys)
+	entity, ok := db.GetEntityWithLock(key)
+	if !ok {
+		reader(nil)
+		return nil
+	}
+	bytes, ok := asString(entity)
+	if !ok {
+		return reply.MakeWrongTypeErrReply()
+	}
+	reader(bytes)
+	return nil
+}
+
+/**
+ * @description: get a copy of a string value of the database, see readString
+ * @param {*DB} db
+ * @param {string} key
+ * @return {*} bytes (nil if not exists), WRONGTYPE error
+ */
+func (db *DB) getAsString(key string) ([]byte, reply.ErrorReply) {
+	var result []byte
+	errReply := db.readString(key, func(bytes []byte) {
+		if bytes != nil {
+			result = copyBytes(bytes)
+		}
+	})
+	return result, errReply
+}
+
+/**
+ * @description: copy an argument, arguments are reused after the command
+ */
+func copyBytes(arg []byte) []byte {
+	bytes := make([]byte, len(arg))
+	copy(bytes, arg)
+	return bytes
+}
+
+/**
+ * @description: pad a string with zeros to at least size bytes. The string is
+ *	written in place, see readString, so only a longer string may be moved.
+ */
+func growString(old []byte, size int64) []byte {
+	if size <= int64(len(old)) {
+		return old
+	}
+	return append(old, make([]byte, size-int64(len(old)))...)
+}
+
+/**
+ * @description: make a bulk reply or a null bulk reply
+ */
+func bulkOrNull(bytes []byte, ok bool) reply.Reply {
+	if !ok {
+		return reply.MakeNullBulkReply()
+	}
+	return reply.MakeBulkReply(bytes)
+}
+
+/**
+ * @description: check the size of a string against proto-max-bulk-len
+ */
+func checkStringLength(size int64) reply.ErrorReply {
+	if size > int64(config.Properties.ProtoMaxBulkLen) {
+		return reply.MakeErrReply("ERR string exceeds maximum allowed size (proto-max-bulk-len)")
+	}
+	return nil
+}
+
+/**
+ * @description: parse the time of EX, PX, EXAT or PXAT into unix time in milliseconds
+ * @param {string} unit ex, px, exat or pxat
+ * @param {[]byte} arg
+ * @param {string} name command name
+ * @return {*}
+ */
+func parseExpireOption(unit string, arg []byte, name string) (int64, reply.ErrorReply) {
+	when, err := strconv.ParseInt(string(arg), 10, 64)
+	if err != nil {
+		return 0, reply.MakeNotIntegerErrReply()
+	}
+	invalid := reply.MakeErrReply("ERR invalid expire time in '" + name + "' command")
+	if when <= 0 {
+		return 0, invalid
+	}
+	if unit == "ex" || unit == "exat" {
+		if when > math.MaxInt64/1000 {
+			return 0, invalid
+		}
+		when *= 1000
+	}
+	if unit == "ex" || unit == "px" {
+		now := dict.NowMillis()
+		if when > math.MaxInt64-now {
+			return 0, invalid
+		}
+		when += now
+	}
+	return when, nil
+}
+
+// options of SET
+type setOptions struct {
+	nx, xx, get, keepTTL bool
+	expireAt             int64 // 0 means no TTL
+}
+
+/**
+ * @description: parse [NX|XX] [GET] [EX|PX|EXAT|PXAT|KEEPTTL] of SET
+ * @param {[][]byte} args
+ * @return {*}
+ */
+func parseSetOptions(args [][]byte) (*setOptions, reply.ErrorReply) {
+	opts := &setOptions{}
+	hasExpire := false
+	for i := 0; i < len(args); i++ {
+		opt := strings.ToLower(string(args[i]))
+		switch {
+		case opt == "nx" && !opts.xx:
+			opts.nx = true
+		case opt == "xx" && !opts.nx:
+			opts.xx = true
+		case opt == "get":
+			opts.get = true
+		case opt == "keepttl" && !hasExpire:
+			opts.keepTTL = true
+		case (opt == "ex" || opt == "px" || opt == "exat" || opt == "pxat") &&
+			!opts.keepTTL && !hasExpire && i+1 < len(args):
+			at, errReply := parseExpireOption(opt, args[i+1], "set")
+			if errReply != nil {
+				return nil, errReply
+			}
+			opts.expireAt = at
+			hasExpire = true
+			i++
+		default:
+			return nil, reply.MakeSyntaxErrReply()
+		}
+	}
+	return opts, nil
+}
+
+/**
+ * @description: GET key
+ */
+func execGet(c *Client, args [][]byte) reply.Reply {
+	bytes, errReply := c.db.getAsString(string(args[0]))
+	if errReply != nil {
+		return errReply
+	}
+	return bulkOrNull(bytes, bytes != nil)
+}
+
+/**
+ * @description: SET key value [NX|XX] [GET] [EX seconds|PX milliseconds|EXAT unix-time-seconds|PXAT unix-time-milliseconds|KEEPTTL]
+ */
+func execSet(c *Client, args [][]byte) reply.Reply {
+	key, value := string(args[0]), makeString(args[1])
+	opts, errReply := parseSetOptions(args[2:])
+	if errReply != nil {
+		return errReply
+	}
+
+	var result reply.Reply
+	c.db.Update(key, func(entry *dict.Entry) {
+		var old []byte
+		if entry.Exists {
+			bytes, ok := asString(entry.Value)
+			if !ok && opts.get {
+				result = reply.MakeWrongTypeErrReply()
+				return
+			}
+			old = bytes
+		}
+
+		if (opts.nx && entry.Exists) || (opts.xx && !entry.Exists) {
+			c.keepVersions()
+			if opts.get {
+				// the value is kept, see readString
+				result = bulkOrNull(copyBytes(old), entry.Exists)
+			} else {
+				result = reply.MakeNullBulkReply()
+			}
+			return
+		}
+
+		if opts.get {
+			result = bulkOrNull(old, entry.Exists)
+		} else {
+			result = reply.MakeOkReply()
+		}
+		entry.Value, entry.Exists = value, true
+		if !opts.keepTTL {
+			entry.ExpireAt = opts.expireAt
+		}
+	})
+	return result
+}
+
+/**
+ * @description: SETNX key value
+ */
+func execSetNX(c *Client, args [][]byte) reply.Reply {
+	return c.changesReply(int64(c.db.PutIfAbsent(string(args[0]), makeString(args[1]))))
+}
+
+/**
+ * @description: SETEX and PSETEX
+ */
+func setexGeneric(c *Client, args [][]byte, unit string, name string) reply.Reply {
+	at, errReply := parseExpireOption(unit, args[1], name)
+	if errReply != nil {
+		return errReply
+	}
+	c.db.PutEntityWithExpire(string(args[0]), makeString(args[2]), at)
+	return reply.MakeOkReply()
+}
+
+/**
+ * @description: SETEX key seconds value
+ */
+func execSetEX(c *Client, args [][]byte) reply.Reply {
+	return setexGeneric(c, args, "ex", "setex")
+}
+
+/**
+ * @description: PSETEX key milliseconds value
+ */
+func execPSetEX(c *Client, args [][]byte) reply.Reply {
+	return setexGeneric(c, args, "px", "psetex")
+}
+
+/**
+ * @description: GETSET key value
+ */
+func execGetSet(c *Client, args [][]byte) reply.Reply {
+	key, value := string(args[0]), makeString(args[1])
+
+	var result reply.Reply
+	c.db.Update(key, func(entry *dict.Entry) {
+		if !entry.Exists {
+			result = reply.MakeNullBulkReply()
+		} else if old, ok := asString(entry.Value); ok {
+			result = reply.MakeBulkReply(old)
+		} else {
+			result = reply.MakeWrongTypeErrReply()
+			return
+		}
+		entry.Value, entry.Exists, entry.ExpireAt = value, true, 0
+	})
+	return result
+}
+
+/**
+ * @description: GETDEL key
+ */
+func execGetDel(c *Client, args [][]byte) reply.Reply {
+	var result reply.Reply
+	c.db.Update(string(args[0]), func(entry *dict.Entry) {
+		if !entry.Exists {
+			c.keepVersions()
+			result = reply.MakeNullBulkReply()
+		} else if old, ok := asString(entry.Value); ok {
+			result = reply.MakeBulkReply(old)
+			entry.Exists = false
+		} else {
+			result = reply.MakeWrongTypeErrReply()
+		}
+	})
+	return result
+}
+
+/**
+ * @description: GETEX key [EX seconds|PX milliseconds|EXAT unix-time-seconds|PXAT unix-time-milliseconds|PERSIST]
+ */
+func execGetEX(c *Client, args [][]byte) reply.Reply {
+	key := string(args[0])
+	var expireAt int64
+	persist, hasExpire := false, false
+	for i := 1; i < len(args); i++ {
+		opt := strings.ToLower(string(args[i]))
+		switch {
+		case opt == "persist" && !hasExpire && !persist:
+			persist = true
+		case (opt == "ex" || opt == "px" || opt == "exat" || opt == "pxat") &&
+			!hasExpire && !persist && i+1 < len(args):
+			at, errReply := parseExpireOption(opt, args[i+1], "getex")
+			if errReply != nil {
+				return errReply
+			}
+			expireAt, hasExpire = at, true
+			i++
+		default:
+			return reply.MakeSyntaxErrReply()
+		}
+	}
+
+	var result reply.Reply
+	c.db.Update(key, func(entry *dict.Entry) {
+		if !entry.Exists {
+			c.keepVersions()
+			result = reply.MakeNullBulkReply()
+			return
+		}
+		old, ok := asString(entry.Value)
+		if !ok {
+			result = reply.MakeWrongTypeErrReply()
+			return
+		}
+		// the value is kept, see readString
+		result = reply.MakeBulkReply(copyBytes(old))
+		if hasExpire {
+			entry.ExpireAt = expireAt
+		} else if persist && entry.ExpireAt != 0 {
+			entry.ExpireAt = 0
+		} else {
+			c.keepVersions()
+		}
+	})
+	return result
+}
+
+/**
+ * @description: MGET key [key ...]
+ */
+func execMGet(c *Client, args [][]byte) reply.Reply {
+	result := make([][]byte, len(args))
+	for i, arg := range args {
+		// not a string is a null bulk
+		bytes, _ := c.db.getAsString(string(arg))
+		result[i] = bytes
+	}
+	return reply.MakeMultiBulkReply(result)
+}
+
+/**
+ * @description: MSET key value [key value ...]
+ */
+func execMSet(c *Client, args [][]byte) reply.Reply {
+	if len(args)%2 != 0 {
+		return reply.MakeArgNumErrReply("mset")
+	}
+	keys := msetKeys(args)
+	c.db.Locks(keys, nil)
+	defer c.db.Unlocks(keys, nil)
+	msetWithLock(c.db, args)
+	return reply.MakeOkReply()
+}
+
+/**
+ * @description: MSETNX key value [key value ...]
+ */
+func execMSetNX(c *Client, args [][]byte) reply.Reply {
+	if len(args)%2 != 0 {
+		return reply.MakeArgNumErrReply("msetnx")
+	}
+	keys := msetKeys(args)
+	c.db.Locks(keys, nil)
+	defer c.db.Unlocks(keys, nil)
+	for _, key := range keys {
+		if _, exists := c.db.GetEntityWithLock(key); exists {
+			return c.changesReply(0)
+		}
+	}
+	msetWithLock(c.db, args)
+	return reply.MakeIntReply(1)
+}
+
+/**
+ * @description: keys of "key value [key value ...]"
+ */
+func msetKeys(args [][]byte) []string {
+	keys := make([]string, 0, len(args)/2)
+	for i := 0; i < len(args); i += 2 {
+		keys = append(keys, string(args[i]))
+	}
+	return keys
+}
+
+/**
+ * @description: set the pairs and clear their TTL, the keys must be locked
+ */
+func msetWithLock(db *DB, args [][]byte) {
+	for i := 0; i < len(args); i += 2 {
+		value := makeString(args[i+1])
+		db.UpdateWithLock(string(args[i]), func(entry *dict.Entry) {
+			entry.Value, entry.Exists, entry.ExpireAt = value, true, 0
+		})
+	}
+}
+
+/**
+ * @description: APPEND key value
+ */
+func execAppend(c *Client, args [][]byte) reply.Reply {
+	var result reply.Reply
+	c.db.Update(string(args[0]), func(entry *dict.Entry) {
+		var old []byte
+		if entry.Exists {
+			bytes, ok := asString(entry.Value)
+			if !ok {
+				result = reply.MakeWrongTypeErrReply()
+				return
+			}
+			old = bytes
+		}
+		if errReply := checkStringLength(int64(len(old) + len(args[1]))); errReply != nil {
+			result = errReply
+			return
+		}
+		// written in place, see readString
+		value := append(old, args[1]...)
+		entry.Value, entry.Exists = value, true
+		result = reply.MakeIntReply(int64(len(value)))
+	})
+	return result
+}
+
+/**
+ * @description: STRLEN key
+ */
+func execStrLen(c *Client, args [][]byte) reply.Reply {
+	var size int
+	if errReply := c.db.readString(string(args[0]), func(bytes []byte) {
+		size = len(bytes)
+	}); errReply != nil {
+		return errReply
+	}
+	return reply.MakeIntReply(int64(size))
+}
+
+/**
+ * @description: GETRANGE key start end
+ */
+func execGetRange(c *Client, args [][]byte) reply.Reply {
+	start, err1 := strconv.ParseInt(string(args[1]), 10, 64)
+	end, err2 := strconv.ParseInt(string(args[2]), 10, 64)
+	if err1 != nil || err2 != nil {
+		return reply.MakeNotIntegerErrReply()
+	}
+	// only the range is copied, see readString
+	var result []byte
+	if errReply := c.db.readString(string(args[0]), func(bytes []byte) {
+		result = copyBytes(stringRange(bytes, start, end))
+	}); errReply != nil {
+		return errReply
+	}
+	return reply.MakeBulkReply(result)
+}
+
+/**
+ * @description: the bytes between two offsets (inclusive) of GETRANGE,
+ *	negative offsets count from the end
+ */
+func stringRange(bytes []byte, start, end int64) []byte {
+	size := int64(len(bytes))
+	if start < 0 && end < 0 && start > end {
+		return nil
+	}
+	if start < 0 {
+		start += size
+	}
+	if end < 0 {
+		end += size
+	}
+	if start < 0 {
+		start = 0
+	}
+	if end < 0 {
+		end = 0
+	}
+	if end >= size {
+		end = size - 1
+	}
+	if start > end || size == 0 {
+		return nil
+	}
+	return bytes[start : end+1]
+}
+
+/**
+ * @description: SETRANGE key offset value
+ */
+func execSetRange(c *Client, args [][]byte) reply.Reply {
+	offset, err := strconv.ParseInt(string(args[1]), 10, 64)
+	if err != nil {
+		return reply.MakeNotIntegerErrReply()
+	}
+	if offset < 0 {
+		return reply.MakeErrReply("ERR offset is out of range")
+	}
+	value := args[2]
+
+	var result reply.Reply
+	c.db.Update(string(args[0]), func(entry *dict.Entry) {
+		var old []byte
+		if entry.Exists {
+			bytes, ok := asString(entry.Value)
+			if !ok {
+				result = reply.MakeWrongTypeErrReply()
+				return
+			}
+			old = bytes
+		}
+		// nothing to write, do not create the key
+		if len(value) == 0 {
+			c.keepVersions()
+			result = reply.MakeIntReply(int64(len(old)))
+			return
+		}
+		if errReply := checkStringLength(offset + int64(len(value))); errReply != nil {
+			result = errReply
+			return
+		}
+
+		// written in place, see readString
+		bytes := growString(old, offset+int64(len(value)))
+		copy(bytes[offset:], value)
+		entry.Value, entry.Exists = bytes, true
+		result = reply.MakeIntReply(int64(len(bytes)))
+	})
+	return result
+}
@@ -0,0 +1,166 @@
+/*
+ * @Description:
+ * @Autor: HTmonster
+ * @Date: 2026-10-18 20:48:19
+ */
+package server
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// a command and its expected reply
+type cmdCase struct {
+	args []string
+	want string
+}
+
+/**
+ * @description: run the commands in order on one client
+ */
+func runCases(t *testing.T, h *Handler, c *Client, cases []cmdCase) {
+	t.Helper()
+	for _, tc := range cases {
+		if got := execString(h, c, tc.args...); got != tc.want {
+			t.Errorf("%q: got %q, want %q", tc.args, got, tc.want)
+		}
+	}
+}
+
+func TestSetGet(t *testing.T) {
+	h := NewHandler()
+	defer h.Close()
+	c := newTestClient(h)
+	c.db = h.selectDB(0)
+	c.db.PutEntity("list", struct{}{})
+
+	runCases(t, h, c, []cmdCase{
+		{[]string{"GET", "k"}, "$-1\r\n"},
+		{[]string{"SET", "k", "v\x00"}, "+OK\r\n"},
+		{[]string{"GET", "k"}, "$2\r\nv\x00\r\n"},
+		{[]string{"SET", "k", "v2", "NX"}, "$-1\r\n"},
+		{[]string{"SET", "k", "v2", "NX", "GET"}, "$2\r\nv\x00\r\n"},
+		{[]string{"SET", "k2", "v2", "XX"}, "$-1\r\n"},
+		{[]string{"SET", "k", "v2", "XX", "GET"}, "$2\r\nv\x00\r\n"},
+		{[]string{"SET", "k", "v3", "NX", "XX"}, "-ERR syntax error\r\n"},
+		{[]string{"SET", "k", "v3", "EX", "10", "PX", "100"}, "-ERR syntax error\r\n"},
+		{[]string{"SET", "k", "v3", "EX", "10", "KEEPTTL"}, "-ERR syntax error\r\n"},
+		{[]string{"SET", "k", "v3", "EX"}, "-ERR syntax error\r\n"},
+		{[]string{"SET", "k", "v3", "EX", "0"}, "-ERR invalid expire time in 'set' command\r\n"},
+		{[]string{"SET", "k", "v3", "EX", "ten"}, "-ERR value is not an integer or out of range\r\n"},
+		{[]string{"SET", "k", "v3", "EX", "100"}, "+OK\r\n"},
+		{[]string{"TTL", "k"}, ":100\r\n"},
+		{[]string{"SET", "k", "v4", "KEEPTTL"}, "+OK\r\n"},
+		{[]string{"TTL", "k"}, ":100\r\n"},
+		{[]string{"SET", "k", "v5"}, "+OK\r\n"},
+		{[]string{"TTL", "k"}, ":-1\r\n"},
+		{[]string{"SET", "k", "v6", "PXAT", "4102444800000"}, "+OK\r\n"},
+		{[]string{"PEXPIRETIME", "k"}, ":4102444800000\r\n"},
+		{[]string{"GET", "list"}, "-WRONGTYPE Operation against a key holding the wrong kind of value\r\n"},
+		{[]string{"SET", "list", "x", "GET"}, "-WRONGTYPE Operation against a key holding the wrong kind of value\r\n"},
+		{[]string{"SET", "list", "x"}, "+OK\r\n"},
+		{[]string{"GET", "list"}, "$1\r\nx\r\n"},
+	})
+}
+
+func TestStringCommands(t *testing.T) {
+	h := NewHandler()
+	defer h.Close()
+	c := newTestClient(h)
+
+	runCases(t, h, c, []cmdCase{
+		{[]string{"SETNX", "a", "1"}, ":1\r\n"},
+		{[]string{"SETNX", "a", "2"}, ":0\r\n"},
+		{[]string{"SETEX", "a", "100", "3"}, "+OK\r\n"},
+		{[]string{"TTL", "a"}, ":100\r\n"},
+		{[]string{"SETEX", "a", "-1", "3"}, "-ERR invalid expire time in 'setex' command\r\n"},
+		{[]string{"PSETEX", "a", "100000", "4"}, "+OK\r\n"},
+		{[]string{"GETSET", "a", "5"}, "$1\r\n4\r\n"},
+		{[]string{"TTL", "a"}, ":-1\r\n"},
+		{[]string{"GETSET", "b", "6"}, "$-1\r\n"},
+		{[]string{"GETDEL", "b"}, "$1\r\n6\r\n"},
+		{[]string{"GETDEL", "b"}, "$-1\r\n"},
+		{[]string{"GETEX", "a", "EX", "100"}, "$1\r\n5\r\n"},
+		{[]string{"TTL", "a"}, ":100\r\n"},
+		{[]string{"GETEX", "a", "PERSIST"}, "$1\r\n5\r\n"},
+		{[]string{"TTL", "a"}, ":-1\r\n"},
+		{[]string{"GETEX", "a", "PERSIST", "EX", "1"}, "-ERR syntax error\r\n"},
+		{[]string{"GETEX", "none"}, "$-1\r\n"},
+		{[]string{"MSET", "x", "1", "y"}, "-ERR wrong number of arguments for 'mset' command\r\n"},
+		{[]string{"MSET", "x", "1", "y", "2"}, "+OK\r\n"},
+		{[]string{"MGET", "x", "none", "y"}, "*3\r\n$1\r\n1\r\n$-1\r\n$1\r\n2\r\n"},
+		{[]string{"MSETNX", "y", "3", "z", "4"}, ":0\r\n"},
+		{[]string{"GET", "z"}, "$-1\r\n"},
+		{[]string{"MSETNX", "z", "4", "w", "5"}, ":1\r\n"},
+		{[]string{"APPEND", "s", "Hello"}, ":5\r\n"},
+		{[]string{"APPEND", "s", " World"}, ":11\r\n"},
+		{[]string{"STRLEN", "s"}, ":11\r\n"},
+		{[]string{"STRLEN", "none"}, ":0\r\n"},
+		{[]string{"GETRANGE", "s", "0", "3"}, "$4\r\nHell\r\n"},
+		{[]string{"GETRANGE", "s", "-3", "-1"}, "$3\r\nrld\r\n"},
+		{[]string{"GETRANGE", "s", "0", "-1"}, "$11\r\nHello World\r\n"},
+		{[]string{"GETRANGE", "s", "10", "100"}, "$1\r\nd\r\n"},
+		{[]string{"GETRANGE", "s", "5", "3"}, "$0\r\n\r\n"},
+		{[]string{"GETRANGE", "s", "-1", "-5"}, "$0\r\n\r\n"},
+		{[]string{"GETRANGE", "none", "0", "-1"}, "$0\r\n\r\n"},
+		{[]string{"SETRANGE", "s", "6", "Redis"}, ":11\r\n"},
+		{[]string{"GET", "s"}, "$11\r\nHello Redis\r\n"},
+		{[]string{"SETRANGE", "p", "3", "ab"}, ":5\r\n"},
+		{[]string{"GET", "p"}, "$5\r\n\x00\x00\x00ab\r\n"},
+		{[]string{"SETRANGE", "q", "3", ""}, ":0\r\n"},
+		{[]string{"GET", "q"}, "$-1\r\n"},
+		{[]string{"SETRANGE", "s", "-1", "x"}, "-ERR offset is out of range\r\n"},
+		{[]string{"SETRANGE", "s", "536870911", "xx"}, "-ERR string exceeds maximum allowed size (proto-max-bulk-len)\r\n"},
+	})
+}
+
+func TestSetBinarySafe(t *testing.T) {
+	h := NewHandler()
+	defer h.Close()
+	c := newTestClient(h)
+
+	value := strings.Repeat("\r\n\x00\xff", 1024)
+	if got := execString(h, c, "SET", "bin", value); got != "+OK\r\n" {
+		t.Fatalf("SET: got %q", got)
+	}
+	if got := execString(h, c, "GET", "bin"); got != "$4096\r\n"+value+"\r\n" {
+		t.Errorf("GET should return the same bytes")
+	}
+}
+
+func TestMSetNXConcurrent(t *testing.T) {
+	h := NewHandler()
+	defer h.Close()
+
+	// clients race on overlapping keys, at most one of them sets a key
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	won := make(map[string]string)
+	for w := 0; w < 20; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			c := newTestClient(h)
+			for i := 0; i < 50; i++ {
+				a, b := "k"+strconv.Itoa(i), "k"+strconv.Itoa(i+1)
+				value := strconv.Itoa(w)
+				if execString(h, c, "MSETNX", a, value, b, value) == ":1\r\n" {
+					mu.Lock()
+					won[a], won[b] = value, value
+					mu.Unlock()
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	c := newTestClient(h)
+	for key, value := range won {
+		if got := execString(h, c, "GET", key); got != "$"+strconv.Itoa(len(value))+"\r\n"+value+"\r\n" {
+			t.Errorf("%s: got %q, want %s", key, got, value)
+		}
+	}
+}